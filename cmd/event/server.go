@@ -1 +1,51 @@
 package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"test-go/internal/bootstrap"
+	"test-go/internal/core/entities"
+	"test-go/internal/infrastructure/config"
+	"test-go/internal/infrastructure/logging"
+)
+
+func main() {
+	// Load configuration
+	conf := config.LoadConfig()
+
+	// Wire adapters and services in one place
+	container := bootstrap.New(conf)
+	defer container.Close(context.Background())
+
+	// Initialize the logger
+	logger := logging.NewLogger("Event: ")
+
+	// Stop consuming on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Deliver webhooks, release expired reservations, run the schedulers and generate thumbnails
+	dispatcher := container.StartWorkers(ctx)
+
+	// Refresh the bundles containing changed products before notifying subscribers; a failed
+	// refresh is logged so the event still reaches the webhooks
+	bundles := container.BundleService()
+	handleEvent := func(ctx context.Context, event *entities.ProductEvent) error {
+		if err := bundles.HandleEvent(ctx, event); err != nil {
+			logger.Error("Failed to refresh bundles of product " + event.ProductID + ": " + err.Error())
+		}
+		return dispatcher.HandleEvent(ctx, event)
+	}
+
+	logger.Info("Event consumer is running")
+	if err := container.EventConsumer().ConsumeProductEvents(ctx, handleEvent); err != nil {
+		logger.Error("Failed to consume product events: " + err.Error())
+	}
+
+	// Let the attempts in flight finish before exiting; retries stay queued for the next start
+	dispatcher.Wait()
+	logger.Info("Event consumer stopped")
+}
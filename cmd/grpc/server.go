@@ -1,14 +1,13 @@
 package main
 
 import (
+	"context"
 	"net"
 
 	grpcHandler "test-go/internal/adapters/primary/grpc"
 	"test-go/internal/adapters/primary/grpc/proto"
-	queue "test-go/internal/adapters/secondary/messaging"
-	"test-go/internal/application"
+	"test-go/internal/bootstrap"
 	"test-go/internal/infrastructure/config"
-	"test-go/internal/infrastructure/db"
 	"test-go/internal/infrastructure/logging"
 	"test-go/internal/infrastructure/middleware"
 
@@ -19,26 +18,81 @@ func main() {
 	// Load configuration
 	conf := config.LoadConfig()
 
-	mongoDB := db.GetMongoInstance(conf.MongoURI, conf.MongoDbName)
-	redisClient := db.GetRedisInstance(conf.RedisURI, conf.RedisDbName)
-	rabbitMQ := queue.GetRmqInstance(conf.RabbitMqURI)
+	// Wire adapters and services in one place
+	container := bootstrap.New(conf)
+	defer container.Close(context.Background())
 
 	// Initialize the logger
 	logger := logging.NewLogger("gRPC: ")
 
+	// Responses to retried mutations are remembered in the idempotency store
+	idempotencyStore := container.IdempotencyStore()
+
+	// Every call is scoped to the tenant of its token or x-tenant-id metadata
+	tenantResolver := middleware.NewTenantResolver(conf.JWTSecret, conf.TenantClaim, conf.TenantRequired)
+
+	// Calls of each client are limited by the first matching rule
+	rateLimiter := container.RateLimiter()
+	rateLimitPolicy := middleware.NewRateLimitPolicy(conf.RateLimits, conf.JWTSecret)
+	// Unknown API keys are counted per IP address before they reach the key repository
+	authFailures := middleware.NewAuthFailureLimiter(rateLimiter, conf.AuthFailureLimit)
+
+	// Machine clients authenticate with API keys, which other replicas revoke through a broadcast
+	apiKeyService := container.APIKeyService()
+	go apiKeyService.RunRevocationListener(context.Background())
+
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			middleware.UnaryLoggingInterceptor(logger),  // Logging interceptor
 			middleware.UnaryRecoveryInterceptor(logger), // Recovery interceptor
+			// middleware.UnaryAuthInterceptor(logger),     // Authentication interceptor
+			middleware.UnaryAPIKeyInterceptor(apiKeyService, authFailures, logger),                // Authenticate API keys
+			middleware.UnaryRateLimitInterceptor(rateLimiter, rateLimitPolicy, logger),            // Limit the calls of each authenticated client
+			middleware.UnaryRequestInfoInterceptor(conf.JWTSecret),                                // Actor and request ID for the change history
+			middleware.UnaryTenantInterceptor(tenantResolver),                                     // Tenant of the call
+			middleware.UnaryLocaleInterceptor(),                                                   // Locales of accept-language for localized text
+			middleware.UnaryIdempotencyInterceptor(idempotencyStore, conf.IdempotencyTTL, logger), // Replay retried mutations
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.StreamLoggingInterceptor(logger),  // Logging interceptor
+			middleware.StreamRecoveryInterceptor(logger), // Recovery interceptor
+			// middleware.StreamAuthInterceptor(logger),     // Authentication interceptor
+			middleware.StreamAPIKeyInterceptor(apiKeyService, authFailures, logger),     // Authenticate API keys
+			middleware.StreamRateLimitInterceptor(rateLimiter, rateLimitPolicy, logger), // Limit the calls of each authenticated client
+			middleware.StreamRequestInfoInterceptor(conf.JWTSecret),                     // Actor and request ID for the change history
+			middleware.StreamTenantInterceptor(tenantResolver),                          // Tenant of the call
+			middleware.StreamLocaleInterceptor(),                                        // Locales of accept-language for localized text
 		),
 	)
 
-	// Initialize ProductService and ProductHandler
-	productService := application.NewProductService(mongoDB, redisClient, rabbitMQ)
+	// Initialize the handlers
+	productService := container.ProductService()
 	productHandler := grpcHandler.NewProductHandler(productService)
+	productRevisionHandler := grpcHandler.NewProductRevisionHandler(productService)
+	webhookHandler := grpcHandler.NewWebhookHandler(container.WebhookService())
+	inventoryHandler := grpcHandler.NewInventoryHandler(container.InventoryService())
+	priceScheduleHandler := grpcHandler.NewPriceScheduleHandler(container.PriceScheduleService())
+	taxHandler := grpcHandler.NewTaxHandler(container.TaxService(), productService)
+	categoryHandler := grpcHandler.NewCategoryHandler(container.CategoryService())
+	variantHandler := grpcHandler.NewVariantHandler(container.VariantService())
+	productTypeHandler := grpcHandler.NewProductTypeHandler(container.ProductTypeService())
+	mediaHandler := grpcHandler.NewMediaHandler(container.MediaService())
 
-	// Register the ProductService server
+	// Register the gRPC servers
 	proto.RegisterProductServiceServer(grpcServer, productHandler)
+	proto.RegisterProductRevisionServiceServer(grpcServer, productRevisionHandler)
+	proto.RegisterWebhookServiceServer(grpcServer, webhookHandler)
+	proto.RegisterInventoryServiceServer(grpcServer, inventoryHandler)
+	proto.RegisterPriceScheduleServiceServer(grpcServer, priceScheduleHandler)
+	proto.RegisterTaxServiceServer(grpcServer, taxHandler)
+	proto.RegisterCategoryServiceServer(grpcServer, categoryHandler)
+	proto.RegisterVariantServiceServer(grpcServer, variantHandler)
+	proto.RegisterProductTypeServiceServer(grpcServer, productTypeHandler)
+	proto.RegisterMediaServiceServer(grpcServer, mediaHandler)
+
+	// Webhooks, bundles, reservations, schedules, exchange rates and thumbnails are handled by the
+	// event consumer, or here when events never leave this process
+	container.StartInProcessEventHandlers(context.Background())
 
 	// Listen on the specified gRPC port
 	listener, err := net.Listen("tcp", ":"+conf.GrpcPort)
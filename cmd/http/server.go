@@ -1,14 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"test-go/internal/adapters/primary/http"
 	_ "test-go/internal/adapters/primary/http/swagger"
-	queue "test-go/internal/adapters/secondary/messaging"
 	"test-go/internal/application"
+	"test-go/internal/bootstrap"
+	"test-go/internal/core/ports"
 	"test-go/internal/infrastructure/config"
-	"test-go/internal/infrastructure/db"
 	"test-go/internal/infrastructure/logging"
 	"test-go/internal/infrastructure/middleware"
 
@@ -16,6 +17,12 @@ import (
 	"github.com/gofiber/swagger"
 )
 
+const (
+	feedHistorySize   = 1000     // Events kept for Last-Event-ID replay
+	feedBufferSize    = 64       // Events queued per subscriber before it is dropped
+	multipartOverhead = 64 << 10 // Room for the multipart headers around an upload of the largest media
+)
+
 // @title Product API
 // @version 1.0
 // @description API for managing products
@@ -32,27 +39,82 @@ func main() {
 	// Load configuration
 	conf := config.LoadConfig()
 
-	mongoDB := db.GetMongoInstance(conf.MongoURI, conf.MongoDbName)
-	redisClient := db.GetRedisInstance(conf.RedisURI, conf.RedisDbName)
-	rabbitMQ := queue.GetRmqInstance(conf.RabbitMqURI)
+	// Wire adapters and services in one place
+	container := bootstrap.New(conf)
+	defer container.Close(context.Background())
 
 	// Initialize the logger
 	logger := logging.NewLogger("HTTP: ")
 
-	// Create a new Fiber app
-	app := fiber.New()
+	// Every request is scoped to the tenant of its token or X-Tenant-ID header
+	tenantResolver := middleware.NewTenantResolver(conf.JWTSecret, conf.TenantClaim, conf.TenantRequired)
+
+	// Requests of each client are limited by the first matching rule
+	rateLimitPolicy := middleware.NewRateLimitPolicy(conf.RateLimits, conf.JWTSecret)
+	// Unknown API keys are counted per IP address before they reach the key repository
+	authFailures := middleware.NewAuthFailureLimiter(container.RateLimiter(), conf.AuthFailureLimit)
+
+	// Machine clients authenticate with API keys, which other replicas revoke through a broadcast
+	apiKeyService := container.APIKeyService()
+	go apiKeyService.RunRevocationListener(context.Background())
+
+	// The admin routes need an admin key, so the first one of each tenant comes from the configuration
+	for tenantID, key := range conf.BootstrapAPIKeys {
+		if err := apiKeyService.SeedAPIKey(ports.WithTenant(context.Background(), tenantID), key); err != nil {
+			log.Fatalf("Failed to seed the bootstrap API key of tenant %s: %v", tenantID, err)
+		}
+	}
+
+	// Create a new Fiber app accepting media uploads up to the configured size
+	app := fiber.New(fiber.Config{
+		BodyLimit: max(fiber.DefaultBodyLimit, int(conf.MediaMaxBytes)+multipartOverhead),
+	})
 
 	// Apply middleware
 	app.Use(middleware.RecoveryMiddleware(logger)) // Handle panics and log them
 	app.Use(middleware.LoggingMiddleware(logger))  // Custom logging middleware for detailed logs
 	// app.Use(middleware.AuthMiddleware)             // Authentication middleware
+	app.Use(middleware.APIKeyMiddleware(apiKeyService, authFailures, logger))                            // Authenticate API keys
+	app.Use(middleware.RateLimitMiddleware(container.RateLimiter(), rateLimitPolicy, logger))            // Limit the requests of each authenticated client
+	app.Use(middleware.RequestInfoMiddleware(conf.JWTSecret))                                            // Actor and request ID for the change history
+	app.Use(middleware.TenantMiddleware(tenantResolver))                                                 // Tenant of the request
+	app.Use(middleware.LocaleMiddleware())                                                               // Locales of Accept-Language for localized text
+	app.Use(middleware.IdempotencyMiddleware(container.IdempotencyStore(), conf.IdempotencyTTL, logger)) // Replay retried mutations
 
-	// Create service and handler, passing MongoDB and Redis clients to the service
-	productService := application.NewProductService(mongoDB, redisClient, rabbitMQ)
+	// Create service and handler
+	productService := container.ProductService()
 	productHandler := http.NewProductHandler(productService)
 
+	// Fan product changes out to SSE and WebSocket subscribers
+	productFeed := application.NewProductFeed(productService, feedHistorySize, feedBufferSize)
+	go productFeed.Run(context.Background())
+	productEventsHandler := http.NewProductEventsHandler(productFeed)
+
+	// Webhooks, bundles, reservations, schedules, exchange rates and thumbnails are handled by the
+	// event consumer, or here when events never leave this process
+	container.StartInProcessEventHandlers(context.Background())
+
 	// Set up routes
-	http.SetupRoutes(app, productHandler)
+	http.SetupRoutes(app, productHandler, productEventsHandler)
+	http.SetupProductRevisionRoutes(app, http.NewProductRevisionHandler(productService))
+	http.SetupProductTranslationRoutes(app, http.NewProductTranslationHandler(productService))
+	http.SetupProductLifecycleRoutes(app, http.NewProductLifecycleHandler(productService))
+	http.SetupWebhookRoutes(app, http.NewWebhookHandler(container.WebhookService()))
+	http.SetupInventoryRoutes(app, http.NewInventoryHandler(container.InventoryService()))
+	http.SetupPriceScheduleRoutes(app, http.NewPriceScheduleHandler(container.PriceScheduleService()))
+	http.SetupPriceListRoutes(app, http.NewPriceListHandler(container.PriceListService()))
+	http.SetupExchangeRateRoutes(app, http.NewExchangeRateHandler(container.ExchangeRateService()))
+	http.SetupBundleRoutes(app, http.NewBundleHandler(container.BundleService()))
+	http.SetupTaxRoutes(app, http.NewTaxHandler(container.TaxService(), productService))
+	http.SetupCategoryRoutes(app, http.NewCategoryHandler(container.CategoryService()))
+	http.SetupVariantRoutes(app, http.NewVariantHandler(container.VariantService()))
+	http.SetupProductTypeRoutes(app, http.NewProductTypeHandler(container.ProductTypeService()))
+	http.SetupAPIKeyRoutes(app, http.NewAPIKeyHandler(apiKeyService))
+	http.SetupAuditRoutes(app, http.NewAuditHandler(container.AuditService()))
+	http.SetupMediaRoutes(app, http.NewMediaHandler(container.MediaService()))
+	if maintenance := container.StorageMaintenance(); maintenance != nil {
+		http.SetupStorageRoutes(app, http.NewStorageHandler(maintenance))
+	}
 
 	// Register Swagger route
 	app.Get("/api/docs/*", swagger.HandlerDefault) // Swagger endpoint
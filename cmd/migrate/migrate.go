@@ -1 +1,32 @@
 package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/bootstrap"
+	"test-go/internal/infrastructure/config"
+	"test-go/migrations"
+)
+
+func main() {
+	// Load configuration
+	conf := config.LoadConfig()
+	if conf.Storage != config.StorageMongoDB {
+		log.Printf("Storage %q has no MongoDB schema, nothing to migrate", conf.Storage)
+		return
+	}
+
+	container := bootstrap.New(conf)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	defer container.Close(context.Background())
+
+	if err := migrations.Run(ctx, container.MongoDB()); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	log.Println("Migrations applied successfully")
+}
@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CategoryHandler implements the gRPC server interface for managing categories
+type CategoryHandler struct {
+	proto.UnimplementedCategoryServiceServer
+	service *application.CategoryService
+}
+
+// NewCategoryHandler creates a new instance of CategoryHandler
+func NewCategoryHandler(service *application.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// CreateCategory handles the creation of a new category via gRPC
+func (h *CategoryHandler) CreateCategory(ctx context.Context, req *proto.CreateCategoryRequest) (*proto.CategoryResponse, error) {
+	category, err := h.service.CreateCategory(ctx, req.Name, req.ParentId)
+	if err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return &proto.CategoryResponse{Category: toProtoCategory(category)}, nil
+}
+
+// GetCategory retrieves a category by its ID via gRPC
+func (h *CategoryHandler) GetCategory(ctx context.Context, req *proto.CategoryIDRequest) (*proto.CategoryResponse, error) {
+	category, err := h.service.GetCategory(ctx, req.Id)
+	if err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return &proto.CategoryResponse{Category: toProtoCategory(category)}, nil
+}
+
+// UpdateCategory renames a category via gRPC
+func (h *CategoryHandler) UpdateCategory(ctx context.Context, req *proto.UpdateCategoryRequest) (*proto.UpdateCategoryResponse, error) {
+	if err := h.service.RenameCategory(ctx, req.Id, req.Name); err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return &proto.UpdateCategoryResponse{Success: true}, nil
+}
+
+// DeleteCategory deletes an empty category via gRPC
+func (h *CategoryHandler) DeleteCategory(ctx context.Context, req *proto.CategoryIDRequest) (*proto.DeleteCategoryResponse, error) {
+	if err := h.service.DeleteCategory(ctx, req.Id); err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return &proto.DeleteCategoryResponse{Success: true}, nil
+}
+
+// ListCategories lists every category, the roots, or the children of a category via gRPC
+func (h *CategoryHandler) ListCategories(ctx context.Context, req *proto.ListCategoriesRequest) (*proto.CategoriesResponse, error) {
+	var (
+		categories []*entities.Category
+		err        error
+	)
+	if req.ParentId == "" && !req.RootsOnly {
+		categories, err = h.service.ListCategories(ctx)
+	} else {
+		categories, err = h.service.ListChildren(ctx, req.ParentId)
+	}
+	if err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return toProtoCategories(categories), nil
+}
+
+// MoveCategory moves or reorders a category via gRPC
+func (h *CategoryHandler) MoveCategory(ctx context.Context, req *proto.MoveCategoryRequest) (*proto.CategoryResponse, error) {
+	category, err := h.service.MoveCategory(ctx, req.Id, req.ParentId, int(req.Position))
+	if err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return &proto.CategoryResponse{Category: toProtoCategory(category)}, nil
+}
+
+// GetBreadcrumb retrieves the categories from the root down to a category via gRPC
+func (h *CategoryHandler) GetBreadcrumb(ctx context.Context, req *proto.CategoryIDRequest) (*proto.CategoriesResponse, error) {
+	breadcrumb, err := h.service.GetBreadcrumb(ctx, req.Id)
+	if err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return toProtoCategories(breadcrumb), nil
+}
+
+// ListDescendants lists every category below a category via gRPC
+func (h *CategoryHandler) ListDescendants(ctx context.Context, req *proto.CategoryIDRequest) (*proto.CategoriesResponse, error) {
+	descendants, err := h.service.ListDescendants(ctx, req.Id)
+	if err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return toProtoCategories(descendants), nil
+}
+
+// AssignProductCategories replaces the categories of a product via gRPC
+func (h *CategoryHandler) AssignProductCategories(ctx context.Context, req *proto.AssignProductCategoriesRequest) (*proto.AssignProductCategoriesResponse, error) {
+	if err := h.service.AssignProductCategories(ctx, req.ProductId, req.CategoryIds); err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	return &proto.AssignProductCategoriesResponse{Success: true}, nil
+}
+
+// ListCategoryProducts lists the products of a category via gRPC
+func (h *CategoryHandler) ListCategoryProducts(ctx context.Context, req *proto.ListCategoryProductsRequest) (*proto.ListProductsResponse, error) {
+	products, err := h.service.ListCategoryProducts(ctx, req.Id, req.IncludeDescendants, req.Status)
+	if err != nil {
+		return nil, categoryStatus(err)
+	}
+
+	var protoProducts []*proto.Product
+	for _, product := range products {
+		protoProducts = append(protoProducts, toProtoProduct(product))
+	}
+
+	return &proto.ListProductsResponse{Products: protoProducts}, nil
+}
+
+// toProtoCategory converts a category to its protobuf representation
+func toProtoCategory(category *entities.Category) *proto.Category {
+	return &proto.Category{
+		Id:       category.ID.Hex(),
+		Name:     category.Name,
+		ParentId: category.ParentID,
+		Path:     category.Path,
+		Depth:    int32(category.Depth),
+		Position: int32(category.Position),
+	}
+}
+
+// toProtoCategories converts a list of categories to a protobuf response
+func toProtoCategories(categories []*entities.Category) *proto.CategoriesResponse {
+	resp := &proto.CategoriesResponse{}
+	for _, category := range categories {
+		resp.Categories = append(resp.Categories, toProtoCategory(category))
+	}
+	return resp
+}
+
+// categoryStatus maps category service errors to gRPC status errors
+func categoryStatus(err error) error {
+	switch {
+	case errors.Is(err, ports.ErrCategoryNotFound), errors.Is(err, ports.ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrInvalidCategoryName), errors.Is(err, usecases.ErrInvalidProductStatus):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, application.ErrCategoryCycle), errors.Is(err, application.ErrCategoryNotEmpty):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, application.ErrAdminScopeRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return err
+	}
+}
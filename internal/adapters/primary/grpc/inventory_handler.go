@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// InventoryHandler implements the gRPC server interface for managing stock
+type InventoryHandler struct {
+	proto.UnimplementedInventoryServiceServer
+	service *application.InventoryService
+}
+
+// NewInventoryHandler creates a new instance of InventoryHandler
+func NewInventoryHandler(service *application.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+// GetStock retrieves the stock of a product via gRPC
+func (h *InventoryHandler) GetStock(ctx context.Context, req *proto.GetStockRequest) (*proto.GetStockResponse, error) {
+	levels, err := h.service.GetStock(ctx, req.ProductId)
+	if err != nil {
+		return nil, inventoryStatus(err)
+	}
+
+	resp := &proto.GetStockResponse{}
+	for _, level := range levels {
+		resp.OnHand += level.OnHand
+		resp.Reserved += level.Reserved
+		resp.Available += level.Available()
+		resp.Levels = append(resp.Levels, toProtoStockLevel(level))
+	}
+
+	return resp, nil
+}
+
+// AdjustStock changes the on-hand quantity of a product via gRPC
+func (h *InventoryHandler) AdjustStock(ctx context.Context, req *proto.AdjustStockRequest) (*proto.AdjustStockResponse, error) {
+	level, err := h.service.AdjustStock(ctx, req.ProductId, req.Warehouse, req.Delta, req.Reason)
+	if err != nil {
+		return nil, inventoryStatus(err)
+	}
+
+	return &proto.AdjustStockResponse{Level: toProtoStockLevel(level)}, nil
+}
+
+// SetLowStockThreshold changes the low-stock threshold of a product via gRPC
+func (h *InventoryHandler) SetLowStockThreshold(ctx context.Context, req *proto.SetLowStockThresholdRequest) (*proto.SetLowStockThresholdResponse, error) {
+	level, err := h.service.SetLowStockThreshold(ctx, req.ProductId, req.Warehouse, req.Threshold)
+	if err != nil {
+		return nil, inventoryStatus(err)
+	}
+
+	return &proto.SetLowStockThresholdResponse{Level: toProtoStockLevel(level)}, nil
+}
+
+// ReserveStock reserves stock for a checkout via gRPC
+func (h *InventoryHandler) ReserveStock(ctx context.Context, req *proto.ReserveStockRequest) (*proto.ReserveStockResponse, error) {
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	reservation, err := h.service.ReserveStock(ctx, req.ProductId, req.Warehouse, req.Quantity, ttl)
+	if err != nil {
+		return nil, inventoryStatus(err)
+	}
+
+	return &proto.ReserveStockResponse{Reservation: toProtoReservation(reservation)}, nil
+}
+
+// GetReservation retrieves a reservation by its ID via gRPC
+func (h *InventoryHandler) GetReservation(ctx context.Context, req *proto.ReservationRequest) (*proto.ReservationResponse, error) {
+	reservation, err := h.service.GetReservation(ctx, req.Id)
+	if err != nil {
+		return nil, inventoryStatus(err)
+	}
+
+	return &proto.ReservationResponse{Reservation: toProtoReservation(reservation)}, nil
+}
+
+// CommitReservation commits a pending reservation via gRPC
+func (h *InventoryHandler) CommitReservation(ctx context.Context, req *proto.ReservationRequest) (*proto.ReservationResponse, error) {
+	reservation, err := h.service.CommitReservation(ctx, req.Id)
+	if err != nil {
+		return nil, inventoryStatus(err)
+	}
+
+	return &proto.ReservationResponse{Reservation: toProtoReservation(reservation)}, nil
+}
+
+// ReleaseReservation releases a pending reservation via gRPC
+func (h *InventoryHandler) ReleaseReservation(ctx context.Context, req *proto.ReservationRequest) (*proto.ReservationResponse, error) {
+	reservation, err := h.service.ReleaseReservation(ctx, req.Id)
+	if err != nil {
+		return nil, inventoryStatus(err)
+	}
+
+	return &proto.ReservationResponse{Reservation: toProtoReservation(reservation)}, nil
+}
+
+// toProtoStockLevel converts a stock level to its protobuf representation
+func toProtoStockLevel(level *entities.StockLevel) *proto.StockLevel {
+	return &proto.StockLevel{
+		ProductId:         level.ProductID,
+		Warehouse:         level.Warehouse,
+		OnHand:            level.OnHand,
+		Reserved:          level.Reserved,
+		Available:         level.Available(),
+		LowStockThreshold: level.LowStockThreshold,
+	}
+}
+
+// toProtoReservation converts a reservation to its protobuf representation
+func toProtoReservation(reservation *entities.Reservation) *proto.Reservation {
+	return &proto.Reservation{
+		Id:        reservation.ID.Hex(),
+		ProductId: reservation.ProductID,
+		Warehouse: reservation.Warehouse,
+		Quantity:  reservation.Quantity,
+		Status:    reservation.Status,
+		ExpiresAt: timestamppb.New(reservation.ExpiresAt),
+	}
+}
+
+// inventoryStatus maps inventory service errors to gRPC status errors
+func inventoryStatus(err error) error {
+	switch {
+	case errors.Is(err, ports.ErrProductNotFound), errors.Is(err, ports.ErrReservationNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ports.ErrInsufficientStock), errors.Is(err, ports.ErrReservationClosed), errors.Is(err, ports.ErrReservationExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, application.ErrInvalidQuantity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,156 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// errMissingUploadInfo is returned when an upload does not start with its info
+	errMissingUploadInfo = errors.New("upload must start with the media info")
+	// errRepeatedUploadInfo is returned when an upload sends its info again after the content started
+	errRepeatedUploadInfo = errors.New("upload must send the media info only once, before the content")
+)
+
+// MediaHandler implements the gRPC server interface for the media attached to products
+type MediaHandler struct {
+	proto.UnimplementedMediaServiceServer
+	service *application.MediaService
+}
+
+// NewMediaHandler creates a new instance of MediaHandler
+func NewMediaHandler(service *application.MediaService) *MediaHandler {
+	return &MediaHandler{service: service}
+}
+
+// UploadMedia attaches the content streamed by the client to a product via gRPC. The first
+// message carries the info; the content follows in chunks until the client closes the stream.
+func (h *MediaHandler) UploadMedia(stream proto.MediaService_UploadMediaServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	info := first.GetInfo()
+	if info == nil {
+		return mediaStatus(errMissingUploadInfo)
+	}
+
+	media, created, err := h.service.UploadMedia(stream.Context(), info.ProductId, info.Filename, &uploadReader{stream: stream})
+	if err != nil {
+		return mediaStatus(err)
+	}
+
+	return stream.SendAndClose(&proto.UploadMediaResponse{Media: toProtoMedia(media), Created: created})
+}
+
+// ListMedia lists the media of a product via gRPC
+func (h *MediaHandler) ListMedia(ctx context.Context, req *proto.ListMediaRequest) (*proto.ListMediaResponse, error) {
+	media, err := h.service.ListMedia(ctx, req.ProductId)
+	if err != nil {
+		return nil, mediaStatus(err)
+	}
+
+	return toProtoMediaList(media), nil
+}
+
+// ReorderMedia reorders the media of a product via gRPC
+func (h *MediaHandler) ReorderMedia(ctx context.Context, req *proto.ReorderMediaRequest) (*proto.ListMediaResponse, error) {
+	media, err := h.service.ReorderMedia(ctx, req.ProductId, req.Ids)
+	if err != nil {
+		return nil, mediaStatus(err)
+	}
+
+	return toProtoMediaList(media), nil
+}
+
+// DeleteMedia deletes a media of a product via gRPC
+func (h *MediaHandler) DeleteMedia(ctx context.Context, req *proto.DeleteMediaRequest) (*proto.DeleteMediaResponse, error) {
+	if err := h.service.DeleteMedia(ctx, req.ProductId, req.Id); err != nil {
+		return nil, mediaStatus(err)
+	}
+
+	return &proto.DeleteMediaResponse{Success: true}, nil
+}
+
+// uploadReader reads the content chunks of an upload stream until the client closes it
+type uploadReader struct {
+	stream proto.MediaService_UploadMediaServer
+	chunk  []byte
+}
+
+// Read implements io.Reader, returning io.EOF once the client closed the stream
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetInfo() != nil {
+			return 0, errRepeatedUploadInfo
+		}
+		r.chunk = req.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+// toProtoMediaList converts the media of a product to their protobuf representation
+func toProtoMediaList(media []*entities.Media) *proto.ListMediaResponse {
+	resp := &proto.ListMediaResponse{}
+	for _, m := range media {
+		resp.Media = append(resp.Media, toProtoMedia(m))
+	}
+	return resp
+}
+
+// toProtoMedia converts a media to its protobuf representation
+func toProtoMedia(media *entities.Media) *proto.Media {
+	protoMedia := &proto.Media{
+		Id:          media.ID.Hex(),
+		ProductId:   media.ProductID,
+		Filename:    media.Filename,
+		ContentType: media.ContentType,
+		Size:        media.Size,
+		Sha256:      media.SHA256,
+		Position:    int32(media.Position),
+		Width:       int32(media.Width),
+		Height:      int32(media.Height),
+		CreatedAt:   timestamppb.New(media.CreatedAt),
+	}
+	for _, thumbnail := range media.Thumbnails {
+		protoMedia.Thumbnails = append(protoMedia.Thumbnails, &proto.MediaThumbnail{
+			Size:        thumbnail.Size,
+			Width:       int32(thumbnail.Width),
+			Height:      int32(thumbnail.Height),
+			ContentType: thumbnail.ContentType,
+		})
+	}
+	return protoMedia
+}
+
+// mediaStatus maps media service errors to gRPC status errors
+func mediaStatus(err error) error {
+	switch {
+	case errors.Is(err, ports.ErrMediaNotFound), errors.Is(err, ports.ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrMediaTooLarge), errors.Is(err, application.ErrMediaTooManyPixels):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, application.ErrEmptyMedia), errors.Is(err, application.ErrUnsupportedMediaType),
+		errors.Is(err, application.ErrInvalidMediaOrder), errors.Is(err, errMissingUploadInfo),
+		errors.Is(err, errRepeatedUploadInfo):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PriceScheduleHandler implements the gRPC server interface for scheduled price changes
+type PriceScheduleHandler struct {
+	proto.UnimplementedPriceScheduleServiceServer
+	service *application.PriceScheduleService
+}
+
+// NewPriceScheduleHandler creates a new instance of PriceScheduleHandler
+func NewPriceScheduleHandler(service *application.PriceScheduleService) *PriceScheduleHandler {
+	return &PriceScheduleHandler{service: service}
+}
+
+// CreatePriceSchedule schedules a price change for a product via gRPC
+func (h *PriceScheduleHandler) CreatePriceSchedule(ctx context.Context, req *proto.CreatePriceScheduleRequest) (*proto.PriceScheduleResponse, error) {
+	schedule, err := h.service.CreateSchedule(ctx, req.ProductId, req.Price, req.StartsAt.AsTime(), req.EndsAt.AsTime(), int(req.Priority))
+	if err != nil {
+		return nil, priceScheduleStatus(err)
+	}
+
+	return &proto.PriceScheduleResponse{Schedule: toProtoPriceSchedule(schedule)}, nil
+}
+
+// GetPriceSchedule retrieves a price schedule by ID via gRPC
+func (h *PriceScheduleHandler) GetPriceSchedule(ctx context.Context, req *proto.PriceScheduleRequest) (*proto.PriceScheduleResponse, error) {
+	schedule, err := h.service.GetSchedule(ctx, req.Id)
+	if err != nil {
+		return nil, priceScheduleStatus(err)
+	}
+
+	return &proto.PriceScheduleResponse{Schedule: toProtoPriceSchedule(schedule)}, nil
+}
+
+// UpdatePriceSchedule changes a price schedule via gRPC
+func (h *PriceScheduleHandler) UpdatePriceSchedule(ctx context.Context, req *proto.UpdatePriceScheduleRequest) (*proto.PriceScheduleResponse, error) {
+	schedule, err := h.service.UpdateSchedule(ctx, req.Id, req.Price, req.StartsAt.AsTime(), req.EndsAt.AsTime(), int(req.Priority))
+	if err != nil {
+		return nil, priceScheduleStatus(err)
+	}
+
+	return &proto.PriceScheduleResponse{Schedule: toProtoPriceSchedule(schedule)}, nil
+}
+
+// DeletePriceSchedule deletes a price schedule via gRPC
+func (h *PriceScheduleHandler) DeletePriceSchedule(ctx context.Context, req *proto.PriceScheduleRequest) (*proto.DeletePriceScheduleResponse, error) {
+	if err := h.service.DeleteSchedule(ctx, req.Id); err != nil {
+		return nil, priceScheduleStatus(err)
+	}
+
+	return &proto.DeletePriceScheduleResponse{Success: true}, nil
+}
+
+// ListPriceSchedules lists the price schedules of a product via gRPC
+func (h *PriceScheduleHandler) ListPriceSchedules(ctx context.Context, req *proto.ListPriceSchedulesRequest) (*proto.ListPriceSchedulesResponse, error) {
+	schedules, err := h.service.ListSchedules(ctx, req.ProductId)
+	if err != nil {
+		return nil, priceScheduleStatus(err)
+	}
+
+	resp := &proto.ListPriceSchedulesResponse{}
+	for _, schedule := range schedules {
+		resp.Schedules = append(resp.Schedules, toProtoPriceSchedule(schedule))
+	}
+
+	return resp, nil
+}
+
+// toProtoPriceSchedule converts a price schedule to its protobuf representation
+func toProtoPriceSchedule(schedule *entities.PriceSchedule) *proto.PriceSchedule {
+	return &proto.PriceSchedule{
+		Id:        schedule.ID.Hex(),
+		ProductId: schedule.ProductID,
+		Price:     schedule.Price,
+		StartsAt:  timestamppb.New(schedule.StartsAt),
+		EndsAt:    timestamppb.New(schedule.EndsAt),
+		Priority:  int32(schedule.Priority),
+	}
+}
+
+// priceScheduleStatus maps price schedule service errors to gRPC status errors
+func priceScheduleStatus(err error) error {
+	switch {
+	case errors.Is(err, ports.ErrPriceScheduleNotFound), errors.Is(err, ports.ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrInvalidPriceSchedule):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ports.ErrPriceScheduleOverlap):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
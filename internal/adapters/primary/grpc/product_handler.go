@@ -2,9 +2,19 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"test-go/internal/adapters/primary/grpc/proto"
 	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // ProductHandler implements the gRPC server interface for managing products
@@ -20,9 +30,9 @@ func NewProductHandler(service *application.ProductService) *ProductHandler {
 
 // CreateProduct handles the creation of a new product via gRPC
 func (h *ProductHandler) CreateProduct(ctx context.Context, req *proto.CreateProductRequest) (*proto.CreateProductResponse, error) {
-	id, err := h.service.CreateProduct(ctx, req.Name, req.Price)
+	id, err := h.service.CreateProduct(ctx, req.Name, req.Description, req.Price, req.TypeId, req.Attributes.AsMap())
 	if err != nil {
-		return nil, err
+		return nil, productStatus(err)
 	}
 
 	return &proto.CreateProductResponse{Id: id}, nil
@@ -30,25 +40,36 @@ func (h *ProductHandler) CreateProduct(ctx context.Context, req *proto.CreatePro
 
 // GetProductByID retrieves a product by its ID via gRPC
 func (h *ProductHandler) GetProductByID(ctx context.Context, req *proto.GetProductByIDRequest) (*proto.GetProductByIDResponse, error) {
-	product, err := h.service.GetProductByID(ctx, req.Id)
+	if req.AsOf != nil || req.Revision != 0 {
+		var (
+			product *entities.Product
+			err     error
+		)
+		if req.AsOf != nil {
+			product, err = h.service.GetProductAsOf(ctx, req.Id, req.AsOf.AsTime(), req.Status)
+		} else {
+			product, err = h.service.GetProductAtRevision(ctx, req.Id, req.Revision, req.Status)
+		}
+		if err != nil {
+			return nil, productStatus(err)
+		}
+		return &proto.GetProductByIDResponse{Product: toProtoProduct(product)}, nil
+	}
+
+	product, err := h.service.GetProductByID(ctx, req.Id, req.Status, application.PriceQuery{PriceList: req.PriceList, Currency: req.Currency})
 	if err != nil {
-		return nil, err
+		return nil, productStatus(err)
 	}
 
-	return &proto.GetProductByIDResponse{
-		Product: &proto.Product{
-			Id:    product.ID.Hex(),
-			Name:  product.Name,
-			Price: product.Price,
-		},
-	}, nil
+	return &proto.GetProductByIDResponse{Product: toProtoProduct(product)}, nil
 }
 
 // UpdateProduct handles updating an existing product via gRPC
 func (h *ProductHandler) UpdateProduct(ctx context.Context, req *proto.UpdateProductRequest) (*proto.UpdateProductResponse, error) {
-	err := h.service.UpdateProduct(ctx, req.Product.Id, req.Product.Name, req.Product.Price)
+	product := req.Product
+	err := h.service.UpdateProduct(ctx, product.Id, product.Name, product.Description, product.Price, product.TypeId, product.Attributes.AsMap())
 	if err != nil {
-		return nil, err
+		return nil, productStatus(err)
 	}
 
 	return &proto.UpdateProductResponse{Success: true}, nil
@@ -58,7 +79,7 @@ func (h *ProductHandler) UpdateProduct(ctx context.Context, req *proto.UpdatePro
 func (h *ProductHandler) DeleteProduct(ctx context.Context, req *proto.DeleteProductRequest) (*proto.DeleteProductResponse, error) {
 	err := h.service.DeleteProduct(ctx, req.Id)
 	if err != nil {
-		return nil, err
+		return nil, productStatus(err)
 	}
 
 	return &proto.DeleteProductResponse{Success: true}, nil
@@ -66,20 +87,168 @@ func (h *ProductHandler) DeleteProduct(ctx context.Context, req *proto.DeletePro
 
 // ListProducts retrieves all products via gRPC
 func (h *ProductHandler) ListProducts(ctx context.Context, req *proto.ListProductsRequest) (*proto.ListProductsResponse, error) {
-	products, err := h.service.ListProducts(ctx)
+	queries := make([]application.AttributeQuery, len(req.AttributeFilters))
+	for i, filter := range req.AttributeFilters {
+		queries[i] = application.AttributeQuery{Name: filter.Name, Op: filter.Op, Value: filter.Value}
+	}
+
+	products, err := h.service.ListProducts(ctx, req.TypeId, queries, req.Search, req.Sort, req.Status, application.PriceQuery{PriceList: req.PriceList, Currency: req.Currency})
 	if err != nil {
-		return nil, err
+		return nil, productStatus(err)
 	}
 
 	// Convert the list of products to the protobuf format
 	var protoProducts []*proto.Product
 	for _, product := range products {
-		protoProducts = append(protoProducts, &proto.Product{
-			Id:    product.ID.Hex(),
-			Name:  product.Name,
-			Price: product.Price,
-		})
+		protoProducts = append(protoProducts, toProtoProduct(product))
 	}
 
 	return &proto.ListProductsResponse{Products: protoProducts}, nil
 }
+
+// TransitionProduct moves a product to another lifecycle status via gRPC
+func (h *ProductHandler) TransitionProduct(ctx context.Context, req *proto.TransitionProductRequest) (*proto.TransitionProductResponse, error) {
+	product, err := h.service.TransitionProduct(ctx, req.Id, req.Status)
+	if err != nil {
+		return nil, productStatus(err)
+	}
+
+	return &proto.TransitionProductResponse{Product: toProtoProduct(product)}, nil
+}
+
+// ScheduleProduct sets the activation and deactivation dates of a product via gRPC
+func (h *ProductHandler) ScheduleProduct(ctx context.Context, req *proto.ScheduleProductRequest) (*proto.ScheduleProductResponse, error) {
+	var activateAt, deactivateAt *time.Time
+	if req.ActivateAt != nil {
+		at := req.ActivateAt.AsTime()
+		activateAt = &at
+	}
+	if req.DeactivateAt != nil {
+		at := req.DeactivateAt.AsTime()
+		deactivateAt = &at
+	}
+
+	if err := h.service.ScheduleProduct(ctx, req.Id, activateAt, deactivateAt); err != nil {
+		return nil, productStatus(err)
+	}
+
+	return &proto.ScheduleProductResponse{Success: true}, nil
+}
+
+// StreamProducts sends every product to the client, one message per product.
+// stream.Send blocks while the client's flow-control window is full, which in turn
+// holds back the next database batch.
+func (h *ProductHandler) StreamProducts(req *proto.StreamProductsRequest, stream proto.ProductService_StreamProductsServer) error {
+	return h.service.StreamProducts(stream.Context(), req.BatchSize, req.Status, func(product *entities.Product) error {
+		return stream.Send(toProtoProduct(product))
+	})
+}
+
+// WatchProducts streams product change notifications until the client disconnects
+func (h *ProductHandler) WatchProducts(req *proto.WatchProductsRequest, stream proto.ProductService_WatchProductsServer) error {
+	err := h.service.WatchProducts(stream.Context(), req.ResumeToken, func(event *entities.ProductEvent) error {
+		protoEvent := &proto.ProductEvent{
+			Type:        event.Type,
+			ProductId:   event.ProductID,
+			ResumeToken: event.ResumeToken,
+			OccurredAt:  timestamppb.New(event.OccurredAt),
+		}
+		if event.Product != nil {
+			protoEvent.Product = toProtoProduct(event.Product)
+		}
+		return stream.Send(protoEvent)
+	})
+
+	switch {
+	case errors.Is(err, ports.ErrInvalidResumeToken):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, context.Canceled):
+		return nil
+	}
+	return err
+}
+
+// toProtoProduct converts a product entity to its protobuf representation
+func toProtoProduct(product *entities.Product) *proto.Product {
+	resp := &proto.Product{
+		Id:             product.ID.Hex(),
+		Name:           product.Name,
+		Description:    product.Description,
+		Price:          product.Price,
+		CategoryIds:    product.CategoryIDs,
+		EffectivePrice: product.EffectivePrice,
+		Locale:         product.Locale,
+		TaxClass:       product.TaxClass,
+		Status:         product.Status,
+	}
+	if product.ActivateAt != nil {
+		resp.ActivateAt = timestamppb.New(*product.ActivateAt)
+	}
+	if product.DeactivateAt != nil {
+		resp.DeactivateAt = timestamppb.New(*product.DeactivateAt)
+	}
+	for locale, text := range product.Translations {
+		if resp.Translations == nil {
+			resp.Translations = make(map[string]*proto.ProductTranslation, len(product.Translations))
+		}
+		resp.Translations[locale] = &proto.ProductTranslation{Name: text.Name, Description: text.Description}
+	}
+	for _, option := range product.Options {
+		resp.Options = append(resp.Options, &proto.ProductOption{Name: option.Name, Values: option.Values})
+	}
+	if len(product.Attributes) > 0 {
+		// Attribute values are normalized to strings, numbers and booleans, which a Struct always holds
+		resp.Attributes, _ = structpb.NewStruct(product.Attributes)
+	}
+	if bundle := product.Bundle; bundle != nil {
+		resp.Bundle = &proto.ProductBundle{Pricing: bundle.Pricing, DiscountPercent: bundle.DiscountPercent}
+		for _, component := range bundle.Components {
+			resp.Bundle.Components = append(resp.Bundle.Components, &proto.BundleComponent{ProductId: component.ProductID, Quantity: int32(component.Quantity)})
+		}
+	}
+	if pricing := product.Pricing; pricing != nil {
+		resp.Pricing = &proto.ProductPricing{
+			Price:        pricing.Price,
+			Currency:     pricing.Currency,
+			Source:       pricing.Source,
+			PriceList:    pricing.PriceList,
+			BasePrice:    pricing.BasePrice,
+			BaseCurrency: pricing.BaseCurrency,
+			ExchangeRate: pricing.ExchangeRate,
+		}
+		if pricing.RateEffectiveAt != nil {
+			resp.Pricing.RateEffectiveAt = timestamppb.New(*pricing.RateEffectiveAt)
+		}
+	}
+	return resp
+}
+
+// productStatus maps product service errors to gRPC status errors
+func productStatus(err error) error {
+	var (
+		validationErr *usecases.ValidationError
+		attributeErr  *usecases.AttributeError
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		return validationStatus(validationErr)
+	case errors.As(err, &attributeErr), errors.Is(err, usecases.ErrInvalidLocale), errors.Is(err, usecases.ErrInvalidSort),
+		errors.Is(err, usecases.ErrInvalidCurrency), errors.Is(err, application.ErrPriceListCurrencyMismatch),
+		errors.Is(err, usecases.ErrInvalidProductStatus), errors.Is(err, usecases.ErrInvalidActivationSchedule):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ports.ErrProductNotFound), errors.Is(err, ports.ErrProductTypeNotFound),
+		errors.Is(err, ports.ErrRevisionNotFound), errors.Is(err, ports.ErrPriceListNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrRevisionNotRestorable), errors.Is(err, ports.ErrExchangeRateNotFound),
+		errors.Is(err, usecases.ErrProductInBundle), errors.Is(err, usecases.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, usecases.ErrPrincipalRequired):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, application.ErrAdminScopeRequired), errors.Is(err, usecases.ErrApprovalRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, application.ErrProductQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProductRevisionHandler implements the gRPC server interface for the change history of products
+type ProductRevisionHandler struct {
+	proto.UnimplementedProductRevisionServiceServer
+	service *application.ProductService
+}
+
+// NewProductRevisionHandler creates a new instance of ProductRevisionHandler
+func NewProductRevisionHandler(service *application.ProductService) *ProductRevisionHandler {
+	return &ProductRevisionHandler{service: service}
+}
+
+// ListProductRevisions retrieves every revision of a product via gRPC
+func (h *ProductRevisionHandler) ListProductRevisions(ctx context.Context, req *proto.ListProductRevisionsRequest) (*proto.ListProductRevisionsResponse, error) {
+	revisions, err := h.service.ListRevisions(ctx, req.ProductId)
+	if err != nil {
+		return nil, productStatus(err)
+	}
+
+	resp := &proto.ListProductRevisionsResponse{}
+	for _, revision := range revisions {
+		resp.Revisions = append(resp.Revisions, toProtoRevision(revision))
+	}
+	return resp, nil
+}
+
+// GetProductRevision retrieves one revision of a product via gRPC
+func (h *ProductRevisionHandler) GetProductRevision(ctx context.Context, req *proto.GetProductRevisionRequest) (*proto.ProductRevision, error) {
+	revision, err := h.service.GetRevision(ctx, req.ProductId, req.Revision)
+	if err != nil {
+		return nil, productStatus(err)
+	}
+
+	return toProtoRevision(revision), nil
+}
+
+// DiffProductRevisions compares two revisions of a product via gRPC
+func (h *ProductRevisionHandler) DiffProductRevisions(ctx context.Context, req *proto.DiffProductRevisionsRequest) (*proto.DiffProductRevisionsResponse, error) {
+	changes, err := h.service.DiffRevisions(ctx, req.ProductId, req.From, req.To)
+	if err != nil {
+		return nil, productStatus(err)
+	}
+
+	return &proto.DiffProductRevisionsResponse{Changes: toProtoChanges(changes)}, nil
+}
+
+// RollbackProduct restores a product from one of its revisions via gRPC
+func (h *ProductRevisionHandler) RollbackProduct(ctx context.Context, req *proto.RollbackProductRequest) (*proto.Product, error) {
+	product, err := h.service.RollbackProduct(ctx, req.ProductId, req.Revision)
+	if err != nil {
+		return nil, productStatus(err)
+	}
+
+	return toProtoProduct(product), nil
+}
+
+// toProtoRevision converts a product revision to its protobuf representation
+func toProtoRevision(revision *entities.ProductRevision) *proto.ProductRevision {
+	resp := &proto.ProductRevision{
+		Id:               revision.ID.Hex(),
+		ProductId:        revision.ProductID,
+		Revision:         revision.Revision,
+		Operation:        revision.Operation,
+		Changes:          toProtoChanges(revision.Changes),
+		RestoredRevision: revision.RestoredRevision,
+		Actor:            revision.Actor,
+		RequestId:        revision.RequestID,
+		CreatedAt:        timestamppb.New(revision.CreatedAt),
+	}
+	if revision.Snapshot != nil {
+		resp.Snapshot = toProtoProduct(revision.Snapshot)
+	}
+	return resp
+}
+
+// toProtoChanges converts field changes to their protobuf representation
+func toProtoChanges(changes []entities.FieldChange) []*proto.FieldChange {
+	var resp []*proto.FieldChange
+	for _, change := range changes {
+		resp = append(resp, &proto.FieldChange{
+			Field: change.Field,
+			From:  toProtoValue(change.From),
+			To:    toProtoValue(change.To),
+		})
+	}
+	return resp
+}
+
+// toProtoValue converts a field value through JSON, which also covers the slice types decoded from BSON.
+// It returns nil for unset values.
+func toProtoValue(value interface{}) *structpb.Value {
+	if value == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	resp := &structpb.Value{}
+	if err := protojson.Unmarshal(data, resp); err != nil {
+		return nil
+	}
+	return resp
+}
@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProductTypeHandler implements the gRPC server interface for managing product types
+type ProductTypeHandler struct {
+	proto.UnimplementedProductTypeServiceServer
+	service *application.ProductTypeService
+}
+
+// NewProductTypeHandler creates a new instance of ProductTypeHandler
+func NewProductTypeHandler(service *application.ProductTypeService) *ProductTypeHandler {
+	return &ProductTypeHandler{service: service}
+}
+
+// CreateProductType handles the creation of a new product type via gRPC
+func (h *ProductTypeHandler) CreateProductType(ctx context.Context, req *proto.CreateProductTypeRequest) (*proto.ProductTypeResponse, error) {
+	productType, err := h.service.CreateProductType(ctx, req.Name, fromProtoAttributeDefinitions(req.Attributes))
+	if err != nil {
+		return nil, productTypeStatus(err)
+	}
+
+	return &proto.ProductTypeResponse{ProductType: toProtoProductType(productType)}, nil
+}
+
+// GetProductType retrieves a product type by its ID via gRPC
+func (h *ProductTypeHandler) GetProductType(ctx context.Context, req *proto.ProductTypeIDRequest) (*proto.ProductTypeResponse, error) {
+	productType, err := h.service.GetProductType(ctx, req.Id)
+	if err != nil {
+		return nil, productTypeStatus(err)
+	}
+
+	return &proto.ProductTypeResponse{ProductType: toProtoProductType(productType)}, nil
+}
+
+// UpdateProductType replaces a product type via gRPC
+func (h *ProductTypeHandler) UpdateProductType(ctx context.Context, req *proto.UpdateProductTypeRequest) (*proto.ProductTypeResponse, error) {
+	productType, err := h.service.UpdateProductType(ctx, req.Id, req.Name, fromProtoAttributeDefinitions(req.Attributes))
+	if err != nil {
+		return nil, productTypeStatus(err)
+	}
+
+	return &proto.ProductTypeResponse{ProductType: toProtoProductType(productType)}, nil
+}
+
+// DeleteProductType deletes an unused product type via gRPC
+func (h *ProductTypeHandler) DeleteProductType(ctx context.Context, req *proto.ProductTypeIDRequest) (*proto.DeleteProductTypeResponse, error) {
+	if err := h.service.DeleteProductType(ctx, req.Id); err != nil {
+		return nil, productTypeStatus(err)
+	}
+
+	return &proto.DeleteProductTypeResponse{Success: true}, nil
+}
+
+// ListProductTypes retrieves every product type via gRPC
+func (h *ProductTypeHandler) ListProductTypes(ctx context.Context, req *proto.ListProductTypesRequest) (*proto.ListProductTypesResponse, error) {
+	productTypes, err := h.service.ListProductTypes(ctx)
+	if err != nil {
+		return nil, productTypeStatus(err)
+	}
+
+	resp := &proto.ListProductTypesResponse{}
+	for _, productType := range productTypes {
+		resp.ProductTypes = append(resp.ProductTypes, toProtoProductType(productType))
+	}
+	return resp, nil
+}
+
+// toProtoProductType converts a product type to its protobuf representation
+func toProtoProductType(productType *entities.ProductType) *proto.ProductType {
+	resp := &proto.ProductType{
+		Id:   productType.ID.Hex(),
+		Name: productType.Name,
+	}
+	for _, definition := range productType.Attributes {
+		resp.Attributes = append(resp.Attributes, &proto.AttributeDefinition{
+			Name:     definition.Name,
+			Type:     definition.Type,
+			Required: definition.Required,
+			Min:      definition.Min,
+			Max:      definition.Max,
+			Pattern:  definition.Pattern,
+			Values:   definition.Values,
+		})
+	}
+	return resp
+}
+
+// fromProtoAttributeDefinitions converts protobuf attribute definitions to entities
+func fromProtoAttributeDefinitions(definitions []*proto.AttributeDefinition) []entities.AttributeDefinition {
+	result := make([]entities.AttributeDefinition, len(definitions))
+	for i, definition := range definitions {
+		result[i] = entities.AttributeDefinition{
+			Name:     definition.Name,
+			Type:     definition.Type,
+			Required: definition.Required,
+			Min:      definition.Min,
+			Max:      definition.Max,
+			Pattern:  definition.Pattern,
+			Values:   definition.Values,
+		}
+	}
+	return result
+}
+
+// productTypeStatus maps product type service errors to gRPC status errors
+func productTypeStatus(err error) error {
+	var attributeErr *usecases.AttributeError
+	switch {
+	case errors.As(err, &attributeErr), errors.Is(err, application.ErrInvalidProductTypeName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ports.ErrProductTypeNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrProductTypeInUse):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
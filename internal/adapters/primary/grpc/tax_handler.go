@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TaxHandler implements the gRPC server interface for tax rates and price quotes
+type TaxHandler struct {
+	proto.UnimplementedTaxServiceServer
+	service  *application.TaxService
+	products *application.ProductService
+}
+
+// NewTaxHandler creates a new instance of TaxHandler
+func NewTaxHandler(service *application.TaxService, products *application.ProductService) *TaxHandler {
+	return &TaxHandler{
+		service:  service,
+		products: products,
+	}
+}
+
+// QuotePrice quotes the price of a product with tax via gRPC
+func (h *TaxHandler) QuotePrice(ctx context.Context, req *proto.QuotePriceRequest) (*proto.QuotePriceResponse, error) {
+	quantity := int(req.Quantity)
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	query := application.PriceQuery{PriceList: req.PriceList, Currency: req.Currency}
+	quote, err := h.service.QuotePrice(ctx, req.ProductId, req.Region, quantity, query)
+	if errors.Is(err, ports.ErrTaxRateNotFound) {
+		// The region has no rate for the tax class of the product until one is created
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, taxStatus(err)
+	}
+
+	return &proto.QuotePriceResponse{Quote: toProtoPriceQuote(quote)}, nil
+}
+
+// ListTaxRates lists the tax rates of a jurisdiction via gRPC
+func (h *TaxHandler) ListTaxRates(ctx context.Context, req *proto.ListTaxRatesRequest) (*proto.ListTaxRatesResponse, error) {
+	rates, err := h.service.ListRates(ctx, req.Jurisdiction)
+	if err != nil {
+		return nil, taxStatus(err)
+	}
+
+	resp := &proto.ListTaxRatesResponse{}
+	for _, rate := range rates {
+		resp.Rates = append(resp.Rates, toProtoTaxRate(rate))
+	}
+
+	return resp, nil
+}
+
+// SetProductTaxClass assigns a tax class to a product via gRPC
+func (h *TaxHandler) SetProductTaxClass(ctx context.Context, req *proto.SetProductTaxClassRequest) (*proto.SetProductTaxClassResponse, error) {
+	if err := h.products.SetProductTaxClass(ctx, req.ProductId, req.TaxClass); err != nil {
+		return nil, taxStatus(err)
+	}
+
+	return &proto.SetProductTaxClassResponse{Success: true}, nil
+}
+
+// toProtoTaxRate converts a tax rate to its protobuf representation
+func toProtoTaxRate(rate *entities.TaxRate) *proto.TaxRate {
+	return &proto.TaxRate{
+		Id:            rate.ID.Hex(),
+		Jurisdiction:  rate.Jurisdiction,
+		TaxClass:      rate.TaxClass,
+		Name:          rate.Name,
+		Percent:       rate.Percent,
+		EffectiveFrom: timestamppb.New(rate.EffectiveFrom),
+	}
+}
+
+// toProtoPriceQuote converts a price quote to its protobuf representation
+func toProtoPriceQuote(quote *entities.PriceQuote) *proto.PriceQuote {
+	return &proto.PriceQuote{
+		ProductId:         quote.ProductID,
+		Region:            quote.Region,
+		Jurisdiction:      quote.Jurisdiction,
+		TaxClass:          quote.TaxClass,
+		TaxName:           quote.TaxName,
+		TaxPercent:        quote.TaxPercent,
+		RateEffectiveFrom: timestamppb.New(quote.RateEffectiveFrom),
+		Currency:          quote.Currency,
+		Quantity:          int32(quote.Quantity),
+		UnitPrice:         quote.UnitPrice,
+		PricesIncludeTax:  quote.PricesIncludeTax,
+		Rounding:          quote.Rounding,
+		Net:               quote.Net,
+		Tax:               quote.Tax,
+		Gross:             quote.Gross,
+	}
+}
+
+// taxStatus maps tax service errors to gRPC status errors, and product errors as productStatus does
+func taxStatus(err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidRegion), errors.Is(err, usecases.ErrInvalidTaxClass),
+		errors.Is(err, application.ErrInvalidQuantity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ports.ErrTaxRateNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return productStatus(err)
+	}
+}
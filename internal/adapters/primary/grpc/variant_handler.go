@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// VariantHandler implements the gRPC server interface for managing product variants
+type VariantHandler struct {
+	proto.UnimplementedVariantServiceServer
+	service *application.VariantService
+}
+
+// NewVariantHandler creates a new instance of VariantHandler
+func NewVariantHandler(service *application.VariantService) *VariantHandler {
+	return &VariantHandler{service: service}
+}
+
+// GenerateVariants generates the variant matrix of a product via gRPC
+func (h *VariantHandler) GenerateVariants(ctx context.Context, req *proto.GenerateVariantsRequest) (*proto.VariantsResponse, error) {
+	options := make([]entities.ProductOption, len(req.Options))
+	for i, option := range req.Options {
+		options[i] = entities.ProductOption{Name: option.Name, Values: option.Values}
+	}
+
+	variants, err := h.service.GenerateVariants(ctx, req.ProductId, options, req.SkuPrefix)
+	if err != nil {
+		return nil, variantStatus(err)
+	}
+
+	return toProtoVariants(variants), nil
+}
+
+// UpdateVariants updates several variants of a product via gRPC
+func (h *VariantHandler) UpdateVariants(ctx context.Context, req *proto.UpdateVariantsRequest) (*proto.VariantsResponse, error) {
+	updates := make([]application.VariantUpdate, len(req.Variants))
+	for i, variant := range req.Variants {
+		updates[i] = application.VariantUpdate{
+			ID:      variant.Id,
+			SKU:     variant.Sku,
+			Price:   variant.Price,
+			Barcode: variant.Barcode,
+		}
+		if len(variant.Attributes) > 0 {
+			updates[i].Attributes = variant.Attributes
+		}
+	}
+
+	variants, err := h.service.UpdateVariants(ctx, req.ProductId, updates)
+	if err != nil {
+		return nil, variantStatus(err)
+	}
+
+	return toProtoVariants(variants), nil
+}
+
+// GetProductWithVariants retrieves a product with its variants via gRPC
+func (h *VariantHandler) GetProductWithVariants(ctx context.Context, req *proto.GetProductWithVariantsRequest) (*proto.ProductWithVariantsResponse, error) {
+	product, variants, err := h.service.GetProductWithVariants(ctx, req.ProductId, req.Status)
+	if err != nil {
+		return nil, variantStatus(err)
+	}
+
+	return &proto.ProductWithVariantsResponse{
+		Product:  toProtoProduct(product),
+		Variants: toProtoVariants(variants).Variants,
+	}, nil
+}
+
+// toProtoVariant converts a variant to its protobuf representation
+func toProtoVariant(variant *entities.ProductVariant) *proto.ProductVariant {
+	return &proto.ProductVariant{
+		Id:         variant.ID.Hex(),
+		ProductId:  variant.ProductID,
+		Sku:        variant.SKU,
+		Options:    variant.Options,
+		Price:      variant.Price,
+		Barcode:    variant.Barcode,
+		Attributes: variant.Attributes,
+		Position:   int32(variant.Position),
+	}
+}
+
+// toProtoVariants converts a list of variants to a protobuf response
+func toProtoVariants(variants []*entities.ProductVariant) *proto.VariantsResponse {
+	resp := &proto.VariantsResponse{}
+	for _, variant := range variants {
+		resp.Variants = append(resp.Variants, toProtoVariant(variant))
+	}
+	return resp
+}
+
+// variantStatus maps variant service errors to gRPC status errors
+func variantStatus(err error) error {
+	switch {
+	case errors.Is(err, ports.ErrProductNotFound), errors.Is(err, ports.ErrVariantNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrInvalidOptions), errors.Is(err, application.ErrTooManyVariants),
+		errors.Is(err, application.ErrInvalidSKU), errors.Is(err, application.ErrInvalidPrice),
+		errors.Is(err, usecases.ErrInvalidProductStatus):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ports.ErrDuplicateSKU):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, application.ErrAdminScopeRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/adapters/primary/grpc/proto"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WebhookHandler implements the gRPC server interface for managing webhook subscriptions
+type WebhookHandler struct {
+	proto.UnimplementedWebhookServiceServer
+	service *application.WebhookService
+}
+
+// NewWebhookHandler creates a new instance of WebhookHandler
+func NewWebhookHandler(service *application.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateWebhook handles the creation of a new webhook subscription via gRPC
+func (h *WebhookHandler) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.CreateWebhookResponse, error) {
+	webhook, err := h.service.CreateWebhook(ctx, req.Url, req.Secret, req.EventTypes)
+	if err != nil {
+		return nil, webhookStatus(err)
+	}
+
+	protoWebhook := toProtoWebhook(webhook)
+	protoWebhook.Secret = webhook.Secret
+
+	return &proto.CreateWebhookResponse{Webhook: protoWebhook}, nil
+}
+
+// GetWebhook retrieves a webhook subscription by its ID via gRPC
+func (h *WebhookHandler) GetWebhook(ctx context.Context, req *proto.GetWebhookRequest) (*proto.GetWebhookResponse, error) {
+	webhook, err := h.service.GetWebhook(ctx, req.Id)
+	if err != nil {
+		return nil, webhookStatus(err)
+	}
+
+	return &proto.GetWebhookResponse{Webhook: toProtoWebhook(webhook)}, nil
+}
+
+// UpdateWebhook handles updating an existing webhook subscription via gRPC
+func (h *WebhookHandler) UpdateWebhook(ctx context.Context, req *proto.UpdateWebhookRequest) (*proto.UpdateWebhookResponse, error) {
+	if err := h.service.UpdateWebhook(ctx, req.Id, req.Url, req.EventTypes, req.Active); err != nil {
+		return nil, webhookStatus(err)
+	}
+
+	return &proto.UpdateWebhookResponse{Success: true}, nil
+}
+
+// DeleteWebhook handles deleting a webhook subscription by its ID via gRPC
+func (h *WebhookHandler) DeleteWebhook(ctx context.Context, req *proto.DeleteWebhookRequest) (*proto.DeleteWebhookResponse, error) {
+	if err := h.service.DeleteWebhook(ctx, req.Id); err != nil {
+		return nil, webhookStatus(err)
+	}
+
+	return &proto.DeleteWebhookResponse{Success: true}, nil
+}
+
+// ListWebhooks retrieves all webhook subscriptions via gRPC
+func (h *WebhookHandler) ListWebhooks(ctx context.Context, req *proto.ListWebhooksRequest) (*proto.ListWebhooksResponse, error) {
+	webhooks, err := h.service.ListWebhooks(ctx)
+	if err != nil {
+		return nil, webhookStatus(err)
+	}
+
+	var protoWebhooks []*proto.Webhook
+	for _, webhook := range webhooks {
+		protoWebhooks = append(protoWebhooks, toProtoWebhook(webhook))
+	}
+
+	return &proto.ListWebhooksResponse{Webhooks: protoWebhooks}, nil
+}
+
+// ListWebhookDeliveries retrieves the delivery log of a webhook subscription via gRPC
+func (h *WebhookHandler) ListWebhookDeliveries(ctx context.Context, req *proto.ListWebhookDeliveriesRequest) (*proto.ListWebhookDeliveriesResponse, error) {
+	deliveries, err := h.service.ListDeliveries(ctx, req.Id, req.Limit)
+	if err != nil {
+		return nil, webhookStatus(err)
+	}
+
+	var protoDeliveries []*proto.WebhookDelivery
+	for _, delivery := range deliveries {
+		protoDeliveries = append(protoDeliveries, &proto.WebhookDelivery{
+			Id:             delivery.ID.Hex(),
+			EventId:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Attempt:        int32(delivery.Attempt),
+			RequestHeaders: delivery.RequestHeaders,
+			RequestBody:    delivery.RequestBody,
+			ResponseStatus: int32(delivery.ResponseStatus),
+			ResponseBody:   delivery.ResponseBody,
+			Error:          delivery.Error,
+			Success:        delivery.Success,
+			LatencyMs:      delivery.LatencyMs,
+			CreatedAt:      timestamppb.New(delivery.CreatedAt),
+		})
+	}
+
+	return &proto.ListWebhookDeliveriesResponse{Deliveries: protoDeliveries}, nil
+}
+
+// toProtoWebhook converts a webhook subscription to its protobuf representation, without the secret
+func toProtoWebhook(webhook *entities.WebhookSubscription) *proto.Webhook {
+	protoWebhook := &proto.Webhook{
+		Id:           webhook.ID.Hex(),
+		Url:          webhook.URL,
+		EventTypes:   webhook.EventTypes,
+		Active:       webhook.Active,
+		FailureCount: int32(webhook.FailureCount),
+	}
+	if webhook.DisabledAt != nil {
+		protoWebhook.DisabledAt = timestamppb.New(*webhook.DisabledAt)
+	}
+	return protoWebhook
+}
+
+// webhookStatus maps webhook service errors to gRPC status errors
+func webhookStatus(err error) error {
+	switch {
+	case errors.Is(err, ports.ErrWebhookNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrInvalidWebhookURL), errors.Is(err, application.ErrInvalidEventType):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"errors"
+	"time"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHandler handles HTTP requests for API key administration
+type APIKeyHandler struct {
+	service *application.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new instance of APIKeyHandler
+func NewAPIKeyHandler(service *application.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// createAPIKeyRequest is the body accepted when creating an API key
+type createAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// rotateAPIKeyRequest is the body accepted when rotating an API key
+type rotateAPIKeyRequest struct {
+	// GracePeriodSeconds keeps the old key working while clients switch over
+	GracePeriodSeconds int64 `json:"grace_period_seconds"`
+}
+
+// issuedAPIKeyResponse is returned when a key is created or rotated. Key is the only copy of the
+// key itself.
+type issuedAPIKeyResponse struct {
+	APIKey *entities.APIKey `json:"api_key"`
+	Key    string           `json:"key"`
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Issue an API key to a machine client of the tenant. The key is only returned by this call.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param api_key body createAPIKeyRequest true "API key details"
+// @Success 201 {object} issuedAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req createAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	key, plaintext, err := h.service.CreateAPIKey(c.Context(), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(issuedAPIKeyResponse{APIKey: key, Key: plaintext})
+}
+
+// GetAPIKey godoc
+// @Summary Get an API key by ID
+// @Description Retrieve an API key by its ID, without the key itself
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} entities.APIKey
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id} [get]
+func (h *APIKeyHandler) GetAPIKey(c *fiber.Ctx) error {
+	key, err := h.service.GetAPIKey(c.Context(), c.Params("id"))
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(key)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Retrieve every API key of the tenant, including revoked ones
+// @Tags api-keys
+// @Produce json
+// @Success 200 {array} entities.APIKey
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.service.ListAPIKeys(c.Context())
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(keys)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Issue a replacement with the same name, scopes and expiry. The old key keeps working for the grace period and is revoked right away without one.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID"
+// @Param rotation body rotateAPIKeyRequest false "Grace period of the old key"
+// @Success 201 {object} issuedAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *fiber.Ctx) error {
+	var req rotateAPIKeyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+	}
+
+	gracePeriod := time.Duration(req.GracePeriodSeconds) * time.Second
+	key, plaintext, err := h.service.RotateAPIKey(c.Context(), c.Params("id"), gracePeriod)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(issuedAPIKeyResponse{APIKey: key, Key: plaintext})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Stop an API key from authenticating requests. Every replica stops accepting it within seconds.
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	if err := h.service.RevokeAPIKey(c.Context(), c.Params("id")); err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// apiKeyError maps API key errors to HTTP responses
+func apiKeyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "API key not found"})
+	case errors.Is(err, application.ErrAPIKeyAdminRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrAPIKeyRevoked):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidAPIKeyName), errors.Is(err, application.ErrInvalidScope),
+		errors.Is(err, application.ErrInvalidAPIKeyExpiry), errors.Is(err, application.ErrInvalidGracePeriod):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
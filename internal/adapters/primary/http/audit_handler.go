@@ -0,0 +1,164 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// mimeNDJSON is the content type of the audit export, one JSON entry per line
+const mimeNDJSON = "application/x-ndjson"
+
+// AuditHandler handles HTTP requests for the audit log
+type AuditHandler struct {
+	service *application.AuditService
+}
+
+// NewAuditHandler creates a new instance of AuditHandler
+func NewAuditHandler(service *application.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListEntries godoc
+// @Summary List audit entries
+// @Description Retrieve the tenant's audit entries matching the filters in sequence order. Page with after set to the sequence of the last entry.
+// @Tags audit
+// @Produce json
+// @Param principal query string false "Authenticated principal, e.g. api_key:<id> or sub:<subject>"
+// @Param actor query string false "Actor named by the caller"
+// @Param operation query string false "Operation: create, update, delete or rollback"
+// @Param resource_type query string false "Resource type, e.g. product"
+// @Param resource_id query string false "Resource ID"
+// @Param transport query string false "Transport: http, grpc, import or internal"
+// @Param from query string false "RFC 3339 time of the oldest entry"
+// @Param to query string false "RFC 3339 time the entries are older than"
+// @Param after query int false "Sequence the entries come after"
+// @Param limit query int false "Maximum number of entries" default(100)
+// @Success 200 {array} entities.AuditEntry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/audit [get]
+func (h *AuditHandler) ListEntries(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	entries, err := h.service.ListEntries(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+// ExportEntries godoc
+// @Summary Export audit entries
+// @Description Stream every audit entry of the tenant matching the filters as newline-delimited JSON, in sequence order
+// @Tags audit
+// @Produce application/x-ndjson
+// @Param principal query string false "Authenticated principal, e.g. api_key:<id> or sub:<subject>"
+// @Param actor query string false "Actor named by the caller"
+// @Param operation query string false "Operation: create, update, delete or rollback"
+// @Param resource_type query string false "Resource type, e.g. product"
+// @Param resource_id query string false "Resource ID"
+// @Param transport query string false "Transport: http, grpc, import or internal"
+// @Param from query string false "RFC 3339 time of the oldest entry"
+// @Param to query string false "RFC 3339 time the entries are older than"
+// @Param after query int false "Sequence the entries come after"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/audit/export [get]
+func (h *AuditHandler) ExportEntries(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	filename := fmt.Sprintf("audit-%s.ndjson", time.Now().UTC().Format("20060102T150405Z"))
+	c.Set(fiber.HeaderContentType, mimeNDJSON)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	// The entries are written after the handler returns, outside the request context
+	ctx := ports.WithTenant(context.Background(), ports.TenantFromContext(c.Context()))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		encoder := json.NewEncoder(w)
+		var exported int
+		err := h.service.ExportEntries(ctx, filter, func(entry *entities.AuditEntry) error {
+			exported++
+			return encoder.Encode(entry)
+		})
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("Audit export failed after %d entries: %v", exported, err)
+		}
+	})
+	return nil
+}
+
+// VerifyChain godoc
+// @Summary Verify the audit log
+// @Description Check the hash chain of the tenant's audit log and report the first entry that was altered, removed or reordered
+// @Tags audit
+// @Produce json
+// @Success 200 {object} application.AuditVerification
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/audit/verify [get]
+func (h *AuditHandler) VerifyChain(c *fiber.Ctx) error {
+	verification, err := h.service.VerifyChain(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(verification)
+}
+
+// auditFilter reads the audit filter from the query string
+func auditFilter(c *fiber.Ctx) (ports.AuditFilter, error) {
+	// Fiber reuses query values once the handler returns, which the export outlives
+	query := func(key string) string { return strings.Clone(c.Query(key)) }
+	filter := ports.AuditFilter{
+		Principal:    query("principal"),
+		Actor:        query("actor"),
+		Operation:    query("operation"),
+		ResourceType: query("resource_type"),
+		ResourceID:   query("resource_id"),
+		Transport:    query("transport"),
+	}
+
+	for param, bound := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.Query(param); value != "" {
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, errors.New(param + " must be an RFC 3339 time")
+			}
+			*bound = at
+		}
+	}
+
+	for param, number := range map[string]*int64{"after": &filter.AfterSequence, "limit": &filter.Limit} {
+		if value := c.Query(param); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed < 0 {
+				return filter, errors.New(param + " must be a non-negative integer")
+			}
+			*number = parsed
+		}
+	}
+
+	return filter, nil
+}
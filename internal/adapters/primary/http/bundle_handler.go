@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BundleHandler handles HTTP requests for bundles and their availability
+type BundleHandler struct {
+	service *application.BundleService
+}
+
+// NewBundleHandler creates a new instance of BundleHandler
+func NewBundleHandler(service *application.BundleService) *BundleHandler {
+	return &BundleHandler{service: service}
+}
+
+// GetBundle godoc
+// @Summary Get a bundle with its availability
+// @Description Retrieve the components of a bundle with their names, prices and stock, and how many units of the bundle the scarcest component is enough for. Nested bundles count with their own availability. Components in another status than the bundle asked for are listed without their name and price and count as unavailable.
+// @Tags bundles
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param status query string false "Lifecycle status the bundle must be in, active by default; another status or any needs the admin scope"
+// @Success 200 {object} entities.BundleView
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/bundle [get]
+func (h *BundleHandler) GetBundle(c *fiber.Ctx) error {
+	view, err := h.service.GetBundle(c.Context(), c.Params("id"), c.Query("status"))
+	if err != nil {
+		return bundleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(view)
+}
+
+// SetBundle godoc
+// @Summary Make a product a bundle
+// @Description Set the components and quantities of a bundle and how it is priced: sum of the components, sum less discount_percent, or fixed at the price of the product. Derived prices replace the price of the product and are refreshed when a component changes. Components must be existing products, listed once, and must not contain the bundle.
+// @Tags bundles
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param bundle body entities.Bundle true "Components and pricing"
+// @Success 204
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/bundle [put]
+func (h *BundleHandler) SetBundle(c *fiber.Ctx) error {
+	var bundle entities.Bundle
+	if err := c.BodyParser(&bundle); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if err := h.service.SetBundle(c.Context(), c.Params("id"), &bundle); err != nil {
+		return bundleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RemoveBundle godoc
+// @Summary Turn a bundle into a plain product
+// @Description Remove the components of a bundle. The product keeps its last price.
+// @Tags bundles
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/bundle [delete]
+func (h *BundleHandler) RemoveBundle(c *fiber.Ctx) error {
+	if err := h.service.RemoveBundle(c.Context(), c.Params("id")); err != nil {
+		return bundleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// bundleError maps bundle service errors to HTTP responses, and product errors as productError does
+func bundleError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, application.ErrNotABundle) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return productError(c, err)
+}
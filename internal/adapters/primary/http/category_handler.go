@@ -0,0 +1,282 @@
+package http
+
+import (
+	"errors"
+
+	"test-go/internal/application"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CategoryHandler handles HTTP requests for category operations
+type CategoryHandler struct {
+	service *application.CategoryService
+}
+
+// NewCategoryHandler creates a new instance of CategoryHandler
+func NewCategoryHandler(service *application.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// categoryRequest is the body accepted when creating or renaming a category
+type categoryRequest struct {
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// moveCategoryRequest is the body accepted when moving or reordering a category
+type moveCategoryRequest struct {
+	ParentID string `json:"parent_id"`
+	Position int    `json:"position"`
+}
+
+// productCategoriesRequest is the body accepted when assigning categories to a product
+type productCategoriesRequest struct {
+	CategoryIDs []string `json:"category_ids"`
+}
+
+// CreateCategory godoc
+// @Summary Create a new category
+// @Description Create a category under an optional parent
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body categoryRequest true "Category details"
+// @Success 201 {object} entities.Category
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories [post]
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var req categoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	category, err := h.service.CreateCategory(c.Context(), req.Name, req.ParentID)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(category)
+}
+
+// GetCategory godoc
+// @Summary Get a category by ID
+// @Description Retrieve a category by its ID
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} entities.Category
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	category, err := h.service.GetCategory(c.Context(), c.Params("id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(category)
+}
+
+// UpdateCategory godoc
+// @Summary Rename a category
+// @Description Change the name of a category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body categoryRequest true "Updated category details"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	var req categoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if err := h.service.RenameCategory(c.Context(), c.Params("id"), req.Name); err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category by ID
+// @Description Delete a category that has no subcategories and no products
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	if err := h.service.DeleteCategory(c.Context(), c.Params("id")); err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListCategories godoc
+// @Summary List categories
+// @Description Retrieve every category, or only the children of parent_id. Use parent_id=root for the top level.
+// @Tags categories
+// @Produce json
+// @Param parent_id query string false "Parent category ID"
+// @Success 200 {array} entities.Category
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories [get]
+func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
+	parentID := c.Query("parent_id")
+	if parentID == "" {
+		categories, err := h.service.ListCategories(c.Context())
+		if err != nil {
+			return categoryError(c, err)
+		}
+		return c.Status(fiber.StatusOK).JSON(categories)
+	}
+
+	if parentID == "root" {
+		parentID = ""
+	}
+	categories, err := h.service.ListChildren(c.Context(), parentID)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(categories)
+}
+
+// MoveCategory godoc
+// @Summary Move or reorder a category
+// @Description Put a category and its subtree under a new parent at the given position. An empty parent_id moves it to the top level.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param move body moveCategoryRequest true "New parent and position"
+// @Success 200 {object} entities.Category
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/categories/{id}/move [post]
+func (h *CategoryHandler) MoveCategory(c *fiber.Ctx) error {
+	var req moveCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	category, err := h.service.MoveCategory(c.Context(), c.Params("id"), req.ParentID, req.Position)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(category)
+}
+
+// GetBreadcrumb godoc
+// @Summary Get a category breadcrumb
+// @Description Retrieve the categories from the root down to the given category
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {array} entities.Category
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/categories/{id}/breadcrumb [get]
+func (h *CategoryHandler) GetBreadcrumb(c *fiber.Ctx) error {
+	breadcrumb, err := h.service.GetBreadcrumb(c.Context(), c.Params("id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(breadcrumb)
+}
+
+// ListDescendants godoc
+// @Summary List category descendants
+// @Description Retrieve every category below the given category
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {array} entities.Category
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/categories/{id}/descendants [get]
+func (h *CategoryHandler) ListDescendants(c *fiber.Ctx) error {
+	descendants, err := h.service.ListDescendants(c.Context(), c.Params("id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(descendants)
+}
+
+// ListCategoryProducts godoc
+// @Summary List products in a category
+// @Description Retrieve the products assigned to a category, optionally including its descendants. Only active products are listed, unless an API key with the admin scope asks for another lifecycle status.
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param include_descendants query bool false "Include products of descendant categories"
+// @Param status query string false "Lifecycle status to list, active by default; another status or any needs the admin scope"
+// @Success 200 {array} entities.Product
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/categories/{id}/products [get]
+func (h *CategoryHandler) ListCategoryProducts(c *fiber.Ctx) error {
+	products, err := h.service.ListCategoryProducts(c.Context(), c.Params("id"), c.QueryBool("include_descendants"), c.Query("status"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(products)
+}
+
+// AssignProductCategories godoc
+// @Summary Assign categories to a product
+// @Description Replace the categories a product belongs to
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param categories body productCategoriesRequest true "Category IDs"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/products/{id}/categories [put]
+func (h *CategoryHandler) AssignProductCategories(c *fiber.Ctx) error {
+	var req productCategoriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if err := h.service.AssignProductCategories(c.Context(), c.Params("id"), req.CategoryIDs); err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// categoryError maps category service errors to HTTP responses
+func categoryError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrCategoryNotFound), errors.Is(err, ports.ErrProductNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidCategoryName), errors.Is(err, usecases.ErrInvalidProductStatus):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrCategoryCycle), errors.Is(err, application.ErrCategoryNotEmpty):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrAdminScopeRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
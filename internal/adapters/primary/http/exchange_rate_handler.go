@@ -0,0 +1,146 @@
+package http
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"test-go/internal/application"
+	"test-go/internal/core/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExchangeRateHandler handles HTTP requests for the exchange rates prices are converted with
+type ExchangeRateHandler struct {
+	service *application.ExchangeRateService
+}
+
+// NewExchangeRateHandler creates a new instance of ExchangeRateHandler
+func NewExchangeRateHandler(service *application.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: service}
+}
+
+// ListRates godoc
+// @Summary List exchange rates
+// @Description Retrieve the rate of every currency pair in effect now, or at the given time
+// @Tags exchange-rates
+// @Produce json
+// @Param at query string false "RFC 3339 time the rates were in effect at"
+// @Success 200 {array} entities.ExchangeRate
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/exchange-rates [get]
+func (h *ExchangeRateHandler) ListRates(c *fiber.Ctx) error {
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "at must be an RFC 3339 time"})
+		}
+	}
+
+	rates, err := h.service.ListRates(c.Context(), at)
+	if err != nil {
+		return exchangeRateError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rates)
+}
+
+// RateHistory godoc
+// @Summary Get the history of an exchange rate
+// @Description Retrieve the rates of a currency pair that took effect from from until to, oldest first
+// @Tags exchange-rates
+// @Produce json
+// @Param base query string true "ISO 4217 base currency"
+// @Param quote query string true "ISO 4217 quote currency"
+// @Param from query string false "RFC 3339 time the range starts at"
+// @Param to query string false "RFC 3339 time the range ends before"
+// @Success 200 {array} entities.ExchangeRate
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/exchange-rates/history [get]
+func (h *ExchangeRateHandler) RateHistory(c *fiber.Ctx) error {
+	var from, to time.Time
+	for param, bound := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := c.Query(param); value != "" {
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": param + " must be an RFC 3339 time"})
+			}
+			*bound = at
+		}
+	}
+
+	rates, err := h.service.RateHistory(c.Context(), c.Query("base"), c.Query("quote"), from, to)
+	if err != nil {
+		return exchangeRateError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rates)
+}
+
+// ImportRates godoc
+// @Summary Import exchange rates from a file
+// @Description Store the rates of a rate table sent as the request body, either a JSON document such as {"base": "USD", "date": "2024-05-31", "rates": {"EUR": 0.92}} or a CSV file with a base,quote,rate,effective_at header. The format is taken from the format parameter or else the Content-Type. Rates of a pair and effective time that were imported before are replaced; older rates are kept as history.
+// @Tags exchange-rates
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "json or csv"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/exchange-rates/import [post]
+func (h *ExchangeRateHandler) ImportRates(c *fiber.Ctx) error {
+	format := c.Query("format")
+	if format == "" {
+		format = application.ExchangeRatesJSON
+		if strings.HasPrefix(c.Get(fiber.HeaderContentType), "text/csv") {
+			format = application.ExchangeRatesCSV
+		}
+	}
+
+	imported, err := h.service.ImportRates(c.Context(), format, c.Body())
+	if err != nil {
+		return exchangeRateError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"imported": imported})
+}
+
+// RefreshRates godoc
+// @Summary Refresh exchange rates
+// @Description Fetch and store the current rates of the exchange rate API configured with EXCHANGE_RATE_URL, without waiting for the next scheduled refresh
+// @Tags exchange-rates
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/exchange-rates/refresh [post]
+func (h *ExchangeRateHandler) RefreshRates(c *fiber.Ctx) error {
+	imported, err := h.service.RefreshRates(c.Context())
+	if errors.Is(err, usecases.ErrInvalidExchangeRates) {
+		// The API answered with something that is not a rate table
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return exchangeRateError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"imported": imported})
+}
+
+// exchangeRateError maps exchange rate service errors to HTTP responses
+func exchangeRateError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidExchangeRates), errors.Is(err, application.ErrInvalidExchangeRateFormat),
+		errors.Is(err, usecases.ErrInvalidCurrency):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrNoExchangeRateSource):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
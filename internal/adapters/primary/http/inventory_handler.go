@@ -0,0 +1,250 @@
+package http
+
+import (
+	"errors"
+	"time"
+
+	"test-go/internal/application"
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InventoryHandler handles HTTP requests for stock levels and reservations
+type InventoryHandler struct {
+	service *application.InventoryService
+}
+
+// NewInventoryHandler creates a new instance of InventoryHandler
+func NewInventoryHandler(service *application.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+// stockAdjustmentRequest is the body accepted when adjusting stock
+type stockAdjustmentRequest struct {
+	Warehouse string `json:"warehouse"`
+	Delta     int64  `json:"delta"`
+	Reason    string `json:"reason"`
+}
+
+// stockThresholdRequest is the body accepted when changing the low-stock threshold
+type stockThresholdRequest struct {
+	Warehouse string `json:"warehouse"`
+	Threshold int64  `json:"threshold"`
+}
+
+// reservationRequest is the body accepted when reserving stock
+type reservationRequest struct {
+	Warehouse  string `json:"warehouse"`
+	Quantity   int64  `json:"quantity"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+}
+
+// stockResponse lists per-warehouse stock levels with their totals
+type stockResponse struct {
+	ProductID  string              `json:"product_id"`
+	OnHand     int64               `json:"on_hand"`
+	Reserved   int64               `json:"reserved"`
+	Available  int64               `json:"available"`
+	Warehouses []stockLevelPayload `json:"warehouses"`
+}
+
+// stockLevelPayload is a stock level with its computed available quantity
+type stockLevelPayload struct {
+	Warehouse         string    `json:"warehouse"`
+	OnHand            int64     `json:"on_hand"`
+	Reserved          int64     `json:"reserved"`
+	Available         int64     `json:"available"`
+	LowStockThreshold int64     `json:"low_stock_threshold"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// GetStock godoc
+// @Summary Get product stock
+// @Description Retrieve on-hand, reserved and available quantities of a product per warehouse
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} stockResponse
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/inventory [get]
+func (h *InventoryHandler) GetStock(c *fiber.Ctx) error {
+	productID := c.Params("id")
+
+	levels, err := h.service.GetStock(c.Context(), productID)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	resp := stockResponse{ProductID: productID, Warehouses: []stockLevelPayload{}}
+	for _, level := range levels {
+		resp.OnHand += level.OnHand
+		resp.Reserved += level.Reserved
+		resp.Available += level.Available()
+		resp.Warehouses = append(resp.Warehouses, stockLevelPayload{
+			Warehouse:         level.Warehouse,
+			OnHand:            level.OnHand,
+			Reserved:          level.Reserved,
+			Available:         level.Available(),
+			LowStockThreshold: level.LowStockThreshold,
+			UpdatedAt:         level.UpdatedAt,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// AdjustStock godoc
+// @Summary Adjust product stock
+// @Description Add to or remove from the on-hand quantity. Removing never takes stock below the reserved quantity.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param adjustment body stockAdjustmentRequest true "Stock adjustment"
+// @Success 200 {object} entities.StockLevel
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/inventory/adjustments [post]
+func (h *InventoryHandler) AdjustStock(c *fiber.Ctx) error {
+	var req stockAdjustmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	level, err := h.service.AdjustStock(c.Context(), c.Params("id"), req.Warehouse, req.Delta, req.Reason)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(level)
+}
+
+// SetLowStockThreshold godoc
+// @Summary Set the low-stock threshold
+// @Description Publish a low-stock event once available stock drops to or below this quantity
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param threshold body stockThresholdRequest true "Low-stock threshold"
+// @Success 200 {object} entities.StockLevel
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/inventory/threshold [put]
+func (h *InventoryHandler) SetLowStockThreshold(c *fiber.Ctx) error {
+	var req stockThresholdRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	level, err := h.service.SetLowStockThreshold(c.Context(), c.Params("id"), req.Warehouse, req.Threshold)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(level)
+}
+
+// ReserveStock godoc
+// @Summary Reserve product stock
+// @Description Hold stock for a checkout. The reservation is released automatically when it expires.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param reservation body reservationRequest true "Reservation details"
+// @Success 201 {object} entities.Reservation
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/inventory/reservations [post]
+func (h *InventoryHandler) ReserveStock(c *fiber.Ctx) error {
+	var req reservationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	reservation, err := h.service.ReserveStock(c.Context(), c.Params("id"), req.Warehouse, req.Quantity, ttl)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(reservation)
+}
+
+// GetReservation godoc
+// @Summary Get a reservation by ID
+// @Description Retrieve a stock reservation by its ID
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} entities.Reservation
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/reservations/{id} [get]
+func (h *InventoryHandler) GetReservation(c *fiber.Ctx) error {
+	reservation, err := h.service.GetReservation(c.Context(), c.Params("id"))
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reservation)
+}
+
+// CommitReservation godoc
+// @Summary Commit a reservation
+// @Description Turn a pending reservation into a permanent stock decrement
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} entities.Reservation
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/reservations/{id}/commit [post]
+func (h *InventoryHandler) CommitReservation(c *fiber.Ctx) error {
+	reservation, err := h.service.CommitReservation(c.Context(), c.Params("id"))
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reservation)
+}
+
+// ReleaseReservation godoc
+// @Summary Release a reservation
+// @Description Return the stock held by a pending reservation
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} entities.Reservation
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/reservations/{id}/release [post]
+func (h *InventoryHandler) ReleaseReservation(c *fiber.Ctx) error {
+	reservation, err := h.service.ReleaseReservation(c.Context(), c.Params("id"))
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reservation)
+}
+
+// inventoryError maps inventory service errors to HTTP responses
+func inventoryError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrProductNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+	case errors.Is(err, ports.ErrReservationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Reservation not found"})
+	case errors.Is(err, ports.ErrInsufficientStock), errors.Is(err, ports.ErrReservationClosed), errors.Is(err, ports.ErrReservationExpired):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidQuantity):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
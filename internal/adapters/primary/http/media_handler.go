@@ -0,0 +1,177 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+
+	"test-go/internal/application"
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MediaHandler handles HTTP requests for the media attached to products
+type MediaHandler struct {
+	service *application.MediaService
+}
+
+// NewMediaHandler creates a new instance of MediaHandler
+func NewMediaHandler(service *application.MediaService) *MediaHandler {
+	return &MediaHandler{service: service}
+}
+
+// reorderMediaRequest is the body accepted when reordering the media of a product
+type reorderMediaRequest struct {
+	// IDs lists every media of the product in the new order
+	IDs []string `json:"ids"`
+}
+
+// UploadMedia godoc
+// @Summary Upload a product media
+// @Description Attach a JPEG, PNG, GIF or WebP image to the end of a product's media. The type is sniffed from the content. Uploading content the product already has returns the existing media with 200 instead of 201. Thumbnails are generated in the background.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param file formData file true "Image file"
+// @Success 200 {object} entities.Media
+// @Success 201 {object} entities.Media
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/media [post]
+func (h *MediaHandler) UploadMedia(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "A multipart file field named file is required"})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer file.Close()
+
+	media, created, err := h.service.UploadMedia(c.Context(), c.Params("id"), header.Filename, file)
+	if err != nil {
+		return mediaError(c, err)
+	}
+
+	if !created {
+		return c.Status(fiber.StatusOK).JSON(media)
+	}
+	return c.Status(fiber.StatusCreated).JSON(media)
+}
+
+// ListMedia godoc
+// @Summary List the media of a product
+// @Description Retrieve the media of a product in order, with the thumbnails generated so far. Products that are not active are not found, unless an API key with the admin scope asks.
+// @Tags media
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} entities.Media
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/media [get]
+func (h *MediaHandler) ListMedia(c *fiber.Ctx) error {
+	media, err := h.service.ListMedia(c.Context(), c.Params("id"))
+	if err != nil {
+		return mediaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(media)
+}
+
+// ReorderMedia godoc
+// @Summary Reorder the media of a product
+// @Description Put the media of a product in the order of the given IDs, which must list each of them once
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param order body reorderMediaRequest true "Media IDs in the new order"
+// @Success 200 {array} entities.Media
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/media/order [put]
+func (h *MediaHandler) ReorderMedia(c *fiber.Ctx) error {
+	var req reorderMediaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	media, err := h.service.ReorderMedia(c.Context(), c.Params("id"), req.IDs)
+	if err != nil {
+		return mediaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(media)
+}
+
+// DeleteMedia godoc
+// @Summary Delete a product media
+// @Description Remove a media from a product; the media after it move up one position
+// @Tags media
+// @Param id path string true "Product ID"
+// @Param mediaId path string true "Media ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/media/{mediaId} [delete]
+func (h *MediaHandler) DeleteMedia(c *fiber.Ctx) error {
+	if err := h.service.DeleteMedia(c.Context(), c.Params("id"), c.Params("mediaId")); err != nil {
+		return mediaError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetMediaContent godoc
+// @Summary Download a product media
+// @Description Retrieve the content of a media, or of one of its thumbnails. Products that are not active are not found, unless an API key with the admin scope asks.
+// @Tags media
+// @Produce image/jpeg,image/png,image/gif,image/webp
+// @Param id path string true "Product ID"
+// @Param mediaId path string true "Media ID"
+// @Param size query string false "original, small, medium or large" default(original)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/media/{mediaId}/content [get]
+func (h *MediaHandler) GetMediaContent(c *fiber.Ctx) error {
+	size := c.Query("size", "original")
+	content, info, media, err := h.service.OpenMediaContent(c.Context(), c.Params("id"), c.Params("mediaId"), size)
+	if err != nil {
+		return mediaError(c, err)
+	}
+
+	// The content of a media in a given size never changes, so its hash identifies it
+	c.Set(fiber.HeaderETag, fmt.Sprintf("%q", media.SHA256+"-"+size))
+	c.Set(fiber.HeaderContentType, info.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", media.Filename))
+	return c.SendStream(content, int(info.Size))
+}
+
+// mediaError maps media service errors to HTTP responses
+func mediaError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrMediaNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Media not found"})
+	case errors.Is(err, ports.ErrProductNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+	case errors.Is(err, application.ErrThumbnailNotReady), errors.Is(err, ports.ErrBlobNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrMediaTooLarge), errors.Is(err, application.ErrMediaTooManyPixels):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrUnsupportedMediaType):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrEmptyMedia), errors.Is(err, application.ErrInvalidMediaOrder),
+		errors.Is(err, application.ErrInvalidThumbnailSize):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"errors"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PriceListHandler handles HTTP requests for price lists and their product prices
+type PriceListHandler struct {
+	service *application.PriceListService
+}
+
+// NewPriceListHandler creates a new instance of PriceListHandler
+func NewPriceListHandler(service *application.PriceListService) *PriceListHandler {
+	return &PriceListHandler{service: service}
+}
+
+// priceListRequest is the body accepted when creating or updating a price list
+type priceListRequest struct {
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	Currency      string `json:"currency"`
+	Market        string `json:"market"`
+	CustomerGroup string `json:"customer_group"`
+}
+
+// listPriceRequest is the body accepted when setting the price of a product in a price list
+type listPriceRequest struct {
+	Price float32 `json:"price"`
+}
+
+// CreatePriceList godoc
+// @Summary Create a price list
+// @Description Create a named price list in a currency, for a market or customer group. Product reads name it by code with price_list.
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Param priceList body priceListRequest true "Price list"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-lists [post]
+func (h *PriceListHandler) CreatePriceList(c *fiber.Ctx) error {
+	var req priceListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	id, err := h.service.CreatePriceList(c.Context(), req.priceList())
+	if err != nil {
+		return priceListError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id})
+}
+
+// GetPriceList godoc
+// @Summary Get a price list by ID
+// @Description Retrieve a price list with its explicit prices by product ID
+// @Tags price-lists
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Success 200 {object} entities.PriceList
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-lists/{id} [get]
+func (h *PriceListHandler) GetPriceList(c *fiber.Ctx) error {
+	list, err := h.service.GetPriceList(c.Context(), c.Params("id"))
+	if err != nil {
+		return priceListError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(list)
+}
+
+// ListPriceLists godoc
+// @Summary List price lists
+// @Description Retrieve the price lists ordered by code
+// @Tags price-lists
+// @Produce json
+// @Success 200 {array} entities.PriceList
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-lists [get]
+func (h *PriceListHandler) ListPriceLists(c *fiber.Ctx) error {
+	lists, err := h.service.ListPriceLists(c.Context())
+	if err != nil {
+		return priceListError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(lists)
+}
+
+// UpdatePriceList godoc
+// @Summary Update a price list by ID
+// @Description Change the code, name, currency, market and customer group of a price list. Its prices are kept as they are.
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param priceList body priceListRequest true "Price list"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-lists/{id} [put]
+func (h *PriceListHandler) UpdatePriceList(c *fiber.Ctx) error {
+	var req priceListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return priceListError(c, ports.ErrPriceListNotFound)
+	}
+
+	list := req.priceList()
+	list.ID = id
+	if err := h.service.UpdatePriceList(c.Context(), list); err != nil {
+		return priceListError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeletePriceList godoc
+// @Summary Delete a price list by ID
+// @Description Delete a price list with its prices
+// @Tags price-lists
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-lists/{id} [delete]
+func (h *PriceListHandler) DeletePriceList(c *fiber.Ctx) error {
+	if err := h.service.DeletePriceList(c.Context(), c.Params("id")); err != nil {
+		return priceListError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetPrice godoc
+// @Summary Set the price of a product in a price list
+// @Description Set the explicit price of a product in the currency of the price list, which replaces the conversion of its effective price
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param productId path string true "Product ID"
+// @Param price body listPriceRequest true "Price in the currency of the list"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-lists/{id}/prices/{productId} [put]
+func (h *PriceListHandler) SetPrice(c *fiber.Ctx) error {
+	var req listPriceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if err := h.service.SetPrice(c.Context(), c.Params("id"), c.Params("productId"), req.Price); err != nil {
+		return priceListError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeletePrice godoc
+// @Summary Remove the price of a product from a price list
+// @Description Remove the explicit price of a product, which is then converted from its effective price in the base currency
+// @Tags price-lists
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param productId path string true "Product ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-lists/{id}/prices/{productId} [delete]
+func (h *PriceListHandler) DeletePrice(c *fiber.Ctx) error {
+	if err := h.service.DeletePrice(c.Context(), c.Params("id"), c.Params("productId")); err != nil {
+		return priceListError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// priceList converts the request to the price list it describes
+func (r priceListRequest) priceList() *entities.PriceList {
+	return &entities.PriceList{
+		Code:          r.Code,
+		Name:          r.Name,
+		Currency:      r.Currency,
+		Market:        r.Market,
+		CustomerGroup: r.CustomerGroup,
+	}
+}
+
+// priceListError maps price list service errors to HTTP responses
+func priceListError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrPriceListNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Price list not found"})
+	case errors.Is(err, ports.ErrProductNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+	case errors.Is(err, application.ErrInvalidPriceList), errors.Is(err, application.ErrInvalidListPrice),
+		errors.Is(err, usecases.ErrInvalidCurrency):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrPriceListCodeTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
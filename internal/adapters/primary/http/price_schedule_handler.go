@@ -0,0 +1,157 @@
+package http
+
+import (
+	"errors"
+	"time"
+
+	"test-go/internal/application"
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PriceScheduleHandler handles HTTP requests for scheduled price changes
+type PriceScheduleHandler struct {
+	service *application.PriceScheduleService
+}
+
+// NewPriceScheduleHandler creates a new instance of PriceScheduleHandler
+func NewPriceScheduleHandler(service *application.PriceScheduleService) *PriceScheduleHandler {
+	return &PriceScheduleHandler{service: service}
+}
+
+// priceScheduleRequest is the body accepted when creating or updating a price schedule
+type priceScheduleRequest struct {
+	Price    float32   `json:"price"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Priority int       `json:"priority"`
+}
+
+// CreateSchedule godoc
+// @Summary Schedule a price change
+// @Description Override the price of a product from starts_at until ends_at (exclusive). Schedules of the same priority may not overlap; otherwise the highest priority applies.
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param schedule body priceScheduleRequest true "Price schedule"
+// @Success 201 {object} entities.PriceSchedule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/price-schedules [post]
+func (h *PriceScheduleHandler) CreateSchedule(c *fiber.Ctx) error {
+	var req priceScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	schedule, err := h.service.CreateSchedule(c.Context(), c.Params("id"), req.Price, req.StartsAt, req.EndsAt, req.Priority)
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(schedule)
+}
+
+// ListSchedules godoc
+// @Summary List the price schedules of a product
+// @Description Retrieve the past, current and future price schedules of a product ordered by start time
+// @Tags price-schedules
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} entities.PriceSchedule
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/price-schedules [get]
+func (h *PriceScheduleHandler) ListSchedules(c *fiber.Ctx) error {
+	schedules, err := h.service.ListSchedules(c.Context(), c.Params("id"))
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(schedules)
+}
+
+// GetSchedule godoc
+// @Summary Get a price schedule by ID
+// @Description Retrieve a price schedule by its ID
+// @Tags price-schedules
+// @Produce json
+// @Param id path string true "Price schedule ID"
+// @Success 200 {object} entities.PriceSchedule
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-schedules/{id} [get]
+func (h *PriceScheduleHandler) GetSchedule(c *fiber.Ctx) error {
+	schedule, err := h.service.GetSchedule(c.Context(), c.Params("id"))
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(schedule)
+}
+
+// UpdateSchedule godoc
+// @Summary Update a price schedule by ID
+// @Description Change the price, period and priority of a price schedule
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Price schedule ID"
+// @Param schedule body priceScheduleRequest true "Price schedule"
+// @Success 200 {object} entities.PriceSchedule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-schedules/{id} [put]
+func (h *PriceScheduleHandler) UpdateSchedule(c *fiber.Ctx) error {
+	var req priceScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	schedule, err := h.service.UpdateSchedule(c.Context(), c.Params("id"), req.Price, req.StartsAt, req.EndsAt, req.Priority)
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a price schedule by ID
+// @Description Delete a price schedule by its ID
+// @Tags price-schedules
+// @Produce json
+// @Param id path string true "Price schedule ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/price-schedules/{id} [delete]
+func (h *PriceScheduleHandler) DeleteSchedule(c *fiber.Ctx) error {
+	if err := h.service.DeleteSchedule(c.Context(), c.Params("id")); err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// priceScheduleError maps price schedule service errors to HTTP responses
+func priceScheduleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrPriceScheduleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Price schedule not found"})
+	case errors.Is(err, ports.ErrProductNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+	case errors.Is(err, application.ErrInvalidPriceSchedule):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, ports.ErrPriceScheduleOverlap):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"test-go/internal/core/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// validationProblemType identifies validation failures in problem details
+const validationProblemType = "/problems/validation-error"
+
+// problemDetails is an RFC 7807 problem details body, extended with the invalid fields
+type problemDetails struct {
+	Type     string                    `json:"type"`
+	Title    string                    `json:"title"`
+	Status   int                       `json:"status"`
+	Detail   string                    `json:"detail,omitempty"`
+	Instance string                    `json:"instance,omitempty"`
+	Errors   []usecases.FieldViolation `json:"errors"`
+}
+
+// validationProblem renders a validation error as RFC 7807 problem details with one entry per invalid field
+func validationProblem(c *fiber.Ctx, err *usecases.ValidationError) error {
+	problem := problemDetails{
+		Type:     validationProblemType,
+		Title:    "Your request has invalid fields",
+		Status:   fiber.StatusBadRequest,
+		Detail:   err.Error(),
+		Instance: c.OriginalURL(),
+		Errors:   err.Violations,
+	}
+
+	return c.Status(fiber.StatusBadRequest).JSON(problem, problemContentType)
+}
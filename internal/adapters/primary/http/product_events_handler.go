@@ -0,0 +1,184 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// heartbeatInterval is how often an idle change feed connection is pinged
+const heartbeatInterval = 15 * time.Second
+
+// tenantLocal and visibleLocal carry the tenant and the products it may see across the WebSocket
+// upgrade, which only keeps string keyed locals
+const (
+	tenantLocal  = "tenant"
+	visibleLocal = "visible"
+)
+
+// ProductEventsHandler pushes product change notifications over SSE and WebSocket
+type ProductEventsHandler struct {
+	feed *application.ProductFeed
+}
+
+// NewProductEventsHandler creates a new instance of ProductEventsHandler
+func NewProductEventsHandler(feed *application.ProductFeed) *ProductEventsHandler {
+	return &ProductEventsHandler{feed: feed}
+}
+
+// StreamEvents godoc
+// @Summary Stream product changes
+// @Description Push product created, updated and deleted notifications as Server-Sent Events. Notifications about products that are not active carry no product, unless an API key with the admin scope subscribes.
+// @Tags products
+// @Produce text/event-stream
+// @Param ids query string false "Comma separated product IDs to include"
+// @Param types query string false "Comma separated event types to include"
+// @Param Last-Event-ID header string false "Replay events after this ID"
+// @Success 200 {object} application.FeedEvent
+// @Router /api/v1/products/events [get]
+func (h *ProductEventsHandler) StreamEvents(c *fiber.Ctx) error {
+	filter := parseFeedFilter(ports.TenantFromContext(c.Context()), c.Query("ids"), c.Query("types"))
+	filter.Visible = application.VisibleProducts(c.Context())
+	lastEventID, _ := strconv.ParseUint(c.Get("Last-Event-ID"), 10, 64)
+
+	sub := h.feed.Subscribe(filter, lastEventID)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.feed.Unsubscribe(sub)
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					// Dropped for being too slow, the client reconnects with Last-Event-ID
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					return
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			// A flush error means the client has gone away
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// UpgradeEvents only lets WebSocket upgrade requests through to the change feed
+func (h *ProductEventsHandler) UpgradeEvents(c *fiber.Ctx) error {
+	if websocket.IsWebSocketUpgrade(c) {
+		c.Locals(tenantLocal, ports.TenantFromContext(c.Context()))
+		c.Locals(visibleLocal, application.VisibleProducts(c.Context()))
+		return c.Next()
+	}
+	return fiber.ErrUpgradeRequired
+}
+
+// SocketEvents godoc
+// @Summary Stream product changes over WebSocket
+// @Description Push product created, updated and deleted notifications as JSON WebSocket messages. Notifications about products that are not active carry no product, unless an API key with the admin scope subscribes.
+// @Tags products
+// @Param ids query string false "Comma separated product IDs to include"
+// @Param types query string false "Comma separated event types to include"
+// @Param last_event_id query string false "Replay events after this ID"
+// @Success 101
+// @Failure 426 {object} map[string]string
+// @Router /api/v1/products/ws [get]
+func (h *ProductEventsHandler) SocketEvents() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		tenantID, _ := conn.Locals(tenantLocal).(string)
+		filter := parseFeedFilter(tenantID, conn.Query("ids"), conn.Query("types"))
+		visible, ok := conn.Locals(visibleLocal).(func(*entities.Product) bool)
+		if !ok {
+			// Without the caller's key only active products are shown
+			visible = application.VisibleProducts(context.Background())
+		}
+		filter.Visible = visible
+		lastEventID, _ := strconv.ParseUint(conn.Query("last_event_id"), 10, 64)
+
+		sub := h.feed.Subscribe(filter, lastEventID)
+		defer h.feed.Unsubscribe(sub)
+
+		// Drain incoming frames so pongs and close messages are processed
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					_ = conn.WriteMessage(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "subscriber too slow"))
+					return
+				}
+				if err := conn.WriteJSON(event); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				deadline := time.Now().Add(heartbeatInterval)
+				if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	})
+}
+
+// parseFeedFilter builds a feed filter for a tenant from comma separated ID and type lists
+func parseFeedFilter(tenantID string, ids string, types string) application.FeedFilter {
+	return application.FeedFilter{
+		TenantID:   tenantID,
+		ProductIDs: splitSet(ids),
+		Types:      splitSet(types),
+	}
+}
+
+// splitSet turns a comma separated list into a set, ignoring blank entries
+func splitSet(list string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = struct{}{}
+		}
+	}
+	return set
+}
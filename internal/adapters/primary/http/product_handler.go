@@ -1,13 +1,23 @@
 package http
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
 
 	"test-go/internal/application"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// attributeQueryPrefix marks list query parameters that filter on custom attributes,
+// as attr.<name>=<value> or attr.<name>.<op>=<value>
+const attributeQueryPrefix = "attr."
+
 // ProductHandler handles HTTP requests for product operations
 type ProductHandler struct {
 	service *application.ProductService
@@ -20,13 +30,14 @@ func NewProductHandler(service *application.ProductService) *ProductHandler {
 
 // CreateProduct godoc
 // @Summary Create a new product
-// @Description Create a new product with the provided details
+// @Description Create a new product with the provided details. Products are created as drafts and are only shown to public readers once they are approved and active.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param product body entities.Product true "Product details"
 // @Success 201 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} problemDetails
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/products [post]
 func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
@@ -35,9 +46,9 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
 	}
 
-	id, err := h.service.CreateProduct(c.Context(), product.Name, product.Price)
+	id, err := h.service.CreateProduct(c.Context(), product.Name, product.Description, product.Price, product.TypeID, product.Attributes)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return productError(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id})
@@ -45,22 +56,55 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 
 // GetProductByID godoc
 // @Summary Get a product by ID
-// @Description Retrieve a product by its ID
+// @Description Retrieve a product by its ID, or as it was at a point in time or right after one of its revisions. The name and description are in the first locale of Accept-Language the product has text in, falling back from id-ID to id and finally the default locale; Content-Language names the locale picked. With price_list or currency, the current product also carries its price in that list or currency and how it was derived: an explicit price of the list, or its effective price converted from the base currency. Only active products are found, unless an API key with the admin scope asks for another lifecycle status.
 // @Tags products
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param Accept-Language header string false "Preferred locales, e.g. id-ID, id;q=0.9"
+// @Param as_of query string false "RFC 3339 time to read the product at"
+// @Param revision query int false "Revision number to read the product at"
+// @Param price_list query string false "Code of the price list to price the product in"
+// @Param currency query string false "ISO 4217 currency to price the product in, which must match the currency of price_list"
+// @Param status query string false "Lifecycle status the product must be in, at as_of or revision when set, active by default; another status or any needs the admin scope"
 // @Success 200 {object} entities.Product
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Router /api/v1/products/{id} [get]
 func (h *ProductHandler) GetProductByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	product, err := h.service.GetProductByID(c.Context(), id)
+	if asOf := c.Query("as_of"); asOf != "" {
+		at, err := time.Parse(time.RFC3339, asOf)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "as_of must be an RFC 3339 time"})
+		}
+		product, err := h.service.GetProductAsOf(c.Context(), id, at, c.Query("status"))
+		if err != nil {
+			return productError(c, err)
+		}
+		return localizedProduct(c, product)
+	}
+
+	if c.Query("revision") != "" {
+		revision, err := revisionParam(c.Query("revision"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		product, err := h.service.GetProductAtRevision(c.Context(), id, revision, c.Query("status"))
+		if err != nil {
+			return productError(c, err)
+		}
+		return localizedProduct(c, product)
+	}
+
+	product, err := h.service.GetProductByID(c.Context(), id, c.Query("status"), priceQuery(c))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Product not found"})
+		return productError(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(product)
+	return localizedProduct(c, product)
 }
 
 // UpdateProduct godoc
@@ -72,7 +116,8 @@ func (h *ProductHandler) GetProductByID(c *fiber.Ctx) error {
 // @Param id path string true "Product ID"
 // @Param product body entities.Product true "Updated product details"
 // @Success 204
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
@@ -82,9 +127,9 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
 	}
 
-	err := h.service.UpdateProduct(c.Context(), id, product.Name, product.Price)
+	err := h.service.UpdateProduct(c.Context(), id, product.Name, product.Description, product.Price, product.TypeID, product.Attributes)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return productError(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
@@ -92,11 +137,13 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 
 // DeleteProduct godoc
 // @Summary Delete a product by ID
-// @Description Delete a product by its ID
+// @Description Delete a product by its ID. Products that are components of a bundle cannot be deleted until they are removed from it.
 // @Tags products
 // @Produce json
 // @Param id path string true "Product ID"
 // @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
@@ -104,7 +151,7 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 
 	err := h.service.DeleteProduct(c.Context(), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return productError(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
@@ -112,17 +159,88 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 
 // ListProducts godoc
 // @Summary List all products
-// @Description Retrieve a list of all products
+// @Description Retrieve a list of all products. With type_id, only products of that type are listed and custom attributes can be filtered with attr.<name>=<value> or attr.<name>.<op>=<value>, where op is one of eq, ne, gt, gte, lt, lte. Names and descriptions are localized as for a single product, and q and sort follow the rules of the most preferred locale, ignoring case and accents when searching. Products are priced in price_list or currency as for a single product, and sorting by price then uses those prices. Only active products are listed, unless an API key with the admin scope asks for another lifecycle status.
 // @Tags products
 // @Produce json
+// @Param type_id query string false "Product type ID"
+// @Param q query string false "Text the localized name or description contains"
+// @Param sort query string false "name, price or created_at, prefixed with - for descending order"
+// @Param price_list query string false "Code of the price list to price the products in"
+// @Param currency query string false "ISO 4217 currency to price the products in, which must match the currency of price_list"
+// @Param status query string false "Lifecycle status to list, active by default; another status or any needs the admin scope"
+// @Param Accept-Language header string false "Preferred locales, e.g. id-ID, id;q=0.9"
 // @Success 200 {array} entities.Product
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/products [get]
 func (h *ProductHandler) ListProducts(c *fiber.Ctx) error {
-	products, err := h.service.ListProducts(c.Context())
+	var queries []application.AttributeQuery
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		name, ok := strings.CutPrefix(string(key), attributeQueryPrefix)
+		if !ok {
+			return
+		}
+		query := application.AttributeQuery{Name: name, Value: string(value)}
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			query.Name, query.Op = name[:i], name[i+1:]
+		}
+		queries = append(queries, query)
+	})
+
+	products, err := h.service.ListProducts(c.Context(), c.Query("type_id"), queries, c.Query("q"), c.Query("sort"), c.Query("status"), priceQuery(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return productError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(products)
 }
+
+// priceQuery reads the price list and currency a product read asks for
+func priceQuery(c *fiber.Ctx) application.PriceQuery {
+	return application.PriceQuery{PriceList: c.Query("price_list"), Currency: c.Query("currency")}
+}
+
+// localizedProduct responds with a product, naming the locale of its text in Content-Language
+func localizedProduct(c *fiber.Ctx, product *entities.Product) error {
+	c.Set(fiber.HeaderContentLanguage, product.Locale)
+	return c.Status(fiber.StatusOK).JSON(product)
+}
+
+// productError maps product service errors to HTTP responses
+func productError(c *fiber.Ctx, err error) error {
+	var (
+		validationErr *usecases.ValidationError
+		attributeErr  *usecases.AttributeError
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		return validationProblem(c, validationErr)
+	case errors.As(err, &attributeErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "violations": attributeErr.Violations})
+	case errors.Is(err, usecases.ErrInvalidLocale), errors.Is(err, usecases.ErrInvalidSort),
+		errors.Is(err, application.ErrDefaultLocaleTranslation), errors.Is(err, usecases.ErrInvalidCurrency),
+		errors.Is(err, application.ErrPriceListCurrencyMismatch), errors.Is(err, usecases.ErrInvalidProductStatus),
+		errors.Is(err, usecases.ErrInvalidActivationSchedule):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, ports.ErrProductNotFound), errors.Is(err, ports.ErrProductTypeNotFound),
+		errors.Is(err, ports.ErrRevisionNotFound), errors.Is(err, application.ErrTranslationNotFound),
+		errors.Is(err, ports.ErrPriceListNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, ports.ErrExchangeRateNotFound):
+		// The request is well-formed, but the price cannot be converted until the rate is imported
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrRevisionNotRestorable), errors.Is(err, usecases.ErrProductInBundle),
+		errors.Is(err, usecases.ErrInvalidTransition):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, usecases.ErrPrincipalRequired):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrProductQuotaExceeded), errors.Is(err, application.ErrAdminScopeRequired),
+		errors.Is(err, usecases.ErrApprovalRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"time"
+
+	"test-go/internal/application"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProductLifecycleHandler handles HTTP requests moving products through their lifecycle
+type ProductLifecycleHandler struct {
+	service *application.ProductService
+}
+
+// NewProductLifecycleHandler creates a new instance of ProductLifecycleHandler
+func NewProductLifecycleHandler(service *application.ProductService) *ProductLifecycleHandler {
+	return &ProductLifecycleHandler{service: service}
+}
+
+// transitionRequest is the body of a lifecycle transition
+type transitionRequest struct {
+	Status string `json:"status"`
+}
+
+// activationScheduleRequest is the body of an activation schedule; omitted dates are cleared
+type activationScheduleRequest struct {
+	ActivateAt   *time.Time `json:"activate_at,omitempty"`
+	DeactivateAt *time.Time `json:"deactivate_at,omitempty"`
+}
+
+// TransitionProduct godoc
+// @Summary Move a product to another lifecycle status
+// @Description Move a product from draft to review or archived, from review to active or back to draft, from active to discontinued, from discontinued back to active or to archived, and from archived back to draft. Submitting a product for review and approving it need an API key or a verified bearer token; X-Actor is not trusted here. Moving from review to active approves the product and must be done by another principal than the one who submitted it. A product approved before its activate_at date stays in review until the scheduler activates it.
+// @Tags lifecycle
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param transition body transitionRequest true "Status to move to"
+// @Success 200 {object} entities.Product
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/transitions [post]
+func (h *ProductLifecycleHandler) TransitionProduct(c *fiber.Ctx) error {
+	var request transitionRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	product, err := h.service.TransitionProduct(c.Context(), c.Params("id"), request.Status)
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(product)
+}
+
+// ScheduleProduct godoc
+// @Summary Schedule the activation and deactivation of a product
+// @Description Set the time an approved product in review becomes active and the time an active product is discontinued. Omitted dates are cleared. The scheduler applies due dates within a minute.
+// @Tags lifecycle
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param schedule body activationScheduleRequest true "Activation and deactivation dates"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/activation-schedule [put]
+func (h *ProductLifecycleHandler) ScheduleProduct(c *fiber.Ctx) error {
+	var request activationScheduleRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if err := h.service.ScheduleProduct(c.Context(), c.Params("id"), request.ActivateAt, request.DeactivateAt); err != nil {
+		return productError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
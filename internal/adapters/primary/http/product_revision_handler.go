@@ -0,0 +1,134 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProductRevisionHandler handles HTTP requests for the change history of products
+type ProductRevisionHandler struct {
+	service *application.ProductService
+}
+
+// NewProductRevisionHandler creates a new instance of ProductRevisionHandler
+func NewProductRevisionHandler(service *application.ProductService) *ProductRevisionHandler {
+	return &ProductRevisionHandler{service: service}
+}
+
+// ListRevisions godoc
+// @Summary List the revisions of a product
+// @Description Retrieve every recorded change of a product, oldest first, with its snapshot, diff, actor and request ID. Changes leaving the product in another status than active are only listed for API keys with the admin scope.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} entities.ProductRevision
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/revisions [get]
+func (h *ProductRevisionHandler) ListRevisions(c *fiber.Ctx) error {
+	revisions, err := h.service.ListRevisions(c.Context(), c.Params("id"))
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(revisions)
+}
+
+// GetRevision godoc
+// @Summary Get a revision of a product
+// @Description Retrieve one recorded change of a product by its revision number. Changes leaving the product in another status than active are only found with an API key with the admin scope.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} entities.ProductRevision
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/products/{id}/revisions/{revision} [get]
+func (h *ProductRevisionHandler) GetRevision(c *fiber.Ctx) error {
+	revision, err := revisionParam(c.Params("revision"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	found, err := h.service.GetRevision(c.Context(), c.Params("id"), revision)
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(found)
+}
+
+// DiffRevisions godoc
+// @Summary Diff two revisions of a product
+// @Description List the fields that differ between the product right after two of its revisions. Both must leave the product active unless the API key has the admin scope.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param from query int true "Revision number to compare from"
+// @Param to query int true "Revision number to compare to"
+// @Success 200 {array} entities.FieldChange
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/products/{id}/revisions/diff [get]
+func (h *ProductRevisionHandler) DiffRevisions(c *fiber.Ctx) error {
+	from, err := revisionParam(c.Query("from"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from: " + err.Error()})
+	}
+	to, err := revisionParam(c.Query("to"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "to: " + err.Error()})
+	}
+
+	changes, err := h.service.DiffRevisions(c.Context(), c.Params("id"), from, to)
+	if err != nil {
+		return productError(c, err)
+	}
+	if changes == nil {
+		changes = []entities.FieldChange{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(changes)
+}
+
+// RollbackProduct godoc
+// @Summary Roll a product back to a revision
+// @Description Restore the fields of a product from one of its revisions. The rollback is validated like an update and recorded as a new revision.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param revision path int true "Revision number to restore"
+// @Success 200 {object} entities.Product
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/revisions/{revision}/rollback [post]
+func (h *ProductRevisionHandler) RollbackProduct(c *fiber.Ctx) error {
+	revision, err := revisionParam(c.Params("revision"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	product, err := h.service.RollbackProduct(c.Context(), c.Params("id"), revision)
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(product)
+}
+
+// revisionParam parses a revision number, which starts at 1
+func revisionParam(value string) (int64, error) {
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || revision < 1 {
+		return 0, errors.New("revision must be a positive integer")
+	}
+	return revision, nil
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"strings"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProductTranslationHandler handles HTTP requests for the localized text of products
+type ProductTranslationHandler struct {
+	service *application.ProductService
+}
+
+// NewProductTranslationHandler creates a new instance of ProductTranslationHandler
+func NewProductTranslationHandler(service *application.ProductService) *ProductTranslationHandler {
+	return &ProductTranslationHandler{service: service}
+}
+
+// ListTranslations godoc
+// @Summary List the translations of a product
+// @Description Retrieve the name and description of a product in every locale it has text in, keyed by BCP 47 tag, the default locale included
+// @Tags translations
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} map[string]entities.ProductTranslation
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/translations [get]
+func (h *ProductTranslationHandler) ListTranslations(c *fiber.Ctx) error {
+	translations, err := h.service.GetProductTranslations(c.Context(), c.Params("id"))
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(translations)
+}
+
+// SetTranslation godoc
+// @Summary Set the translation of a product in a locale
+// @Description Set the name and description of a product in a BCP 47 locale such as id-ID. For the default locale, this replaces the name and description of the product itself. Empty fields fall back to the next locale of the fallback chain.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param locale path string true "BCP 47 language tag"
+// @Param translation body entities.ProductTranslation true "Name and description in the locale"
+// @Success 204
+// @Failure 400 {object} problemDetails
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/translations/{locale} [put]
+func (h *ProductTranslationHandler) SetTranslation(c *fiber.Ctx) error {
+	var translation entities.ProductTranslation
+	if err := c.BodyParser(&translation); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if err := h.service.SetProductTranslation(c.Context(), c.Params("id"), c.Params("locale"), translation); err != nil {
+		return productError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteTranslation godoc
+// @Summary Delete the translation of a product in a locale
+// @Description Remove the name and description of a product in a locale, which then falls back to the next locale of its fallback chain. The default locale cannot be deleted.
+// @Tags translations
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param locale path string true "BCP 47 language tag"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/translations/{locale} [delete]
+func (h *ProductTranslationHandler) DeleteTranslation(c *fiber.Ctx) error {
+	if err := h.service.DeleteProductTranslation(c.Context(), c.Params("id"), c.Params("locale")); err != nil {
+		return productError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListMissingTranslations godoc
+// @Summary Report missing translations
+// @Description List, for every product and locale, the text fields the product has no translation for. A description is only missing when the product has one in the default locale.
+// @Tags translations
+// @Produce json
+// @Param locales query string false "Comma separated BCP 47 tags to check, defaults to the configured locales"
+// @Success 200 {array} entities.MissingTranslation
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/translations/missing [get]
+func (h *ProductTranslationHandler) ListMissingTranslations(c *fiber.Ctx) error {
+	var locales []string
+	if param := c.Query("locales"); param != "" {
+		for _, locale := range strings.Split(param, ",") {
+			locales = append(locales, strings.TrimSpace(locale))
+		}
+	}
+
+	missing, err := h.service.MissingTranslations(c.Context(), locales)
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(missing)
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"errors"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProductTypeHandler handles HTTP requests for product types
+type ProductTypeHandler struct {
+	service *application.ProductTypeService
+}
+
+// NewProductTypeHandler creates a new instance of ProductTypeHandler
+func NewProductTypeHandler(service *application.ProductTypeService) *ProductTypeHandler {
+	return &ProductTypeHandler{service: service}
+}
+
+// productTypeRequest is the body accepted when creating or updating a product type
+type productTypeRequest struct {
+	Name       string                         `json:"name"`
+	Attributes []entities.AttributeDefinition `json:"attributes"`
+}
+
+// CreateProductType godoc
+// @Summary Create a product type
+// @Description Create a product type with typed attribute definitions. Types are string, number, enum, boolean and date.
+// @Tags product-types
+// @Accept json
+// @Produce json
+// @Param productType body productTypeRequest true "Product type details"
+// @Success 201 {object} entities.ProductType
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/product-types [post]
+func (h *ProductTypeHandler) CreateProductType(c *fiber.Ctx) error {
+	var req productTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	productType, err := h.service.CreateProductType(c.Context(), req.Name, req.Attributes)
+	if err != nil {
+		return productTypeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(productType)
+}
+
+// GetProductType godoc
+// @Summary Get a product type by ID
+// @Description Retrieve a product type and its attribute definitions
+// @Tags product-types
+// @Produce json
+// @Param id path string true "Product type ID"
+// @Success 200 {object} entities.ProductType
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/product-types/{id} [get]
+func (h *ProductTypeHandler) GetProductType(c *fiber.Ctx) error {
+	productType, err := h.service.GetProductType(c.Context(), c.Params("id"))
+	if err != nil {
+		return productTypeError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(productType)
+}
+
+// UpdateProductType godoc
+// @Summary Update a product type
+// @Description Replace the name and attribute definitions of a product type
+// @Tags product-types
+// @Accept json
+// @Produce json
+// @Param id path string true "Product type ID"
+// @Param productType body productTypeRequest true "Updated product type details"
+// @Success 200 {object} entities.ProductType
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/product-types/{id} [put]
+func (h *ProductTypeHandler) UpdateProductType(c *fiber.Ctx) error {
+	var req productTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	productType, err := h.service.UpdateProductType(c.Context(), c.Params("id"), req.Name, req.Attributes)
+	if err != nil {
+		return productTypeError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(productType)
+}
+
+// DeleteProductType godoc
+// @Summary Delete a product type
+// @Description Delete a product type that no product belongs to
+// @Tags product-types
+// @Produce json
+// @Param id path string true "Product type ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/product-types/{id} [delete]
+func (h *ProductTypeHandler) DeleteProductType(c *fiber.Ctx) error {
+	if err := h.service.DeleteProductType(c.Context(), c.Params("id")); err != nil {
+		return productTypeError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListProductTypes godoc
+// @Summary List product types
+// @Description Retrieve every product type
+// @Tags product-types
+// @Produce json
+// @Success 200 {array} entities.ProductType
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/product-types [get]
+func (h *ProductTypeHandler) ListProductTypes(c *fiber.Ctx) error {
+	productTypes, err := h.service.ListProductTypes(c.Context())
+	if err != nil {
+		return productTypeError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(productTypes)
+}
+
+// productTypeError maps product type service errors to HTTP responses
+func productTypeError(c *fiber.Ctx, err error) error {
+	var attributeErr *usecases.AttributeError
+	switch {
+	case errors.As(err, &attributeErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "violations": attributeErr.Violations})
+	case errors.Is(err, ports.ErrProductTypeNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidProductTypeName):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrProductTypeInUse):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
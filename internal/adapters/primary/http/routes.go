@@ -4,10 +4,147 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupRoutes(app *fiber.App, handler *ProductHandler) {
+func SetupRoutes(app *fiber.App, handler *ProductHandler, eventsHandler *ProductEventsHandler) {
+	// Change feed routes are registered first so they are not captured by /:id
+	app.Get("/api/v1/products/events", eventsHandler.StreamEvents)
+	app.Get("/api/v1/products/ws", eventsHandler.UpgradeEvents, eventsHandler.SocketEvents())
+
 	app.Post("/api/v1/products", handler.CreateProduct)
 	app.Get("/api/v1/products/:id", handler.GetProductByID)
 	app.Put("/api/v1/products/:id", handler.UpdateProduct)
 	app.Delete("/api/v1/products/:id", handler.DeleteProduct)
 	app.Get("/api/v1/products", handler.ListProducts)
 }
+
+func SetupProductRevisionRoutes(app *fiber.App, handler *ProductRevisionHandler) {
+	// The diff route is registered first so it is not captured by /:revision
+	app.Get("/api/v1/products/:id/revisions/diff", handler.DiffRevisions)
+	app.Get("/api/v1/products/:id/revisions", handler.ListRevisions)
+	app.Get("/api/v1/products/:id/revisions/:revision", handler.GetRevision)
+	app.Post("/api/v1/products/:id/revisions/:revision/rollback", handler.RollbackProduct)
+}
+
+func SetupProductTranslationRoutes(app *fiber.App, handler *ProductTranslationHandler) {
+	app.Get("/api/v1/products/translations/missing", handler.ListMissingTranslations)
+	app.Get("/api/v1/products/:id/translations", handler.ListTranslations)
+	app.Put("/api/v1/products/:id/translations/:locale", handler.SetTranslation)
+	app.Delete("/api/v1/products/:id/translations/:locale", handler.DeleteTranslation)
+}
+
+func SetupProductLifecycleRoutes(app *fiber.App, handler *ProductLifecycleHandler) {
+	app.Post("/api/v1/products/:id/transitions", handler.TransitionProduct)
+	app.Put("/api/v1/products/:id/activation-schedule", handler.ScheduleProduct)
+}
+
+func SetupWebhookRoutes(app *fiber.App, handler *WebhookHandler) {
+	app.Post("/api/v1/webhooks", handler.CreateWebhook)
+	app.Get("/api/v1/webhooks/:id", handler.GetWebhook)
+	app.Put("/api/v1/webhooks/:id", handler.UpdateWebhook)
+	app.Delete("/api/v1/webhooks/:id", handler.DeleteWebhook)
+	app.Get("/api/v1/webhooks", handler.ListWebhooks)
+	app.Get("/api/v1/webhooks/:id/deliveries", handler.ListDeliveries)
+}
+
+func SetupInventoryRoutes(app *fiber.App, handler *InventoryHandler) {
+	app.Get("/api/v1/products/:id/inventory", handler.GetStock)
+	app.Post("/api/v1/products/:id/inventory/adjustments", handler.AdjustStock)
+	app.Put("/api/v1/products/:id/inventory/threshold", handler.SetLowStockThreshold)
+	app.Post("/api/v1/products/:id/inventory/reservations", handler.ReserveStock)
+	app.Get("/api/v1/reservations/:id", handler.GetReservation)
+	app.Post("/api/v1/reservations/:id/commit", handler.CommitReservation)
+	app.Post("/api/v1/reservations/:id/release", handler.ReleaseReservation)
+}
+
+func SetupCategoryRoutes(app *fiber.App, handler *CategoryHandler) {
+	app.Post("/api/v1/categories", handler.CreateCategory)
+	app.Get("/api/v1/categories/:id", handler.GetCategory)
+	app.Put("/api/v1/categories/:id", handler.UpdateCategory)
+	app.Delete("/api/v1/categories/:id", handler.DeleteCategory)
+	app.Get("/api/v1/categories", handler.ListCategories)
+	app.Post("/api/v1/categories/:id/move", handler.MoveCategory)
+	app.Get("/api/v1/categories/:id/breadcrumb", handler.GetBreadcrumb)
+	app.Get("/api/v1/categories/:id/descendants", handler.ListDescendants)
+	app.Get("/api/v1/categories/:id/products", handler.ListCategoryProducts)
+	app.Put("/api/v1/products/:id/categories", handler.AssignProductCategories)
+}
+
+func SetupVariantRoutes(app *fiber.App, handler *VariantHandler) {
+	app.Get("/api/v1/products/:id/variants", handler.GetProductWithVariants)
+	app.Patch("/api/v1/products/:id/variants", handler.UpdateVariants)
+	app.Post("/api/v1/products/:id/variants/generate", handler.GenerateVariants)
+}
+
+func SetupProductTypeRoutes(app *fiber.App, handler *ProductTypeHandler) {
+	app.Post("/api/v1/product-types", handler.CreateProductType)
+	app.Get("/api/v1/product-types/:id", handler.GetProductType)
+	app.Put("/api/v1/product-types/:id", handler.UpdateProductType)
+	app.Delete("/api/v1/product-types/:id", handler.DeleteProductType)
+	app.Get("/api/v1/product-types", handler.ListProductTypes)
+}
+
+func SetupStorageRoutes(app *fiber.App, handler *StorageHandler) {
+	app.Get("/api/v1/admin/storage/backup", handler.Backup)
+	app.Post("/api/v1/admin/storage/compact", handler.Compact)
+}
+
+func SetupPriceScheduleRoutes(app *fiber.App, handler *PriceScheduleHandler) {
+	app.Post("/api/v1/products/:id/price-schedules", handler.CreateSchedule)
+	app.Get("/api/v1/products/:id/price-schedules", handler.ListSchedules)
+	app.Get("/api/v1/price-schedules/:id", handler.GetSchedule)
+	app.Put("/api/v1/price-schedules/:id", handler.UpdateSchedule)
+	app.Delete("/api/v1/price-schedules/:id", handler.DeleteSchedule)
+}
+
+func SetupPriceListRoutes(app *fiber.App, handler *PriceListHandler) {
+	app.Post("/api/v1/price-lists", handler.CreatePriceList)
+	app.Get("/api/v1/price-lists", handler.ListPriceLists)
+	app.Get("/api/v1/price-lists/:id", handler.GetPriceList)
+	app.Put("/api/v1/price-lists/:id", handler.UpdatePriceList)
+	app.Delete("/api/v1/price-lists/:id", handler.DeletePriceList)
+	app.Put("/api/v1/price-lists/:id/prices/:productId", handler.SetPrice)
+	app.Delete("/api/v1/price-lists/:id/prices/:productId", handler.DeletePrice)
+}
+
+func SetupExchangeRateRoutes(app *fiber.App, handler *ExchangeRateHandler) {
+	app.Get("/api/v1/exchange-rates/history", handler.RateHistory)
+	app.Get("/api/v1/exchange-rates", handler.ListRates)
+	app.Post("/api/v1/admin/exchange-rates/import", handler.ImportRates)
+	app.Post("/api/v1/admin/exchange-rates/refresh", handler.RefreshRates)
+}
+
+func SetupBundleRoutes(app *fiber.App, handler *BundleHandler) {
+	app.Get("/api/v1/products/:id/bundle", handler.GetBundle)
+	app.Put("/api/v1/products/:id/bundle", handler.SetBundle)
+	app.Delete("/api/v1/products/:id/bundle", handler.RemoveBundle)
+}
+
+func SetupTaxRoutes(app *fiber.App, handler *TaxHandler) {
+	app.Get("/api/v1/products/:id/price-quote", handler.QuotePrice)
+	app.Put("/api/v1/products/:id/tax-class", handler.SetTaxClass)
+	app.Get("/api/v1/tax-rates", handler.ListRates)
+	app.Get("/api/v1/tax-rates/:id", handler.GetRate)
+	app.Post("/api/v1/admin/tax-rates", handler.CreateRate)
+	app.Delete("/api/v1/admin/tax-rates/:id", handler.DeleteRate)
+}
+
+func SetupAPIKeyRoutes(app *fiber.App, handler *APIKeyHandler) {
+	app.Post("/api/v1/admin/api-keys", handler.CreateAPIKey)
+	app.Get("/api/v1/admin/api-keys", handler.ListAPIKeys)
+	app.Get("/api/v1/admin/api-keys/:id", handler.GetAPIKey)
+	app.Post("/api/v1/admin/api-keys/:id/rotate", handler.RotateAPIKey)
+	app.Delete("/api/v1/admin/api-keys/:id", handler.RevokeAPIKey)
+}
+
+func SetupAuditRoutes(app *fiber.App, handler *AuditHandler) {
+	app.Get("/api/v1/audit", handler.ListEntries)
+	app.Get("/api/v1/audit/export", handler.ExportEntries)
+	app.Get("/api/v1/audit/verify", handler.VerifyChain)
+}
+
+func SetupMediaRoutes(app *fiber.App, handler *MediaHandler) {
+	app.Post("/api/v1/products/:id/media", handler.UploadMedia)
+	app.Get("/api/v1/products/:id/media", handler.ListMedia)
+	app.Put("/api/v1/products/:id/media/order", handler.ReorderMedia)
+	app.Delete("/api/v1/products/:id/media/:mediaId", handler.DeleteMedia)
+	app.Get("/api/v1/products/:id/media/:mediaId/content", handler.GetMediaContent)
+}
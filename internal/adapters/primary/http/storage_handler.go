@@ -0,0 +1,65 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StorageHandler handles HTTP requests for storage maintenance
+type StorageHandler struct {
+	maintenance ports.StorageMaintenance
+}
+
+// NewStorageHandler creates a new instance of StorageHandler
+func NewStorageHandler(maintenance ports.StorageMaintenance) *StorageHandler {
+	return &StorageHandler{maintenance: maintenance}
+}
+
+// Backup godoc
+// @Summary Download a storage backup
+// @Description Stream a consistent snapshot of the embedded database file while the service keeps serving requests. Only available with STORAGE=bolt.
+// @Tags storage
+// @Produce octet-stream
+// @Success 200 {file} file
+// @Router /api/v1/admin/storage/backup [get]
+func (h *StorageHandler) Backup(c *fiber.Ctx) error {
+	filename := fmt.Sprintf("catalog-%s.db", time.Now().UTC().Format("20060102T150405Z"))
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	// The snapshot is written after the handler returns, so a failure can only be logged
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		written, err := h.maintenance.Backup(context.Background(), w)
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("Storage backup failed after %d bytes: %v", written, err)
+			return
+		}
+		log.Printf("Storage backup of %d bytes sent", written)
+	})
+	return nil
+}
+
+// Compact godoc
+// @Summary Compact the storage
+// @Description Rewrite the embedded database file without free pages. Requests wait while the file is swapped. Only available with STORAGE=bolt.
+// @Tags storage
+// @Success 204
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/storage/compact [post]
+func (h *StorageHandler) Compact(c *fiber.Ctx) error {
+	if err := h.maintenance.Compact(c.Context()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"errors"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TaxHandler handles HTTP requests for tax rates, the tax classes of products and price quotes
+type TaxHandler struct {
+	service  *application.TaxService
+	products *application.ProductService
+}
+
+// NewTaxHandler creates a new instance of TaxHandler
+func NewTaxHandler(service *application.TaxService, products *application.ProductService) *TaxHandler {
+	return &TaxHandler{
+		service:  service,
+		products: products,
+	}
+}
+
+// taxClassRequest is the body of a tax class assignment
+type taxClassRequest struct {
+	TaxClass string `json:"tax_class"`
+}
+
+// QuotePrice godoc
+// @Summary Quote the price of a product with tax
+// @Description Compute the net, tax and gross amounts of a quantity of a product in a region, with the rate of the tax class of the product in effect now in the region or else in its country. Amounts are rounded to the minor unit of the currency with the configured rounding mode; whether product prices include tax is configured with PRICES_INCLUDE_TAX.
+// @Tags taxes
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param region query string true "ISO 3166-1 country code, optionally with an ISO 3166-2 subdivision such as US-CA"
+// @Param quantity query int false "Number of units, defaults to 1"
+// @Param price_list query string false "Code of the price list to quote the price of"
+// @Param currency query string false "ISO 4217 currency to quote the price in"
+// @Success 200 {object} entities.PriceQuote
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/price-quote [get]
+func (h *TaxHandler) QuotePrice(c *fiber.Ctx) error {
+	quote, err := h.service.QuotePrice(c.Context(), c.Params("id"), c.Query("region"), c.QueryInt("quantity", 1), priceQuery(c))
+	if errors.Is(err, ports.ErrTaxRateNotFound) {
+		// The request is well-formed, but the region has no rate for the tax class of the product
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return taxError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(quote)
+}
+
+// SetTaxClass godoc
+// @Summary Assign a tax class to a product
+// @Description Set the tax class the tax rates of a product are looked up with. An empty class resets the product to the standard class.
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param tax_class body taxClassRequest true "Tax class"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/tax-class [put]
+func (h *TaxHandler) SetTaxClass(c *fiber.Ctx) error {
+	var req taxClassRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if err := h.products.SetProductTaxClass(c.Context(), c.Params("id"), req.TaxClass); err != nil {
+		return taxError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListRates godoc
+// @Summary List tax rates
+// @Description Retrieve the tax rates of a jurisdiction, or of every jurisdiction, with the rates they replaced, ordered by jurisdiction, tax class and effective time
+// @Tags taxes
+// @Produce json
+// @Param jurisdiction query string false "ISO 3166-1 country code or subdivision such as US-CA"
+// @Success 200 {array} entities.TaxRate
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/tax-rates [get]
+func (h *TaxHandler) ListRates(c *fiber.Ctx) error {
+	rates, err := h.service.ListRates(c.Context(), c.Query("jurisdiction"))
+	if err != nil {
+		return taxError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rates)
+}
+
+// GetRate godoc
+// @Summary Get a tax rate
+// @Description Retrieve a tax rate by its ID
+// @Tags taxes
+// @Produce json
+// @Param id path string true "Tax rate ID"
+// @Success 200 {object} entities.TaxRate
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/tax-rates/{id} [get]
+func (h *TaxHandler) GetRate(c *fiber.Ctx) error {
+	rate, err := h.service.GetRate(c.Context(), c.Params("id"))
+	if err != nil {
+		return taxError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rate)
+}
+
+// CreateRate godoc
+// @Summary Create a tax rate
+// @Description Add the rate of a tax class in a jurisdiction from its effective time on. The rate it replaces is kept, so quotes before that time still use it. Rates are shared by every tenant.
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Param rate body entities.TaxRate true "Tax rate"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/tax-rates [post]
+func (h *TaxHandler) CreateRate(c *fiber.Ctx) error {
+	var rate entities.TaxRate
+	if err := c.BodyParser(&rate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	id, err := h.service.CreateRate(c.Context(), &rate)
+	if err != nil {
+		return taxError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id})
+}
+
+// DeleteRate godoc
+// @Summary Delete a tax rate
+// @Description Remove a tax rate, so the rate it replaced stays in effect
+// @Tags taxes
+// @Produce json
+// @Param id path string true "Tax rate ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/tax-rates/{id} [delete]
+func (h *TaxHandler) DeleteRate(c *fiber.Ctx) error {
+	if err := h.service.DeleteRate(c.Context(), c.Params("id")); err != nil {
+		return taxError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// taxError maps tax service errors to HTTP responses, and product errors as productError does
+func taxError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidRegion), errors.Is(err, usecases.ErrInvalidTaxClass),
+		errors.Is(err, application.ErrInvalidTaxRate), errors.Is(err, application.ErrInvalidQuantity):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, ports.ErrTaxRateNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrTaxRateExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return productError(c, err)
+	}
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VariantHandler handles HTTP requests for product variants
+type VariantHandler struct {
+	service *application.VariantService
+}
+
+// NewVariantHandler creates a new instance of VariantHandler
+func NewVariantHandler(service *application.VariantService) *VariantHandler {
+	return &VariantHandler{service: service}
+}
+
+// generateVariantsRequest is the body accepted when generating the variant matrix of a product
+type generateVariantsRequest struct {
+	Options   []entities.ProductOption `json:"options"`
+	SKUPrefix string                   `json:"sku_prefix,omitempty"`
+}
+
+// variantUpdateRequest holds the changes to one variant; omitted fields are left as they are
+type variantUpdateRequest struct {
+	ID         string            `json:"id"`
+	SKU        *string           `json:"sku,omitempty"`
+	Price      *float32          `json:"price,omitempty"`
+	Barcode    *string           `json:"barcode,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// updateVariantsRequest is the body accepted when updating variants in bulk
+type updateVariantsRequest struct {
+	Variants []variantUpdateRequest `json:"variants"`
+}
+
+// productWithVariantsResponse is a product together with its variants
+type productWithVariantsResponse struct {
+	Product  *entities.Product          `json:"product"`
+	Variants []*entities.ProductVariant `json:"variants"`
+}
+
+// GetProductWithVariants godoc
+// @Summary Get a product with its variants
+// @Description Retrieve a product, its option definitions and every variant. Only active products are found, unless an API key with the admin scope asks for another lifecycle status.
+// @Tags variants
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param status query string false "Lifecycle status the product must be in, active by default; another status or any needs the admin scope"
+// @Success 200 {object} productWithVariantsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/variants [get]
+func (h *VariantHandler) GetProductWithVariants(c *fiber.Ctx) error {
+	product, variants, err := h.service.GetProductWithVariants(c.Context(), c.Params("id"), c.Query("status"))
+	if err != nil {
+		return variantError(c, err)
+	}
+
+	if variants == nil {
+		variants = []*entities.ProductVariant{}
+	}
+	return c.Status(fiber.StatusOK).JSON(productWithVariantsResponse{Product: product, Variants: variants})
+}
+
+// GenerateVariants godoc
+// @Summary Generate the variant matrix of a product
+// @Description Set the options of a product and create a variant for every combination of values. Existing combinations keep their SKU, price and barcode; combinations no longer present are deleted.
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param options body generateVariantsRequest true "Option definitions"
+// @Success 200 {array} entities.ProductVariant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/variants/generate [post]
+func (h *VariantHandler) GenerateVariants(c *fiber.Ctx) error {
+	var req generateVariantsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	variants, err := h.service.GenerateVariants(c.Context(), c.Params("id"), req.Options, req.SKUPrefix)
+	if err != nil {
+		return variantError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(variants)
+}
+
+// UpdateVariants godoc
+// @Summary Update variants in bulk
+// @Description Change the SKU, price override, barcode or attributes of several variants of a product at once. Nothing is changed if any entry is invalid.
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param variants body updateVariantsRequest true "Variant changes"
+// @Success 200 {array} entities.ProductVariant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/products/{id}/variants [patch]
+func (h *VariantHandler) UpdateVariants(c *fiber.Ctx) error {
+	var req updateVariantsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	updates := make([]application.VariantUpdate, len(req.Variants))
+	for i, variant := range req.Variants {
+		updates[i] = application.VariantUpdate{
+			ID:         variant.ID,
+			SKU:        variant.SKU,
+			Price:      variant.Price,
+			Barcode:    variant.Barcode,
+			Attributes: variant.Attributes,
+		}
+	}
+
+	variants, err := h.service.UpdateVariants(c.Context(), c.Params("id"), updates)
+	if err != nil {
+		return variantError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(variants)
+}
+
+// variantError maps variant service errors to HTTP responses
+func variantError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrProductNotFound), errors.Is(err, ports.ErrVariantNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidOptions), errors.Is(err, application.ErrTooManyVariants),
+		errors.Is(err, application.ErrInvalidSKU), errors.Is(err, application.ErrInvalidPrice),
+		errors.Is(err, usecases.ErrInvalidProductStatus):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, ports.ErrDuplicateSKU):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrAdminScopeRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"errors"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscription operations
+type WebhookHandler struct {
+	service *application.WebhookService
+}
+
+// NewWebhookHandler creates a new instance of WebhookHandler
+func NewWebhookHandler(service *application.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// webhookRequest is the body accepted when creating or updating a webhook subscription
+type webhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook subscription
+// @Description Subscribe a URL to product events. The signing secret is only returned by this call.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body webhookRequest true "Webhook details"
+// @Success 201 {object} entities.WebhookSubscription
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req webhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	webhook, err := h.service.CreateWebhook(c.Context(), req.URL, req.Secret, req.EventTypes)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(webhook)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook subscription by ID
+// @Description Retrieve a webhook subscription by its ID
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} entities.WebhookSubscription
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	webhook, err := h.service.GetWebhook(c.Context(), c.Params("id"))
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(redactSecret(webhook))
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription
+// @Description Change the URL, event types or active flag of a webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param webhook body webhookRequest true "Updated webhook details"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	var req webhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	if err := h.service.UpdateWebhook(c.Context(), c.Params("id"), req.URL, req.EventTypes, active); err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription by ID
+// @Description Delete a webhook subscription by its ID
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	if err := h.service.DeleteWebhook(c.Context(), c.Params("id")); err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListWebhooks godoc
+// @Summary List all webhook subscriptions
+// @Description Retrieve a list of all webhook subscriptions
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} entities.WebhookSubscription
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	webhooks, err := h.service.ListWebhooks(c.Context())
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	for i, webhook := range webhooks {
+		webhooks[i] = redactSecret(webhook)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(webhooks)
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Retrieve the latest delivery attempts for a webhook subscription, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Maximum number of deliveries" default(50)
+// @Success 200 {array} entities.WebhookDelivery
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	deliveries, err := h.service.ListDeliveries(c.Context(), c.Params("id"), int64(c.QueryInt("limit")))
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(deliveries)
+}
+
+// redactSecret returns a copy of the subscription without its signing secret
+func redactSecret(webhook *entities.WebhookSubscription) *entities.WebhookSubscription {
+	redacted := *webhook
+	redacted.Secret = ""
+	return &redacted
+}
+
+// webhookError maps webhook service errors to HTTP responses
+func webhookError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ports.ErrWebhookNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Webhook not found"})
+	case errors.Is(err, application.ErrInvalidWebhookURL), errors.Is(err, application.ErrInvalidEventType):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
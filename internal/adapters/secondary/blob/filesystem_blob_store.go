@@ -0,0 +1,143 @@
+// Package blob implements the ports.BlobStore interface on a local directory and on
+// S3-compatible object storage.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"test-go/internal/core/ports"
+)
+
+// defaultContentType is reported for blobs whose content type was lost
+const defaultContentType = "application/octet-stream"
+
+// FilesystemBlobStore implements the ports.BlobStore interface on a local directory. The content
+// of a key is kept under blobs/<key> and its content type under types/<key>. Blobs are written to
+// a temporary file first and renamed into place, so readers never see a partial blob.
+type FilesystemBlobStore struct {
+	root string
+}
+
+// NewFilesystemBlobStore creates a blob store keeping its files under root, creating it if needed
+func NewFilesystemBlobStore(root string) (ports.BlobStore, error) {
+	for _, dir := range []string{"blobs", "types"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &FilesystemBlobStore{root: root}, nil
+}
+
+// Put writes the blob and its content type
+func (s *FilesystemBlobStore) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error {
+	if !ports.ValidBlobKey(key) {
+		return ports.ErrInvalidBlobKey
+	}
+
+	if err := s.writeFile(s.typePath(key), func(f *os.File) error {
+		_, err := f.WriteString(contentType)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return s.writeFile(s.blobPath(key), func(f *os.File) error {
+		written, err := io.Copy(f, io.LimitReader(body, size))
+		if err != nil {
+			return err
+		}
+		if written != size {
+			return fmt.Errorf("blob %s: read %d of %d bytes", key, written, size)
+		}
+		return nil
+	})
+}
+
+// Get opens the blob for reading
+func (s *FilesystemBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, ports.BlobInfo, error) {
+	if !ports.ValidBlobKey(key) {
+		return nil, ports.BlobInfo{}, ports.ErrInvalidBlobKey
+	}
+
+	f, err := os.Open(s.blobPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ports.BlobInfo{}, ports.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, ports.BlobInfo{}, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ports.BlobInfo{}, err
+	}
+
+	info := ports.BlobInfo{Size: stat.Size(), ContentType: defaultContentType}
+	if contentType, err := os.ReadFile(s.typePath(key)); err == nil && len(contentType) > 0 {
+		info.ContentType = string(contentType)
+	}
+	return f, info, nil
+}
+
+// Delete removes the blob and its content type
+func (s *FilesystemBlobStore) Delete(ctx context.Context, key string) error {
+	if !ports.ValidBlobKey(key) {
+		return ports.ErrInvalidBlobKey
+	}
+
+	for _, path := range []string{s.blobPath(key), s.typePath(key)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exists reports whether a blob is stored under key
+func (s *FilesystemBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	if !ports.ValidBlobKey(key) {
+		return false, ports.ErrInvalidBlobKey
+	}
+
+	_, err := os.Stat(s.blobPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// blobPath returns the file holding the content of key
+func (s *FilesystemBlobStore) blobPath(key string) string {
+	return filepath.Join(s.root, "blobs", filepath.FromSlash(key))
+}
+
+// typePath returns the file holding the content type of key
+func (s *FilesystemBlobStore) typePath(key string) string {
+	return filepath.Join(s.root, "types", filepath.FromSlash(key))
+}
+
+// writeFile writes a temporary file next to path with write and renames it to path
+func (s *FilesystemBlobStore) writeFile(path string, write func(*os.File) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// apiKeyPrefix namespaces verified API keys in Redis, keyed by the hash of the key
+const apiKeyPrefix = "apikey:"
+
+// RedisAPIKeyCache implements the ports.APIKeyCache interface on top of Redis
+type RedisAPIKeyCache struct {
+	client *redis.Client
+}
+
+// NewRedisAPIKeyCache creates a new instance of RedisAPIKeyCache
+func NewRedisAPIKeyCache(client *redis.Client) ports.APIKeyCache {
+	return &RedisAPIKeyCache{
+		client: client,
+	}
+}
+
+// Get reads an API key stored as JSON
+func (c *RedisAPIKeyCache) Get(ctx context.Context, hash string) (*entities.APIKey, error) {
+	val, err := c.client.Get(ctx, apiKeyPrefix+hash).Bytes()
+	if err == redis.Nil {
+		return nil, ports.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &entities.APIKey{}
+	if err := json.Unmarshal(val, key); err != nil {
+		return nil, err
+	}
+	// The hash is never encoded, it is the cache key
+	key.Hash = hash
+
+	return key, nil
+}
+
+// Set stores an API key as JSON for ttl
+func (c *RedisAPIKeyCache) Set(ctx context.Context, key *entities.APIKey, ttl time.Duration) error {
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, apiKeyPrefix+key.Hash, keyJSON, ttl).Err()
+}
+
+// Delete removes a cached API key
+func (c *RedisAPIKeyCache) Delete(ctx context.Context, hash string) error {
+	return c.client.Del(ctx, apiKeyPrefix+hash).Err()
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"test-go/internal/core/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// idempotencyKeyPrefix namespaces idempotency records in Redis
+const idempotencyKeyPrefix = "idempotency:"
+
+// RedisIdempotencyStore implements the ports.IdempotencyStore interface on top of Redis
+type RedisIdempotencyStore struct {
+	client *redis.Client
+}
+
+// NewRedisIdempotencyStore creates a new instance of RedisIdempotencyStore
+func NewRedisIdempotencyStore(client *redis.Client) ports.IdempotencyStore {
+	return &RedisIdempotencyStore{
+		client: client,
+	}
+}
+
+// Reserve atomically claims the key with SETNX, returning the stored record if someone else holds it
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, key string, requestHash string, lockTTL time.Duration) (*ports.IdempotencyRecord, error) {
+	pending, err := json.Marshal(&ports.IdempotencyRecord{RequestHash: requestHash})
+	if err != nil {
+		return nil, err
+	}
+
+	// Loop once more if the existing record expires between SETNX and GET
+	for i := 0; i < 2; i++ {
+		ok, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, pending, lockTTL).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return nil, nil
+		}
+
+		val, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := &ports.IdempotencyRecord{}
+		if err := json.Unmarshal(val, record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+
+	return nil, redis.TxFailedErr
+}
+
+// Complete overwrites the pending record with the final response
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, key string, record *ports.IdempotencyRecord, ttl time.Duration) error {
+	record.Completed = true
+	val, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, idempotencyKeyPrefix+key, val, ttl).Err()
+}
+
+// Release deletes the record so the key can be used again
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
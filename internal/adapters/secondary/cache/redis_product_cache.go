@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// productKeyPrefix namespaces cached products in Redis
+const productKeyPrefix = "product:"
+
+// productKey returns the Redis key of a product of the tenant carried by ctx, e.g. "product:acme:<id>"
+func productKey(ctx context.Context, id string) string {
+	return productKeyPrefix + ports.TenantFromContext(ctx) + ":" + id
+}
+
+// RedisProductCache implements the ports.ProductCache interface on top of Redis
+type RedisProductCache struct {
+	client *redis.Client
+}
+
+// NewRedisProductCache creates a new instance of RedisProductCache
+func NewRedisProductCache(client *redis.Client) ports.ProductCache {
+	return &RedisProductCache{
+		client: client,
+	}
+}
+
+// Get reads a product stored as JSON
+func (c *RedisProductCache) Get(ctx context.Context, id string) (*entities.Product, error) {
+	val, err := c.client.Get(ctx, productKey(ctx, id)).Bytes()
+	if err == redis.Nil {
+		return nil, ports.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	product := &entities.Product{}
+	if err := json.Unmarshal(val, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Set stores a product as JSON for ttl, or without expiry when ttl is zero
+func (c *RedisProductCache) Set(ctx context.Context, product *entities.Product, ttl time.Duration) error {
+	productJSON, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, productKey(ctx, product.ID.Hex()), productJSON, ttl).Err()
+}
+
+// Delete removes a cached product
+func (c *RedisProductCache) Delete(ctx context.Context, id string) error {
+	return c.client.Del(ctx, productKey(ctx, id)).Err()
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"test-go/internal/core/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// rateLimitKeyPrefix namespaces token buckets in Redis
+const rateLimitKeyPrefix = "ratelimit:"
+
+// takeTokenScript refills and takes from a bucket atomically, using the Redis clock so replicas
+// with skewed clocks agree. Buckets expire once they would be full again.
+var takeTokenScript = redis.NewScript(`
+redis.replicate_commands()
+local rate = tonumber(ARGV[1]) / 1000
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisRateLimiter implements the ports.RateLimiter interface on top of Redis, so every replica
+// shares the same buckets
+type RedisRateLimiter struct {
+	client   *redis.Client
+	fallback ports.RateLimiter
+}
+
+// NewRedisRateLimiter creates a new instance of RedisRateLimiter. Requests are limited by fallback
+// while Redis is unavailable, so clients stay limited per replica instead of not at all.
+func NewRedisRateLimiter(client *redis.Client, fallback ports.RateLimiter) ports.RateLimiter {
+	return &RedisRateLimiter{
+		client:   client,
+		fallback: fallback,
+	}
+}
+
+// Take removes a token from the bucket of key
+func (l *RedisRateLimiter) Take(ctx context.Context, key string, limit ports.RateLimit) (*ports.RateLimitResult, error) {
+	rate := strconv.FormatFloat(limit.RefillRate(), 'f', -1, 64)
+	reply, err := takeTokenScript.Run(ctx, l.client, []string{rateLimitKeyPrefix + key}, rate, limit.Burst).Slice()
+	if err == nil && len(reply) != 2 {
+		err = redis.Nil
+	}
+	if err != nil {
+		log.Printf("Failed to take rate limit token from Redis, limiting in memory: %v", err)
+		return l.fallback.Take(ctx, key, limit)
+	}
+
+	allowed, _ := reply[0].(int64)
+	tokensStr, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return l.fallback.Take(ctx, key, limit)
+	}
+
+	return limit.Result(allowed == 1, tokens), nil
+}
@@ -0,0 +1,66 @@
+package exchangerates
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"test-go/internal/core/ports"
+)
+
+const (
+	// fetchTimeout bounds a single request to the exchange rate API
+	fetchTimeout = 30 * time.Second
+	// maxResponseBytes bounds the rate tables read from the API
+	maxResponseBytes = 1 << 20
+)
+
+// HTTPExchangeRateSource implements the ports.ExchangeRateSource interface by getting a JSON rate
+// table from a URL, such as the latest rates endpoint of an exchange rate API
+type HTTPExchangeRateSource struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPExchangeRateSource creates a source getting the rate table from rawURL
+func NewHTTPExchangeRateSource(rawURL string) (ports.ExchangeRateSource, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("invalid exchange rate URL %q", rawURL)
+	}
+
+	return &HTTPExchangeRateSource{
+		url:    rawURL,
+		client: &http.Client{Timeout: fetchTimeout},
+	}, nil
+}
+
+// Fetch gets the current rate table
+func (s *HTTPExchangeRateSource) Fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate API %s: %s", s.Name(), resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+}
+
+// Name returns the URL of the rate table, without its query string, which may carry an API key
+func (s *HTTPExchangeRateSource) Name() string {
+	parsed, _ := url.Parse(s.url)
+	parsed.RawQuery = ""
+	return parsed.String()
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// APIKeyCache implements the ports.APIKeyCache interface in memory. Keys are kept as JSON, like
+// the Redis cache, and expired entries are overwritten when the key is cached again.
+type APIKeyCache struct {
+	mu   sync.RWMutex
+	keys map[string]cachedAPIKey
+}
+
+// cachedAPIKey is an API key encoded as JSON with its expiry
+type cachedAPIKey struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewAPIKeyCache creates a new instance of APIKeyCache
+func NewAPIKeyCache() ports.APIKeyCache {
+	return &APIKeyCache{
+		keys: make(map[string]cachedAPIKey),
+	}
+}
+
+// Get decodes a cached API key that has not expired
+func (c *APIKeyCache) Get(ctx context.Context, hash string) (*entities.APIKey, error) {
+	c.mu.RLock()
+	cached, ok := c.keys[hash]
+	c.mu.RUnlock()
+	if !ok || !time.Now().Before(cached.expiresAt) {
+		return nil, ports.ErrCacheMiss
+	}
+
+	key := &entities.APIKey{}
+	if err := json.Unmarshal(cached.data, key); err != nil {
+		return nil, err
+	}
+	key.Hash = hash
+
+	return key, nil
+}
+
+// Set stores an API key for ttl
+func (c *APIKeyCache) Set(ctx context.Context, key *entities.APIKey, ttl time.Duration) error {
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys[key.Hash] = cachedAPIKey{data: keyJSON, expiresAt: time.Now().Add(ttl)}
+
+	return nil
+}
+
+// Delete removes a cached API key
+func (c *APIKeyCache) Delete(ctx context.Context, hash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.keys, hash)
+
+	return nil
+}
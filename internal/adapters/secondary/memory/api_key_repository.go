@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyRepository implements the ports.APIKeyRepository interface in memory
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[primitive.ObjectID]*entities.APIKey
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository
+func NewAPIKeyRepository() ports.APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make(map[primitive.ObjectID]*entities.APIKey),
+	}
+}
+
+// Create stores a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) (string, error) {
+	key.ID = primitive.NewObjectID()
+	key.TenantID = ports.TenantFromContext(ctx)
+	key.CreatedAt = time.Now()
+	key.UpdatedAt = time.Now()
+
+	stored, err := clone(key)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[stored.ID] = stored
+
+	log.Printf("API key created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves an API key by its ID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*entities.APIKey, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrAPIKeyNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[objectID]
+	if !ok || !ownedBy(ctx, key.TenantID) {
+		return nil, ports.ErrAPIKeyNotFound
+	}
+	return clone(key)
+}
+
+// Update replaces an existing API key
+func (r *APIKeyRepository) Update(ctx context.Context, key *entities.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.keys[key.ID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrAPIKeyNotFound
+	}
+
+	key.TenantID = existing.TenantID
+	key.UpdatedAt = time.Now()
+	stored, err := clone(key)
+	if err != nil {
+		return err
+	}
+	r.keys[stored.ID] = stored
+
+	log.Printf("API key with ID: %s updated successfully", key.ID.Hex())
+	return nil
+}
+
+// FindAll retrieves every API key of the tenant in _id order
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []*entities.APIKey
+	for _, key := range r.keys {
+		if ownedBy(ctx, key.TenantID) {
+			keys = append(keys, key)
+		}
+	}
+	sortByID(keys, func(k *entities.APIKey) primitive.ObjectID { return k.ID })
+
+	return cloneAll(keys)
+}
+
+// FindByHash retrieves the API key with the given hash, whatever its tenant
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return clone(key)
+		}
+	}
+	return nil, ports.ErrAPIKeyNotFound
+}
+
+// TouchLastUsed records when an API key of any tenant was last used
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrAPIKeyNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[objectID]
+	if !ok {
+		return ports.ErrAPIKeyNotFound
+	}
+	key.LastUsedAt = &usedAt
+	return nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditRepository implements the ports.AuditRepository interface in memory
+type AuditRepository struct {
+	mu sync.RWMutex
+	// entries holds the audit log of each tenant in sequence order
+	entries map[string][]*entities.AuditEntry
+}
+
+// NewAuditRepository creates a new instance of AuditRepository
+func NewAuditRepository() ports.AuditRepository {
+	return &AuditRepository{
+		entries: make(map[string][]*entities.AuditEntry),
+	}
+}
+
+// Append seals the entry after the latest entry of the tenant and stores it
+func (r *AuditRepository) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := ports.TenantFromContext(ctx)
+	chain := r.entries[tenantID]
+
+	var prev *entities.AuditEntry
+	if len(chain) > 0 {
+		prev = chain[len(chain)-1]
+	}
+	entry.ID = primitive.NewObjectID()
+	entry.TenantID = tenantID
+	entry.Seal(prev)
+
+	stored, err := clone(entry)
+	if err != nil {
+		return err
+	}
+	r.entries[tenantID] = append(chain, stored)
+	return nil
+}
+
+// Find retrieves up to filter.Limit matching entries in sequence order
+func (r *AuditRepository) Find(ctx context.Context, filter ports.AuditFilter) ([]*entities.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []*entities.AuditEntry
+	for _, entry := range r.entries[ports.TenantFromContext(ctx)] {
+		if filter.Limit > 0 && int64(len(entries)) >= filter.Limit {
+			break
+		}
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return cloneAll(entries)
+}
+
+// Stream calls fn for every matching entry in sequence order. The log is copied first, so fn
+// may take its time without holding up writers.
+func (r *AuditRepository) Stream(ctx context.Context, filter ports.AuditFilter, fn func(*entities.AuditEntry) error) error {
+	filter.Limit = 0
+	entries, err := r.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategoryRepository implements the ports.CategoryRepository interface in memory using materialized paths
+type CategoryRepository struct {
+	mu         sync.RWMutex
+	categories map[primitive.ObjectID]*entities.Category
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository() ports.CategoryRepository {
+	return &CategoryRepository{
+		categories: make(map[primitive.ObjectID]*entities.Category),
+	}
+}
+
+// Create stores a new category.
+// The ID may be preset by the caller so the path can be computed before insertion.
+func (r *CategoryRepository) Create(ctx context.Context, category *entities.Category) (string, error) {
+	if category.ID.IsZero() {
+		category.ID = primitive.NewObjectID()
+	}
+	category.TenantID = ports.TenantFromContext(ctx)
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = time.Now()
+
+	stored, err := clone(category)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.categories[stored.ID] = stored
+
+	log.Printf("Category created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a category by its ID
+func (r *CategoryRepository) FindByID(ctx context.Context, id string) (*entities.Category, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrCategoryNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	category, ok := r.categories[objectID]
+	if !ok || !ownedBy(ctx, category.TenantID) {
+		return nil, ports.ErrCategoryNotFound
+	}
+	return clone(category)
+}
+
+// Update replaces an existing category
+func (r *CategoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.categories[category.ID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrCategoryNotFound
+	}
+
+	category.TenantID = existing.TenantID
+	category.UpdatedAt = time.Now()
+	stored, err := clone(category)
+	if err != nil {
+		return err
+	}
+	r.categories[stored.ID] = stored
+
+	return nil
+}
+
+// Delete removes a category by its ID
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrCategoryNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.categories[objectID]; !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrCategoryNotFound
+	}
+	delete(r.categories, objectID)
+
+	log.Printf("Category with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves every category ordered by depth, so parents come before their children
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entities.Category, error) {
+	return r.find(ctx, func(*entities.Category) bool { return true }, byDepthAndPosition)
+}
+
+// FindByIDs retrieves the categories with the given IDs
+func (r *CategoryRepository) FindByIDs(ctx context.Context, ids []string) ([]*entities.Category, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	return r.find(ctx, func(category *entities.Category) bool { return wanted[category.ID.Hex()] }, byDepthAndPosition)
+}
+
+// FindChildren retrieves the direct children of a category, or the roots for an empty parentID
+func (r *CategoryRepository) FindChildren(ctx context.Context, parentID string) ([]*entities.Category, error) {
+	return r.find(ctx, func(category *entities.Category) bool {
+		return category.ParentID == parentID
+	}, func(a, b *entities.Category) bool {
+		return a.Position < b.Position
+	})
+}
+
+// FindDescendants retrieves every category whose path starts with the given path
+func (r *CategoryRepository) FindDescendants(ctx context.Context, path string) ([]*entities.Category, error) {
+	return r.find(ctx, func(category *entities.Category) bool {
+		return isBelow(category.Path, path)
+	}, byDepthAndPosition)
+}
+
+// MoveDescendants replaces the oldPath prefix of every descendant path with newPath
+func (r *CategoryRepository) MoveDescendants(ctx context.Context, oldPath string, newPath string, depthDelta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, category := range r.categories {
+		if !ownedBy(ctx, category.TenantID) || !isBelow(category.Path, oldPath) {
+			continue
+		}
+
+		moved := *category
+		moved.Path = newPath + strings.TrimPrefix(category.Path, oldPath)
+		moved.Depth += depthDelta
+		moved.UpdatedAt = now
+		r.categories[id] = &moved
+	}
+
+	return nil
+}
+
+// find copies the categories of the tenant accepted by match, ordered by less and then by _id
+func (r *CategoryRepository) find(ctx context.Context, match func(*entities.Category) bool, less func(a, b *entities.Category) bool) ([]*entities.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var categories []*entities.Category
+	for _, category := range r.categories {
+		if ownedBy(ctx, category.TenantID) && match(category) {
+			categories = append(categories, category)
+		}
+	}
+	sortByID(categories, func(c *entities.Category) primitive.ObjectID { return c.ID })
+	sort.SliceStable(categories, func(i, j int) bool {
+		return less(categories[i], categories[j])
+	})
+
+	return cloneAll(categories)
+}
+
+// byDepthAndPosition orders parents before their children and siblings by position
+func byDepthAndPosition(a, b *entities.Category) bool {
+	if a.Depth != b.Depth {
+		return a.Depth < b.Depth
+	}
+	return a.Position < b.Position
+}
+
+// isBelow reports whether path lies strictly below ancestor in the tree
+func isBelow(path string, ancestor string) bool {
+	return path != ancestor && strings.HasPrefix(path, ancestor)
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// defaultChangeHistory is how many product changes are kept for resuming watches
+const defaultChangeHistory = 1000
+
+// ChangeStream implements the ports.ProductWatcher interface for the in-memory product repository.
+// Like a MongoDB change stream it keeps a bounded history, so a watch can resume from any token
+// still in that history; older tokens fail with ports.ErrInvalidResumeToken.
+type ChangeStream struct {
+	mu       sync.Mutex
+	history  []*entities.ProductEvent
+	capacity int
+	// sequence is the number of changes recorded so far and the token of the latest one
+	sequence uint64
+	// changed is closed and replaced whenever a change is recorded, waking every watcher
+	changed chan struct{}
+}
+
+// NewChangeStream creates a change stream remembering the last capacity changes,
+// or a default number of changes when capacity is not positive
+func NewChangeStream(capacity int) *ChangeStream {
+	if capacity <= 0 {
+		capacity = defaultChangeHistory
+	}
+	return &ChangeStream{
+		capacity: capacity,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Watch calls fn for every change recorded after resumeToken, or after the call when it is empty,
+// until ctx is cancelled or fn returns an error
+func (s *ChangeStream) Watch(ctx context.Context, resumeToken string, fn func(*entities.ProductEvent) error) error {
+	s.mu.Lock()
+	position := s.sequence
+	if resumeToken != "" {
+		token, err := strconv.ParseUint(resumeToken, 10, 64)
+		if err != nil || token > s.sequence || token < s.oldest()-1 {
+			s.mu.Unlock()
+			return ports.ErrInvalidResumeToken
+		}
+		position = token
+	}
+	s.mu.Unlock()
+
+	for {
+		s.mu.Lock()
+		if position < s.oldest()-1 {
+			// The watcher fell behind further than the history reaches
+			s.mu.Unlock()
+			return ports.ErrInvalidResumeToken
+		}
+		pending := s.since(position)
+		changed := s.changed
+		s.mu.Unlock()
+
+		for _, event := range pending {
+			if err := fn(event); err != nil {
+				return err
+			}
+			position++
+		}
+		if len(pending) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Append records a change and wakes every watcher. The resume token and time are assigned here,
+// so callers must append changes in the order they were committed.
+func (s *ChangeStream) Append(event *entities.ProductEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sequence++
+	event.ResumeToken = strconv.FormatUint(s.sequence, 10)
+	event.OccurredAt = time.Now().UTC()
+
+	s.history = append(s.history, event)
+	if len(s.history) > s.capacity {
+		s.history = s.history[len(s.history)-s.capacity:]
+	}
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// oldest returns the sequence number of the oldest change still in the history
+func (s *ChangeStream) oldest() uint64 {
+	return s.sequence - uint64(len(s.history)) + 1
+}
+
+// since returns copies of the changes recorded after position, which must be within the history
+func (s *ChangeStream) since(position uint64) []*entities.ProductEvent {
+	skip := int(position - (s.oldest() - 1))
+	events := make([]*entities.ProductEvent, 0, len(s.history)-skip)
+	for _, event := range s.history[skip:] {
+		copied := *event
+		if event.Product != nil {
+			// The product was encoded once when recorded, so it cannot fail now
+			copied.Product, _ = clone(event.Product)
+		}
+		events = append(events, &copied)
+	}
+	return events
+}
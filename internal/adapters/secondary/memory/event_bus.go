@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"path"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// Message is an event as delivered to EventBus subscribers
+type Message struct {
+	RoutingKey string
+	Body       []byte
+	Timestamp  time.Time
+}
+
+// EventBus is an in-process message broker implementing the ports.EventPublisher,
+// ports.EventConsumer, ports.APIKeyRevocationConsumer and ports.MediaEventConsumer interfaces.
+// Messages are JSON encoded like on RabbitMQ and handed to every matching subscriber on its own
+// goroutine, in publish order, so slow subscribers never block publishers. Messages published
+// with no subscriber are dropped.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber queues the messages for one subscription until its goroutine handles them
+type subscriber struct {
+	pattern string
+	handler func(Message)
+
+	mu      sync.Mutex
+	queue   []Message
+	closed  bool
+	pending chan struct{}
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish encodes message as JSON and delivers it to every subscriber matching routingKey. Keys
+// without a tenant prefix are delivered for the default tenant, as on RabbitMQ.
+func (b *EventBus) Publish(routingKey string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	tenantID, eventType := ports.SplitRoutingKey(routingKey)
+	routingKey = tenantID + "." + eventType
+	msg := Message{RoutingKey: routingKey, Body: body, Timestamp: time.Now().UTC()}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subscribers {
+		if matched, _ := path.Match(sub.pattern, routingKey); matched {
+			sub.enqueue(msg)
+		}
+	}
+
+	log.Printf("Message published: %s", routingKey)
+	return nil
+}
+
+// Subscribe calls handler for every message whose routing key matches pattern, using path.Match
+// syntax: routing keys are prefixed with the tenant, so "*.product.*" matches the product events
+// of every tenant and "acme.*.*" every event of one tenant. The returned function
+// stops the subscription; messages already queued are still handled.
+func (b *EventBus) Subscribe(pattern string, handler func(Message)) func() {
+	sub := &subscriber{
+		pattern: pattern,
+		handler: handler,
+		pending: make(chan struct{}, 1),
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go sub.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+			sub.close()
+		})
+	}
+}
+
+// ConsumeProductEvents calls fn for every product.created, product.updated and product.deleted
+// message of every tenant until ctx is cancelled. Messages fn fails on are logged and dropped, as
+// on RabbitMQ.
+func (b *EventBus) ConsumeProductEvents(ctx context.Context, fn func(context.Context, *entities.ProductEvent) error) error {
+	unsubscribe := b.Subscribe("*.product.*", func(msg Message) {
+		_, eventType := ports.SplitRoutingKey(msg.RoutingKey)
+		if eventType != entities.ProductCreated && eventType != entities.ProductUpdated && eventType != entities.ProductDeleted {
+			return
+		}
+
+		event, err := decodeProductEvent(msg)
+		if err != nil {
+			log.Printf("Failed to decode %s message: %v", msg.RoutingKey, err)
+			return
+		}
+		if err := fn(ctx, event); err != nil {
+			log.Printf("Failed to handle %s message: %v", msg.RoutingKey, err)
+		}
+	})
+	defer unsubscribe()
+
+	<-ctx.Done()
+	return nil
+}
+
+// ConsumeAPIKeyRevocations calls fn for every API key revocation until ctx is cancelled
+func (b *EventBus) ConsumeAPIKeyRevocations(ctx context.Context, fn func(context.Context, *entities.APIKeyRevocation) error) error {
+	unsubscribe := b.Subscribe("*."+entities.APIKeyRevoked, func(msg Message) {
+		revocation := &entities.APIKeyRevocation{}
+		if err := json.Unmarshal(msg.Body, revocation); err != nil {
+			log.Printf("Failed to decode %s message: %v", msg.RoutingKey, err)
+			return
+		}
+		if err := fn(ctx, revocation); err != nil {
+			log.Printf("Failed to handle %s message: %v", msg.RoutingKey, err)
+		}
+	})
+	defer unsubscribe()
+
+	<-ctx.Done()
+	return nil
+}
+
+// ConsumeMediaUploads calls fn for every media.uploaded message of every tenant until ctx is
+// cancelled. Messages fn fails on are logged and dropped, as on RabbitMQ.
+func (b *EventBus) ConsumeMediaUploads(ctx context.Context, fn func(context.Context, *entities.Media) error) error {
+	unsubscribe := b.Subscribe("*."+entities.MediaUploaded, func(msg Message) {
+		media := &entities.Media{}
+		if err := json.Unmarshal(msg.Body, media); err != nil {
+			log.Printf("Failed to decode %s message: %v", msg.RoutingKey, err)
+			return
+		}
+		media.TenantID, _ = ports.SplitRoutingKey(msg.RoutingKey)
+
+		if err := fn(ctx, media); err != nil {
+			log.Printf("Failed to handle %s message: %v", msg.RoutingKey, err)
+		}
+	})
+	defer unsubscribe()
+
+	<-ctx.Done()
+	return nil
+}
+
+// enqueue adds a message to the subscriber's queue and wakes its goroutine
+func (s *subscriber) enqueue(msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.queue = append(s.queue, msg)
+
+	select {
+	case s.pending <- struct{}{}:
+	default:
+	}
+}
+
+// close stops the subscriber once its queue is drained
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+
+	select {
+	case s.pending <- struct{}{}:
+	default:
+	}
+}
+
+// run hands queued messages to the handler one at a time until the subscriber is closed
+func (s *subscriber) run() {
+	for range s.pending {
+		s.mu.Lock()
+		queue := s.queue
+		s.queue = nil
+		closed := s.closed
+		s.mu.Unlock()
+
+		for _, msg := range queue {
+			s.handler(msg)
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+// decodeProductEvent converts a published product message back into a product event
+func decodeProductEvent(msg Message) (*entities.ProductEvent, error) {
+	tenantID, eventType := ports.SplitRoutingKey(msg.RoutingKey)
+	event := &entities.ProductEvent{
+		Type:       eventType,
+		TenantID:   tenantID,
+		OccurredAt: msg.Timestamp,
+	}
+
+	if eventType == entities.ProductDeleted {
+		// Deleted events only carry the product ID
+		if err := json.Unmarshal(msg.Body, &event.ProductID); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+
+	product := &entities.Product{}
+	if err := json.Unmarshal(msg.Body, product); err != nil {
+		return nil, err
+	}
+	event.Product = product
+	event.ProductID = product.ID.Hex()
+
+	return event, nil
+}
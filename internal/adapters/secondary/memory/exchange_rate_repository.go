@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// exchangeRateKey identifies the rate of a pair from one effective time
+type exchangeRateKey struct {
+	base        string
+	quote       string
+	effectiveAt int64
+}
+
+// ExchangeRateRepository implements the ports.ExchangeRateRepository interface in memory.
+// Rates are shared by every tenant.
+type ExchangeRateRepository struct {
+	mu    sync.RWMutex
+	rates map[exchangeRateKey]*entities.ExchangeRate
+}
+
+// NewExchangeRateRepository creates a new instance of ExchangeRateRepository
+func NewExchangeRateRepository() ports.ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		rates: make(map[exchangeRateKey]*entities.ExchangeRate),
+	}
+}
+
+// Save stores rates, replacing the rate of the same pair and effective time
+func (r *ExchangeRateRepository) Save(ctx context.Context, rates []*entities.ExchangeRate) error {
+	stored, err := cloneAll(rates)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rate := range stored {
+		key := exchangeRateKey{base: rate.Base, quote: rate.Quote, effectiveAt: rate.EffectiveAt.UnixMilli()}
+		if existing, ok := r.rates[key]; ok {
+			rate.ID = existing.ID
+		} else {
+			rate.ID = primitive.NewObjectID()
+		}
+		r.rates[key] = rate
+	}
+	return nil
+}
+
+// FindRate retrieves the rate of a pair in effect at the given time
+func (r *ExchangeRateRepository) FindRate(ctx context.Context, base string, quote string, at time.Time) (*entities.ExchangeRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *entities.ExchangeRate
+	for _, rate := range r.rates {
+		if rate.Base == base && rate.Quote == quote && !rate.EffectiveAt.After(at) &&
+			(found == nil || rate.EffectiveAt.After(found.EffectiveAt)) {
+			found = rate
+		}
+	}
+	if found == nil {
+		return nil, ports.ErrExchangeRateNotFound
+	}
+	return clone(found)
+}
+
+// FindLatest retrieves the rate of every pair in effect at the given time, ordered by pair
+func (r *ExchangeRateRepository) FindLatest(ctx context.Context, at time.Time) ([]*entities.ExchangeRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	latest := make(map[[2]string]*entities.ExchangeRate)
+	for _, rate := range r.rates {
+		pair := [2]string{rate.Base, rate.Quote}
+		if !rate.EffectiveAt.After(at) && (latest[pair] == nil || rate.EffectiveAt.After(latest[pair].EffectiveAt)) {
+			latest[pair] = rate
+		}
+	}
+
+	rates := make([]*entities.ExchangeRate, 0, len(latest))
+	for _, rate := range latest {
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Base != rates[j].Base {
+			return rates[i].Base < rates[j].Base
+		}
+		return rates[i].Quote < rates[j].Quote
+	})
+
+	return cloneAll(rates)
+}
+
+// FindHistory retrieves the rates of a pair that took effect in the range, oldest first
+func (r *ExchangeRateRepository) FindHistory(ctx context.Context, base string, quote string, from time.Time, to time.Time) ([]*entities.ExchangeRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rates []*entities.ExchangeRate
+	for _, rate := range r.rates {
+		if rate.Base != base || rate.Quote != quote {
+			continue
+		}
+		if (!from.IsZero() && rate.EffectiveAt.Before(from)) || (!to.IsZero() && !rate.EffectiveAt.Before(to)) {
+			continue
+		}
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].EffectiveAt.Before(rates[j].EffectiveAt) })
+
+	return cloneAll(rates)
+}
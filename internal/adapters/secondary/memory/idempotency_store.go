@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"test-go/internal/core/ports"
+)
+
+// idempotencyEntry is a stored record with its expiry; a zero expiry never expires
+type idempotencyEntry struct {
+	record    ports.IdempotencyRecord
+	expiresAt time.Time
+}
+
+// expired reports whether the entry has outlived its TTL at now
+func (e *idempotencyEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// IdempotencyStore implements the ports.IdempotencyStore interface in memory.
+// Expired records are dropped when their key is used again.
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotencyEntry
+}
+
+// NewIdempotencyStore creates a new instance of IdempotencyStore
+func NewIdempotencyStore() ports.IdempotencyStore {
+	return &IdempotencyStore{
+		records: make(map[string]*idempotencyEntry),
+	}
+}
+
+// Reserve claims the key, returning a copy of the stored record if someone else holds it
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, requestHash string, lockTTL time.Duration) (*ports.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := s.records[key]; ok && !entry.expired(now) {
+		record := entry.record
+		record.Body = append([]byte(nil), entry.record.Body...)
+		return &record, nil
+	}
+
+	s.records[key] = &idempotencyEntry{
+		record:    ports.IdempotencyRecord{RequestHash: requestHash},
+		expiresAt: expiry(now, lockTTL),
+	}
+	return nil, nil
+}
+
+// Complete overwrites the pending record with the final response
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record *ports.IdempotencyRecord, ttl time.Duration) error {
+	record.Completed = true
+
+	stored := *record
+	stored.Body = append([]byte(nil), record.Body...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = &idempotencyEntry{record: stored, expiresAt: expiry(time.Now(), ttl)}
+
+	return nil
+}
+
+// Release deletes the record so the key can be used again
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+
+	return nil
+}
+
+// expiry returns when an entry written at now with ttl expires, or the zero time for no expiry
+func expiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
@@ -0,0 +1,254 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stockKey identifies the stock level of a product of a tenant in one warehouse
+type stockKey struct {
+	tenantID  string
+	productID string
+	warehouse string
+}
+
+// InventoryRepository implements the ports.InventoryRepository interface in memory.
+// Every change checks its condition and applies under one lock, so quantities can never go negative.
+// Stock levels are only created for products of the tenant found in products.
+type InventoryRepository struct {
+	products ports.ProductRepository
+
+	mu     sync.Mutex
+	levels map[stockKey]*entities.StockLevel
+}
+
+// NewInventoryRepository creates a new instance of InventoryRepository
+func NewInventoryRepository(products ports.ProductRepository) ports.InventoryRepository {
+	return &InventoryRepository{
+		products: products,
+		levels:   make(map[stockKey]*entities.StockLevel),
+	}
+}
+
+// FindByProduct retrieves the stock levels of a product in every warehouse
+func (r *InventoryRepository) FindByProduct(ctx context.Context, productID string) ([]*entities.StockLevel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var levels []*entities.StockLevel
+	tenantID := ports.TenantFromContext(ctx)
+	for key, level := range r.levels {
+		if key.tenantID == tenantID && key.productID == productID {
+			levels = append(levels, level)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Warehouse < levels[j].Warehouse
+	})
+
+	return cloneAll(levels)
+}
+
+// AdjustOnHand increments the on-hand quantity. Decrements only apply while enough
+// unreserved stock is left, so the quantity never drops below what is reserved.
+func (r *InventoryRepository) AdjustOnHand(ctx context.Context, productID string, warehouse string, delta int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, delta >= 0, func(level *entities.StockLevel) bool {
+		if delta < 0 && level.Available() < -delta {
+			return false
+		}
+		level.OnHand += delta
+		return true
+	})
+}
+
+// SetLowStockThreshold sets the threshold, creating the stock level if needed
+func (r *InventoryRepository) SetLowStockThreshold(ctx context.Context, productID string, warehouse string, threshold int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, true, func(level *entities.StockLevel) bool {
+		level.LowStockThreshold = threshold
+		return true
+	})
+}
+
+// Reserve increments the reserved quantity if enough stock is available
+func (r *InventoryRepository) Reserve(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, false, func(level *entities.StockLevel) bool {
+		if level.Available() < quantity {
+			return false
+		}
+		level.Reserved += quantity
+		return true
+	})
+}
+
+// Release decrements the reserved quantity
+func (r *InventoryRepository) Release(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, false, func(level *entities.StockLevel) bool {
+		if level.Reserved < quantity {
+			return false
+		}
+		level.Reserved -= quantity
+		return true
+	})
+}
+
+// Commit decrements both the reserved and on-hand quantities
+func (r *InventoryRepository) Commit(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, false, func(level *entities.StockLevel) bool {
+		if level.Reserved < quantity || level.OnHand < quantity {
+			return false
+		}
+		level.Reserved -= quantity
+		level.OnHand -= quantity
+		return true
+	})
+}
+
+// update applies change to a copy of the stock level and stores it when change accepts it.
+// A missing stock level is created when upsert is set and reported as insufficient stock otherwise,
+// as is a rejected change. Upserts fail with ports.ErrProductNotFound unless the tenant has the product.
+func (r *InventoryRepository) update(ctx context.Context, productID string, warehouse string, upsert bool, change func(*entities.StockLevel) bool) (*entities.StockLevel, error) {
+	if upsert {
+		if _, err := r.products.FindByID(ctx, productID); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The key outlives the request, whose buffers transports such as fiber reuse
+	key := stockKey{tenantID: ports.TenantFromContext(ctx), productID: strings.Clone(productID), warehouse: strings.Clone(warehouse)}
+	level := &entities.StockLevel{ID: primitive.NewObjectID(), TenantID: key.tenantID, ProductID: key.productID, Warehouse: key.warehouse}
+	if existing, ok := r.levels[key]; ok {
+		copied := *existing
+		level = &copied
+	} else if !upsert {
+		return nil, ports.ErrInsufficientStock
+	}
+
+	if !change(level) {
+		return nil, ports.ErrInsufficientStock
+	}
+	level.UpdatedAt = time.Now()
+
+	stored, err := clone(level)
+	if err != nil {
+		return nil, err
+	}
+	r.levels[key] = stored
+
+	return clone(stored)
+}
+
+// ReservationRepository implements the ports.ReservationRepository interface in memory
+type ReservationRepository struct {
+	mu           sync.RWMutex
+	reservations map[primitive.ObjectID]*entities.Reservation
+}
+
+// NewReservationRepository creates a new instance of ReservationRepository
+func NewReservationRepository() ports.ReservationRepository {
+	return &ReservationRepository{
+		reservations: make(map[primitive.ObjectID]*entities.Reservation),
+	}
+}
+
+// Create stores a new reservation
+func (r *ReservationRepository) Create(ctx context.Context, reservation *entities.Reservation) (string, error) {
+	reservation.ID = primitive.NewObjectID()
+	reservation.TenantID = ports.TenantFromContext(ctx)
+	reservation.CreatedAt = time.Now()
+	reservation.UpdatedAt = time.Now()
+
+	stored, err := clone(reservation)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reservations[stored.ID] = stored
+
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a reservation by its ID
+func (r *ReservationRepository) FindByID(ctx context.Context, id string) (*entities.Reservation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrReservationNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reservation, ok := r.reservations[objectID]
+	if !ok || !ownedBy(ctx, reservation.TenantID) {
+		return nil, ports.ErrReservationNotFound
+	}
+	return clone(reservation)
+}
+
+// Transition moves a reservation from one status to another only if it is still in the expected status.
+// Commits also require the reservation not to have expired.
+func (r *ReservationRepository) Transition(ctx context.Context, id string, from string, to string) (*entities.Reservation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrReservationNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[objectID]
+	if !ok || !ownedBy(ctx, reservation.TenantID) {
+		return nil, ports.ErrReservationNotFound
+	}
+	if reservation.Status != from {
+		return nil, ports.ErrReservationClosed
+	}
+	now := time.Now()
+	if from == entities.ReservationPending && to == entities.ReservationCommitted && !reservation.ExpiresAt.After(now) {
+		return nil, ports.ErrReservationExpired
+	}
+
+	moved := *reservation
+	moved.Status = to
+	moved.UpdatedAt = now
+	stored, err := clone(&moved)
+	if err != nil {
+		return nil, err
+	}
+	r.reservations[objectID] = stored
+
+	return clone(stored)
+}
+
+// FindExpired retrieves pending reservations of every tenant that expired before now, oldest first
+func (r *ReservationRepository) FindExpired(ctx context.Context, now time.Time, limit int64) ([]*entities.Reservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var reservations []*entities.Reservation
+	for _, reservation := range r.reservations {
+		if reservation.Status == entities.ReservationPending && reservation.ExpiresAt.Before(now) {
+			reservations = append(reservations, reservation)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].ExpiresAt.Before(reservations[j].ExpiresAt)
+	})
+	if limit > 0 && int64(len(reservations)) > limit {
+		reservations = reservations[:limit]
+	}
+
+	return cloneAll(reservations)
+}
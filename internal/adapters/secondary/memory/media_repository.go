@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MediaRepository implements the ports.MediaRepository interface in memory
+type MediaRepository struct {
+	mu    sync.RWMutex
+	media map[primitive.ObjectID]*entities.Media
+}
+
+// NewMediaRepository creates a new instance of MediaRepository
+func NewMediaRepository() ports.MediaRepository {
+	return &MediaRepository{
+		media: make(map[primitive.ObjectID]*entities.Media),
+	}
+}
+
+// Create stores a new media
+func (r *MediaRepository) Create(ctx context.Context, media *entities.Media) (string, error) {
+	media.ID = primitive.NewObjectID()
+	media.TenantID = ports.TenantFromContext(ctx)
+	media.CreatedAt = time.Now()
+	media.UpdatedAt = time.Now()
+
+	stored, err := clone(media)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.media[stored.ID] = stored
+
+	log.Printf("Media created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a media by its ID
+func (r *MediaRepository) FindByID(ctx context.Context, id string) (*entities.Media, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrMediaNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	media, ok := r.media[objectID]
+	if !ok || !ownedBy(ctx, media.TenantID) {
+		return nil, ports.ErrMediaNotFound
+	}
+	return clone(media)
+}
+
+// Delete removes a media by its ID
+func (r *MediaRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrMediaNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.media[objectID]; !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrMediaNotFound
+	}
+	delete(r.media, objectID)
+
+	log.Printf("Media with ID: %s deleted successfully", id)
+	return nil
+}
+
+// UpdatePosition moves a media to position
+func (r *MediaRepository) UpdatePosition(ctx context.Context, id string, position int) error {
+	return r.update(ctx, id, func(media *entities.Media) {
+		media.Position = position
+	})
+}
+
+// SetThumbnails records the thumbnails generated for a media
+func (r *MediaRepository) SetThumbnails(ctx context.Context, id string, thumbnails []entities.MediaThumbnail) error {
+	return r.update(ctx, id, func(media *entities.Media) {
+		// Thumbnails hold no references, so copying the slice keeps the caller's apart
+		media.Thumbnails = append([]entities.MediaThumbnail(nil), thumbnails...)
+	})
+}
+
+// FindByProductID retrieves the media of a product ordered by position
+func (r *MediaRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.Media, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var media []*entities.Media
+	for _, m := range r.media {
+		if ownedBy(ctx, m.TenantID) && m.ProductID == productID {
+			media = append(media, m)
+		}
+	}
+	sort.SliceStable(media, func(i, j int) bool {
+		if media[i].Position != media[j].Position {
+			return media[i].Position < media[j].Position
+		}
+		return media[i].ID.Hex() < media[j].ID.Hex()
+	})
+
+	return cloneAll(media)
+}
+
+// FindByProductAndHash retrieves the media of a product with the given content hash
+func (r *MediaRepository) FindByProductAndHash(ctx context.Context, productID string, sha256 string) (*entities.Media, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.media {
+		if ownedBy(ctx, m.TenantID) && m.ProductID == productID && m.SHA256 == sha256 {
+			return clone(m)
+		}
+	}
+	return nil, ports.ErrMediaNotFound
+}
+
+// CountByHash counts the media of the tenant with the given content hash
+func (r *MediaRepository) CountByHash(ctx context.Context, sha256 string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, m := range r.media {
+		if ownedBy(ctx, m.TenantID) && m.SHA256 == sha256 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// update changes the stored media with change
+func (r *MediaRepository) update(ctx context.Context, id string, change func(*entities.Media)) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrMediaNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	media, ok := r.media[objectID]
+	if !ok || !ownedBy(ctx, media.TenantID) {
+		return ports.ErrMediaNotFound
+	}
+	change(media)
+	media.UpdatedAt = time.Now()
+
+	log.Printf("Media with ID: %s updated successfully", id)
+	return nil
+}
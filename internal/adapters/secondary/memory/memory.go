@@ -0,0 +1,56 @@
+// Package memory implements the secondary ports in process memory, so the services can run
+// without MongoDB, Redis or RabbitMQ. Data lives as long as the process and is not shared
+// between processes.
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// clone copies a document through a BSON round trip. Callers never share memory with the
+// store, and values come back with the same types and precision as from the MongoDB adapters.
+func clone[T any](document *T) (*T, error) {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied T
+	if err := bson.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// cloneAll copies every document, stopping at the first one that cannot be encoded
+func cloneAll[T any](documents []*T) ([]*T, error) {
+	copies := make([]*T, 0, len(documents))
+	for _, document := range documents {
+		copied, err := clone(document)
+		if err != nil {
+			return nil, err
+		}
+		copies = append(copies, copied)
+	}
+	return copies, nil
+}
+
+// ownedBy reports whether a document of tenantID belongs to the tenant carried by ctx.
+// Documents of other tenants are treated as missing, like in the MongoDB adapters.
+func ownedBy(ctx context.Context, tenantID string) bool {
+	return tenantID == ports.TenantFromContext(ctx)
+}
+
+// sortByID orders documents by ObjectID, which matches insertion order within a process
+func sortByID[T any](documents []*T, id func(*T) primitive.ObjectID) {
+	sort.SliceStable(documents, func(i, j int) bool {
+		a, b := id(documents[i]), id(documents[j])
+		return a.Hex() < b.Hex()
+	})
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PriceListRepository implements the ports.PriceListRepository interface in memory
+type PriceListRepository struct {
+	mu    sync.RWMutex
+	lists map[primitive.ObjectID]*entities.PriceList
+}
+
+// NewPriceListRepository creates a new instance of PriceListRepository
+func NewPriceListRepository() ports.PriceListRepository {
+	return &PriceListRepository{
+		lists: make(map[primitive.ObjectID]*entities.PriceList),
+	}
+}
+
+// Create stores a new price list
+func (r *PriceListRepository) Create(ctx context.Context, list *entities.PriceList) (string, error) {
+	list.ID = primitive.NewObjectID()
+	list.TenantID = ports.TenantFromContext(ctx)
+	list.CreatedAt = time.Now()
+	list.UpdatedAt = time.Now()
+
+	stored, err := clone(list)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lists[stored.ID] = stored
+
+	log.Printf("Price list created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a price list by its ID
+func (r *PriceListRepository) FindByID(ctx context.Context, id string) (*entities.PriceList, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrPriceListNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list, ok := r.lists[objectID]
+	if !ok || !ownedBy(ctx, list.TenantID) {
+		return nil, ports.ErrPriceListNotFound
+	}
+	return clone(list)
+}
+
+// FindByCode retrieves a price list by its code
+func (r *PriceListRepository) FindByCode(ctx context.Context, code string) (*entities.PriceList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, list := range r.lists {
+		if ownedBy(ctx, list.TenantID) && list.Code == code {
+			return clone(list)
+		}
+	}
+	return nil, ports.ErrPriceListNotFound
+}
+
+// FindAll retrieves the price lists of the tenant ordered by code
+func (r *PriceListRepository) FindAll(ctx context.Context) ([]*entities.PriceList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var lists []*entities.PriceList
+	for _, list := range r.lists {
+		if ownedBy(ctx, list.TenantID) {
+			lists = append(lists, list)
+		}
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Code < lists[j].Code })
+
+	return cloneAll(lists)
+}
+
+// Update replaces the details of an existing price list, keeping its prices
+func (r *PriceListRepository) Update(ctx context.Context, list *entities.PriceList) error {
+	return r.update(ctx, list.ID.Hex(), func(stored *entities.PriceList) {
+		stored.Code = list.Code
+		stored.Name = list.Name
+		stored.Currency = list.Currency
+		stored.Market = list.Market
+		stored.CustomerGroup = list.CustomerGroup
+	})
+}
+
+// Delete removes a price list by its ID
+func (r *PriceListRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrPriceListNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.lists[objectID]; !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrPriceListNotFound
+	}
+	delete(r.lists, objectID)
+
+	log.Printf("Price list with ID: %s deleted successfully", id)
+	return nil
+}
+
+// SetPrice sets the explicit price of a product in a price list
+func (r *PriceListRepository) SetPrice(ctx context.Context, id string, productID string, price float32) error {
+	return r.update(ctx, id, func(list *entities.PriceList) {
+		if list.Prices == nil {
+			list.Prices = make(map[string]float32)
+		}
+		// The key outlives the request, whose buffers transports such as fiber reuse
+		list.Prices[strings.Clone(productID)] = price
+	})
+}
+
+// DeletePrice removes the explicit price of a product from a price list
+func (r *PriceListRepository) DeletePrice(ctx context.Context, id string, productID string) error {
+	return r.update(ctx, id, func(list *entities.PriceList) {
+		delete(list.Prices, productID)
+	})
+}
+
+// update changes the stored price list with change
+func (r *PriceListRepository) update(ctx context.Context, id string, change func(*entities.PriceList)) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrPriceListNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list, ok := r.lists[objectID]
+	if !ok || !ownedBy(ctx, list.TenantID) {
+		return ports.ErrPriceListNotFound
+	}
+	change(list)
+	list.UpdatedAt = time.Now()
+
+	log.Printf("Price list with ID: %s updated successfully", id)
+	return nil
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PriceScheduleRepository implements the ports.PriceScheduleRepository interface in memory.
+// Writes check for overlapping schedules under the same lock they store with.
+type PriceScheduleRepository struct {
+	mu        sync.RWMutex
+	schedules map[primitive.ObjectID]*entities.PriceSchedule
+}
+
+// NewPriceScheduleRepository creates a new instance of PriceScheduleRepository
+func NewPriceScheduleRepository() ports.PriceScheduleRepository {
+	return &PriceScheduleRepository{
+		schedules: make(map[primitive.ObjectID]*entities.PriceSchedule),
+	}
+}
+
+// Create stores a new price schedule
+func (r *PriceScheduleRepository) Create(ctx context.Context, schedule *entities.PriceSchedule) (string, error) {
+	schedule.ID = primitive.NewObjectID()
+	schedule.TenantID = ports.TenantFromContext(ctx)
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+
+	stored, err := clone(schedule)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conflicts(ctx, stored) {
+		return "", ports.ErrPriceScheduleOverlap
+	}
+	r.schedules[stored.ID] = stored
+
+	log.Printf("Price schedule created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a price schedule by its ID
+func (r *PriceScheduleRepository) FindByID(ctx context.Context, id string) (*entities.PriceSchedule, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrPriceScheduleNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedule, ok := r.schedules[objectID]
+	if !ok || !ownedBy(ctx, schedule.TenantID) {
+		return nil, ports.ErrPriceScheduleNotFound
+	}
+	return clone(schedule)
+}
+
+// Update replaces an existing price schedule
+func (r *PriceScheduleRepository) Update(ctx context.Context, schedule *entities.PriceSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.schedules[schedule.ID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrPriceScheduleNotFound
+	}
+
+	schedule.TenantID = existing.TenantID
+	schedule.UpdatedAt = time.Now()
+	stored, err := clone(schedule)
+	if err != nil {
+		return err
+	}
+	if r.conflicts(ctx, stored) {
+		return ports.ErrPriceScheduleOverlap
+	}
+	r.schedules[stored.ID] = stored
+
+	log.Printf("Price schedule with ID: %s updated successfully", schedule.ID.Hex())
+	return nil
+}
+
+// Delete removes a price schedule by its ID
+func (r *PriceScheduleRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrPriceScheduleNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.schedules[objectID]; !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrPriceScheduleNotFound
+	}
+	delete(r.schedules, objectID)
+
+	log.Printf("Price schedule with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindByProductID retrieves the schedules of a product ordered by start time
+func (r *PriceScheduleRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.PriceSchedule, error) {
+	return r.find(func(schedule *entities.PriceSchedule) bool {
+		return ownedBy(ctx, schedule.TenantID) && schedule.ProductID == productID
+	})
+}
+
+// FindActiveAt retrieves the schedules of every product of the tenant that apply at the given time
+func (r *PriceScheduleRepository) FindActiveAt(ctx context.Context, at time.Time) ([]*entities.PriceSchedule, error) {
+	return r.find(func(schedule *entities.PriceSchedule) bool {
+		return ownedBy(ctx, schedule.TenantID) && schedule.ActiveAt(at)
+	})
+}
+
+// FindChangingBetween retrieves the schedules of every tenant starting or ending in (from, to]
+func (r *PriceScheduleRepository) FindChangingBetween(ctx context.Context, from time.Time, to time.Time) ([]*entities.PriceSchedule, error) {
+	within := func(t time.Time) bool { return t.After(from) && !t.After(to) }
+	return r.find(func(schedule *entities.PriceSchedule) bool {
+		return within(schedule.StartsAt) || within(schedule.EndsAt)
+	})
+}
+
+// find copies the schedules accepted by match ordered by start time
+func (r *PriceScheduleRepository) find(match func(*entities.PriceSchedule) bool) ([]*entities.PriceSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var schedules []*entities.PriceSchedule
+	for _, schedule := range r.schedules {
+		if match(schedule) {
+			schedules = append(schedules, schedule)
+		}
+	}
+	sort.SliceStable(schedules, func(i, j int) bool {
+		if !schedules[i].StartsAt.Equal(schedules[j].StartsAt) {
+			return schedules[i].StartsAt.Before(schedules[j].StartsAt)
+		}
+		return schedules[i].ID.Hex() < schedules[j].ID.Hex()
+	})
+
+	return cloneAll(schedules)
+}
+
+// conflicts reports whether a stored schedule of the tenant conflicts with schedule.
+// The caller holds the lock.
+func (r *PriceScheduleRepository) conflicts(ctx context.Context, schedule *entities.PriceSchedule) bool {
+	for _, other := range r.schedules {
+		if ownedBy(ctx, other.TenantID) && schedule.ConflictsWith(other) {
+			return true
+		}
+	}
+	return false
+}
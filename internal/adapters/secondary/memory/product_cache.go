@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// ProductCache implements the ports.ProductCache interface in memory.
+// Products are kept as JSON, like the Redis cache, so cached reads look exactly the same.
+type ProductCache struct {
+	mu sync.RWMutex
+	// products is keyed by tenant and product ID, see cacheKey
+	products map[string]cachedProduct
+}
+
+// cacheKey namespaces a product ID by the tenant carried by ctx
+func cacheKey(ctx context.Context, id string) string {
+	return ports.TenantFromContext(ctx) + ":" + id
+}
+
+// cachedProduct is a product encoded as JSON with its expiry, zero for none
+type cachedProduct struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewProductCache creates a new instance of ProductCache
+func NewProductCache() ports.ProductCache {
+	return &ProductCache{
+		products: make(map[string]cachedProduct),
+	}
+}
+
+// Get decodes a cached product that has not expired
+func (c *ProductCache) Get(ctx context.Context, id string) (*entities.Product, error) {
+	c.mu.RLock()
+	cached, ok := c.products[cacheKey(ctx, id)]
+	c.mu.RUnlock()
+	if !ok || (!cached.expiresAt.IsZero() && !time.Now().Before(cached.expiresAt)) {
+		return nil, ports.ErrCacheMiss
+	}
+
+	product := &entities.Product{}
+	if err := json.Unmarshal(cached.data, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Set stores a product for ttl, or without expiry when ttl is zero
+func (c *ProductCache) Set(ctx context.Context, product *entities.Product, ttl time.Duration) error {
+	productJSON, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+
+	cached := cachedProduct{data: productJSON}
+	if ttl > 0 {
+		cached.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.products[cacheKey(ctx, product.ID.Hex())] = cached
+
+	return nil
+}
+
+// Delete removes a cached product
+func (c *ProductCache) Delete(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.products, cacheKey(ctx, id))
+
+	return nil
+}
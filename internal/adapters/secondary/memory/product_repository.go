@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductRepository implements the ports.ProductRepository interface in memory
+type ProductRepository struct {
+	mu       sync.RWMutex
+	products map[primitive.ObjectID]*entities.Product
+	changes  *ChangeStream
+}
+
+// NewProductRepository creates a new instance of ProductRepository.
+// Every write is recorded on changes, which may be nil when nobody watches products.
+func NewProductRepository(changes *ChangeStream) ports.ProductRepository {
+	return &ProductRepository{
+		products: make(map[primitive.ObjectID]*entities.Product),
+		changes:  changes,
+	}
+}
+
+// Create stores a new product
+func (r *ProductRepository) Create(ctx context.Context, product *entities.Product) (string, error) {
+	product.ID = primitive.NewObjectID()
+	product.TenantID = ports.TenantFromContext(ctx)
+	product.CreatedAt = time.Now()
+	product.UpdatedAt = time.Now()
+
+	stored, err := clone(product)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products[stored.ID] = stored
+	r.record(entities.ProductCreated, stored)
+
+	log.Printf("Product created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a product by its ID
+func (r *ProductRepository) FindByID(ctx context.Context, id string) (*entities.Product, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrProductNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[objectID]
+	if !ok || !ownedBy(ctx, product.TenantID) {
+		return nil, ports.ErrProductNotFound
+	}
+	return clone(product)
+}
+
+// Update replaces an existing product, keeping its stored creation time
+func (r *ProductRepository) Update(ctx context.Context, product *entities.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.products[product.ID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrProductNotFound
+	}
+
+	product.TenantID = existing.TenantID
+	product.UpdatedAt = time.Now()
+	stored, err := clone(product)
+	if err != nil {
+		return err
+	}
+	stored.CreatedAt = existing.CreatedAt
+	r.products[stored.ID] = stored
+	r.record(entities.ProductUpdated, stored)
+
+	log.Printf("Product with ID: %s updated successfully", product.ID.Hex())
+	return nil
+}
+
+// Delete removes a product by its ID
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrProductNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.products[objectID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrProductNotFound
+	}
+	delete(r.products, objectID)
+	r.record(entities.ProductDeleted, existing)
+
+	log.Printf("Product with ID: %s deleted successfully", id)
+	return nil
+}
+
+// Count returns the number of products of the tenant
+func (r *ProductRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, product := range r.products {
+		if ownedBy(ctx, product.TenantID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FindAll retrieves every product of the tenant in insertion order
+func (r *ProductRepository) FindAll(ctx context.Context) ([]*entities.Product, error) {
+	return r.find(ctx, func(*entities.Product) bool { return true })
+}
+
+// FindByCategoryIDs retrieves the products assigned to any of the given categories
+func (r *ProductRepository) FindByCategoryIDs(ctx context.Context, categoryIDs []string) ([]*entities.Product, error) {
+	wanted := make(map[string]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		wanted[id] = true
+	}
+
+	return r.find(ctx, func(product *entities.Product) bool {
+		for _, id := range product.CategoryIDs {
+			if wanted[id] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FindByFilter retrieves the products matching every set field of the filter
+func (r *ProductRepository) FindByFilter(ctx context.Context, filter ports.ProductFilter) ([]*entities.Product, error) {
+	return r.find(ctx, filter.Matches)
+}
+
+// Stream calls fn for every product in _id order. The products are copied up front,
+// so fn may write to the repository without deadlocking.
+func (r *ProductRepository) Stream(ctx context.Context, batchSize int32, fn func(*entities.Product) error) error {
+	products, err := r.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindDueTransitions retrieves the products of every tenant with a status change due at the given time
+func (r *ProductRepository) FindDueTransitions(ctx context.Context, at time.Time) ([]*entities.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var products []*entities.Product
+	for _, product := range r.products {
+		if product.ScheduledStatus(at) != "" {
+			products = append(products, product)
+		}
+	}
+	sortByID(products, func(p *entities.Product) primitive.ObjectID { return p.ID })
+
+	return cloneAll(products)
+}
+
+// find copies the products of the tenant accepted by match in _id order
+func (r *ProductRepository) find(ctx context.Context, match func(*entities.Product) bool) ([]*entities.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var products []*entities.Product
+	for _, product := range r.products {
+		if ownedBy(ctx, product.TenantID) && match(product) {
+			products = append(products, product)
+		}
+	}
+	sortByID(products, func(p *entities.Product) primitive.ObjectID { return p.ID })
+
+	return cloneAll(products)
+}
+
+// record appends a change for watchers; it must be called with the lock held so changes keep write order
+func (r *ProductRepository) record(eventType string, product *entities.Product) {
+	if r.changes == nil {
+		return
+	}
+
+	event := &entities.ProductEvent{Type: eventType, TenantID: product.TenantID, ProductID: product.ID.Hex()}
+	if eventType != entities.ProductDeleted {
+		// Watchers receive their own copy, like the full document of a change stream
+		if copied, err := clone(product); err == nil {
+			event.Product = copied
+		}
+	}
+	r.changes.Append(event)
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductRevisionRepository implements the ports.ProductRevisionRepository interface in memory
+type ProductRevisionRepository struct {
+	mu sync.RWMutex
+	// revisions holds the revisions of each product, oldest first
+	revisions map[revisionKey][]*entities.ProductRevision
+}
+
+// revisionKey identifies the history of a product within its tenant
+type revisionKey struct {
+	tenantID  string
+	productID string
+}
+
+// key returns the history key of a product of the tenant carried by ctx
+func key(ctx context.Context, productID string) revisionKey {
+	return revisionKey{tenantID: ports.TenantFromContext(ctx), productID: productID}
+}
+
+// NewProductRevisionRepository creates a new instance of ProductRevisionRepository
+func NewProductRevisionRepository() ports.ProductRevisionRepository {
+	return &ProductRevisionRepository{
+		revisions: make(map[revisionKey][]*entities.ProductRevision),
+	}
+}
+
+// Append stores a revision, numbering it right after the latest revision of its product
+func (r *ProductRevisionRepository) Append(ctx context.Context, revision *entities.ProductRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := key(ctx, revision.ProductID)
+	revision.ID = primitive.NewObjectID()
+	revision.TenantID = history.tenantID
+	revision.Revision = int64(len(r.revisions[history])) + 1
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+
+	stored, err := clone(revision)
+	if err != nil {
+		return err
+	}
+	r.revisions[history] = append(r.revisions[history], stored)
+	return nil
+}
+
+// FindByProductID retrieves every revision of a product, oldest first
+func (r *ProductRevisionRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.ProductRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return cloneAll(r.revisions[key(ctx, productID)])
+}
+
+// FindByRevision retrieves one revision of a product
+func (r *ProductRevisionRepository) FindByRevision(ctx context.Context, productID string, revision int64) (*entities.ProductRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.revisions[key(ctx, productID)]
+	if revision < 1 || revision > int64(len(revisions)) {
+		return nil, ports.ErrRevisionNotFound
+	}
+	return clone(revisions[revision-1])
+}
+
+// FindAsOf retrieves the latest revision of a product created at or before at
+func (r *ProductRevisionRepository) FindAsOf(ctx context.Context, productID string, at time.Time) (*entities.ProductRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.revisions[key(ctx, productID)]
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if !revisions[i].CreatedAt.After(at) {
+			return clone(revisions[i])
+		}
+	}
+	return nil, ports.ErrRevisionNotFound
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductTypeRepository implements the ports.ProductTypeRepository interface in memory
+type ProductTypeRepository struct {
+	mu           sync.RWMutex
+	productTypes map[primitive.ObjectID]*entities.ProductType
+}
+
+// NewProductTypeRepository creates a new instance of ProductTypeRepository
+func NewProductTypeRepository() ports.ProductTypeRepository {
+	return &ProductTypeRepository{
+		productTypes: make(map[primitive.ObjectID]*entities.ProductType),
+	}
+}
+
+// Create stores a new product type
+func (r *ProductTypeRepository) Create(ctx context.Context, productType *entities.ProductType) (string, error) {
+	productType.ID = primitive.NewObjectID()
+	productType.TenantID = ports.TenantFromContext(ctx)
+	productType.CreatedAt = time.Now()
+	productType.UpdatedAt = time.Now()
+
+	stored, err := clone(productType)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.productTypes[stored.ID] = stored
+
+	log.Printf("Product type created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a product type by its ID
+func (r *ProductTypeRepository) FindByID(ctx context.Context, id string) (*entities.ProductType, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrProductTypeNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	productType, ok := r.productTypes[objectID]
+	if !ok || !ownedBy(ctx, productType.TenantID) {
+		return nil, ports.ErrProductTypeNotFound
+	}
+	return clone(productType)
+}
+
+// Update replaces an existing product type
+func (r *ProductTypeRepository) Update(ctx context.Context, productType *entities.ProductType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.productTypes[productType.ID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrProductTypeNotFound
+	}
+
+	productType.TenantID = existing.TenantID
+	productType.UpdatedAt = time.Now()
+	stored, err := clone(productType)
+	if err != nil {
+		return err
+	}
+	r.productTypes[stored.ID] = stored
+
+	return nil
+}
+
+// Delete removes a product type by its ID
+func (r *ProductTypeRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrProductTypeNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.productTypes[objectID]; !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrProductTypeNotFound
+	}
+	delete(r.productTypes, objectID)
+
+	log.Printf("Product type with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves every product type of the tenant ordered by name
+func (r *ProductTypeRepository) FindAll(ctx context.Context) ([]*entities.ProductType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	productTypes := make([]*entities.ProductType, 0, len(r.productTypes))
+	for _, productType := range r.productTypes {
+		if ownedBy(ctx, productType.TenantID) {
+			productTypes = append(productTypes, productType)
+		}
+	}
+	sortByID(productTypes, func(t *entities.ProductType) primitive.ObjectID { return t.ID })
+	sort.SliceStable(productTypes, func(i, j int) bool {
+		return productTypes[i].Name < productTypes[j].Name
+	})
+
+	return cloneAll(productTypes)
+}
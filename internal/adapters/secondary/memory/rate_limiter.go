@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"test-go/internal/core/ports"
+)
+
+// rateLimitSweepInterval is how often buckets that have refilled completely are dropped
+const rateLimitSweepInterval = time.Minute
+
+// bucket is the state of one token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // When the bucket is full again if left alone
+}
+
+// RateLimiter implements the ports.RateLimiter interface in memory. Buckets are only shared
+// within the process, so every replica enforces the limits on its own.
+type RateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a new instance of RateLimiter
+func NewRateLimiter() ports.RateLimiter {
+	return &RateLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take refills the bucket of key for the time passed since its last request and removes a token
+func (l *RateLimiter) Take(ctx context.Context, key string, limit ports.RateLimit) (*ports.RateLimitResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.RefillRate())
+	b.updated = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	result := limit.Result(allowed, b.tokens)
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+// sweep drops buckets that are full again, which behave like missing ones
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
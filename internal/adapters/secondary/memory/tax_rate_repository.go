@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaxRateRepository implements the ports.TaxRateRepository interface in memory.
+// Rates are shared by every tenant.
+type TaxRateRepository struct {
+	mu    sync.RWMutex
+	rates map[primitive.ObjectID]*entities.TaxRate
+}
+
+// NewTaxRateRepository creates a new instance of TaxRateRepository
+func NewTaxRateRepository() ports.TaxRateRepository {
+	return &TaxRateRepository{
+		rates: make(map[primitive.ObjectID]*entities.TaxRate),
+	}
+}
+
+// Create stores a new tax rate
+func (r *TaxRateRepository) Create(ctx context.Context, rate *entities.TaxRate) (string, error) {
+	rate.ID = primitive.NewObjectID()
+	rate.CreatedAt = time.Now()
+
+	stored, err := clone(rate)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rates[stored.ID] = stored
+
+	log.Printf("Tax rate created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a tax rate by its ID
+func (r *TaxRateRepository) FindByID(ctx context.Context, id string) (*entities.TaxRate, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrTaxRateNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rate, ok := r.rates[objectID]
+	if !ok {
+		return nil, ports.ErrTaxRateNotFound
+	}
+	return clone(rate)
+}
+
+// FindAll retrieves the rates of a jurisdiction, or of all of them, ordered by jurisdiction, class and effective time
+func (r *TaxRateRepository) FindAll(ctx context.Context, jurisdiction string) ([]*entities.TaxRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rates []*entities.TaxRate
+	for _, rate := range r.rates {
+		if jurisdiction == "" || rate.Jurisdiction == jurisdiction {
+			rates = append(rates, rate)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		a, b := rates[i], rates[j]
+		if a.Jurisdiction != b.Jurisdiction {
+			return a.Jurisdiction < b.Jurisdiction
+		}
+		if a.TaxClass != b.TaxClass {
+			return a.TaxClass < b.TaxClass
+		}
+		return a.EffectiveFrom.Before(b.EffectiveFrom)
+	})
+
+	return cloneAll(rates)
+}
+
+// FindEffective retrieves the rate of a tax class in a jurisdiction in effect at the given time
+func (r *TaxRateRepository) FindEffective(ctx context.Context, jurisdiction string, taxClass string, at time.Time) (*entities.TaxRate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *entities.TaxRate
+	for _, rate := range r.rates {
+		if rate.Jurisdiction == jurisdiction && rate.TaxClass == taxClass && !rate.EffectiveFrom.After(at) &&
+			(found == nil || rate.EffectiveFrom.After(found.EffectiveFrom)) {
+			found = rate
+		}
+	}
+	if found == nil {
+		return nil, ports.ErrTaxRateNotFound
+	}
+	return clone(found)
+}
+
+// Delete removes a tax rate by its ID
+func (r *TaxRateRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrTaxRateNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rates[objectID]; !ok {
+		return ports.ErrTaxRateNotFound
+	}
+	delete(r.rates, objectID)
+
+	log.Printf("Tax rate with ID: %s deleted successfully", id)
+	return nil
+}
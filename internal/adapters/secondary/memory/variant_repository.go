@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VariantRepository implements the ports.VariantRepository interface in memory.
+// SKUs are unique across the variants of a tenant, like the unique index of migration 009.
+type VariantRepository struct {
+	mu       sync.RWMutex
+	variants map[primitive.ObjectID]*entities.ProductVariant
+}
+
+// NewVariantRepository creates a new instance of VariantRepository
+func NewVariantRepository() ports.VariantRepository {
+	return &VariantRepository{
+		variants: make(map[primitive.ObjectID]*entities.ProductVariant),
+	}
+}
+
+// CreateMany stores the variants in order. Like an ordered MongoDB insert, the variants before
+// a duplicate SKU stay stored when it is rejected.
+func (r *VariantRepository) CreateMany(ctx context.Context, variants []*entities.ProductVariant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, variant := range variants {
+		if variant.ID.IsZero() {
+			variant.ID = primitive.NewObjectID()
+		}
+		variant.TenantID = ports.TenantFromContext(ctx)
+		variant.CreatedAt = time.Now()
+		variant.UpdatedAt = time.Now()
+
+		if r.skuTaken(variant.TenantID, variant.SKU, variant.ID) {
+			return ports.ErrDuplicateSKU
+		}
+
+		stored, err := clone(variant)
+		if err != nil {
+			return err
+		}
+		r.variants[stored.ID] = stored
+	}
+
+	return nil
+}
+
+// FindByID retrieves a variant by its ID
+func (r *VariantRepository) FindByID(ctx context.Context, id string) (*entities.ProductVariant, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrVariantNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	variant, ok := r.variants[objectID]
+	if !ok || !ownedBy(ctx, variant.TenantID) {
+		return nil, ports.ErrVariantNotFound
+	}
+	return clone(variant)
+}
+
+// FindByProduct retrieves the variants of a product ordered by position
+func (r *VariantRepository) FindByProduct(ctx context.Context, productID string) ([]*entities.ProductVariant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var variants []*entities.ProductVariant
+	for _, variant := range r.variants {
+		if ownedBy(ctx, variant.TenantID) && variant.ProductID == productID {
+			variants = append(variants, variant)
+		}
+	}
+	sortByID(variants, func(v *entities.ProductVariant) primitive.ObjectID { return v.ID })
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].Position < variants[j].Position
+	})
+
+	return cloneAll(variants)
+}
+
+// Update replaces an existing variant
+func (r *VariantRepository) Update(ctx context.Context, variant *entities.ProductVariant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.variants[variant.ID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrVariantNotFound
+	}
+	if r.skuTaken(existing.TenantID, variant.SKU, variant.ID) {
+		return ports.ErrDuplicateSKU
+	}
+
+	variant.TenantID = existing.TenantID
+	variant.UpdatedAt = time.Now()
+	stored, err := clone(variant)
+	if err != nil {
+		return err
+	}
+	r.variants[stored.ID] = stored
+
+	return nil
+}
+
+// DeleteByIDs removes the variants of the tenant with the given IDs, ignoring unknown IDs
+func (r *VariantRepository) DeleteByIDs(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		if variant, ok := r.variants[objectID]; ok && ownedBy(ctx, variant.TenantID) {
+			delete(r.variants, objectID)
+		}
+	}
+	return nil
+}
+
+// skuTaken reports whether a variant of the tenant other than id uses sku; the lock must be held
+func (r *VariantRepository) skuTaken(tenantID string, sku string, id primitive.ObjectID) bool {
+	for _, variant := range r.variants {
+		if variant.TenantID == tenantID && variant.SKU == sku && variant.ID != id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookRepository implements the ports.WebhookRepository interface in memory
+type WebhookRepository struct {
+	mu       sync.RWMutex
+	webhooks map[primitive.ObjectID]*entities.WebhookSubscription
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository() ports.WebhookRepository {
+	return &WebhookRepository{
+		webhooks: make(map[primitive.ObjectID]*entities.WebhookSubscription),
+	}
+}
+
+// Create stores a new webhook subscription
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entities.WebhookSubscription) (string, error) {
+	webhook.ID = primitive.NewObjectID()
+	webhook.TenantID = ports.TenantFromContext(ctx)
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+
+	stored, err := clone(webhook)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks[stored.ID] = stored
+
+	log.Printf("Webhook created with ID: %s", stored.ID.Hex())
+	return stored.ID.Hex(), nil
+}
+
+// FindByID retrieves a webhook subscription by its ID
+func (r *WebhookRepository) FindByID(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrWebhookNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[objectID]
+	if !ok || !ownedBy(ctx, webhook.TenantID) {
+		return nil, ports.ErrWebhookNotFound
+	}
+	return clone(webhook)
+}
+
+// Update replaces an existing webhook subscription
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entities.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.webhooks[webhook.ID]
+	if !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrWebhookNotFound
+	}
+
+	webhook.TenantID = existing.TenantID
+	webhook.UpdatedAt = time.Now()
+	stored, err := clone(webhook)
+	if err != nil {
+		return err
+	}
+	r.webhooks[stored.ID] = stored
+
+	log.Printf("Webhook with ID: %s updated successfully", webhook.ID.Hex())
+	return nil
+}
+
+// Delete removes a webhook subscription by its ID
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrWebhookNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.webhooks[objectID]; !ok || !ownedBy(ctx, existing.TenantID) {
+		return ports.ErrWebhookNotFound
+	}
+	delete(r.webhooks, objectID)
+
+	log.Printf("Webhook with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves every webhook subscription of the tenant
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	return r.find(ctx, func(*entities.WebhookSubscription) bool { return true })
+}
+
+// FindActiveByEventType retrieves the active subscriptions listening to eventType,
+// including those that did not restrict their event types
+func (r *WebhookRepository) FindActiveByEventType(ctx context.Context, eventType string) ([]*entities.WebhookSubscription, error) {
+	return r.find(ctx, func(webhook *entities.WebhookSubscription) bool {
+		return webhook.Active && webhook.Subscribes(eventType)
+	})
+}
+
+// RecordFailure increments the failure count of a subscription and disables it once the count
+// reaches disableAfter, under the lock so concurrent failures are all counted
+func (r *WebhookRepository) RecordFailure(ctx context.Context, id string, disableAfter int, at time.Time) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, ports.ErrWebhookNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[objectID]
+	if !ok || !ownedBy(ctx, webhook.TenantID) {
+		return false, ports.ErrWebhookNotFound
+	}
+	webhook.FailureCount++
+	if !webhook.Active || webhook.FailureCount < disableAfter {
+		return false, nil
+	}
+	webhook.Active = false
+	webhook.DisabledAt = &at
+	webhook.UpdatedAt = at
+	return true, nil
+}
+
+// ResetFailures sets the failure count of a subscription back to zero
+func (r *WebhookRepository) ResetFailures(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrWebhookNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[objectID]
+	if !ok || !ownedBy(ctx, webhook.TenantID) {
+		return ports.ErrWebhookNotFound
+	}
+	webhook.FailureCount = 0
+	return nil
+}
+
+// find copies the webhook subscriptions of the tenant accepted by match in _id order
+func (r *WebhookRepository) find(ctx context.Context, match func(*entities.WebhookSubscription) bool) ([]*entities.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var webhooks []*entities.WebhookSubscription
+	for _, webhook := range r.webhooks {
+		if ownedBy(ctx, webhook.TenantID) && match(webhook) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sortByID(webhooks, func(w *entities.WebhookSubscription) primitive.ObjectID { return w.ID })
+
+	return cloneAll(webhooks)
+}
+
+// WebhookDeliveryRepository implements the ports.WebhookDeliveryRepository interface in memory
+type WebhookDeliveryRepository struct {
+	mu         sync.RWMutex
+	deliveries []*entities.WebhookDelivery
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository
+func NewWebhookDeliveryRepository() ports.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{}
+}
+
+// Create appends a delivery attempt to the log
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *entities.WebhookDelivery) (string, error) {
+	delivery.ID = primitive.NewObjectID()
+	delivery.TenantID = ports.TenantFromContext(ctx)
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	stored, err := clone(delivery)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, stored)
+
+	return stored.ID.Hex(), nil
+}
+
+// FindBySubscription retrieves the latest delivery attempts for a webhook subscription
+func (r *WebhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID string, limit int64) ([]*entities.WebhookDelivery, error) {
+	objectID, err := primitive.ObjectIDFromHex(subscriptionID)
+	if err != nil {
+		return nil, ports.ErrWebhookNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var deliveries []*entities.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if ownedBy(ctx, delivery.TenantID) && delivery.SubscriptionID == objectID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if limit > 0 && int64(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return cloneAll(deliveries)
+}
+
+// PendingWebhookDeliveryRepository implements the ports.PendingWebhookDeliveryRepository interface in memory
+type PendingWebhookDeliveryRepository struct {
+	mu      sync.Mutex
+	pending map[primitive.ObjectID]*entities.PendingWebhookDelivery
+}
+
+// NewPendingWebhookDeliveryRepository creates a new instance of PendingWebhookDeliveryRepository
+func NewPendingWebhookDeliveryRepository() ports.PendingWebhookDeliveryRepository {
+	return &PendingWebhookDeliveryRepository{
+		pending: make(map[primitive.ObjectID]*entities.PendingWebhookDelivery),
+	}
+}
+
+// Create queues deliveries for the tenant
+func (r *PendingWebhookDeliveryRepository) Create(ctx context.Context, deliveries []*entities.PendingWebhookDelivery) error {
+	stored := make([]*entities.PendingWebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		delivery.ID = primitive.NewObjectID()
+		delivery.TenantID = ports.TenantFromContext(ctx)
+		delivery.CreatedAt = time.Now()
+
+		copied, err := clone(delivery)
+		if err != nil {
+			return err
+		}
+		stored = append(stored, copied)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, delivery := range stored {
+		r.pending[delivery.ID] = delivery
+	}
+	return nil
+}
+
+// ClaimDue leases up to limit deliveries of every tenant due at now, oldest first
+func (r *PendingWebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int64) ([]*entities.PendingWebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*entities.PendingWebhookDelivery
+	for _, delivery := range r.pending {
+		if !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if limit > 0 && int64(len(due)) > limit {
+		due = due[:limit]
+	}
+
+	claimed, err := cloneAll(due)
+	if err != nil {
+		return nil, err
+	}
+	for _, delivery := range due {
+		delivery.NextAttemptAt = leaseUntil
+	}
+	return claimed, nil
+}
+
+// Reschedule records an attempt of a delivery and when to try it again
+func (r *PendingWebhookDeliveryRepository) Reschedule(ctx context.Context, id string, attempts int, next time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if delivery, ok := r.pending[objectID]; ok && ownedBy(ctx, delivery.TenantID) {
+		delivery.Attempts = attempts
+		delivery.NextAttemptAt = next
+	}
+	return nil
+}
+
+// Delete removes a delivery from the queue
+func (r *PendingWebhookDeliveryRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if delivery, ok := r.pending[objectID]; ok && ownedBy(ctx, delivery.TenantID) {
+		delete(r.pending, objectID)
+	}
+	return nil
+}
@@ -1,12 +1,23 @@
 package queue
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
 
 	"github.com/streadway/amqp"
 )
 
+// exchange is the durable topic exchange every event is published to. Routing keys are prefixed
+// with the tenant, e.g. "acme.product.created", so queues bind to "*.product.created" for every
+// tenant or "acme.#" for a single one.
+const exchange = "catalog"
+
 // RabbitMQ struct to hold the connection
 type RabbitMQ struct {
 	Conn *amqp.Connection
@@ -22,19 +33,26 @@ func NewRabbitMQ(url string) (*RabbitMQ, error) {
 	return &RabbitMQ{Conn: conn}, nil
 }
 
-// PublishProductCreated publishes a message when a product is created
+// PublishProductCreated publishes a message when a product of the default tenant is created
 func (r *RabbitMQ) PublishProductCreated(product interface{}) error {
-	return r.publish("product.created", product)
+	return r.Publish(entities.ProductCreated, product)
 }
 
-// PublishProductUpdated publishes a message when a product is updated
+// PublishProductUpdated publishes a message when a product of the default tenant is updated
 func (r *RabbitMQ) PublishProductUpdated(product interface{}) error {
-	return r.publish("product.updated", product)
+	return r.Publish(entities.ProductUpdated, product)
 }
 
-// PublishProductDeleted publishes a message when a product is deleted
+// PublishProductDeleted publishes a message when a product of the default tenant is deleted
 func (r *RabbitMQ) PublishProductDeleted(productID string) error {
-	return r.publish("product.deleted", productID)
+	return r.Publish(entities.ProductDeleted, productID)
+}
+
+// Publish publishes a message with the given routing key. Keys without a tenant prefix, such as
+// those left in an outbox before tenants were introduced, are published for the default tenant.
+func (r *RabbitMQ) Publish(routingKey string, message interface{}) error {
+	tenantID, eventType := ports.SplitRoutingKey(routingKey)
+	return r.publish(tenantID+"."+eventType, message)
 }
 
 // publish is a helper method to publish messages to RabbitMQ
@@ -45,18 +63,23 @@ func (r *RabbitMQ) publish(routingKey string, message interface{}) error {
 	}
 	defer ch.Close()
 
+	if err := declareExchange(ch); err != nil {
+		return err
+	}
+
 	body, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
 	err = ch.Publish(
-		"",         // exchange
+		exchange,   // exchange
 		routingKey, // routing key
 		false,      // mandatory
 		false,      // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
+			Timestamp:   time.Now().UTC(),
 			Body:        body,
 		},
 	)
@@ -69,6 +92,212 @@ func (r *RabbitMQ) publish(routingKey string, message interface{}) error {
 	return nil
 }
 
+// ConsumeProductEvents consumes the product.created, product.updated and product.deleted queues,
+// each bound to the events of every tenant, and calls fn for every message until ctx is cancelled.
+// Messages are acknowledged once fn returns nil and dropped otherwise.
+func (r *RabbitMQ) ConsumeProductEvents(ctx context.Context, fn func(context.Context, *entities.ProductEvent) error) error {
+	ch, err := r.Conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := declareExchange(ch); err != nil {
+		return err
+	}
+
+	messages := make(chan amqp.Delivery)
+	var wg sync.WaitGroup
+	for _, eventType := range []string{entities.ProductCreated, entities.ProductUpdated, entities.ProductDeleted} {
+		if _, err := ch.QueueDeclare(eventType, true, false, false, false, nil); err != nil {
+			return err
+		}
+		if err := ch.QueueBind(eventType, "*."+eventType, exchange, false, nil); err != nil {
+			return err
+		}
+		deliveries, err := ch.Consume(eventType, "", false, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func(deliveries <-chan amqp.Delivery) {
+			defer wg.Done()
+			for msg := range deliveries {
+				select {
+				case messages <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(deliveries)
+	}
+
+	// Close the merged channel once every consumer stops, e.g. when the connection drops
+	go func() {
+		wg.Wait()
+		close(messages)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return amqp.ErrClosed
+			}
+
+			event, err := decodeProductEvent(msg)
+			if err != nil {
+				log.Printf("Failed to decode %s message: %v", msg.RoutingKey, err)
+				_ = msg.Nack(false, false)
+				continue
+			}
+
+			if err := fn(ctx, event); err != nil {
+				log.Printf("Failed to handle %s message: %v", msg.RoutingKey, err)
+				_ = msg.Nack(false, false)
+				continue
+			}
+			_ = msg.Ack(false)
+		}
+	}
+}
+
+// ConsumeAPIKeyRevocations calls fn for every API key revocation of every tenant until ctx is
+// cancelled. Each call declares its own exclusive queue, so every process receives every revocation.
+func (r *RabbitMQ) ConsumeAPIKeyRevocations(ctx context.Context, fn func(context.Context, *entities.APIKeyRevocation) error) error {
+	ch, err := r.Conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := declareExchange(ch); err != nil {
+		return err
+	}
+
+	queue, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		return err
+	}
+	if err := ch.QueueBind(queue.Name, "*."+entities.APIKeyRevoked, exchange, false, nil); err != nil {
+		return err
+	}
+	deliveries, err := ch.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-deliveries:
+			if !ok {
+				return amqp.ErrClosed
+			}
+
+			revocation := &entities.APIKeyRevocation{}
+			if err := json.Unmarshal(msg.Body, revocation); err != nil {
+				log.Printf("Failed to decode %s message: %v", msg.RoutingKey, err)
+				continue
+			}
+			if err := fn(ctx, revocation); err != nil {
+				log.Printf("Failed to handle %s message: %v", msg.RoutingKey, err)
+			}
+		}
+	}
+}
+
+// ConsumeMediaUploads consumes the durable media.uploaded queue, bound to the uploads of every
+// tenant, and calls fn for every message until ctx is cancelled. Messages are acknowledged once fn
+// returns nil and dropped otherwise.
+func (r *RabbitMQ) ConsumeMediaUploads(ctx context.Context, fn func(context.Context, *entities.Media) error) error {
+	ch, err := r.Conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := declareExchange(ch); err != nil {
+		return err
+	}
+
+	if _, err := ch.QueueDeclare(entities.MediaUploaded, true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.QueueBind(entities.MediaUploaded, "*."+entities.MediaUploaded, exchange, false, nil); err != nil {
+		return err
+	}
+	deliveries, err := ch.Consume(entities.MediaUploaded, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-deliveries:
+			if !ok {
+				return amqp.ErrClosed
+			}
+
+			media := &entities.Media{}
+			if err := json.Unmarshal(msg.Body, media); err != nil {
+				log.Printf("Failed to decode %s message: %v", msg.RoutingKey, err)
+				_ = msg.Nack(false, false)
+				continue
+			}
+			media.TenantID, _ = ports.SplitRoutingKey(msg.RoutingKey)
+
+			if err := fn(ctx, media); err != nil {
+				log.Printf("Failed to handle %s message: %v", msg.RoutingKey, err)
+				_ = msg.Nack(false, false)
+				continue
+			}
+			_ = msg.Ack(false)
+		}
+	}
+}
+
+// declareExchange declares the topic exchange events are published to
+func declareExchange(ch *amqp.Channel) error {
+	return ch.ExchangeDeclare(exchange, amqp.ExchangeTopic, true, false, false, false, nil)
+}
+
+// decodeProductEvent converts a published product message back into a product event
+func decodeProductEvent(msg amqp.Delivery) (*entities.ProductEvent, error) {
+	tenantID, eventType := ports.SplitRoutingKey(msg.RoutingKey)
+	event := &entities.ProductEvent{
+		Type:       eventType,
+		TenantID:   tenantID,
+		OccurredAt: msg.Timestamp,
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	if eventType == entities.ProductDeleted {
+		// Deleted events only carry the product ID
+		if err := json.Unmarshal(msg.Body, &event.ProductID); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+
+	product := &entities.Product{}
+	if err := json.Unmarshal(msg.Body, product); err != nil {
+		return nil, err
+	}
+	event.Product = product
+	event.ProductID = product.ID.Hex()
+
+	return event, nil
+}
+
 // GetRabbitMQ initializes a new RabbitMQ connection and returns a RabbitMQ instance
 func GetRmqInstance(rabbitMQURL string) *RabbitMQ {
 	conn, err := amqp.Dial(rabbitMQURL)
@@ -0,0 +1,165 @@
+package boltdb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyRepository implements the ports.APIKeyRepository interface on bbolt.
+// Keys are stored as BSON under their ObjectID, and the api_keys_by_hash bucket maps each hash to
+// its key, as the tenant of a request is only known once its key is found.
+type APIKeyRepository struct {
+	store *Store
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository
+func NewAPIKeyRepository(store *Store) ports.APIKeyRepository {
+	return &APIKeyRepository{
+		store: store,
+	}
+}
+
+// Create stores a new API key and indexes its hash
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) (string, error) {
+	key.ID = primitive.NewObjectID()
+	key.TenantID = ports.TenantFromContext(ctx)
+	key.CreatedAt = time.Now()
+	key.UpdatedAt = time.Now()
+
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		if err := putDocument(tx, apiKeysBucket, key.ID[:], key); err != nil {
+			return err
+		}
+		return tx.Bucket(apiKeysByHashBucket).Put([]byte(key.Hash), key.ID[:])
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("API key created with ID: %s", key.ID.Hex())
+	return key.ID.Hex(), nil
+}
+
+// FindByID retrieves an API key by its ID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*entities.APIKey, error) {
+	objectID, err := parseID(id, ports.ErrAPIKeyNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var key *entities.APIKey
+	err = r.store.view(func(tx *bbolt.Tx) error {
+		key, err = getAPIKey(tx, objectID)
+		if err == nil && !ownedBy(ctx, key.TenantID) {
+			return ports.ErrAPIKeyNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Update replaces an existing API key, keeping its stored creation time, and moves its hash index entry
+func (r *APIKeyRepository) Update(ctx context.Context, key *entities.APIKey) error {
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		existing, err := getAPIKey(tx, key.ID)
+		if err != nil {
+			return err
+		}
+		if !ownedBy(ctx, existing.TenantID) {
+			return ports.ErrAPIKeyNotFound
+		}
+
+		key.TenantID = existing.TenantID
+		key.CreatedAt = existing.CreatedAt
+		key.UpdatedAt = time.Now()
+		if err := tx.Bucket(apiKeysByHashBucket).Delete([]byte(existing.Hash)); err != nil {
+			return err
+		}
+		if err := putDocument(tx, apiKeysBucket, key.ID[:], key); err != nil {
+			return err
+		}
+		return tx.Bucket(apiKeysByHashBucket).Put([]byte(key.Hash), key.ID[:])
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("API key with ID: %s updated successfully", key.ID.Hex())
+	return nil
+}
+
+// FindAll retrieves every API key of the tenant in _id order
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*entities.APIKey, error) {
+	var keys []*entities.APIKey
+	err := r.store.view(func(tx *bbolt.Tx) error {
+		var err error
+		keys, err = scanDocuments(tx, apiKeysBucket, func(key *entities.APIKey) bool {
+			return ownedBy(ctx, key.TenantID)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FindByHash looks up the API key with the given hash in the hash index, whatever its tenant
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*entities.APIKey, error) {
+	var key *entities.APIKey
+	err := r.store.view(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(apiKeysByHashBucket).Get([]byte(hash))
+		if id == nil {
+			return ports.ErrAPIKeyNotFound
+		}
+		var err error
+		key, err = getAPIKey(tx, primitive.ObjectID(id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// TouchLastUsed records when an API key of any tenant was last used
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	objectID, err := parseID(id, ports.ErrAPIKeyNotFound)
+	if err != nil {
+		return err
+	}
+
+	return r.store.update(func(tx *bbolt.Tx) error {
+		key, err := getAPIKey(tx, objectID)
+		if err != nil {
+			return err
+		}
+		key.LastUsedAt = &usedAt
+		return putDocument(tx, apiKeysBucket, objectID[:], key)
+	})
+}
+
+// getAPIKey reads an API key of any tenant, returning ports.ErrAPIKeyNotFound when it does not exist
+func getAPIKey(tx *bbolt.Tx, id primitive.ObjectID) (*entities.APIKey, error) {
+	key, err := getDocument[entities.APIKey](tx, apiKeysBucket, id[:])
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ports.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
@@ -0,0 +1,149 @@
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditRepository implements the ports.AuditRepository interface on bbolt.
+// Entries are stored as BSON under the tenant followed by a zero byte and the big-endian sequence,
+// so the log of a tenant is adjacent and in sequence order.
+type AuditRepository struct {
+	store *Store
+	// tx is set for repositories handed out by Store.Do; every call then joins that transaction
+	tx *bbolt.Tx
+}
+
+// NewAuditRepository creates a new instance of AuditRepository
+func NewAuditRepository(store *Store) ports.AuditRepository {
+	return &AuditRepository{
+		store: store,
+	}
+}
+
+// Append seals the entry after the latest entry of the tenant and stores it
+func (r *AuditRepository) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	tenantID := ports.TenantFromContext(ctx)
+
+	return r.update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(auditBucket)
+
+		var prev *entities.AuditEntry
+		// Seek past the last possible key of the tenant and step back to its latest entry
+		cursor := bucket.Cursor()
+		key, value := cursor.Seek(auditKey(tenantID, math.MaxUint64))
+		if key == nil {
+			key, value = cursor.Last()
+		} else {
+			key, value = cursor.Prev()
+		}
+		if key != nil && bytes.HasPrefix(key, tenantPrefix(tenantID)) {
+			var err error
+			if prev, err = decodeAuditEntry(value); err != nil {
+				return err
+			}
+		}
+
+		entry.ID = primitive.NewObjectID()
+		entry.TenantID = tenantID
+		entry.Seal(prev)
+		data, err := bson.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(auditKey(tenantID, uint64(entry.Sequence)), data)
+	})
+}
+
+// Find retrieves up to filter.Limit matching entries in sequence order
+func (r *AuditRepository) Find(ctx context.Context, filter ports.AuditFilter) ([]*entities.AuditEntry, error) {
+	var entries []*entities.AuditEntry
+	err := r.scan(ctx, filter, func(entry *entities.AuditEntry) error {
+		if filter.Limit > 0 && int64(len(entries)) >= filter.Limit {
+			return errStopScan
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil && err != errStopScan {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Stream calls fn for every matching entry in sequence order. fn runs inside a read-only
+// transaction, which does not hold up writers.
+func (r *AuditRepository) Stream(ctx context.Context, filter ports.AuditFilter, fn func(*entities.AuditEntry) error) error {
+	return r.scan(ctx, filter, fn)
+}
+
+// errStopScan ends a scan early without reporting an error
+var errStopScan = errors.New("stop scan")
+
+// scan calls fn for every entry of the tenant matching filter, starting after filter.AfterSequence
+func (r *AuditRepository) scan(ctx context.Context, filter ports.AuditFilter, fn func(*entities.AuditEntry) error) error {
+	tenantID := ports.TenantFromContext(ctx)
+	start := uint64(1)
+	if filter.AfterSequence > 0 {
+		start = uint64(filter.AfterSequence) + 1
+	}
+
+	return r.view(func(tx *bbolt.Tx) error {
+		prefix := tenantPrefix(tenantID)
+		cursor := tx.Bucket(auditBucket).Cursor()
+		for key, value := cursor.Seek(auditKey(tenantID, start)); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			entry, err := decodeAuditEntry(value)
+			if err != nil {
+				return err
+			}
+			if !filter.Matches(entry) {
+				continue
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// update runs fn in the repository's transaction, or in a new read-write transaction
+func (r *AuditRepository) update(fn func(tx *bbolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.update(fn)
+}
+
+// view runs fn in the repository's transaction, or in a new read-only transaction
+func (r *AuditRepository) view(fn func(tx *bbolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.view(fn)
+}
+
+// decodeAuditEntry decodes a stored entry. The returned entry does not reference data.
+func decodeAuditEntry(data []byte) (*entities.AuditEntry, error) {
+	var entry entities.AuditEntry
+	if err := bson.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// auditKey builds the key of one entry of the log of a tenant
+func auditKey(tenantID string, sequence uint64) []byte {
+	return binary.BigEndian.AppendUint64(tenantPrefix(tenantID), sequence)
+}
@@ -0,0 +1,204 @@
+package boltdb
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategoryRepository implements the ports.CategoryRepository interface on bbolt using materialized
+// paths. Categories are stored as BSON under their ObjectID.
+type CategoryRepository struct {
+	store *Store
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository(store *Store) ports.CategoryRepository {
+	return &CategoryRepository{
+		store: store,
+	}
+}
+
+// Create stores a new category.
+// The ID may be preset by the caller so the path can be computed before insertion.
+func (r *CategoryRepository) Create(ctx context.Context, category *entities.Category) (string, error) {
+	if category.ID.IsZero() {
+		category.ID = primitive.NewObjectID()
+	}
+	category.TenantID = ports.TenantFromContext(ctx)
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = time.Now()
+
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		return putDocument(tx, categoriesBucket, category.ID[:], category)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Category created with ID: %s", category.ID.Hex())
+	return category.ID.Hex(), nil
+}
+
+// FindByID retrieves a category by its ID
+func (r *CategoryRepository) FindByID(ctx context.Context, id string) (*entities.Category, error) {
+	objectID, err := parseID(id, ports.ErrCategoryNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var category *entities.Category
+	err = r.store.view(func(tx *bbolt.Tx) error {
+		category, err = getCategory(ctx, tx, objectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Update replaces an existing category, keeping its stored creation time
+func (r *CategoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	return r.store.update(func(tx *bbolt.Tx) error {
+		existing, err := getCategory(ctx, tx, category.ID)
+		if err != nil {
+			return err
+		}
+
+		category.TenantID = existing.TenantID
+		category.CreatedAt = existing.CreatedAt
+		category.UpdatedAt = time.Now()
+		return putDocument(tx, categoriesBucket, category.ID[:], category)
+	})
+}
+
+// Delete removes a category by its ID
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := parseID(id, ports.ErrCategoryNotFound)
+	if err != nil {
+		return err
+	}
+
+	err = r.store.update(func(tx *bbolt.Tx) error {
+		if _, err := getCategory(ctx, tx, objectID); err != nil {
+			return err
+		}
+		return tx.Bucket(categoriesBucket).Delete(objectID[:])
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Category with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves every category ordered by depth, so parents come before their children
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entities.Category, error) {
+	return r.find(ctx, func(*entities.Category) bool { return true }, byDepthAndPosition)
+}
+
+// FindByIDs retrieves the categories with the given IDs
+func (r *CategoryRepository) FindByIDs(ctx context.Context, ids []string) ([]*entities.Category, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	return r.find(ctx, func(category *entities.Category) bool { return wanted[category.ID.Hex()] }, byDepthAndPosition)
+}
+
+// FindChildren retrieves the direct children of a category, or the roots for an empty parentID
+func (r *CategoryRepository) FindChildren(ctx context.Context, parentID string) ([]*entities.Category, error) {
+	return r.find(ctx, func(category *entities.Category) bool {
+		return category.ParentID == parentID
+	}, func(a, b *entities.Category) bool {
+		return a.Position < b.Position
+	})
+}
+
+// FindDescendants retrieves every category whose path starts with the given path
+func (r *CategoryRepository) FindDescendants(ctx context.Context, path string) ([]*entities.Category, error) {
+	return r.find(ctx, func(category *entities.Category) bool {
+		return isBelow(category.Path, path)
+	}, byDepthAndPosition)
+}
+
+// MoveDescendants replaces the oldPath prefix of every descendant path with newPath in one transaction
+func (r *CategoryRepository) MoveDescendants(ctx context.Context, oldPath string, newPath string, depthDelta int) error {
+	return r.store.update(func(tx *bbolt.Tx) error {
+		descendants, err := scanDocuments(tx, categoriesBucket, func(category *entities.Category) bool {
+			return ownedBy(ctx, category.TenantID) && isBelow(category.Path, oldPath)
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, category := range descendants {
+			category.Path = newPath + strings.TrimPrefix(category.Path, oldPath)
+			category.Depth += depthDelta
+			category.UpdatedAt = now
+			if err := putDocument(tx, categoriesBucket, category.ID[:], category); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// find decodes the categories of the tenant accepted by match, ordered by less and then by _id
+func (r *CategoryRepository) find(ctx context.Context, match func(*entities.Category) bool, less func(a, b *entities.Category) bool) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := r.store.view(func(tx *bbolt.Tx) error {
+		var err error
+		categories, err = scanDocuments(tx, categoriesBucket, func(category *entities.Category) bool {
+			return ownedBy(ctx, category.TenantID) && match(category)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return less(categories[i], categories[j])
+	})
+	return categories, nil
+}
+
+// getCategory reads a category of the tenant carried by ctx, returning ports.ErrCategoryNotFound
+// when it does not exist or belongs to another tenant
+func getCategory(ctx context.Context, tx *bbolt.Tx, id primitive.ObjectID) (*entities.Category, error) {
+	category, err := getDocument[entities.Category](tx, categoriesBucket, id[:])
+	if err != nil {
+		return nil, err
+	}
+	if category == nil || !ownedBy(ctx, category.TenantID) {
+		return nil, ports.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// byDepthAndPosition orders parents before their children and siblings by position
+func byDepthAndPosition(a, b *entities.Category) bool {
+	if a.Depth != b.Depth {
+		return a.Depth < b.Depth
+	}
+	return a.Position < b.Position
+}
+
+// isBelow reports whether path lies strictly below ancestor in the tree
+func isBelow(path string, ancestor string) bool {
+	return path != ancestor && strings.HasPrefix(path, ancestor)
+}
@@ -0,0 +1,73 @@
+package boltdb
+
+import (
+	"context"
+
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The repositories below products keep each document as BSON in a bucket of its own, most under
+// their 12-byte ObjectID so a bucket iterates in _id order. Their data is small next to the
+// product catalog, so queries other than by key scan the bucket.
+
+// getDocument reads and decodes the document stored under key, or returns nil when there is none
+func getDocument[T any](tx *bbolt.Tx, bucket []byte, key []byte) (*T, error) {
+	data := tx.Bucket(bucket).Get(key)
+	if data == nil {
+		return nil, nil
+	}
+	return decodeDocument[T](data)
+}
+
+// putDocument encodes a document and stores it under key
+func putDocument[T any](tx *bbolt.Tx, bucket []byte, key []byte, document *T) error {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(bucket).Put(key, data)
+}
+
+// scanDocuments decodes every document of a bucket accepted by match in key order
+func scanDocuments[T any](tx *bbolt.Tx, bucket []byte, match func(*T) bool) ([]*T, error) {
+	var documents []*T
+	err := tx.Bucket(bucket).ForEach(func(key, value []byte) error {
+		document, err := decodeDocument[T](value)
+		if err != nil {
+			return err
+		}
+		if match(document) {
+			documents = append(documents, document)
+		}
+		return nil
+	})
+	return documents, err
+}
+
+// decodeDocument decodes a stored document. The returned document does not reference data.
+func decodeDocument[T any](data []byte) (*T, error) {
+	var document T
+	if err := bson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// parseID parses the hex ObjectID of a document, returning notFound when it is malformed
+func parseID(id string, notFound error) (primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, notFound
+	}
+	return objectID, nil
+}
+
+// ownedBy reports whether a document of tenantID belongs to the tenant carried by ctx.
+// Documents of other tenants are treated as missing, like in the MongoDB adapters.
+func ownedBy(ctx context.Context, tenantID string) bool {
+	return tenantID == ports.TenantFromContext(ctx)
+}
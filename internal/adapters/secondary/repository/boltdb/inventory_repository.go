@@ -0,0 +1,276 @@
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InventoryRepository implements the ports.InventoryRepository interface on bbolt.
+// Stock levels are stored as BSON under the tenant, the product ID, each followed by a zero byte, and
+// the warehouse, so the levels of a product are adjacent and ordered by warehouse. Every change
+// checks its condition and applies in one read-write transaction, so quantities can never go negative
+// and stock levels are only created for products of the tenant.
+type InventoryRepository struct {
+	store *Store
+}
+
+// NewInventoryRepository creates a new instance of InventoryRepository
+func NewInventoryRepository(store *Store) ports.InventoryRepository {
+	return &InventoryRepository{
+		store: store,
+	}
+}
+
+// FindByProduct retrieves the stock levels of a product in every warehouse
+func (r *InventoryRepository) FindByProduct(ctx context.Context, productID string) ([]*entities.StockLevel, error) {
+	var levels []*entities.StockLevel
+	err := r.store.view(func(tx *bbolt.Tx) error {
+		prefix := stockKey(ports.TenantFromContext(ctx), productID, "")
+		cursor := tx.Bucket(stockLevelsBucket).Cursor()
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			level, err := decodeDocument[entities.StockLevel](value)
+			if err != nil {
+				return err
+			}
+			levels = append(levels, level)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return levels, nil
+}
+
+// AdjustOnHand increments the on-hand quantity. Decrements only apply while enough
+// unreserved stock is left, so the quantity never drops below what is reserved.
+func (r *InventoryRepository) AdjustOnHand(ctx context.Context, productID string, warehouse string, delta int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, delta >= 0, func(level *entities.StockLevel) bool {
+		if delta < 0 && level.Available() < -delta {
+			return false
+		}
+		level.OnHand += delta
+		return true
+	})
+}
+
+// SetLowStockThreshold sets the threshold, creating the stock level if needed
+func (r *InventoryRepository) SetLowStockThreshold(ctx context.Context, productID string, warehouse string, threshold int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, true, func(level *entities.StockLevel) bool {
+		level.LowStockThreshold = threshold
+		return true
+	})
+}
+
+// Reserve increments the reserved quantity if enough stock is available
+func (r *InventoryRepository) Reserve(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, false, func(level *entities.StockLevel) bool {
+		if level.Available() < quantity {
+			return false
+		}
+		level.Reserved += quantity
+		return true
+	})
+}
+
+// Release decrements the reserved quantity
+func (r *InventoryRepository) Release(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, false, func(level *entities.StockLevel) bool {
+		if level.Reserved < quantity {
+			return false
+		}
+		level.Reserved -= quantity
+		return true
+	})
+}
+
+// Commit decrements both the reserved and on-hand quantities
+func (r *InventoryRepository) Commit(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	return r.update(ctx, productID, warehouse, false, func(level *entities.StockLevel) bool {
+		if level.Reserved < quantity || level.OnHand < quantity {
+			return false
+		}
+		level.Reserved -= quantity
+		level.OnHand -= quantity
+		return true
+	})
+}
+
+// update applies change to the stock level and stores it when change accepts it.
+// A missing stock level is created when upsert is set and reported as insufficient stock otherwise,
+// as is a rejected change. Upserts fail with ports.ErrProductNotFound unless the tenant has the product.
+func (r *InventoryRepository) update(ctx context.Context, productID string, warehouse string, upsert bool, change func(*entities.StockLevel) bool) (*entities.StockLevel, error) {
+	tenantID := ports.TenantFromContext(ctx)
+	key := stockKey(tenantID, productID, warehouse)
+
+	var level *entities.StockLevel
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		if upsert {
+			objectID, err := parseID(productID, ports.ErrProductNotFound)
+			if err != nil {
+				return err
+			}
+			if _, err := getProduct(ctx, tx, objectID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if level, err = getDocument[entities.StockLevel](tx, stockLevelsBucket, key); err != nil {
+			return err
+		}
+		if level == nil {
+			if !upsert {
+				return ports.ErrInsufficientStock
+			}
+			level = &entities.StockLevel{ID: primitive.NewObjectID(), TenantID: tenantID, ProductID: productID, Warehouse: warehouse}
+		}
+
+		if !change(level) {
+			return ports.ErrInsufficientStock
+		}
+		level.UpdatedAt = time.Now()
+		return putDocument(tx, stockLevelsBucket, key, level)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return level, nil
+}
+
+// stockKey builds the key of the stock level of a product in a warehouse: the tenant prefix, the
+// product ID, a zero byte, then the warehouse. An empty warehouse gives the prefix of every
+// warehouse of the product.
+func stockKey(tenantID string, productID string, warehouse string) []byte {
+	key := tenantPrefix(tenantID)
+	key = append(key, productID...)
+	key = append(key, 0)
+	return append(key, warehouse...)
+}
+
+// ReservationRepository implements the ports.ReservationRepository interface on bbolt.
+// Reservations are stored as BSON under their ObjectID.
+type ReservationRepository struct {
+	store *Store
+}
+
+// NewReservationRepository creates a new instance of ReservationRepository
+func NewReservationRepository(store *Store) ports.ReservationRepository {
+	return &ReservationRepository{
+		store: store,
+	}
+}
+
+// Create stores a new reservation
+func (r *ReservationRepository) Create(ctx context.Context, reservation *entities.Reservation) (string, error) {
+	reservation.ID = primitive.NewObjectID()
+	reservation.TenantID = ports.TenantFromContext(ctx)
+	reservation.CreatedAt = time.Now()
+	reservation.UpdatedAt = time.Now()
+
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		return putDocument(tx, reservationsBucket, reservation.ID[:], reservation)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return reservation.ID.Hex(), nil
+}
+
+// FindByID retrieves a reservation by its ID
+func (r *ReservationRepository) FindByID(ctx context.Context, id string) (*entities.Reservation, error) {
+	objectID, err := parseID(id, ports.ErrReservationNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var reservation *entities.Reservation
+	err = r.store.view(func(tx *bbolt.Tx) error {
+		reservation, err = getReservation(ctx, tx, objectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// Transition moves a reservation from one status to another only if it is still in the expected status.
+// Commits also require the reservation not to have expired.
+func (r *ReservationRepository) Transition(ctx context.Context, id string, from string, to string) (*entities.Reservation, error) {
+	objectID, err := parseID(id, ports.ErrReservationNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var reservation *entities.Reservation
+	err = r.store.update(func(tx *bbolt.Tx) error {
+		if reservation, err = getReservation(ctx, tx, objectID); err != nil {
+			return err
+		}
+		if reservation.Status != from {
+			return ports.ErrReservationClosed
+		}
+		now := time.Now()
+		if from == entities.ReservationPending && to == entities.ReservationCommitted && !reservation.ExpiresAt.After(now) {
+			return ports.ErrReservationExpired
+		}
+
+		reservation.Status = to
+		reservation.UpdatedAt = now
+		return putDocument(tx, reservationsBucket, objectID[:], reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// FindExpired retrieves pending reservations of every tenant that expired before now, oldest first
+func (r *ReservationRepository) FindExpired(ctx context.Context, now time.Time, limit int64) ([]*entities.Reservation, error) {
+	var reservations []*entities.Reservation
+	err := r.store.view(func(tx *bbolt.Tx) error {
+		var err error
+		reservations, err = scanDocuments(tx, reservationsBucket, func(reservation *entities.Reservation) bool {
+			return reservation.Status == entities.ReservationPending && reservation.ExpiresAt.Before(now)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(reservations, func(i, j int) bool {
+		return reservations[i].ExpiresAt.Before(reservations[j].ExpiresAt)
+	})
+	if limit > 0 && int64(len(reservations)) > limit {
+		reservations = reservations[:limit]
+	}
+	return reservations, nil
+}
+
+// getReservation reads a reservation of the tenant carried by ctx, returning
+// ports.ErrReservationNotFound when it does not exist or belongs to another tenant
+func getReservation(ctx context.Context, tx *bbolt.Tx, id primitive.ObjectID) (*entities.Reservation, error) {
+	reservation, err := getDocument[entities.Reservation](tx, reservationsBucket, id[:])
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil || !ownedBy(ctx, reservation.TenantID) {
+		return nil, ports.ErrReservationNotFound
+	}
+	return reservation, nil
+}
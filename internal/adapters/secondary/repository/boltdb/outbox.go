@@ -0,0 +1,143 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"log"
+	"time"
+
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+)
+
+// outboxBatchSize is how many queued events are relayed per round
+const outboxBatchSize = 100
+
+// outboxMessage is an event waiting in the outbox to be relayed to the message broker
+type outboxMessage struct {
+	RoutingKey string          `json:"routing_key"`
+	Body       json.RawMessage `json:"body"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// txPublisher implements the ports.EventPublisher interface by queueing events in the outbox
+// as part of a transaction, so they are only relayed if the transaction commits
+type txPublisher struct {
+	store *Store
+	tx    *bbolt.Tx
+}
+
+// Publish encodes the message as JSON and appends it to the outbox
+func (p *txPublisher) Publish(routingKey string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(&outboxMessage{RoutingKey: routingKey, Body: body, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	bucket := p.tx.Bucket(outboxBucket)
+	sequence, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+	if err := bucket.Put(key, data); err != nil {
+		return err
+	}
+
+	p.tx.OnCommit(p.store.signalOutbox)
+	return nil
+}
+
+// RelayOutbox forwards queued events to publisher in the order they were queued until ctx is
+// cancelled. Events are removed once published, so delivery is at least once: an event whose
+// removal fails is published again. Failed publishes are retried every interval.
+func (s *Store) RelayOutbox(ctx context.Context, publisher ports.EventPublisher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			relayed, err := s.relayBatch(publisher)
+			if err != nil {
+				log.Printf("Failed to relay outbox: %v", err)
+				break
+			}
+			if relayed < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.outboxReady:
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayBatch publishes the oldest queued events and removes the ones that were published
+func (s *Store) relayBatch(publisher ports.EventPublisher) (int, error) {
+	type queued struct {
+		key     []byte
+		message outboxMessage
+	}
+
+	var batch []queued
+	err := s.view(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(outboxBucket).Cursor()
+		for key, value := cursor.First(); key != nil && len(batch) < outboxBatchSize; key, value = cursor.Next() {
+			var message outboxMessage
+			if err := json.Unmarshal(value, &message); err != nil {
+				return err
+			}
+			batch = append(batch, queued{key: append([]byte(nil), key...), message: message})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var publishErr error
+	for _, item := range batch {
+		// RawMessage is marshalled as is, so the broker receives the body that was queued
+		if publishErr = publisher.Publish(item.message.RoutingKey, item.message.Body); publishErr != nil {
+			break
+		}
+		published++
+	}
+
+	if published > 0 {
+		err := s.update(func(tx *bbolt.Tx) error {
+			bucket := tx.Bucket(outboxBucket)
+			for _, item := range batch[:published] {
+				if err := bucket.Delete(item.key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return published, err
+		}
+	}
+
+	return published, publishErr
+}
+
+// signalOutbox wakes the relay without blocking when it is already awake
+func (s *Store) signalOutbox() {
+	select {
+	case s.outboxReady <- struct{}{}:
+	default:
+	}
+}
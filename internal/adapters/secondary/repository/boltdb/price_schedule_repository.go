@@ -0,0 +1,182 @@
+package boltdb
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PriceScheduleRepository implements the ports.PriceScheduleRepository interface on bbolt.
+// Schedules are stored as BSON under their ObjectID. Writes check for overlapping schedules in the
+// transaction they store in.
+type PriceScheduleRepository struct {
+	store *Store
+}
+
+// NewPriceScheduleRepository creates a new instance of PriceScheduleRepository
+func NewPriceScheduleRepository(store *Store) ports.PriceScheduleRepository {
+	return &PriceScheduleRepository{
+		store: store,
+	}
+}
+
+// Create stores a new price schedule
+func (r *PriceScheduleRepository) Create(ctx context.Context, schedule *entities.PriceSchedule) (string, error) {
+	schedule.ID = primitive.NewObjectID()
+	schedule.TenantID = ports.TenantFromContext(ctx)
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		if err := checkConflicts(ctx, tx, schedule); err != nil {
+			return err
+		}
+		return putDocument(tx, priceSchedulesBucket, schedule.ID[:], schedule)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Price schedule created with ID: %s", schedule.ID.Hex())
+	return schedule.ID.Hex(), nil
+}
+
+// FindByID retrieves a price schedule by its ID
+func (r *PriceScheduleRepository) FindByID(ctx context.Context, id string) (*entities.PriceSchedule, error) {
+	objectID, err := parseID(id, ports.ErrPriceScheduleNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule *entities.PriceSchedule
+	err = r.store.view(func(tx *bbolt.Tx) error {
+		schedule, err = getPriceSchedule(ctx, tx, objectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// Update replaces an existing price schedule, keeping its stored creation time
+func (r *PriceScheduleRepository) Update(ctx context.Context, schedule *entities.PriceSchedule) error {
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		existing, err := getPriceSchedule(ctx, tx, schedule.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkConflicts(ctx, tx, schedule); err != nil {
+			return err
+		}
+
+		schedule.TenantID = existing.TenantID
+		schedule.CreatedAt = existing.CreatedAt
+		schedule.UpdatedAt = time.Now()
+		return putDocument(tx, priceSchedulesBucket, schedule.ID[:], schedule)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Price schedule with ID: %s updated successfully", schedule.ID.Hex())
+	return nil
+}
+
+// Delete removes a price schedule by its ID
+func (r *PriceScheduleRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := parseID(id, ports.ErrPriceScheduleNotFound)
+	if err != nil {
+		return err
+	}
+
+	err = r.store.update(func(tx *bbolt.Tx) error {
+		if _, err := getPriceSchedule(ctx, tx, objectID); err != nil {
+			return err
+		}
+		return tx.Bucket(priceSchedulesBucket).Delete(objectID[:])
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Price schedule with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindByProductID retrieves the schedules of a product ordered by start time
+func (r *PriceScheduleRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.PriceSchedule, error) {
+	return r.find(func(schedule *entities.PriceSchedule) bool {
+		return ownedBy(ctx, schedule.TenantID) && schedule.ProductID == productID
+	})
+}
+
+// FindActiveAt retrieves the schedules of every product of the tenant that apply at the given time
+func (r *PriceScheduleRepository) FindActiveAt(ctx context.Context, at time.Time) ([]*entities.PriceSchedule, error) {
+	return r.find(func(schedule *entities.PriceSchedule) bool {
+		return ownedBy(ctx, schedule.TenantID) && schedule.ActiveAt(at)
+	})
+}
+
+// FindChangingBetween retrieves the schedules of every tenant starting or ending in (from, to]
+func (r *PriceScheduleRepository) FindChangingBetween(ctx context.Context, from time.Time, to time.Time) ([]*entities.PriceSchedule, error) {
+	within := func(t time.Time) bool { return t.After(from) && !t.After(to) }
+	return r.find(func(schedule *entities.PriceSchedule) bool {
+		return within(schedule.StartsAt) || within(schedule.EndsAt)
+	})
+}
+
+// find decodes the schedules accepted by match ordered by start time, then by _id
+func (r *PriceScheduleRepository) find(match func(*entities.PriceSchedule) bool) ([]*entities.PriceSchedule, error) {
+	var schedules []*entities.PriceSchedule
+	err := r.store.view(func(tx *bbolt.Tx) error {
+		var err error
+		schedules, err = scanDocuments(tx, priceSchedulesBucket, match)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].StartsAt.Before(schedules[j].StartsAt)
+	})
+	return schedules, nil
+}
+
+// getPriceSchedule reads a price schedule of the tenant carried by ctx, returning
+// ports.ErrPriceScheduleNotFound when it does not exist or belongs to another tenant
+func getPriceSchedule(ctx context.Context, tx *bbolt.Tx, id primitive.ObjectID) (*entities.PriceSchedule, error) {
+	schedule, err := getDocument[entities.PriceSchedule](tx, priceSchedulesBucket, id[:])
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || !ownedBy(ctx, schedule.TenantID) {
+		return nil, ports.ErrPriceScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// checkConflicts returns ports.ErrPriceScheduleOverlap when a schedule of the tenant carried by ctx
+// conflicts with schedule
+func checkConflicts(ctx context.Context, tx *bbolt.Tx, schedule *entities.PriceSchedule) error {
+	conflicting, err := scanDocuments(tx, priceSchedulesBucket, func(other *entities.PriceSchedule) bool {
+		return ownedBy(ctx, other.TenantID) && schedule.ConflictsWith(other)
+	})
+	if err != nil {
+		return err
+	}
+	if len(conflicting) > 0 {
+		return ports.ErrPriceScheduleOverlap
+	}
+	return nil
+}
@@ -0,0 +1,458 @@
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"log"
+	"sort"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultStreamBatchSize is used when Stream is called without a batch size
+const defaultStreamBatchSize = 100
+
+// ProductRepository implements the ports.ProductRepository interface on bbolt.
+// Products are stored as BSON under their 12-byte ObjectID, so they decode exactly like documents
+// read from MongoDB, and the name and created_at indexes are kept in the same transaction.
+// Index keys start with the tenant, so lookups never leave the tenant carried by the context.
+type ProductRepository struct {
+	store *Store
+	// tx is set for repositories handed out by Store.Do; every call then joins that transaction
+	tx *bbolt.Tx
+}
+
+// NewProductRepository creates a new instance of ProductRepository
+func NewProductRepository(store *Store) ports.ProductRepository {
+	return &ProductRepository{
+		store: store,
+	}
+}
+
+// Create stores a new product and indexes it
+func (r *ProductRepository) Create(ctx context.Context, product *entities.Product) (string, error) {
+	product.ID = primitive.NewObjectID()
+	product.TenantID = ports.TenantFromContext(ctx)
+	product.CreatedAt = time.Now()
+	product.UpdatedAt = time.Now()
+
+	err := r.update(func(tx *bbolt.Tx) error {
+		data, err := bson.Marshal(product)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(productsBucket).Put(product.ID[:], data); err != nil {
+			return err
+		}
+		if err := putIndexes(tx, product); err != nil {
+			return err
+		}
+		return r.recordChange(tx, entities.ProductCreated, product.TenantID, product.ID, data)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Product created with ID: %s", product.ID.Hex())
+	return product.ID.Hex(), nil
+}
+
+// FindByID retrieves a product by its ID
+func (r *ProductRepository) FindByID(ctx context.Context, id string) (*entities.Product, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrProductNotFound
+	}
+
+	var product *entities.Product
+	err = r.view(func(tx *bbolt.Tx) error {
+		product, err = getProduct(ctx, tx, objectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Update replaces an existing product, keeping its stored creation time, and moves its name index entry
+func (r *ProductRepository) Update(ctx context.Context, product *entities.Product) error {
+	product.TenantID = ports.TenantFromContext(ctx)
+	product.UpdatedAt = time.Now()
+
+	err := r.update(func(tx *bbolt.Tx) error {
+		existing, err := getProduct(ctx, tx, product.ID)
+		if err != nil {
+			return err
+		}
+
+		stored := *product
+		stored.CreatedAt = existing.CreatedAt
+		data, err := bson.Marshal(&stored)
+		if err != nil {
+			return err
+		}
+
+		if err := deleteIndexes(tx, existing); err != nil {
+			return err
+		}
+		if err := tx.Bucket(productsBucket).Put(stored.ID[:], data); err != nil {
+			return err
+		}
+		if err := putIndexes(tx, &stored); err != nil {
+			return err
+		}
+		return r.recordChange(tx, entities.ProductUpdated, stored.TenantID, stored.ID, data)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Product with ID: %s updated successfully", product.ID.Hex())
+	return nil
+}
+
+// Delete removes a product and its index entries
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrProductNotFound
+	}
+
+	err = r.update(func(tx *bbolt.Tx) error {
+		existing, err := getProduct(ctx, tx, objectID)
+		if err != nil {
+			return err
+		}
+		if err := deleteIndexes(tx, existing); err != nil {
+			return err
+		}
+		if err := tx.Bucket(productsBucket).Delete(objectID[:]); err != nil {
+			return err
+		}
+		return r.recordChange(tx, entities.ProductDeleted, existing.TenantID, objectID, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Product with ID: %s deleted successfully", id)
+	return nil
+}
+
+// Count returns the number of products of the tenant by counting its created_at index entries
+func (r *ProductRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.view(func(tx *bbolt.Tx) error {
+		prefix := tenantPrefix(ports.TenantFromContext(ctx))
+		cursor := tx.Bucket(productsByCreatedBucket).Cursor()
+		for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// FindAll retrieves every product of the tenant in _id order
+func (r *ProductRepository) FindAll(ctx context.Context) ([]*entities.Product, error) {
+	return r.scan(ctx, func(*entities.Product) bool { return true })
+}
+
+// FindByCategoryIDs retrieves the products assigned to any of the given categories
+func (r *ProductRepository) FindByCategoryIDs(ctx context.Context, categoryIDs []string) ([]*entities.Product, error) {
+	wanted := make(map[string]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		wanted[id] = true
+	}
+
+	return r.scan(ctx, func(product *entities.Product) bool {
+		for _, id := range product.CategoryIDs {
+			if wanted[id] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FindByFilter retrieves the products matching every set field of the filter in _id order.
+// A name is looked up in the name index and a creation time range in the created_at index;
+// other filters scan every product.
+func (r *ProductRepository) FindByFilter(ctx context.Context, filter ports.ProductFilter) ([]*entities.Product, error) {
+	if filter.Name == "" && filter.CreatedFrom.IsZero() && filter.CreatedTo.IsZero() {
+		return r.scan(ctx, filter.Matches)
+	}
+
+	tenantID := ports.TenantFromContext(ctx)
+	var products []*entities.Product
+	err := r.view(func(tx *bbolt.Tx) error {
+		var ids [][]byte
+		if filter.Name != "" {
+			ids = nameIndexLookup(tx, tenantID, filter.Name)
+		} else {
+			ids = createdIndexRange(tx, tenantID, filter.CreatedFrom, filter.CreatedTo)
+		}
+		sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i], ids[j]) < 0 })
+
+		for _, id := range ids {
+			product, err := decodeProduct(tx.Bucket(productsBucket).Get(id))
+			if err != nil {
+				return err
+			}
+			if filter.Matches(product) {
+				products = append(products, product)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// Stream calls fn for every product of the tenant in _id order, reading batchSize products per
+// transaction. No transaction is open while fn runs, so fn may write to the repository.
+func (r *ProductRepository) Stream(ctx context.Context, batchSize int32, fn func(*entities.Product) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+	tenantID := ports.TenantFromContext(ctx)
+
+	var after []byte
+	for {
+		batch := make([]*entities.Product, 0, batchSize)
+		read := 0
+		err := r.view(func(tx *bbolt.Tx) error {
+			cursor := tx.Bucket(productsBucket).Cursor()
+			key, value := cursor.First()
+			if after != nil {
+				key, value = cursor.Seek(after)
+				if bytes.Equal(key, after) {
+					key, value = cursor.Next()
+				}
+			}
+
+			// Products of other tenants are skipped but still count towards the batch,
+			// so a transaction never reads more than batchSize products
+			for ; key != nil && read < int(batchSize); key, value = cursor.Next() {
+				read++
+				after = append([]byte(nil), key...)
+
+				product, err := decodeProduct(value)
+				if err != nil {
+					return err
+				}
+				if product.TenantID == tenantID {
+					batch = append(batch, product)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if read == 0 {
+			return nil
+		}
+
+		for _, product := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(product); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// FindDueTransitions scans the products of every tenant for a status change due at the given time
+func (r *ProductRepository) FindDueTransitions(ctx context.Context, at time.Time) ([]*entities.Product, error) {
+	var products []*entities.Product
+	err := r.view(func(tx *bbolt.Tx) error {
+		return tx.Bucket(productsBucket).ForEach(func(key, value []byte) error {
+			product, err := decodeProduct(value)
+			if err != nil {
+				return err
+			}
+			if product.ScheduledStatus(at) != "" {
+				products = append(products, product)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// scan decodes every product of the tenant accepted by match in _id order
+func (r *ProductRepository) scan(ctx context.Context, match func(*entities.Product) bool) ([]*entities.Product, error) {
+	tenantID := ports.TenantFromContext(ctx)
+	var products []*entities.Product
+	err := r.view(func(tx *bbolt.Tx) error {
+		return tx.Bucket(productsBucket).ForEach(func(key, value []byte) error {
+			product, err := decodeProduct(value)
+			if err != nil {
+				return err
+			}
+			if product.TenantID == tenantID && match(product) {
+				products = append(products, product)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// update runs fn in the repository's transaction, or in a new read-write transaction
+func (r *ProductRepository) update(fn func(tx *bbolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.update(fn)
+}
+
+// view runs fn in the repository's transaction, or in a new read-only transaction
+func (r *ProductRepository) view(fn func(tx *bbolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.view(fn)
+}
+
+// recordChange hands the change to watchers once the transaction commits; data is nil for deletes
+func (r *ProductRepository) recordChange(tx *bbolt.Tx, eventType string, tenantID string, id primitive.ObjectID, data []byte) error {
+	if r.store.changes == nil {
+		return nil
+	}
+
+	event := &entities.ProductEvent{Type: eventType, TenantID: tenantID, ProductID: id.Hex()}
+	if data != nil {
+		// Decode a copy now, since data is only valid while the transaction is open
+		product, err := decodeProduct(data)
+		if err != nil {
+			return err
+		}
+		event.Product = product
+	}
+
+	tx.OnCommit(func() { r.store.changes.Append(event) })
+	return nil
+}
+
+// getProduct reads and decodes a product of the tenant carried by ctx, returning
+// ports.ErrProductNotFound when it does not exist or belongs to another tenant
+func getProduct(ctx context.Context, tx *bbolt.Tx, id primitive.ObjectID) (*entities.Product, error) {
+	data := tx.Bucket(productsBucket).Get(id[:])
+	if data == nil {
+		return nil, ports.ErrProductNotFound
+	}
+	product, err := decodeProduct(data)
+	if err != nil {
+		return nil, err
+	}
+	if product.TenantID != ports.TenantFromContext(ctx) {
+		return nil, ports.ErrProductNotFound
+	}
+	return product, nil
+}
+
+// decodeProduct decodes a stored product. The returned product does not reference data.
+func decodeProduct(data []byte) (*entities.Product, error) {
+	var product entities.Product
+	if err := bson.Unmarshal(data, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// putIndexes adds the name and created_at index entries of a product
+func putIndexes(tx *bbolt.Tx, product *entities.Product) error {
+	if err := tx.Bucket(productsByNameBucket).Put(nameKey(product.TenantID, product.Name, product.ID), nil); err != nil {
+		return err
+	}
+	return tx.Bucket(productsByCreatedBucket).Put(createdKey(product.TenantID, product.CreatedAt, product.ID), nil)
+}
+
+// deleteIndexes removes the name and created_at index entries of a stored product
+func deleteIndexes(tx *bbolt.Tx, product *entities.Product) error {
+	if err := tx.Bucket(productsByNameBucket).Delete(nameKey(product.TenantID, product.Name, product.ID)); err != nil {
+		return err
+	}
+	return tx.Bucket(productsByCreatedBucket).Delete(createdKey(product.TenantID, product.CreatedAt, product.ID))
+}
+
+// tenantPrefix is the index key prefix shared by the products of a tenant: its ID and a zero byte
+func tenantPrefix(tenantID string) []byte {
+	return append([]byte(tenantID), 0)
+}
+
+// nameKey builds a name index key: the tenant prefix, the name, a zero byte, then the product ID
+func nameKey(tenantID string, name string, id primitive.ObjectID) []byte {
+	key := tenantPrefix(tenantID)
+	key = append(key, name...)
+	key = append(key, 0)
+	return append(key, id[:]...)
+}
+
+// createdKey builds a created_at index key: the tenant prefix, the creation time in big-endian
+// milliseconds, the precision it is stored with, then the product ID, so keys sort by creation time
+func createdKey(tenantID string, createdAt time.Time, id primitive.ObjectID) []byte {
+	key := tenantPrefix(tenantID)
+	key = binary.BigEndian.AppendUint64(key, uint64(createdAt.UnixMilli()))
+	return append(key, id[:]...)
+}
+
+// nameIndexLookup returns the IDs of the products of a tenant named name
+func nameIndexLookup(tx *bbolt.Tx, tenantID string, name string) [][]byte {
+	prefix := append(tenantPrefix(tenantID), name...)
+	prefix = append(prefix, 0)
+
+	var ids [][]byte
+	cursor := tx.Bucket(productsByNameBucket).Cursor()
+	for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+		// Copy the ID, since keys are only valid while the transaction is open
+		ids = append(ids, append([]byte(nil), key[len(prefix):]...))
+	}
+	return ids
+}
+
+// createdIndexRange returns the IDs of the products of a tenant created in [from, to); zero times
+// leave the range open
+func createdIndexRange(tx *bbolt.Tx, tenantID string, from time.Time, to time.Time) [][]byte {
+	prefix := tenantPrefix(tenantID)
+
+	var ids [][]byte
+	cursor := tx.Bucket(productsByCreatedBucket).Cursor()
+
+	key, _ := cursor.Seek(prefix)
+	if !from.IsZero() {
+		key, _ = cursor.Seek(createdKey(tenantID, from, primitive.NilObjectID))
+	}
+	for ; key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+		millis := key[len(prefix) : len(prefix)+8]
+		if !to.IsZero() && int64(binary.BigEndian.Uint64(millis)) >= to.UnixMilli() {
+			break
+		}
+		ids = append(ids, append([]byte(nil), key[len(prefix)+8:]...))
+	}
+	return ids
+}
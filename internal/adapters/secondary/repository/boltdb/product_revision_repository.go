@@ -0,0 +1,180 @@
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductRevisionRepository implements the ports.ProductRevisionRepository interface on bbolt.
+// Revisions are stored as BSON under the tenant, the product ID, each followed by a zero byte, and
+// the big-endian revision number, so the revisions of a product are adjacent and in order.
+type ProductRevisionRepository struct {
+	store *Store
+	// tx is set for repositories handed out by Store.Do; every call then joins that transaction
+	tx *bbolt.Tx
+}
+
+// NewProductRevisionRepository creates a new instance of ProductRevisionRepository
+func NewProductRevisionRepository(store *Store) ports.ProductRevisionRepository {
+	return &ProductRevisionRepository{
+		store: store,
+	}
+}
+
+// Append stores a revision numbered right after the latest revision of its product
+func (r *ProductRevisionRepository) Append(ctx context.Context, revision *entities.ProductRevision) error {
+	revision.TenantID = ports.TenantFromContext(ctx)
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+
+	return r.update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(productRevisionsBucket)
+
+		var latest int64
+		prefix := revisionPrefix(revision.TenantID, revision.ProductID)
+		// Seek past the last possible key of the product and step back to its latest revision
+		cursor := bucket.Cursor()
+		key, _ := cursor.Seek(revisionKey(revision.TenantID, revision.ProductID, -1))
+		if key == nil {
+			key, _ = cursor.Last()
+		} else {
+			key, _ = cursor.Prev()
+		}
+		if key != nil && bytes.HasPrefix(key, prefix) {
+			latest = int64(binary.BigEndian.Uint64(key[len(prefix):]))
+		}
+
+		revision.ID = primitive.NewObjectID()
+		revision.Revision = latest + 1
+		data, err := bson.Marshal(revision)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(revisionKey(revision.TenantID, revision.ProductID, revision.Revision), data)
+	})
+}
+
+// FindByProductID retrieves every revision of a product, oldest first
+func (r *ProductRevisionRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.ProductRevision, error) {
+	var revisions []*entities.ProductRevision
+	err := r.view(func(tx *bbolt.Tx) error {
+		prefix := revisionPrefix(ports.TenantFromContext(ctx), productID)
+		cursor := tx.Bucket(productRevisionsBucket).Cursor()
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			revision, err := decodeRevision(value)
+			if err != nil {
+				return err
+			}
+			revisions = append(revisions, revision)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// FindByRevision retrieves one revision of a product
+func (r *ProductRevisionRepository) FindByRevision(ctx context.Context, productID string, revision int64) (*entities.ProductRevision, error) {
+	if revision < 1 {
+		return nil, ports.ErrRevisionNotFound
+	}
+
+	var found *entities.ProductRevision
+	err := r.view(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(productRevisionsBucket).Get(revisionKey(ports.TenantFromContext(ctx), productID, revision))
+		if data == nil {
+			return ports.ErrRevisionNotFound
+		}
+
+		var err error
+		found, err = decodeRevision(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// FindAsOf retrieves the latest revision of a product created at or before at.
+// Revisions are appended in time order, so the scan stops at the first later one.
+func (r *ProductRevisionRepository) FindAsOf(ctx context.Context, productID string, at time.Time) (*entities.ProductRevision, error) {
+	var found *entities.ProductRevision
+	err := r.view(func(tx *bbolt.Tx) error {
+		prefix := revisionPrefix(ports.TenantFromContext(ctx), productID)
+		cursor := tx.Bucket(productRevisionsBucket).Cursor()
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			revision, err := decodeRevision(value)
+			if err != nil {
+				return err
+			}
+			if revision.CreatedAt.After(at) {
+				break
+			}
+			found = revision
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ports.ErrRevisionNotFound
+	}
+
+	return found, nil
+}
+
+// update runs fn in the repository's transaction, or in a new read-write transaction
+func (r *ProductRevisionRepository) update(fn func(tx *bbolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.update(fn)
+}
+
+// view runs fn in the repository's transaction, or in a new read-only transaction
+func (r *ProductRevisionRepository) view(fn func(tx *bbolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.view(fn)
+}
+
+// decodeRevision decodes a stored revision. The returned revision does not reference data.
+func decodeRevision(data []byte) (*entities.ProductRevision, error) {
+	var revision entities.ProductRevision
+	if err := bson.Unmarshal(data, &revision); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// revisionPrefix is the key prefix shared by the revisions of a product of a tenant
+func revisionPrefix(tenantID string, productID string) []byte {
+	key := tenantPrefix(tenantID)
+	key = append(key, productID...)
+	return append(key, 0)
+}
+
+// revisionKey builds the key of one revision; -1 sorts after every revision of the product
+func revisionKey(tenantID string, productID string, revision int64) []byte {
+	key := revisionPrefix(tenantID, productID)
+	number := make([]byte, 8)
+	binary.BigEndian.PutUint64(number, uint64(revision))
+	return append(key, number...)
+}
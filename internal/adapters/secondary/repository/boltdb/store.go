@@ -0,0 +1,309 @@
+// Package boltdb implements the repository ports on top of bbolt, an embedded single-file
+// key/value store, for installations that cannot run MongoDB.
+package boltdb
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Bucket names; products_by_* and api_keys_by_hash hold the secondary indexes of the products and
+// api_keys buckets
+var (
+	productsBucket          = []byte("products")
+	productsByNameBucket    = []byte("products_by_name")
+	productsByCreatedBucket = []byte("products_by_created_at")
+	productRevisionsBucket  = []byte("product_revisions")
+	auditBucket             = []byte("audit_log")
+	outboxBucket            = []byte("outbox")
+	metaBucket              = []byte("meta")
+	priceSchedulesBucket    = []byte("price_schedules")
+	categoriesBucket        = []byte("categories")
+	stockLevelsBucket       = []byte("stock_levels")
+	reservationsBucket      = []byte("reservations")
+	webhooksBucket          = []byte("webhooks")
+	webhookDeliveriesBucket = []byte("webhook_deliveries")
+	pendingWebhooksBucket   = []byte("pending_webhook_deliveries")
+	apiKeysBucket           = []byte("api_keys")
+	apiKeysByHashBucket     = []byte("api_keys_by_hash")
+
+	schemaVersionKey = []byte("schema_version")
+)
+
+// buckets lists every bucket, created when missing as the file is opened
+var buckets = [][]byte{
+	productsBucket, productsByNameBucket, productsByCreatedBucket, productRevisionsBucket, auditBucket, outboxBucket, metaBucket,
+	priceSchedulesBucket, categoriesBucket, stockLevelsBucket, reservationsBucket, webhooksBucket, webhookDeliveriesBucket,
+	pendingWebhooksBucket, apiKeysBucket, apiKeysByHashBucket,
+}
+
+// schemaVersion is the key layout written by this version. Version 1 keyed the indexes and
+// revisions without a tenant; such files are upgraded when they are opened.
+const schemaVersion = 2
+
+// openTimeout bounds the wait for the file lock, which another process may hold
+const openTimeout = 5 * time.Second
+
+// compactTxMaxSize is how many bytes are copied per transaction while compacting
+const compactTxMaxSize = 64 << 20
+
+// Store owns the bbolt database file. Only one process can open the file at a time.
+// Besides the repositories kept in the file it implements the ports.ProductUnitOfWork interface,
+// writing products, their revisions, their audit entries and their events in one transaction, and the ports.EventPublisher interface by
+// queueing events in a durable outbox that RelayOutbox forwards to the message broker.
+type Store struct {
+	path    string
+	changes ports.ProductChangeRecorder
+
+	// mu is held for reading by every transaction and for writing while the file is swapped during compaction
+	mu sync.RWMutex
+	db *bbolt.DB
+
+	// outboxReady is signalled whenever a transaction that queued events commits
+	outboxReady chan struct{}
+}
+
+// Open opens or creates the database file at path. Committed product writes are appended to
+// changes, which may be nil when nobody watches products.
+func Open(path string, changes ports.ProductChangeRecorder) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		path:        path,
+		changes:     changes,
+		outboxReady: make(chan struct{}, 1),
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Opened bbolt database %s", path)
+	return s, nil
+}
+
+// open opens the file and creates any missing bucket
+func (s *Store) open() error {
+	db, err := bbolt.Open(s.path, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("open %s: %w", s.path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return upgrade(tx)
+	})
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	s.db = db
+	return nil
+}
+
+// upgrade rewrites the keys of a file written by an older version. Products and revisions stored
+// before tenants were introduced are assigned to ports.DefaultTenant.
+func upgrade(tx *bbolt.Tx) error {
+	meta := tx.Bucket(metaBucket)
+	if version := meta.Get(schemaVersionKey); version != nil && binary.BigEndian.Uint64(version) >= schemaVersion {
+		return nil
+	}
+
+	for _, name := range [][]byte{productsByNameBucket, productsByCreatedBucket} {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+
+	products, err := readAll(tx.Bucket(productsBucket))
+	if err != nil {
+		return err
+	}
+	for _, data := range products {
+		product, err := decodeProduct(data)
+		if err != nil {
+			return err
+		}
+		if product.TenantID == "" {
+			product.TenantID = ports.DefaultTenant
+			if data, err = bson.Marshal(product); err != nil {
+				return err
+			}
+			if err := tx.Bucket(productsBucket).Put(product.ID[:], data); err != nil {
+				return err
+			}
+		}
+		if err := putIndexes(tx, product); err != nil {
+			return err
+		}
+	}
+
+	revisions, err := readAll(tx.Bucket(productRevisionsBucket))
+	if err != nil {
+		return err
+	}
+	if err := tx.DeleteBucket(productRevisionsBucket); err != nil {
+		return err
+	}
+	bucket, err := tx.CreateBucket(productRevisionsBucket)
+	if err != nil {
+		return err
+	}
+	for _, data := range revisions {
+		revision, err := decodeRevision(data)
+		if err != nil {
+			return err
+		}
+		if revision.TenantID == "" {
+			revision.TenantID = ports.DefaultTenant
+		}
+		if data, err = bson.Marshal(revision); err != nil {
+			return err
+		}
+		if err := bucket.Put(revisionKey(revision.TenantID, revision.ProductID, revision.Revision), data); err != nil {
+			return err
+		}
+	}
+
+	if len(products) > 0 || len(revisions) > 0 {
+		log.Printf("Upgraded %d products and %d revisions to schema version %d", len(products), len(revisions), schemaVersion)
+	}
+	return meta.Put(schemaVersionKey, binary.BigEndian.AppendUint64(nil, schemaVersion))
+}
+
+// readAll copies every value of a bucket, so the bucket can be changed afterwards
+func readAll(bucket *bbolt.Bucket) ([][]byte, error) {
+	var values [][]byte
+	err := bucket.ForEach(func(key, value []byte) error {
+		values = append(values, append([]byte(nil), value...))
+		return nil
+	})
+	return values, err
+}
+
+// Close closes the database file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+// Do runs fn in a single read-write transaction: the product writes, their revisions, their audit entries and the events
+// queued through the publisher are committed together, or rolled back together when fn returns an error
+func (s *Store) Do(ctx context.Context, fn func(tx ports.ProductTx) error) error {
+	return s.update(func(tx *bbolt.Tx) error {
+		return fn(ports.ProductTx{
+			Products:  &ProductRepository{store: s, tx: tx},
+			Revisions: &ProductRevisionRepository{store: s, tx: tx},
+			Audit:     &AuditRepository{store: s, tx: tx},
+			Events:    &txPublisher{store: s, tx: tx},
+		})
+	})
+}
+
+// Publish queues an event in the outbox in its own transaction
+func (s *Store) Publish(routingKey string, message interface{}) error {
+	return s.update(func(tx *bbolt.Tx) error {
+		return (&txPublisher{store: s, tx: tx}).Publish(routingKey, message)
+	})
+}
+
+// Backup writes a consistent snapshot of the database to w while reads and writes carry on.
+// It returns the number of bytes written.
+func (s *Store) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	var written int64
+	err := s.view(func(tx *bbolt.Tx) error {
+		var err error
+		written, err = tx.WriteTo(w)
+		return err
+	})
+	return written, err
+}
+
+// Compact rewrites the database into a new file without the free pages left behind by deletes and
+// updates, then swaps it in place of the old file. Transactions wait while the files are swapped.
+func (s *Store) Compact(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	compactPath := s.path + ".compact"
+	if err := os.Remove(compactPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	dst, err := bbolt.Open(compactPath, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return err
+	}
+	if err := bbolt.Compact(dst, s.db, compactTxMaxSize); err != nil {
+		dst.Close()
+		os.Remove(compactPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(compactPath)
+		return err
+	}
+
+	before := fileSize(s.path)
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(compactPath, s.path); err != nil {
+		// Keep serving from the original file
+		if reopenErr := s.open(); reopenErr != nil {
+			return fmt.Errorf("compaction failed: %v; reopening failed: %w", err, reopenErr)
+		}
+		return err
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	log.Printf("Compacted bbolt database %s from %d to %d bytes", s.path, before, fileSize(s.path))
+	return nil
+}
+
+// update runs fn in a read-write transaction
+func (s *Store) update(fn func(tx *bbolt.Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.Update(fn)
+}
+
+// view runs fn in a read-only transaction
+func (s *Store) view(fn func(tx *bbolt.Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.View(fn)
+}
+
+// fileSize returns the size of the file at path, or zero when it cannot be read
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,366 @@
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookRepository implements the ports.WebhookRepository interface on bbolt.
+// Subscriptions are stored as BSON under their ObjectID.
+type WebhookRepository struct {
+	store *Store
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(store *Store) ports.WebhookRepository {
+	return &WebhookRepository{
+		store: store,
+	}
+}
+
+// Create stores a new webhook subscription
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entities.WebhookSubscription) (string, error) {
+	webhook.ID = primitive.NewObjectID()
+	webhook.TenantID = ports.TenantFromContext(ctx)
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		return putDocument(tx, webhooksBucket, webhook.ID[:], webhook)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Webhook created with ID: %s", webhook.ID.Hex())
+	return webhook.ID.Hex(), nil
+}
+
+// FindByID retrieves a webhook subscription by its ID
+func (r *WebhookRepository) FindByID(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	objectID, err := parseID(id, ports.ErrWebhookNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook *entities.WebhookSubscription
+	err = r.store.view(func(tx *bbolt.Tx) error {
+		webhook, err = getWebhook(ctx, tx, objectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Update replaces an existing webhook subscription, keeping its stored creation time
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entities.WebhookSubscription) error {
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		existing, err := getWebhook(ctx, tx, webhook.ID)
+		if err != nil {
+			return err
+		}
+
+		webhook.TenantID = existing.TenantID
+		webhook.CreatedAt = existing.CreatedAt
+		webhook.UpdatedAt = time.Now()
+		return putDocument(tx, webhooksBucket, webhook.ID[:], webhook)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Webhook with ID: %s updated successfully", webhook.ID.Hex())
+	return nil
+}
+
+// Delete removes a webhook subscription by its ID
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := parseID(id, ports.ErrWebhookNotFound)
+	if err != nil {
+		return err
+	}
+
+	err = r.store.update(func(tx *bbolt.Tx) error {
+		if _, err := getWebhook(ctx, tx, objectID); err != nil {
+			return err
+		}
+		return tx.Bucket(webhooksBucket).Delete(objectID[:])
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Webhook with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves every webhook subscription of the tenant in _id order
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	return r.find(ctx, func(*entities.WebhookSubscription) bool { return true })
+}
+
+// FindActiveByEventType retrieves the active subscriptions listening to eventType,
+// including those that did not restrict their event types
+func (r *WebhookRepository) FindActiveByEventType(ctx context.Context, eventType string) ([]*entities.WebhookSubscription, error) {
+	return r.find(ctx, func(webhook *entities.WebhookSubscription) bool {
+		return webhook.Active && webhook.Subscribes(eventType)
+	})
+}
+
+// RecordFailure increments the failure count of a subscription and disables it once the count
+// reaches disableAfter, in one transaction so concurrent failures are all counted
+func (r *WebhookRepository) RecordFailure(ctx context.Context, id string, disableAfter int, at time.Time) (bool, error) {
+	objectID, err := parseID(id, ports.ErrWebhookNotFound)
+	if err != nil {
+		return false, err
+	}
+
+	disabled := false
+	err = r.store.update(func(tx *bbolt.Tx) error {
+		webhook, err := getWebhook(ctx, tx, objectID)
+		if err != nil {
+			return err
+		}
+
+		webhook.FailureCount++
+		if webhook.Active && webhook.FailureCount >= disableAfter {
+			webhook.Active = false
+			webhook.DisabledAt = &at
+			webhook.UpdatedAt = at
+			disabled = true
+		}
+		return putDocument(tx, webhooksBucket, objectID[:], webhook)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return disabled, nil
+}
+
+// ResetFailures sets the failure count of a subscription back to zero
+func (r *WebhookRepository) ResetFailures(ctx context.Context, id string) error {
+	objectID, err := parseID(id, ports.ErrWebhookNotFound)
+	if err != nil {
+		return err
+	}
+
+	return r.store.update(func(tx *bbolt.Tx) error {
+		webhook, err := getWebhook(ctx, tx, objectID)
+		if err != nil {
+			return err
+		}
+		webhook.FailureCount = 0
+		return putDocument(tx, webhooksBucket, objectID[:], webhook)
+	})
+}
+
+// find decodes the webhook subscriptions of the tenant accepted by match in _id order
+func (r *WebhookRepository) find(ctx context.Context, match func(*entities.WebhookSubscription) bool) ([]*entities.WebhookSubscription, error) {
+	var webhooks []*entities.WebhookSubscription
+	err := r.store.view(func(tx *bbolt.Tx) error {
+		var err error
+		webhooks, err = scanDocuments(tx, webhooksBucket, func(webhook *entities.WebhookSubscription) bool {
+			return ownedBy(ctx, webhook.TenantID) && match(webhook)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// getWebhook reads a webhook subscription of the tenant carried by ctx, returning
+// ports.ErrWebhookNotFound when it does not exist or belongs to another tenant
+func getWebhook(ctx context.Context, tx *bbolt.Tx, id primitive.ObjectID) (*entities.WebhookSubscription, error) {
+	webhook, err := getDocument[entities.WebhookSubscription](tx, webhooksBucket, id[:])
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || !ownedBy(ctx, webhook.TenantID) {
+		return nil, ports.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// WebhookDeliveryRepository implements the ports.WebhookDeliveryRepository interface on bbolt.
+// Deliveries are stored as BSON under the tenant prefix, the subscription ID and their own ID, so
+// the log of a subscription is adjacent.
+type WebhookDeliveryRepository struct {
+	store *Store
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository
+func NewWebhookDeliveryRepository(store *Store) ports.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		store: store,
+	}
+}
+
+// Create appends a delivery attempt to the log
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *entities.WebhookDelivery) (string, error) {
+	delivery.ID = primitive.NewObjectID()
+	delivery.TenantID = ports.TenantFromContext(ctx)
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		key := append(deliveryPrefix(delivery.TenantID, delivery.SubscriptionID), delivery.ID[:]...)
+		return putDocument(tx, webhookDeliveriesBucket, key, delivery)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return delivery.ID.Hex(), nil
+}
+
+// FindBySubscription retrieves the latest delivery attempts for a webhook subscription
+func (r *WebhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID string, limit int64) ([]*entities.WebhookDelivery, error) {
+	objectID, err := parseID(subscriptionID, ports.ErrWebhookNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*entities.WebhookDelivery
+	err = r.store.view(func(tx *bbolt.Tx) error {
+		prefix := deliveryPrefix(ports.TenantFromContext(ctx), objectID)
+		cursor := tx.Bucket(webhookDeliveriesBucket).Cursor()
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			delivery, err := decodeDocument[entities.WebhookDelivery](value)
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if limit > 0 && int64(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// deliveryPrefix builds the key prefix shared by the deliveries of a subscription
+func deliveryPrefix(tenantID string, subscriptionID primitive.ObjectID) []byte {
+	return append(tenantPrefix(tenantID), subscriptionID[:]...)
+}
+
+// PendingWebhookDeliveryRepository implements the ports.PendingWebhookDeliveryRepository interface
+// on bbolt. Deliveries are stored as BSON under their ObjectID.
+type PendingWebhookDeliveryRepository struct {
+	store *Store
+}
+
+// NewPendingWebhookDeliveryRepository creates a new instance of PendingWebhookDeliveryRepository
+func NewPendingWebhookDeliveryRepository(store *Store) ports.PendingWebhookDeliveryRepository {
+	return &PendingWebhookDeliveryRepository{
+		store: store,
+	}
+}
+
+// Create queues deliveries for the tenant in one transaction
+func (r *PendingWebhookDeliveryRepository) Create(ctx context.Context, deliveries []*entities.PendingWebhookDelivery) error {
+	return r.store.update(func(tx *bbolt.Tx) error {
+		for _, delivery := range deliveries {
+			delivery.ID = primitive.NewObjectID()
+			delivery.TenantID = ports.TenantFromContext(ctx)
+			delivery.CreatedAt = time.Now()
+			if err := putDocument(tx, pendingWebhooksBucket, delivery.ID[:], delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClaimDue leases up to limit deliveries of every tenant due at now, oldest first, in one
+// transaction so a delivery is never claimed twice
+func (r *PendingWebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int64) ([]*entities.PendingWebhookDelivery, error) {
+	var claimed []*entities.PendingWebhookDelivery
+	err := r.store.update(func(tx *bbolt.Tx) error {
+		due, err := scanDocuments(tx, pendingWebhooksBucket, func(delivery *entities.PendingWebhookDelivery) bool {
+			return !delivery.NextAttemptAt.After(now)
+		})
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(due, func(i, j int) bool {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		})
+		if limit > 0 && int64(len(due)) > limit {
+			due = due[:limit]
+		}
+
+		for _, delivery := range due {
+			leased := *delivery
+			leased.NextAttemptAt = leaseUntil
+			if err := putDocument(tx, pendingWebhooksBucket, delivery.ID[:], &leased); err != nil {
+				return err
+			}
+		}
+		claimed = due
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// Reschedule records an attempt of a delivery and when to try it again
+func (r *PendingWebhookDeliveryRepository) Reschedule(ctx context.Context, id string, attempts int, next time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+
+	return r.store.update(func(tx *bbolt.Tx) error {
+		delivery, err := getDocument[entities.PendingWebhookDelivery](tx, pendingWebhooksBucket, objectID[:])
+		if err != nil || delivery == nil || !ownedBy(ctx, delivery.TenantID) {
+			return err
+		}
+		delivery.Attempts = attempts
+		delivery.NextAttemptAt = next
+		return putDocument(tx, pendingWebhooksBucket, objectID[:], delivery)
+	})
+}
+
+// Delete removes a delivery from the queue
+func (r *PendingWebhookDeliveryRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+
+	return r.store.update(func(tx *bbolt.Tx) error {
+		delivery, err := getDocument[entities.PendingWebhookDelivery](tx, pendingWebhooksBucket, objectID[:])
+		if err != nil || delivery == nil || !ownedBy(ctx, delivery.TenantID) {
+			return err
+		}
+		return tx.Bucket(pendingWebhooksBucket).Delete(objectID[:])
+	})
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// APIKeyRepository implements the ports.APIKeyRepository interface. Keys are looked up by the
+// unique hash index created by migration 010.
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository
+func NewAPIKeyRepository(db *mongo.Database) ports.APIKeyRepository {
+	return &APIKeyRepository{
+		collection: db.Collection("api_keys"),
+	}
+}
+
+// Create inserts a new API key into the MongoDB collection
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) (string, error) {
+	key.ID = primitive.NewObjectID()
+	key.TenantID = ports.TenantFromContext(ctx)
+	key.CreatedAt = time.Now()
+	key.UpdatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, key); err != nil {
+		return "", err
+	}
+
+	log.Printf("API key created with ID: %s", key.ID.Hex())
+	return key.ID.Hex(), nil
+}
+
+// FindByID retrieves an API key by its ID from the MongoDB collection
+func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*entities.APIKey, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrAPIKeyNotFound
+	}
+
+	return r.findOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+}
+
+// Update replaces an existing API key in the MongoDB collection
+func (r *APIKeyRepository) Update(ctx context.Context, key *entities.APIKey) error {
+	key.TenantID = ports.TenantFromContext(ctx)
+	key.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.M{"_id": key.ID}), key)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrAPIKeyNotFound
+	}
+
+	log.Printf("API key with ID: %s updated successfully", key.ID.Hex())
+	return nil
+}
+
+// FindAll retrieves every API key of the tenant from the MongoDB collection
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*entities.APIKey, error) {
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{}), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*entities.APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FindByHash retrieves the API key with the given hash, whatever its tenant
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*entities.APIKey, error) {
+	return r.findOne(ctx, bson.M{"hash": hash})
+}
+
+// TouchLastUsed records when an API key of any tenant was last used
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrAPIKeyNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_used_at": usedAt}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// findOne decodes the API key matching filter
+func (r *APIKeyRepository) findOne(ctx context.Context, filter bson.M) (*entities.APIKey, error) {
+	var key entities.APIKey
+	err := r.collection.FindOne(ctx, filter).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"context"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditRepository implements the ports.AuditRepository interface. The collection is only ever
+// inserted into; the unique (tenant_id, sequence) index created by migration 011 keeps each
+// tenant's chain linear.
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditRepository creates a new instance of AuditRepository
+func NewAuditRepository(db *mongo.Database) ports.AuditRepository {
+	return &AuditRepository{
+		collection: db.Collection("audit_log"),
+	}
+}
+
+// Append seals the entry after the latest entry of the tenant and inserts it. An entry sealed
+// after the same latest entry as a concurrent writer's is rejected by the unique index, in which
+// case it is sealed again after the new latest entry.
+func (r *AuditRepository) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	entry.TenantID = ports.TenantFromContext(ctx)
+
+	var err error
+	for attempt := 0; attempt < appendAttempts; attempt++ {
+		var prev *entities.AuditEntry
+		prev, err = r.latest(ctx)
+		if err != nil {
+			return err
+		}
+
+		entry.ID = primitive.NewObjectID()
+		entry.Seal(prev)
+		_, err = r.collection.InsertOne(ctx, entry)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// Find retrieves up to filter.Limit matching entries in sequence order
+func (r *AuditRepository) Find(ctx context.Context, filter ports.AuditFilter) ([]*entities.AuditEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cursor, err := r.collection.Find(ctx, auditQuery(ctx, filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*entities.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Stream calls fn for every matching entry in sequence order, decoding one at a time
+func (r *AuditRepository) Stream(ctx context.Context, filter ports.AuditFilter, fn func(*entities.AuditEntry) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+	cursor, err := r.collection.Find(ctx, auditQuery(ctx, filter), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry entities.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// latest returns the latest entry of the tenant, or nil when its log is empty
+func (r *AuditRepository) latest(ctx context.Context) (*entities.AuditEntry, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+
+	var entry entities.AuditEntry
+	err := r.collection.FindOne(ctx, byTenant(ctx, bson.M{}), opts).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// auditQuery translates an audit filter into a query on the tenant's entries
+func auditQuery(ctx context.Context, filter ports.AuditFilter) bson.M {
+	query := bson.M{}
+	for field, value := range map[string]string{
+		"principal":     filter.Principal,
+		"actor":         filter.Actor,
+		"operation":     filter.Operation,
+		"resource_type": filter.ResourceType,
+		"resource_id":   filter.ResourceID,
+		"transport":     filter.Transport,
+	} {
+		if value != "" {
+			query[field] = value
+		}
+	}
+
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+	if filter.AfterSequence > 0 {
+		query["sequence"] = bson.M{"$gt": filter.AfterSequence}
+	}
+
+	return byTenant(ctx, query)
+}
@@ -0,0 +1,173 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CategoryRepository implements the ports.CategoryRepository interface using materialized paths
+type CategoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository(db *mongo.Database) ports.CategoryRepository {
+	return &CategoryRepository{
+		collection: db.Collection("categories"),
+	}
+}
+
+// Create inserts a new category into the MongoDB collection.
+// The ID may be preset by the caller so the path can be computed before insertion.
+func (r *CategoryRepository) Create(ctx context.Context, category *entities.Category) (string, error) {
+	if category.ID.IsZero() {
+		category.ID = primitive.NewObjectID()
+	}
+	category.TenantID = ports.TenantFromContext(ctx)
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, category); err != nil {
+		return "", err
+	}
+
+	log.Printf("Category created with ID: %s", category.ID.Hex())
+	return category.ID.Hex(), nil
+}
+
+// FindByID retrieves a category by its ID from the MongoDB collection
+func (r *CategoryRepository) FindByID(ctx context.Context, id string) (*entities.Category, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrCategoryNotFound
+	}
+
+	var category entities.Category
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.M{"_id": objectID})).Decode(&category)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Update modifies an existing category in the MongoDB collection
+func (r *CategoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	category.TenantID = ports.TenantFromContext(ctx)
+	category.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.M{"_id": category.ID}), category)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a category by its ID from the MongoDB collection
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrCategoryNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ports.ErrCategoryNotFound
+	}
+
+	log.Printf("Category with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves every category ordered by depth, so parents come before their children
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entities.Category, error) {
+	return r.find(ctx, bson.M{}, bson.D{{Key: "depth", Value: 1}, {Key: "position", Value: 1}})
+}
+
+// FindByIDs retrieves the categories with the given IDs
+func (r *CategoryRepository) FindByIDs(ctx context.Context, ids []string) ([]*entities.Category, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}}, bson.D{{Key: "depth", Value: 1}})
+}
+
+// FindChildren retrieves the direct children of a category, or the roots for an empty parentID
+func (r *CategoryRepository) FindChildren(ctx context.Context, parentID string) ([]*entities.Category, error) {
+	filter := bson.M{"parent_id": parentID}
+	if parentID == "" {
+		filter = bson.M{"parent_id": bson.M{"$exists": false}}
+	}
+
+	return r.find(ctx, filter, bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+}
+
+// FindDescendants retrieves every category whose path starts with the given path.
+// An anchored prefix regex can use the index on path.
+func (r *CategoryRepository) FindDescendants(ctx context.Context, path string) ([]*entities.Category, error) {
+	filter := bson.M{
+		"path": bson.M{"$regex": "^" + regexp.QuoteMeta(path), "$ne": path},
+	}
+
+	return r.find(ctx, filter, bson.D{{Key: "depth", Value: 1}, {Key: "position", Value: 1}})
+}
+
+// MoveDescendants replaces the oldPath prefix of every descendant path with newPath in a single update
+func (r *CategoryRepository) MoveDescendants(ctx context.Context, oldPath string, newPath string, depthDelta int) error {
+	filter := bson.M{
+		"path": bson.M{"$regex": "^" + regexp.QuoteMeta(oldPath), "$ne": oldPath},
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"path": bson.M{"$concat": bson.A{
+				newPath,
+				bson.M{"$substrCP": bson.A{"$path", len(oldPath), bson.M{"$strLenCP": "$path"}}},
+			}},
+			"depth":      bson.M{"$add": bson.A{"$depth", depthDelta}},
+			"updated_at": time.Now(),
+		}}},
+	}
+
+	_, err := r.collection.UpdateMany(ctx, byTenant(ctx, filter), update)
+	return err
+}
+
+// find runs a query on the categories of the tenant and decodes every matching category
+func (r *CategoryRepository) find(ctx context.Context, filter bson.M, sort bson.D) ([]*entities.Category, error) {
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, filter), options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []*entities.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
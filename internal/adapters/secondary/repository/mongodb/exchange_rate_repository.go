@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExchangeRateRepository implements the ports.ExchangeRateRepository interface. Rates are shared
+// by every tenant, so its queries are not scoped with byTenant.
+type ExchangeRateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewExchangeRateRepository creates a new instance of ExchangeRateRepository
+func NewExchangeRateRepository(db *mongo.Database) ports.ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		collection: db.Collection("exchange_rates"),
+	}
+}
+
+// Save upserts rates by currency pair and effective time in one bulk write
+func (r *ExchangeRateRepository) Save(ctx context.Context, rates []*entities.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(rates))
+	for i, rate := range rates {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"base": rate.Base, "quote": rate.Quote, "effective_at": rate.EffectiveAt}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"rate":        rate.Rate,
+				"source":      rate.Source,
+				"imported_at": rate.ImportedAt,
+			}}).
+			SetUpsert(true)
+	}
+
+	_, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// FindRate retrieves the rate of a pair in effect at the given time
+func (r *ExchangeRateRepository) FindRate(ctx context.Context, base string, quote string, at time.Time) (*entities.ExchangeRate, error) {
+	filter := bson.M{"base": base, "quote": quote, "effective_at": bson.M{"$lte": at}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "effective_at", Value: -1}})
+
+	var rate entities.ExchangeRate
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&rate)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrExchangeRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+// FindLatest retrieves the rate of every pair in effect at the given time, ordered by pair
+func (r *ExchangeRateRepository) FindLatest(ctx context.Context, at time.Time) ([]*entities.ExchangeRate, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"effective_at": bson.M{"$lte": at}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "effective_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":  bson.M{"base": "$base", "quote": "$quote"},
+			"rate": bson.M{"$first": "$$ROOT"},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$rate"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "base", Value: 1}, {Key: "quote", Value: 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rates []*entities.ExchangeRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// FindHistory retrieves the rates of a pair that took effect in the range, oldest first
+func (r *ExchangeRateRepository) FindHistory(ctx context.Context, base string, quote string, from time.Time, to time.Time) ([]*entities.ExchangeRate, error) {
+	filter := bson.M{"base": base, "quote": quote}
+	within := bson.M{}
+	if !from.IsZero() {
+		within["$gte"] = from
+	}
+	if !to.IsZero() {
+		within["$lt"] = to
+	}
+	if len(within) > 0 {
+		filter["effective_at"] = within
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "effective_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rates []*entities.ExchangeRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,172 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InventoryRepository implements the ports.InventoryRepository interface.
+// Stock levels are only created for products of the tenant. The product is looked up before the
+// upsert rather than with it, so a product deleted in between may still get a stock level.
+type InventoryRepository struct {
+	collection *mongo.Collection
+	products   *mongo.Collection
+}
+
+// NewInventoryRepository creates a new instance of InventoryRepository
+func NewInventoryRepository(db *mongo.Database) ports.InventoryRepository {
+	return &InventoryRepository{
+		collection: db.Collection("inventory"),
+		products:   db.Collection("products"),
+	}
+}
+
+// FindByProduct retrieves the stock levels of a product in every warehouse
+func (r *InventoryRepository) FindByProduct(ctx context.Context, productID string) ([]*entities.StockLevel, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "warehouse", Value: 1}})
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{"product_id": productID}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var levels []*entities.StockLevel
+	if err := cursor.All(ctx, &levels); err != nil {
+		return nil, err
+	}
+
+	return levels, nil
+}
+
+// AdjustOnHand increments the on-hand quantity. Decrements only match while enough
+// unreserved stock is left, so the quantity never drops below what is reserved.
+func (r *InventoryRepository) AdjustOnHand(ctx context.Context, productID string, warehouse string, delta int64) (*entities.StockLevel, error) {
+	filter := bson.M{"product_id": productID, "warehouse": warehouse}
+	if delta < 0 {
+		filter["$expr"] = availableAtLeast(-delta)
+	}
+
+	update := bson.M{
+		"$inc":         bson.M{"on_hand": delta},
+		"$set":         bson.M{"updated_at": time.Now()},
+		"$setOnInsert": bson.M{"reserved": int64(0), "low_stock_threshold": int64(0)},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update, delta >= 0)
+}
+
+// SetLowStockThreshold sets the threshold, creating the stock level if needed
+func (r *InventoryRepository) SetLowStockThreshold(ctx context.Context, productID string, warehouse string, threshold int64) (*entities.StockLevel, error) {
+	filter := bson.M{"product_id": productID, "warehouse": warehouse}
+	update := bson.M{
+		"$set":         bson.M{"low_stock_threshold": threshold, "updated_at": time.Now()},
+		"$setOnInsert": bson.M{"on_hand": int64(0), "reserved": int64(0)},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update, true)
+}
+
+// Reserve increments the reserved quantity if enough stock is available
+func (r *InventoryRepository) Reserve(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	filter := bson.M{
+		"product_id": productID,
+		"warehouse":  warehouse,
+		"$expr":      availableAtLeast(quantity),
+	}
+	update := bson.M{
+		"$inc": bson.M{"reserved": quantity},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update, false)
+}
+
+// Release decrements the reserved quantity
+func (r *InventoryRepository) Release(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	filter := bson.M{
+		"product_id": productID,
+		"warehouse":  warehouse,
+		"reserved":   bson.M{"$gte": quantity},
+	}
+	update := bson.M{
+		"$inc": bson.M{"reserved": -quantity},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update, false)
+}
+
+// Commit decrements both the reserved and on-hand quantities
+func (r *InventoryRepository) Commit(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error) {
+	filter := bson.M{
+		"product_id": productID,
+		"warehouse":  warehouse,
+		"reserved":   bson.M{"$gte": quantity},
+		"on_hand":    bson.M{"$gte": quantity},
+	}
+	update := bson.M{
+		"$inc": bson.M{"reserved": -quantity, "on_hand": -quantity},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update, false)
+}
+
+// findOneAndUpdate applies a conditional update to a stock level of the tenant and returns it.
+// A filter that does not match means the condition failed, reported as insufficient stock.
+// Upserts take the tenant from the filter and fail with ports.ErrProductNotFound unless the
+// product of the filter belongs to the tenant.
+func (r *InventoryRepository) findOneAndUpdate(ctx context.Context, filter bson.M, update bson.M, upsert bool) (*entities.StockLevel, error) {
+	if upsert {
+		if err := r.checkProduct(ctx, filter["product_id"].(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(upsert).
+		SetReturnDocument(options.After)
+
+	var level entities.StockLevel
+	err := r.collection.FindOneAndUpdate(ctx, byTenant(ctx, filter), update, opts).Decode(&level)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrInsufficientStock
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
+// checkProduct returns ports.ErrProductNotFound unless the tenant has a product with the given ID
+func (r *InventoryRepository) checkProduct(ctx context.Context, productID string) error {
+	objectID, err := primitive.ObjectIDFromHex(productID)
+	if err != nil {
+		return ports.ErrProductNotFound
+	}
+
+	count, err := r.products.CountDocuments(ctx, byTenant(ctx, bson.M{"_id": objectID}), options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ports.ErrProductNotFound
+	}
+	return nil
+}
+
+// availableAtLeast builds an $expr matching documents with on_hand - reserved >= quantity
+func availableAtLeast(quantity int64) bson.M {
+	return bson.M{
+		"$gte": bson.A{bson.M{"$subtract": bson.A{"$on_hand", "$reserved"}}, quantity},
+	}
+}
@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MediaRepository implements the ports.MediaRepository interface
+type MediaRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMediaRepository creates a new instance of MediaRepository
+func NewMediaRepository(db *mongo.Database) ports.MediaRepository {
+	return &MediaRepository{
+		collection: db.Collection("media"),
+	}
+}
+
+// Create inserts a new media into the MongoDB collection
+func (r *MediaRepository) Create(ctx context.Context, media *entities.Media) (string, error) {
+	media.ID = primitive.NewObjectID()
+	media.TenantID = ports.TenantFromContext(ctx)
+	media.CreatedAt = time.Now()
+	media.UpdatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, media); err != nil {
+		return "", err
+	}
+
+	log.Printf("Media created with ID: %s", media.ID.Hex())
+	return media.ID.Hex(), nil
+}
+
+// FindByID retrieves a media by its ID from the MongoDB collection
+func (r *MediaRepository) FindByID(ctx context.Context, id string) (*entities.Media, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrMediaNotFound
+	}
+
+	return r.findOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+}
+
+// Delete removes a media by its ID from the MongoDB collection
+func (r *MediaRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrMediaNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ports.ErrMediaNotFound
+	}
+
+	log.Printf("Media with ID: %s deleted successfully", id)
+	return nil
+}
+
+// UpdatePosition moves a media to position
+func (r *MediaRepository) UpdatePosition(ctx context.Context, id string, position int) error {
+	return r.set(ctx, id, bson.M{"position": position})
+}
+
+// SetThumbnails records the thumbnails generated for a media
+func (r *MediaRepository) SetThumbnails(ctx context.Context, id string, thumbnails []entities.MediaThumbnail) error {
+	return r.set(ctx, id, bson.M{"thumbnails": thumbnails})
+}
+
+// FindByProductID retrieves the media of a product ordered by position
+func (r *MediaRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.Media, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{"product_id": productID}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var media []*entities.Media
+	if err := cursor.All(ctx, &media); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// FindByProductAndHash retrieves the media of a product with the given content hash
+func (r *MediaRepository) FindByProductAndHash(ctx context.Context, productID string, sha256 string) (*entities.Media, error) {
+	return r.findOne(ctx, byTenant(ctx, bson.M{"product_id": productID, "sha256": sha256}))
+}
+
+// CountByHash counts the media of the tenant with the given content hash
+func (r *MediaRepository) CountByHash(ctx context.Context, sha256 string) (int64, error) {
+	return r.collection.CountDocuments(ctx, byTenant(ctx, bson.M{"sha256": sha256}))
+}
+
+// set updates the given fields of a media
+func (r *MediaRepository) set(ctx context.Context, id string, fields bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrMediaNotFound
+	}
+
+	fields["updated_at"] = time.Now()
+	result, err := r.collection.UpdateOne(ctx, byTenant(ctx, bson.M{"_id": objectID}), bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrMediaNotFound
+	}
+
+	log.Printf("Media with ID: %s updated successfully", id)
+	return nil
+}
+
+// findOne decodes the media matching filter
+func (r *MediaRepository) findOne(ctx context.Context, filter bson.M) (*entities.Media, error) {
+	var media entities.Media
+	err := r.collection.FindOne(ctx, filter).Decode(&media)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &media, nil
+}
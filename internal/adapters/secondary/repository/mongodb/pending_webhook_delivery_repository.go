@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PendingWebhookDeliveryRepository implements the ports.PendingWebhookDeliveryRepository interface
+type PendingWebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPendingWebhookDeliveryRepository creates a new instance of PendingWebhookDeliveryRepository
+func NewPendingWebhookDeliveryRepository(db *mongo.Database) ports.PendingWebhookDeliveryRepository {
+	return &PendingWebhookDeliveryRepository{
+		collection: db.Collection("webhook_pending_deliveries"),
+	}
+}
+
+// Create inserts the deliveries of an event into the MongoDB collection
+func (r *PendingWebhookDeliveryRepository) Create(ctx context.Context, deliveries []*entities.PendingWebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		delivery.ID = primitive.NewObjectID()
+		delivery.TenantID = ports.TenantFromContext(ctx)
+		delivery.CreatedAt = time.Now()
+		documents = append(documents, delivery)
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents)
+	return err
+}
+
+// ClaimDue leases up to limit deliveries of every tenant due at now, oldest first. Each one is
+// leased with its own update, so concurrent consumers never claim the same delivery.
+func (r *PendingWebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int64) ([]*entities.PendingWebhookDelivery, error) {
+	filter := bson.M{"next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": leaseUntil}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}})
+
+	var claimed []*entities.PendingWebhookDelivery
+	for int64(len(claimed)) < limit {
+		var delivery entities.PendingWebhookDelivery
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, &delivery)
+	}
+
+	return claimed, nil
+}
+
+// Reschedule records an attempt of a delivery and when to try it again
+func (r *PendingWebhookDeliveryRepository) Reschedule(ctx context.Context, id string, attempts int, next time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.collection.UpdateOne(ctx, byTenant(ctx, bson.M{"_id": objectID}), bson.M{
+		"$set": bson.M{"attempts": attempts, "next_attempt_at": next},
+	})
+	return err
+}
+
+// Delete removes a delivery from the MongoDB collection
+func (r *PendingWebhookDeliveryRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+	return err
+}
@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PriceListRepository implements the ports.PriceListRepository interface
+type PriceListRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPriceListRepository creates a new instance of PriceListRepository
+func NewPriceListRepository(db *mongo.Database) ports.PriceListRepository {
+	return &PriceListRepository{
+		collection: db.Collection("price_lists"),
+	}
+}
+
+// Create inserts a new price list into the MongoDB collection
+func (r *PriceListRepository) Create(ctx context.Context, list *entities.PriceList) (string, error) {
+	list.ID = primitive.NewObjectID()
+	list.TenantID = ports.TenantFromContext(ctx)
+	list.CreatedAt = time.Now()
+	list.UpdatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, list); err != nil {
+		return "", err
+	}
+
+	log.Printf("Price list created with ID: %s", list.ID.Hex())
+	return list.ID.Hex(), nil
+}
+
+// FindByID retrieves a price list by its ID from the MongoDB collection
+func (r *PriceListRepository) FindByID(ctx context.Context, id string) (*entities.PriceList, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrPriceListNotFound
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// FindByCode retrieves a price list by its code from the MongoDB collection
+func (r *PriceListRepository) FindByCode(ctx context.Context, code string) (*entities.PriceList, error) {
+	return r.findOne(ctx, bson.M{"code": code})
+}
+
+// FindAll retrieves the price lists of the tenant ordered by code
+func (r *PriceListRepository) FindAll(ctx context.Context) ([]*entities.PriceList, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "code", Value: 1}})
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var lists []*entities.PriceList
+	if err := cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// Update replaces the details of an existing price list, keeping its prices
+func (r *PriceListRepository) Update(ctx context.Context, list *entities.PriceList) error {
+	return r.update(ctx, list.ID, bson.M{"$set": bson.M{
+		"code":           list.Code,
+		"name":           list.Name,
+		"currency":       list.Currency,
+		"market":         list.Market,
+		"customer_group": list.CustomerGroup,
+		"updated_at":     time.Now(),
+	}})
+}
+
+// Delete removes a price list by its ID from the MongoDB collection
+func (r *PriceListRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrPriceListNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ports.ErrPriceListNotFound
+	}
+
+	log.Printf("Price list with ID: %s deleted successfully", id)
+	return nil
+}
+
+// SetPrice sets the explicit price of a product in a price list
+func (r *PriceListRepository) SetPrice(ctx context.Context, id string, productID string, price float32) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrPriceListNotFound
+	}
+	return r.update(ctx, objectID, bson.M{"$set": bson.M{
+		"prices." + productID: price,
+		"updated_at":          time.Now(),
+	}})
+}
+
+// DeletePrice removes the explicit price of a product from a price list
+func (r *PriceListRepository) DeletePrice(ctx context.Context, id string, productID string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrPriceListNotFound
+	}
+	return r.update(ctx, objectID, bson.M{
+		"$unset": bson.M{"prices." + productID: ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	})
+}
+
+// findOne decodes the price list of the tenant matching filter
+func (r *PriceListRepository) findOne(ctx context.Context, filter bson.M) (*entities.PriceList, error) {
+	var list entities.PriceList
+	err := r.collection.FindOne(ctx, byTenant(ctx, filter)).Decode(&list)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrPriceListNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// update applies an update document to a price list of the tenant
+func (r *PriceListRepository) update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, byTenant(ctx, bson.M{"_id": id}), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrPriceListNotFound
+	}
+
+	log.Printf("Price list with ID: %s updated successfully", id.Hex())
+	return nil
+}
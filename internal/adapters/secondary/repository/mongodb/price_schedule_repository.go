@@ -0,0 +1,221 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// priceScheduleLease is how long a write may hold the lock on the schedules of a product
+	priceScheduleLease = 10 * time.Second
+	// priceScheduleLockRetry is how long a write waits before trying to take a held lock again
+	priceScheduleLockRetry = 20 * time.Millisecond
+)
+
+// PriceScheduleRepository implements the ports.PriceScheduleRepository interface.
+// Writes check for overlapping schedules while holding a lock on the schedules of the product, a
+// document in the price_schedule_locks collection, so concurrent writers cannot both pass the check.
+// A lock is leased for priceScheduleLease, after which another writer may take it over.
+type PriceScheduleRepository struct {
+	collection *mongo.Collection
+	locks      *mongo.Collection
+}
+
+// NewPriceScheduleRepository creates a new instance of PriceScheduleRepository
+func NewPriceScheduleRepository(db *mongo.Database) ports.PriceScheduleRepository {
+	return &PriceScheduleRepository{
+		collection: db.Collection("price_schedules"),
+		locks:      db.Collection("price_schedule_locks"),
+	}
+}
+
+// Create inserts a new price schedule into the MongoDB collection
+func (r *PriceScheduleRepository) Create(ctx context.Context, schedule *entities.PriceSchedule) (string, error) {
+	schedule.ID = primitive.NewObjectID()
+	schedule.TenantID = ports.TenantFromContext(ctx)
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+
+	unlock, err := r.lock(ctx, schedule.ProductID)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if err := r.checkConflicts(ctx, schedule); err != nil {
+		return "", err
+	}
+	if _, err := r.collection.InsertOne(ctx, schedule); err != nil {
+		return "", err
+	}
+
+	log.Printf("Price schedule created with ID: %s", schedule.ID.Hex())
+	return schedule.ID.Hex(), nil
+}
+
+// FindByID retrieves a price schedule by its ID from the MongoDB collection
+func (r *PriceScheduleRepository) FindByID(ctx context.Context, id string) (*entities.PriceSchedule, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrPriceScheduleNotFound
+	}
+
+	var schedule entities.PriceSchedule
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.M{"_id": objectID})).Decode(&schedule)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrPriceScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// Update replaces an existing price schedule in the MongoDB collection
+func (r *PriceScheduleRepository) Update(ctx context.Context, schedule *entities.PriceSchedule) error {
+	schedule.TenantID = ports.TenantFromContext(ctx)
+	schedule.UpdatedAt = time.Now()
+
+	unlock, err := r.lock(ctx, schedule.ProductID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := r.checkConflicts(ctx, schedule); err != nil {
+		return err
+	}
+	result, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.M{"_id": schedule.ID}), schedule)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrPriceScheduleNotFound
+	}
+
+	log.Printf("Price schedule with ID: %s updated successfully", schedule.ID.Hex())
+	return nil
+}
+
+// Delete removes a price schedule by its ID from the MongoDB collection
+func (r *PriceScheduleRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrPriceScheduleNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ports.ErrPriceScheduleNotFound
+	}
+
+	log.Printf("Price schedule with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindByProductID retrieves the schedules of a product ordered by start time
+func (r *PriceScheduleRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.PriceSchedule, error) {
+	return r.find(ctx, byTenant(ctx, bson.M{"product_id": productID}))
+}
+
+// FindActiveAt retrieves the schedules of every product of the tenant that apply at the given time
+func (r *PriceScheduleRepository) FindActiveAt(ctx context.Context, at time.Time) ([]*entities.PriceSchedule, error) {
+	return r.find(ctx, byTenant(ctx, bson.M{
+		"starts_at": bson.M{"$lte": at},
+		"ends_at":   bson.M{"$gt": at},
+	}))
+}
+
+// FindChangingBetween retrieves the schedules of every tenant starting or ending in (from, to]
+func (r *PriceScheduleRepository) FindChangingBetween(ctx context.Context, from time.Time, to time.Time) ([]*entities.PriceSchedule, error) {
+	within := bson.M{"$gt": from, "$lte": to}
+	return r.find(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"starts_at": within},
+			bson.M{"ends_at": within},
+		},
+	})
+}
+
+// find runs a query and decodes every matching price schedule ordered by start time
+func (r *PriceScheduleRepository) find(ctx context.Context, filter bson.M) ([]*entities.PriceSchedule, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []*entities.PriceSchedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// lock takes the lock on the schedules of a product of the tenant, waiting while another write holds
+// an unexpired lease, and returns the function releasing it
+func (r *PriceScheduleRepository) lock(ctx context.Context, productID string) (func(), error) {
+	key := ports.TenantFromContext(ctx) + "/" + productID
+	token := primitive.NewObjectID()
+	for {
+		now := time.Now()
+		// A held lease does not match the filter, so the upsert collides with it on _id
+		_, err := r.locks.UpdateOne(ctx,
+			bson.M{"_id": key, "expires_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"token": token, "expires_at": now.Add(priceScheduleLease)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(priceScheduleLockRetry):
+		}
+	}
+
+	return func() {
+		// Only release the lease if it has not been taken over since
+		if _, err := r.locks.DeleteOne(context.Background(), bson.M{"_id": key, "token": token}); err != nil {
+			log.Printf("Failed to release the price schedule lock of product %s: %v", productID, err)
+		}
+	}, nil
+}
+
+// checkConflicts returns ports.ErrPriceScheduleOverlap when a schedule of the tenant conflicts with schedule
+func (r *PriceScheduleRepository) checkConflicts(ctx context.Context, schedule *entities.PriceSchedule) error {
+	count, err := r.collection.CountDocuments(ctx, byTenant(ctx, bson.M{
+		"_id":        bson.M{"$ne": schedule.ID},
+		"product_id": schedule.ProductID,
+		"priority":   schedule.Priority,
+		"starts_at":  bson.M{"$lt": schedule.EndsAt},
+		"ends_at":    bson.M{"$gt": schedule.StartsAt},
+	}), options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ports.ErrPriceScheduleOverlap
+	}
+	return nil
+}
@@ -11,8 +11,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// defaultStreamBatchSize is used when Stream is called without a batch size
+const defaultStreamBatchSize = 100
+
 // ProductRepository implements the ports.ProductRepository interface
 type ProductRepository struct {
 	collection *mongo.Collection
@@ -28,6 +32,7 @@ func NewProductRepository(db *mongo.Database) ports.ProductRepository {
 // Create inserts a new product into the MongoDB collection
 func (r *ProductRepository) Create(ctx context.Context, product *entities.Product) (string, error) {
 	product.ID = primitive.NewObjectID()
+	product.TenantID = ports.TenantFromContext(ctx)
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
 
@@ -44,13 +49,13 @@ func (r *ProductRepository) Create(ctx context.Context, product *entities.Produc
 func (r *ProductRepository) FindByID(ctx context.Context, id string) (*entities.Product, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
+		return nil, ports.ErrProductNotFound
 	}
 
 	var product entities.Product
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&product)
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.M{"_id": objectID})).Decode(&product)
 	if err == mongo.ErrNoDocuments {
-		return nil, nil
+		return nil, ports.ErrProductNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -59,13 +64,19 @@ func (r *ProductRepository) FindByID(ctx context.Context, id string) (*entities.
 	return &product, nil
 }
 
-// Update modifies an existing product in the MongoDB collection
+// Update replaces an existing product in the MongoDB collection, keeping its stored creation time.
+// The replacement runs as a single pipeline update, so fields cleared on the product are removed
+// from the document too; $literal keeps values starting with "$" from being read as field paths.
 func (r *ProductRepository) Update(ctx context.Context, product *entities.Product) error {
+	product.TenantID = ports.TenantFromContext(ctx)
 	product.UpdatedAt = time.Now()
 
-	filter := bson.M{"_id": product.ID}
-	update := bson.M{
-		"$set": product,
+	filter := byTenant(ctx, bson.M{"_id": product.ID})
+	update := mongo.Pipeline{
+		{{Key: "$replaceWith", Value: bson.M{"$mergeObjects": bson.A{
+			bson.M{"$literal": product},
+			bson.M{"created_at": "$created_at"},
+		}}}},
 	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
@@ -73,7 +84,7 @@ func (r *ProductRepository) Update(ctx context.Context, product *entities.Produc
 		return err
 	}
 	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return ports.ErrProductNotFound
 	}
 
 	log.Printf("Product with ID: %s updated successfully", product.ID.Hex())
@@ -84,24 +95,29 @@ func (r *ProductRepository) Update(ctx context.Context, product *entities.Produc
 func (r *ProductRepository) Delete(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return err
+		return ports.ErrProductNotFound
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	result, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
 	if err != nil {
 		return err
 	}
 	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+		return ports.ErrProductNotFound
 	}
 
 	log.Printf("Product with ID: %s deleted successfully", id)
 	return nil
 }
 
-// FindAll retrieves all products from the MongoDB collection
+// Count returns the number of products of the tenant
+func (r *ProductRepository) Count(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, byTenant(ctx, bson.M{}))
+}
+
+// FindAll retrieves all products of the tenant from the MongoDB collection
 func (r *ProductRepository) FindAll(ctx context.Context) ([]*entities.Product, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -122,3 +138,126 @@ func (r *ProductRepository) FindAll(ctx context.Context) ([]*entities.Product, e
 
 	return products, nil
 }
+
+// FindByCategoryIDs retrieves the products assigned to any of the given categories
+func (r *ProductRepository) FindByCategoryIDs(ctx context.Context, categoryIDs []string) ([]*entities.Product, error) {
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{"category_ids": bson.M{"$in": categoryIDs}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var products []*entities.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// FindByFilter retrieves the products matching every set field of the filter
+func (r *ProductRepository) FindByFilter(ctx context.Context, filter ports.ProductFilter) ([]*entities.Product, error) {
+	query := byTenant(ctx, bson.M{})
+	if filter.Name != "" {
+		query["name"] = filter.Name
+	}
+	if filter.TypeID != "" {
+		query["type_id"] = filter.TypeID
+	}
+	if !filter.CreatedFrom.IsZero() || !filter.CreatedTo.IsZero() {
+		createdAt := bson.M{}
+		if !filter.CreatedFrom.IsZero() {
+			createdAt["$gte"] = filter.CreatedFrom
+		}
+		if !filter.CreatedTo.IsZero() {
+			createdAt["$lt"] = filter.CreatedTo
+		}
+		query["created_at"] = createdAt
+	}
+	if filter.ComponentID != "" {
+		query["bundle.components.product_id"] = filter.ComponentID
+	}
+	for _, condition := range filter.Attributes {
+		field := "attributes." + condition.Name
+		comparison, ok := query[field].(bson.M)
+		if !ok {
+			comparison = bson.M{}
+			query[field] = comparison
+		}
+		comparison["$"+condition.Op] = condition.Value
+	}
+
+	cursor, err := r.collection.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var products []*entities.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// Stream walks the products of the tenant in _id order, fetching batchSize documents per round trip.
+// The next batch is only requested once fn has consumed the current one, so a slow consumer
+// applies back-pressure to the cursor instead of buffering the whole collection in memory.
+func (r *ProductRepository) Stream(ctx context.Context, batchSize int32, fn func(*entities.Product) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetBatchSize(batchSize)
+
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{}), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var product entities.Product
+		if err := cursor.Decode(&product); err != nil {
+			return err
+		}
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// FindDueTransitions retrieves the products of every tenant with a status change due at the given time.
+// The query mirrors Product.ScheduledStatus; products stored without a status were migrated to active.
+func (r *ProductRepository) FindDueTransitions(ctx context.Context, at time.Time) ([]*entities.Product, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{
+			"status":      entities.ProductStatusInReview,
+			"approved_by": bson.M{"$gt": ""},
+			"activate_at": bson.M{"$lte": at},
+		},
+		bson.M{
+			"status":        entities.ProductStatusActive,
+			"deactivate_at": bson.M{"$lte": at},
+		},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var products []*entities.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
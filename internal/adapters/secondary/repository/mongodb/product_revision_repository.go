@@ -0,0 +1,111 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// appendAttempts bounds the retries when concurrent writers race for the same revision number
+const appendAttempts = 5
+
+// ProductRevisionRepository implements the ports.ProductRevisionRepository interface
+type ProductRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewProductRevisionRepository creates a new instance of ProductRevisionRepository
+func NewProductRevisionRepository(db *mongo.Database) ports.ProductRevisionRepository {
+	return &ProductRevisionRepository{
+		collection: db.Collection("product_revisions"),
+	}
+}
+
+// Append inserts a revision numbered right after the latest revision of its product.
+// The unique (product_id, revision) index rejects a number taken by a concurrent writer,
+// in which case the next number is tried.
+func (r *ProductRevisionRepository) Append(ctx context.Context, revision *entities.ProductRevision) error {
+	revision.TenantID = ports.TenantFromContext(ctx)
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+
+	var err error
+	for attempt := 0; attempt < appendAttempts; attempt++ {
+		var latest int64
+		latest, err = r.latestRevision(ctx, revision.ProductID)
+		if err != nil {
+			return err
+		}
+
+		revision.ID = primitive.NewObjectID()
+		revision.Revision = latest + 1
+		_, err = r.collection.InsertOne(ctx, revision)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// FindByProductID retrieves every revision of a product, oldest first
+func (r *ProductRevisionRepository) FindByProductID(ctx context.Context, productID string) ([]*entities.ProductRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: 1}})
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{"product_id": productID}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var revisions []*entities.ProductRevision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// FindByRevision retrieves one revision of a product
+func (r *ProductRevisionRepository) FindByRevision(ctx context.Context, productID string, revision int64) (*entities.ProductRevision, error) {
+	return r.findOne(ctx, bson.M{"product_id": productID, "revision": revision}, nil)
+}
+
+// FindAsOf retrieves the latest revision of a product created at or before at
+func (r *ProductRevisionRepository) FindAsOf(ctx context.Context, productID string, at time.Time) (*entities.ProductRevision, error) {
+	filter := bson.M{"product_id": productID, "created_at": bson.M{"$lte": at}}
+	return r.findOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}}))
+}
+
+// latestRevision returns the highest revision number of a product, or zero when it has none
+func (r *ProductRevisionRepository) latestRevision(ctx context.Context, productID string) (int64, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}}).SetProjection(bson.M{"revision": 1})
+	latest, err := r.findOne(ctx, bson.M{"product_id": productID}, opts)
+	if err == ports.ErrRevisionNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return latest.Revision, nil
+}
+
+// findOne decodes the first revision of the tenant matching filter, returning ports.ErrRevisionNotFound when there is none
+func (r *ProductRevisionRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*entities.ProductRevision, error) {
+	var revision entities.ProductRevision
+	err := r.collection.FindOne(ctx, byTenant(ctx, filter), opts).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
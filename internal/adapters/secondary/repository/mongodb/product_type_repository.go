@@ -0,0 +1,112 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ProductTypeRepository implements the ports.ProductTypeRepository interface
+type ProductTypeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewProductTypeRepository creates a new instance of ProductTypeRepository
+func NewProductTypeRepository(db *mongo.Database) ports.ProductTypeRepository {
+	return &ProductTypeRepository{
+		collection: db.Collection("product_types"),
+	}
+}
+
+// Create inserts a new product type into the MongoDB collection
+func (r *ProductTypeRepository) Create(ctx context.Context, productType *entities.ProductType) (string, error) {
+	productType.ID = primitive.NewObjectID()
+	productType.TenantID = ports.TenantFromContext(ctx)
+	productType.CreatedAt = time.Now()
+	productType.UpdatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, productType); err != nil {
+		return "", err
+	}
+
+	log.Printf("Product type created with ID: %s", productType.ID.Hex())
+	return productType.ID.Hex(), nil
+}
+
+// FindByID retrieves a product type by its ID from the MongoDB collection
+func (r *ProductTypeRepository) FindByID(ctx context.Context, id string) (*entities.ProductType, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrProductTypeNotFound
+	}
+
+	var productType entities.ProductType
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.M{"_id": objectID})).Decode(&productType)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrProductTypeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &productType, nil
+}
+
+// Update modifies an existing product type in the MongoDB collection
+func (r *ProductTypeRepository) Update(ctx context.Context, productType *entities.ProductType) error {
+	productType.TenantID = ports.TenantFromContext(ctx)
+	productType.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.M{"_id": productType.ID}), productType)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrProductTypeNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a product type by its ID from the MongoDB collection
+func (r *ProductTypeRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrProductTypeNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ports.ErrProductTypeNotFound
+	}
+
+	log.Printf("Product type with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves every product type of the tenant ordered by name
+func (r *ProductTypeRepository) FindAll(ctx context.Context) ([]*entities.ProductType, error) {
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{}), options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var productTypes []*entities.ProductType
+	if err := cursor.All(ctx, &productTypes); err != nil {
+		return nil, err
+	}
+
+	return productTypes, nil
+}
@@ -0,0 +1,118 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDB server error codes returned for resume tokens that cannot be used
+const (
+	errCodeFailedToParse           = 9
+	errCodeChangeStreamHistoryLost = 286
+)
+
+// ProductWatcher implements the ports.ProductWatcher interface using MongoDB change streams
+type ProductWatcher struct {
+	collection *mongo.Collection
+}
+
+// changeEvent is the subset of a change stream document we care about
+type changeEvent struct {
+	OperationType string            `bson:"operationType"`
+	FullDocument  *entities.Product `bson:"fullDocument"`
+	// FullDocumentBeforeChange is only set where pre-images are enabled, see migration 009
+	FullDocumentBeforeChange *entities.Product `bson:"fullDocumentBeforeChange"`
+	DocumentKey              struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	ClusterTime primitive.Timestamp `bson:"clusterTime"`
+}
+
+// NewProductWatcher creates a new instance of ProductWatcher
+func NewProductWatcher(db *mongo.Database) ports.ProductWatcher {
+	return &ProductWatcher{
+		collection: db.Collection("products"),
+	}
+}
+
+// Watch opens a change stream on the products collection and forwards every insert, update,
+// replace and delete of every tenant to fn. The change stream's own resume token is handed out
+// with each event. Deletes carry their tenant only when the collection records pre-images.
+func (w *ProductWatcher) Watch(ctx context.Context, resumeToken string, fn func(*entities.ProductEvent) error) error {
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if resumeToken != "" {
+		opts.SetResumeAfter(bson.M{"_data": resumeToken})
+	}
+
+	stream, err := w.collection.Watch(ctx, mongo.Pipeline{}, opts)
+	if err != nil {
+		if isInvalidResumeToken(err) {
+			return ports.ErrInvalidResumeToken
+		}
+		return err
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var change changeEvent
+		if err := stream.Decode(&change); err != nil {
+			return err
+		}
+
+		event := &entities.ProductEvent{
+			ProductID:   change.DocumentKey.ID.Hex(),
+			ResumeToken: stream.ResumeToken().Lookup("_data").StringValue(),
+			OccurredAt:  time.Unix(int64(change.ClusterTime.T), 0).UTC(),
+		}
+		switch change.OperationType {
+		case "insert":
+			event.Type = entities.ProductCreated
+			event.Product = change.FullDocument
+		case "update", "replace":
+			event.Type = entities.ProductUpdated
+			event.Product = change.FullDocument
+		case "delete":
+			event.Type = entities.ProductDeleted
+			if change.FullDocumentBeforeChange != nil {
+				event.TenantID = change.FullDocumentBeforeChange.TenantID
+			}
+		default:
+			continue
+		}
+		if event.Product != nil {
+			event.TenantID = event.Product.TenantID
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	if err := stream.Err(); err != nil && ctx.Err() == nil {
+		if isInvalidResumeToken(err) {
+			return ports.ErrInvalidResumeToken
+		}
+		return err
+	}
+	return ctx.Err()
+}
+
+// isInvalidResumeToken reports whether err was caused by a malformed or expired resume token
+func isInvalidResumeToken(err error) bool {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr.HasErrorCode(errCodeChangeStreamHistoryLost) || serverErr.HasErrorCode(errCodeFailedToParse)
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReservationRepository implements the ports.ReservationRepository interface
+type ReservationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReservationRepository creates a new instance of ReservationRepository
+func NewReservationRepository(db *mongo.Database) ports.ReservationRepository {
+	return &ReservationRepository{
+		collection: db.Collection("reservations"),
+	}
+}
+
+// Create inserts a new reservation into the MongoDB collection
+func (r *ReservationRepository) Create(ctx context.Context, reservation *entities.Reservation) (string, error) {
+	reservation.ID = primitive.NewObjectID()
+	reservation.TenantID = ports.TenantFromContext(ctx)
+	reservation.CreatedAt = time.Now()
+	reservation.UpdatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, reservation); err != nil {
+		return "", err
+	}
+
+	return reservation.ID.Hex(), nil
+}
+
+// FindByID retrieves a reservation by its ID from the MongoDB collection
+func (r *ReservationRepository) FindByID(ctx context.Context, id string) (*entities.Reservation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrReservationNotFound
+	}
+
+	var reservation entities.Reservation
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.M{"_id": objectID})).Decode(&reservation)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// Transition moves a reservation from one status to another only if it is still in the expected status.
+// Commits also require the reservation not to have expired, so they cannot race the reaper releasing it.
+func (r *ReservationRepository) Transition(ctx context.Context, id string, from string, to string) (*entities.Reservation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrReservationNotFound
+	}
+
+	now := time.Now()
+	filter := bson.M{"_id": objectID, "status": from}
+	if from == entities.ReservationPending && to == entities.ReservationCommitted {
+		filter["expires_at"] = bson.M{"$gt": now}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	update := bson.M{"$set": bson.M{"status": to, "updated_at": now}}
+
+	var reservation entities.Reservation
+	err = r.collection.FindOneAndUpdate(ctx, byTenant(ctx, filter), update, opts).Decode(&reservation)
+	if err == mongo.ErrNoDocuments {
+		// Distinguish a missing reservation from one that already moved on or expired
+		current, findErr := r.FindByID(ctx, id)
+		if findErr != nil {
+			return nil, findErr
+		}
+		if current.Status == from {
+			return nil, ports.ErrReservationExpired
+		}
+		return nil, ports.ErrReservationClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// FindExpired retrieves pending reservations of every tenant that expired before now, oldest first
+func (r *ReservationRepository) FindExpired(ctx context.Context, now time.Time, limit int64) ([]*entities.Reservation, error) {
+	filter := bson.M{
+		"status":     entities.ReservationPending,
+		"expires_at": bson.M{"$lt": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reservations []*entities.Reservation
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
@@ -0,0 +1,111 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TaxRateRepository implements the ports.TaxRateRepository interface. Rates are shared by every
+// tenant, so its queries are not scoped with byTenant.
+type TaxRateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTaxRateRepository creates a new instance of TaxRateRepository
+func NewTaxRateRepository(db *mongo.Database) ports.TaxRateRepository {
+	return &TaxRateRepository{
+		collection: db.Collection("tax_rates"),
+	}
+}
+
+// Create inserts a new tax rate into the MongoDB collection
+func (r *TaxRateRepository) Create(ctx context.Context, rate *entities.TaxRate) (string, error) {
+	rate.ID = primitive.NewObjectID()
+	rate.CreatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, rate); err != nil {
+		return "", err
+	}
+
+	log.Printf("Tax rate created with ID: %s", rate.ID.Hex())
+	return rate.ID.Hex(), nil
+}
+
+// FindByID retrieves a tax rate by its ID from the MongoDB collection
+func (r *TaxRateRepository) FindByID(ctx context.Context, id string) (*entities.TaxRate, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrTaxRateNotFound
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID}, options.FindOne())
+}
+
+// FindAll retrieves the rates of a jurisdiction, or of all of them, ordered by jurisdiction, class and effective time
+func (r *TaxRateRepository) FindAll(ctx context.Context, jurisdiction string) ([]*entities.TaxRate, error) {
+	filter := bson.M{}
+	if jurisdiction != "" {
+		filter["jurisdiction"] = jurisdiction
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "jurisdiction", Value: 1}, {Key: "tax_class", Value: 1}, {Key: "effective_from", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rates []*entities.TaxRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// FindEffective retrieves the rate of a tax class in a jurisdiction in effect at the given time
+func (r *TaxRateRepository) FindEffective(ctx context.Context, jurisdiction string, taxClass string, at time.Time) (*entities.TaxRate, error) {
+	filter := bson.M{"jurisdiction": jurisdiction, "tax_class": taxClass, "effective_from": bson.M{"$lte": at}}
+	return r.findOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "effective_from", Value: -1}}))
+}
+
+// Delete removes a tax rate by its ID from the MongoDB collection
+func (r *TaxRateRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrTaxRateNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ports.ErrTaxRateNotFound
+	}
+
+	log.Printf("Tax rate with ID: %s deleted successfully", id)
+	return nil
+}
+
+// findOne decodes the first tax rate matching filter
+func (r *TaxRateRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*entities.TaxRate, error) {
+	var rate entities.TaxRate
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&rate)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrTaxRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rate, nil
+}
@@ -0,0 +1,20 @@
+package mongodb
+
+import (
+	"context"
+
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// tenantField holds the owning tenant on every document of the catalog collections
+const tenantField = "tenant_id"
+
+// byTenant limits filter to the documents of the tenant carried by ctx and returns it.
+// Every query of the repositories in this package goes through it, so a tenant never
+// reads or changes the documents of another; IDs of other tenants are simply not found.
+func byTenant(ctx context.Context, filter bson.M) bson.M {
+	filter[tenantField] = ports.TenantFromContext(ctx)
+	return filter
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VariantRepository implements the ports.VariantRepository interface.
+// SKU uniqueness per tenant relies on the unique index created by migration 004 and scoped by migration 009.
+type VariantRepository struct {
+	collection *mongo.Collection
+}
+
+// NewVariantRepository creates a new instance of VariantRepository
+func NewVariantRepository(db *mongo.Database) ports.VariantRepository {
+	return &VariantRepository{
+		collection: db.Collection("product_variants"),
+	}
+}
+
+// CreateMany inserts the variants in a single ordered batch
+func (r *VariantRepository) CreateMany(ctx context.Context, variants []*entities.ProductVariant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(variants))
+	for i, variant := range variants {
+		if variant.ID.IsZero() {
+			variant.ID = primitive.NewObjectID()
+		}
+		variant.TenantID = ports.TenantFromContext(ctx)
+		variant.CreatedAt = time.Now()
+		variant.UpdatedAt = time.Now()
+		documents[i] = variant
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents)
+	if mongo.IsDuplicateKeyError(err) {
+		return ports.ErrDuplicateSKU
+	}
+	return err
+}
+
+// FindByID retrieves a variant by its ID from the MongoDB collection
+func (r *VariantRepository) FindByID(ctx context.Context, id string) (*entities.ProductVariant, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrVariantNotFound
+	}
+
+	var variant entities.ProductVariant
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.M{"_id": objectID})).Decode(&variant)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+// FindByProduct retrieves the variants of a product ordered by position
+func (r *VariantRepository) FindByProduct(ctx context.Context, productID string) ([]*entities.ProductVariant, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}})
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{"product_id": productID}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var variants []*entities.ProductVariant
+	if err := cursor.All(ctx, &variants); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// Update modifies an existing variant in the MongoDB collection
+func (r *VariantRepository) Update(ctx context.Context, variant *entities.ProductVariant) error {
+	variant.TenantID = ports.TenantFromContext(ctx)
+	variant.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.M{"_id": variant.ID}), variant)
+	if mongo.IsDuplicateKeyError(err) {
+		return ports.ErrDuplicateSKU
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrVariantNotFound
+	}
+
+	return nil
+}
+
+// DeleteByIDs removes the variants with the given IDs
+func (r *VariantRepository) DeleteByIDs(ctx context.Context, ids []string) error {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	if len(objectIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.DeleteMany(ctx, byTenant(ctx, bson.M{"_id": bson.M{"$in": objectIDs}}))
+	return err
+}
@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookDeliveryRepository implements the ports.WebhookDeliveryRepository interface
+type WebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository
+func NewWebhookDeliveryRepository(db *mongo.Database) ports.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		collection: db.Collection("webhook_deliveries"),
+	}
+}
+
+// Create appends a delivery attempt to the MongoDB collection
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *entities.WebhookDelivery) (string, error) {
+	delivery.ID = primitive.NewObjectID()
+	delivery.TenantID = ports.TenantFromContext(ctx)
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	if _, err := r.collection.InsertOne(ctx, delivery); err != nil {
+		return "", err
+	}
+
+	return delivery.ID.Hex(), nil
+}
+
+// FindBySubscription retrieves the latest delivery attempts for a webhook subscription
+func (r *WebhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID string, limit int64) ([]*entities.WebhookDelivery, error) {
+	objectID, err := primitive.ObjectIDFromHex(subscriptionID)
+	if err != nil {
+		return nil, ports.ErrWebhookNotFound
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, bson.M{"subscription_id": objectID}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []*entities.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,176 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookRepository implements the ports.WebhookRepository interface
+type WebhookRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(db *mongo.Database) ports.WebhookRepository {
+	return &WebhookRepository{
+		collection: db.Collection("webhooks"),
+	}
+}
+
+// Create inserts a new webhook subscription into the MongoDB collection
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entities.WebhookSubscription) (string, error) {
+	webhook.ID = primitive.NewObjectID()
+	webhook.TenantID = ports.TenantFromContext(ctx)
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, webhook); err != nil {
+		return "", err
+	}
+
+	log.Printf("Webhook created with ID: %s", webhook.ID.Hex())
+	return webhook.ID.Hex(), nil
+}
+
+// FindByID retrieves a webhook subscription by its ID from the MongoDB collection
+func (r *WebhookRepository) FindByID(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ports.ErrWebhookNotFound
+	}
+
+	var webhook entities.WebhookSubscription
+	err = r.collection.FindOne(ctx, byTenant(ctx, bson.M{"_id": objectID})).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// Update modifies an existing webhook subscription in the MongoDB collection
+func (r *WebhookRepository) Update(ctx context.Context, webhook *entities.WebhookSubscription) error {
+	webhook.TenantID = ports.TenantFromContext(ctx)
+	webhook.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, byTenant(ctx, bson.M{"_id": webhook.ID}), webhook)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ports.ErrWebhookNotFound
+	}
+
+	log.Printf("Webhook with ID: %s updated successfully", webhook.ID.Hex())
+	return nil
+}
+
+// Delete removes a webhook subscription by its ID from the MongoDB collection
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrWebhookNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, byTenant(ctx, bson.M{"_id": objectID}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ports.ErrWebhookNotFound
+	}
+
+	log.Printf("Webhook with ID: %s deleted successfully", id)
+	return nil
+}
+
+// FindAll retrieves all webhook subscriptions of the tenant from the MongoDB collection
+func (r *WebhookRepository) FindAll(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	return r.find(ctx, bson.M{})
+}
+
+// FindActiveByEventType retrieves the active subscriptions listening to eventType,
+// including those that did not restrict their event types
+func (r *WebhookRepository) FindActiveByEventType(ctx context.Context, eventType string) ([]*entities.WebhookSubscription, error) {
+	return r.find(ctx, bson.M{
+		"active": true,
+		"$or": bson.A{
+			bson.M{"event_types": eventType},
+			bson.M{"event_types": bson.M{"$size": 0}},
+			bson.M{"event_types": nil},
+		},
+	})
+}
+
+// RecordFailure increments the failure count of a subscription, then disables it if it is still
+// active and the count reached disableAfter. Both updates are conditional on the stored document,
+// so concurrent failures are all counted and an admin re-enabling it in between is not overwritten.
+func (r *WebhookRepository) RecordFailure(ctx context.Context, id string, disableAfter int, at time.Time) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, ports.ErrWebhookNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx, byTenant(ctx, bson.M{"_id": objectID}), bson.M{
+		"$inc": bson.M{"failure_count": 1},
+	})
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount == 0 {
+		return false, ports.ErrWebhookNotFound
+	}
+
+	result, err = r.collection.UpdateOne(ctx, byTenant(ctx, bson.M{
+		"_id":           objectID,
+		"active":        true,
+		"failure_count": bson.M{"$gte": disableAfter},
+	}), bson.M{
+		"$set": bson.M{"active": false, "disabled_at": at, "updated_at": at},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ResetFailures sets the failure count of a subscription back to zero if it is not already
+func (r *WebhookRepository) ResetFailures(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ports.ErrWebhookNotFound
+	}
+
+	_, err = r.collection.UpdateOne(ctx, byTenant(ctx, bson.M{"_id": objectID, "failure_count": bson.M{"$ne": 0}}), bson.M{
+		"$set": bson.M{"failure_count": 0},
+	})
+	return err
+}
+
+// find runs a query on the subscriptions of the tenant and decodes every matching one
+func (r *WebhookRepository) find(ctx context.Context, filter bson.M) ([]*entities.WebhookSubscription, error) {
+	cursor, err := r.collection.Find(ctx, byTenant(ctx, filter), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []*entities.WebhookSubscription
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
@@ -0,0 +1,384 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+const (
+	// apiKeyCacheTTL bounds how long a verified key is kept in the shared cache
+	apiKeyCacheTTL = 5 * time.Minute
+	// apiKeyLocalCacheTTL bounds how long a replica that missed a revocation broadcast keeps
+	// accepting the key
+	apiKeyLocalCacheTTL = 30 * time.Second
+	// apiKeyTouchInterval throttles the last-used writes of busy keys
+	apiKeyTouchInterval = time.Minute
+	// apiKeyRevocationRetryDelay is how long the revocation listener waits before consuming again
+	apiKeyRevocationRetryDelay = 2 * time.Second
+	// apiKeySecretBytes is the entropy of a key, which is encoded after entities.APIKeyPrefix
+	apiKeySecretBytes = 32
+	// apiKeyVisiblePrefix is the length of the start of a key that is stored in clear
+	apiKeyVisiblePrefix = len(entities.APIKeyPrefix) + 8
+	// bootstrapAPIKeyName names the admin keys seeded from the configuration
+	bootstrapAPIKeyName = "bootstrap"
+)
+
+var (
+	// ErrInvalidAPIKeyName is returned when an API key is created without a name
+	ErrInvalidAPIKeyName = errors.New("api key name is required")
+
+	// ErrInvalidScope is returned when an API key is granted no scope or an unknown one
+	ErrInvalidScope = errors.New("api key scopes must be one or more of catalog:read, catalog:write or admin")
+
+	// ErrInvalidAPIKeyExpiry is returned when an API key would expire in the past
+	ErrInvalidAPIKeyExpiry = errors.New("api key expiry must be in the future")
+
+	// ErrInvalidGracePeriod is returned when a rotation is given a negative grace period
+	ErrInvalidGracePeriod = errors.New("grace period must not be negative")
+
+	// ErrAPIKeyRevoked is returned when rotating or revoking a key that is already revoked, or
+	// rotating one that was already rotated
+	ErrAPIKeyRevoked = errors.New("api key is already revoked")
+
+	// ErrAPIKeyAdminRequired is returned when API keys are managed without an API key with the admin scope
+	ErrAPIKeyAdminRequired = errors.New("managing api keys requires an api key with the admin scope")
+)
+
+// APIKeyService manages the API keys of machine clients and authenticates the requests carrying
+// them. Verified keys are cached in a cache shared by every replica and in a short-lived local
+// one; revocations are broadcast so each replica drops the key from its local cache within seconds.
+type APIKeyService struct {
+	repo        ports.APIKeyRepository
+	cache       ports.APIKeyCache
+	local       ports.APIKeyCache
+	publisher   ports.EventPublisher
+	revocations ports.APIKeyRevocationConsumer
+
+	mu      sync.Mutex
+	touched map[string]time.Time // When the last-used time of each key was last written
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService
+func NewAPIKeyService(
+	repo ports.APIKeyRepository,
+	cache ports.APIKeyCache,
+	local ports.APIKeyCache,
+	publisher ports.EventPublisher,
+	revocations ports.APIKeyRevocationConsumer,
+) *APIKeyService {
+	return &APIKeyService{
+		repo:        repo,
+		cache:       cache,
+		local:       local,
+		publisher:   publisher,
+		revocations: revocations,
+		touched:     make(map[string]time.Time),
+	}
+}
+
+// CreateAPIKey issues a new key to the tenant of ctx. The key itself is only returned here;
+// it cannot be recovered later.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*entities.APIKey, string, error) {
+	if err := requireAdminKey(ctx); err != nil {
+		return nil, "", err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", ErrInvalidAPIKeyName
+	}
+	if !validScopes(scopes) {
+		return nil, "", ErrInvalidScope
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidAPIKeyExpiry
+	}
+
+	return s.issue(ctx, name, scopes, expiresAt)
+}
+
+// GetAPIKey retrieves an API key by its ID
+func (s *APIKeyService) GetAPIKey(ctx context.Context, id string) (*entities.APIKey, error) {
+	if err := requireAdminKey(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListAPIKeys retrieves every API key of the tenant, including revoked ones
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*entities.APIKey, error) {
+	if err := requireAdminKey(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.FindAll(ctx)
+}
+
+// RotateAPIKey issues a replacement with the name, scopes and expiry of a key. The old key keeps
+// working for the grace period, so clients can switch over, and is revoked right away without one.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, id string, gracePeriod time.Duration) (*entities.APIKey, string, error) {
+	if err := requireAdminKey(ctx); err != nil {
+		return nil, "", err
+	}
+	if gracePeriod < 0 {
+		return nil, "", ErrInvalidGracePeriod
+	}
+
+	old, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	if !old.ValidAt(now) || old.ReplacedBy != "" {
+		return nil, "", ErrAPIKeyRevoked
+	}
+
+	key, plaintext, err := s.issue(ctx, old.Name, old.Scopes, old.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	revokedAt := now.Add(gracePeriod)
+	old.RevokedAt = &revokedAt
+	old.ReplacedBy = key.ID.Hex()
+	if err := s.repo.Update(ctx, old); err != nil {
+		return nil, "", err
+	}
+	// Cached copies do not know about the revocation yet, even when it only takes effect later
+	s.invalidate(ctx, old)
+
+	return key, plaintext, nil
+}
+
+// RevokeAPIKey stops a key from authenticating requests on every replica
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := requireAdminKey(ctx); err != nil {
+		return err
+	}
+	key, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if !key.ValidAt(now) {
+		return ErrAPIKeyRevoked
+	}
+
+	key.RevokedAt = &now
+	if err := s.repo.Update(ctx, key); err != nil {
+		return err
+	}
+	s.invalidate(ctx, key)
+
+	return nil
+}
+
+// SeedAPIKey stores plaintext as an admin key of the tenant of ctx unless it is stored already, so the
+// first key of a tenant can be issued without one. A seeded key that was since rotated or revoked
+// stays so.
+func (s *APIKeyService) SeedAPIKey(ctx context.Context, plaintext string) error {
+	if !strings.HasPrefix(plaintext, entities.APIKeyPrefix) {
+		return ports.ErrInvalidAPIKey
+	}
+
+	hash := hashAPIKey(plaintext)
+	_, err := s.repo.FindByHash(ctx, hash)
+	if !errors.Is(err, ports.ErrAPIKeyNotFound) {
+		return err
+	}
+
+	key := &entities.APIKey{
+		Name:   bootstrapAPIKeyName,
+		Prefix: plaintext[:apiKeyVisiblePrefix],
+		Hash:   hash,
+		Scopes: []string{entities.ScopeAdmin},
+	}
+	if _, err := s.repo.Create(ctx, key); err != nil {
+		return err
+	}
+	log.Printf("Seeded bootstrap API key %s of tenant %s", key.Prefix, ports.TenantFromContext(ctx))
+	return nil
+}
+
+// Authenticate implements ports.APIKeyAuthenticator. Keys are looked up in the local cache, then
+// the shared cache, then the repository, and their last-used time is recorded at most once a minute.
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (*entities.APIKey, error) {
+	if !strings.HasPrefix(plaintext, entities.APIKeyPrefix) {
+		return nil, ports.ErrInvalidAPIKey
+	}
+
+	key, err := s.lookup(ctx, hashAPIKey(plaintext))
+	if errors.Is(err, ports.ErrAPIKeyNotFound) {
+		return nil, ports.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.ValidAt(now) {
+		return nil, ports.ErrInvalidAPIKey
+	}
+	s.touch(ctx, key, now)
+
+	return key, nil
+}
+
+// RunRevocationListener drops revoked keys from the local cache until ctx is cancelled,
+// consuming again after a failure
+func (s *APIKeyService) RunRevocationListener(ctx context.Context) {
+	for {
+		err := s.revocations.ConsumeAPIKeyRevocations(ctx, func(ctx context.Context, revocation *entities.APIKeyRevocation) error {
+			return s.local.Delete(ctx, revocation.Hash)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("API key revocation listener stopped, retrying: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(apiKeyRevocationRetryDelay):
+		}
+	}
+}
+
+// issue generates and stores a new key
+func (s *APIKeyService) issue(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*entities.APIKey, string, error) {
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	plaintext := entities.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &entities.APIKey{
+		Name:      name,
+		Prefix:    plaintext[:apiKeyVisiblePrefix],
+		Hash:      hashAPIKey(plaintext),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if _, err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	return key, plaintext, nil
+}
+
+// lookup finds a key by hash in the caches or the repository, caching it on the way back
+func (s *APIKeyService) lookup(ctx context.Context, hash string) (*entities.APIKey, error) {
+	if key, err := s.local.Get(ctx, hash); err == nil {
+		return key, nil
+	}
+
+	key, err := s.cache.Get(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, ports.ErrCacheMiss) {
+			log.Printf("Failed to read API key from cache: %v", err)
+		}
+
+		key, err = s.repo.FindByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if ttl := cacheTTL(key, apiKeyCacheTTL); ttl > 0 {
+			if err := s.cache.Set(ctx, key, ttl); err != nil {
+				log.Printf("Failed to cache API key %s: %v", key.ID.Hex(), err)
+			}
+		}
+	}
+
+	if ttl := cacheTTL(key, apiKeyLocalCacheTTL); ttl > 0 {
+		if err := s.local.Set(ctx, key, ttl); err != nil {
+			log.Printf("Failed to cache API key %s: %v", key.ID.Hex(), err)
+		}
+	}
+	return key, nil
+}
+
+// invalidate drops a key from the shared cache and tells every replica to drop it from theirs.
+// Failures are logged: the local caches expire on their own shortly after.
+func (s *APIKeyService) invalidate(ctx context.Context, key *entities.APIKey) {
+	if err := s.cache.Delete(ctx, key.Hash); err != nil {
+		log.Printf("Failed to remove API key %s from cache: %v", key.ID.Hex(), err)
+	}
+	if err := s.local.Delete(ctx, key.Hash); err != nil {
+		log.Printf("Failed to remove API key %s from cache: %v", key.ID.Hex(), err)
+	}
+
+	revocation := entities.APIKeyRevocation{ID: key.ID.Hex(), Hash: key.Hash}
+	if err := s.publisher.Publish(ports.RoutingKey(ctx, entities.APIKeyRevoked), revocation); err != nil {
+		log.Printf("Failed to broadcast revocation of API key %s: %v", key.ID.Hex(), err)
+	}
+}
+
+// touch records the last use of a key unless it was recorded within apiKeyTouchInterval
+func (s *APIKeyService) touch(ctx context.Context, key *entities.APIKey, now time.Time) {
+	id := key.ID.Hex()
+
+	s.mu.Lock()
+	if last, ok := s.touched[id]; ok && now.Sub(last) < apiKeyTouchInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.touched[id] = now
+	s.mu.Unlock()
+
+	if err := s.repo.TouchLastUsed(ctx, id, now); err != nil {
+		log.Printf("Failed to record last use of API key %s: %v", id, err)
+	}
+}
+
+// cacheTTL caps ttl so a cached key never outlives its expiry or scheduled revocation
+func cacheTTL(key *entities.APIKey, ttl time.Duration) time.Duration {
+	now := time.Now()
+	for _, end := range []*time.Time{key.ExpiresAt, key.RevokedAt} {
+		if end != nil && end.Sub(now) < ttl {
+			ttl = end.Sub(now)
+		}
+	}
+	return ttl
+}
+
+// hashAPIKey returns the hash under which a key is stored. Keys carry enough entropy that an
+// unsalted SHA-256 cannot be reversed, and it lets a key be looked up by its hash.
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// requireAdminKey fails with ErrAPIKeyAdminRequired unless the request was authenticated with an API
+// key with the admin scope. The API key middleware already requires one on the admin routes.
+func requireAdminKey(ctx context.Context) error {
+	if key := ports.APIKeyFromContext(ctx); key == nil || !key.HasScope(entities.ScopeAdmin) {
+		return ErrAPIKeyAdminRequired
+	}
+	return nil
+}
+
+// validScopes reports whether scopes is a non-empty list of known scopes
+func validScopes(scopes []string) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		known := false
+		for _, s := range entities.APIKeyScopes {
+			known = known || s == scope
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
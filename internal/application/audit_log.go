@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+const (
+	// defaultAuditLimit is the number of entries returned when no limit is given
+	defaultAuditLimit = 100
+	// maxAuditLimit bounds a page of entries; larger extracts use the export
+	maxAuditLimit = 1000
+)
+
+// errChainBroken stops the walk of VerifyChain at the first broken entry
+var errChainBroken = errors.New("audit chain broken")
+
+// auditRecorder implements the ports.ProductRepository interface by appending an audit entry for
+// every write to the wrapped repository. Reads are passed through.
+type auditRecorder struct {
+	ports.ProductRepository
+	audit ports.AuditRepository
+}
+
+// NewAuditRecordingRepository wraps repo so every create, update and delete is appended to the
+// audit log, together with the actor, transport, IP and request ID carried by the context
+func NewAuditRecordingRepository(repo ports.ProductRepository, audit ports.AuditRepository) ports.ProductRepository {
+	return &auditRecorder{
+		ProductRepository: repo,
+		audit:             audit,
+	}
+}
+
+// Create stores the product and audits its creation
+func (r *auditRecorder) Create(ctx context.Context, product *entities.Product) (string, error) {
+	id, err := r.ProductRepository.Create(ctx, product)
+	if err != nil {
+		return "", err
+	}
+
+	return id, r.record(ctx, entities.RevisionCreate, id, nil, product)
+}
+
+// Update stores the product and audits the changes since its previous state
+func (r *auditRecorder) Update(ctx context.Context, product *entities.Product) error {
+	before, err := r.ProductRepository.FindByID(ctx, product.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if err := r.ProductRepository.Update(ctx, product); err != nil {
+		return err
+	}
+
+	operation := entities.RevisionUpdate
+	if _, ok := ctx.Value(rollbackKey{}).(int64); ok {
+		operation = entities.RevisionRollback
+	}
+	return r.record(ctx, operation, product.ID.Hex(), before, product)
+}
+
+// Delete removes the product and audits its last state
+func (r *auditRecorder) Delete(ctx context.Context, id string) error {
+	before, err := r.ProductRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.ProductRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return r.record(ctx, entities.RevisionDelete, id, before, nil)
+}
+
+// record appends an audit entry for a write of the product
+func (r *auditRecorder) record(ctx context.Context, operation string, id string, before *entities.Product, after *entities.Product) error {
+	info := ports.RequestInfoFromContext(ctx)
+	entry := &entities.AuditEntry{
+		Principal:    info.Principal,
+		Actor:        info.Actor,
+		Operation:    operation,
+		ResourceType: entities.AuditResourceProduct,
+		ResourceID:   id,
+		Changes:      usecases.DiffProducts(before, after),
+		Transport:    info.Transport,
+		IP:           info.IP,
+		RequestID:    info.RequestID,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := r.audit.Append(ctx, entry); err != nil {
+		return fmt.Errorf("audit %s of product %s: %w", operation, id, err)
+	}
+	return nil
+}
+
+// AuditVerification is the result of checking the hash chain of a tenant's audit log
+type AuditVerification struct {
+	Valid   bool  `json:"valid"`
+	Entries int64 `json:"entries"`
+	// BrokenAt is the sequence of the first entry that does not match the chain
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// AuditService queries the audit log and verifies its hash chain
+type AuditService struct {
+	repo ports.AuditRepository
+}
+
+// NewAuditService creates a new instance of AuditService
+func NewAuditService(repo ports.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// ListEntries retrieves a page of the tenant's audit entries in sequence order
+func (s *AuditService) ListEntries(ctx context.Context, filter ports.AuditFilter) ([]*entities.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	return s.repo.Find(ctx, filter)
+}
+
+// ExportEntries calls fn for every matching entry of the tenant in sequence order
+func (s *AuditService) ExportEntries(ctx context.Context, filter ports.AuditFilter, fn func(*entities.AuditEntry) error) error {
+	return s.repo.Stream(ctx, filter, fn)
+}
+
+// VerifyChain walks the tenant's audit log and reports the first entry that was altered, removed
+// or inserted out of order
+func (s *AuditService) VerifyChain(ctx context.Context) (*AuditVerification, error) {
+	verification := &AuditVerification{Valid: true}
+
+	var sequence int64
+	var prevHash string
+	err := s.repo.Stream(ctx, ports.AuditFilter{}, func(entry *entities.AuditEntry) error {
+		reason := ""
+		switch {
+		case entry.Sequence != sequence+1:
+			reason = fmt.Sprintf("expected sequence %d", sequence+1)
+		case entry.PrevHash != prevHash:
+			reason = "previous hash does not match the previous entry"
+		case entry.Hash != entry.ComputeHash():
+			reason = "hash does not match the entry"
+		}
+		if reason != "" {
+			verification.Valid = false
+			verification.BrokenAt = entry.Sequence
+			verification.Reason = reason
+			return errChainBroken
+		}
+
+		verification.Entries++
+		sequence, prevHash = entry.Sequence, entry.Hash
+		return nil
+	})
+	if err != nil && err != errChainBroken {
+		return nil, err
+	}
+
+	return verification, nil
+}
@@ -0,0 +1,224 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// bundleRefresherRetryDelay is how long the bundle refresher waits before consuming again
+const bundleRefresherRetryDelay = 2 * time.Second
+
+// ErrNotABundle is returned when reading the bundle of a product that has no components
+var ErrNotABundle = errors.New("product is not a bundle")
+
+// BundleService manages bundles, computes their availability from the stock of their components
+// and keeps their derived prices up to date as components change
+type BundleService struct {
+	products *ProductService
+	stock    ports.InventoryRepository
+	events   ports.EventConsumer
+}
+
+// NewBundleService creates a new instance of BundleService. events is only consumed by
+// RunBundleRefresher.
+func NewBundleService(products *ProductService, stock ports.InventoryRepository, events ports.EventConsumer) *BundleService {
+	return &BundleService{
+		products: products,
+		stock:    stock,
+		events:   events,
+	}
+}
+
+// SetBundle makes a product a bundle of components, or changes its components and pricing. The
+// bundle is validated, priced and recorded like any update.
+func (s *BundleService) SetBundle(ctx context.Context, id string, bundle *entities.Bundle) error {
+	return s.products.updateProduct(ctx, id, func(product *entities.Product) error {
+		product.Bundle = bundle
+		return nil
+	})
+}
+
+// RemoveBundle turns a bundle back into a plain product, which keeps its last derived price
+func (s *BundleService) RemoveBundle(ctx context.Context, id string) error {
+	return s.products.updateProduct(ctx, id, func(product *entities.Product) error {
+		if product.Bundle == nil {
+			return ErrNotABundle
+		}
+		product.Bundle = nil
+		return nil
+	})
+}
+
+// GetBundle returns a bundle in the lifecycle status asked for, as statusFilter reads it, with the
+// name, price and stock of its components and how many units of it the stock is enough for.
+// Components in another status are listed without their name and price and count as unavailable,
+// since the bundle cannot be sold without them.
+func (s *BundleService) GetBundle(ctx context.Context, id string, status string) (*entities.BundleView, error) {
+	visible, err := statusFilter(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.products.useCase.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visible(product) {
+		return nil, ports.ErrProductNotFound
+	}
+	if product.Bundle == nil {
+		return nil, ErrNotABundle
+	}
+	return s.bundleView(ctx, product, visible, 0)
+}
+
+// bundleView computes the availability of a bundle nested depth levels deep, limited by its
+// scarcest component. Components visible hides are unavailable.
+func (s *BundleService) bundleView(ctx context.Context, product *entities.Product, visible func(*entities.Product) bool, depth int) (*entities.BundleView, error) {
+	view := &entities.BundleView{
+		ProductID:       product.ID.Hex(),
+		Name:            product.Name,
+		Price:           product.Price,
+		Pricing:         product.Bundle.Pricing,
+		DiscountPercent: product.Bundle.DiscountPercent,
+		Components:      []entities.BundleComponentView{},
+	}
+
+	for i, component := range product.Bundle.Components {
+		item, err := s.products.useCase.GetProductByID(ctx, component.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		componentView := entities.BundleComponentView{
+			ProductID: component.ProductID,
+			Quantity:  component.Quantity,
+		}
+		if visible(item) {
+			available, err := s.available(ctx, item, visible, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			componentView.Name = item.Name
+			componentView.Price = item.Price
+			componentView.Available = available
+			componentView.Sets = usecases.BundleSets(available, component.Quantity)
+		}
+
+		if i == 0 || componentView.Sets < view.Available {
+			view.Available = componentView.Sets
+		}
+		view.Components = append(view.Components, componentView)
+	}
+	return view, nil
+}
+
+// available returns the quantity of a product that can still be reserved in every warehouse, or
+// the availability of a bundle nested depth levels deep. Validation keeps bundles from nesting
+// deeper than usecases.MaxBundleDepth, but stored bundles may predate it or have been nested
+// concurrently into a cycle, so deeper ones count as unavailable instead of recursing on.
+func (s *BundleService) available(ctx context.Context, product *entities.Product, visible func(*entities.Product) bool, depth int) (int64, error) {
+	if product.Bundle != nil {
+		if depth >= usecases.MaxBundleDepth {
+			log.Printf("Bundle %s is nested more than %d levels deep, counting it as unavailable", product.ID.Hex(), usecases.MaxBundleDepth)
+			return 0, nil
+		}
+		view, err := s.bundleView(ctx, product, visible, depth)
+		if err != nil {
+			return 0, err
+		}
+		return view.Available, nil
+	}
+
+	levels, err := s.stock.FindByProduct(ctx, product.ID.Hex())
+	if err != nil {
+		return 0, err
+	}
+	var available int64
+	for _, level := range levels {
+		available += level.Available()
+	}
+	return available, nil
+}
+
+// HandleEvent refreshes the bundles containing an updated product. A bundle whose derived price
+// changes is saved and published in turn, which refreshes the bundles it is nested in.
+func (s *BundleService) HandleEvent(ctx context.Context, event *entities.ProductEvent) error {
+	if event.Type != entities.ProductUpdated {
+		// Created products are in no bundle yet, and products in a bundle cannot be deleted
+		return nil
+	}
+	ctx = ports.WithTenant(ctx, event.TenantID)
+
+	bundles, err := s.products.useCase.FindProducts(ctx, ports.ProductFilter{ComponentID: event.ProductID})
+	if err != nil {
+		return err
+	}
+	// One bundle failing to refresh does not hold back the others
+	var errs []error
+	for _, bundle := range bundles {
+		if err := s.products.refreshBundle(ctx, bundle.ID.Hex()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunBundleRefresher refreshes bundles as their components change until ctx is cancelled,
+// consuming again after a failure. It is only needed where product events never leave the
+// process; otherwise the event consumer calls HandleEvent.
+func (s *BundleService) RunBundleRefresher(ctx context.Context) {
+	for {
+		err := s.events.ConsumeProductEvents(ctx, s.HandleEvent)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Bundle refresher stopped, retrying: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bundleRefresherRetryDelay):
+		}
+	}
+}
+
+// refreshBundle derives the price of a bundle again and saves it with its revision and event when
+// it changed, also refreshing the cached bundle
+func (s *ProductService) refreshBundle(ctx context.Context, id string) error {
+	var changed *entities.Product
+	err := s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+		useCase := s.productUseCase(tx)
+
+		product, err := useCase.GetProductByID(ctx, id)
+		if errors.Is(err, ports.ErrProductNotFound) {
+			// Deleted since the bundles were listed
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		price := product.Price
+		if err := useCase.DeriveBundlePrice(ctx, product); err != nil || product.Price == price {
+			return err
+		}
+
+		if err := useCase.UpdateProduct(ctx, product); err != nil {
+			return err
+		}
+		changed = product
+		return tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductUpdated), product)
+	})
+	if err != nil || changed == nil {
+		return err
+	}
+
+	log.Printf("Price of bundle %s refreshed to %g", id, changed.Price)
+	s.cacheProduct(ctx, changed)
+	return nil
+}
@@ -0,0 +1,317 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCategoryName is returned when a category name is blank
+var ErrInvalidCategoryName = errors.New("category name is required")
+
+// ErrCategoryCycle is returned when a category would be moved below itself
+var ErrCategoryCycle = errors.New("category cannot be moved below itself or its descendants")
+
+// ErrCategoryNotEmpty is returned when deleting a category that still has subcategories or products
+var ErrCategoryNotEmpty = errors.New("category still has subcategories or products")
+
+// CategoryService manages the category tree and product category assignment. Products are read
+// from their repository and written through productService, which records and caches the change.
+type CategoryService struct {
+	repo           ports.CategoryRepository
+	products       ports.ProductRepository
+	productService *ProductService
+	publisher      ports.EventPublisher
+}
+
+// NewCategoryService creates a new instance of CategoryService
+func NewCategoryService(repo ports.CategoryRepository, products ports.ProductRepository, productService *ProductService, publisher ports.EventPublisher) *CategoryService {
+	return &CategoryService{
+		repo:           repo,
+		products:       products,
+		productService: productService,
+		publisher:      publisher,
+	}
+}
+
+// CreateCategory adds a category at the end of its parent's children, or as a root when parentID is empty
+func (s *CategoryService) CreateCategory(ctx context.Context, name string, parentID string) (*entities.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidCategoryName
+	}
+
+	parentPath, depth := "/", 0
+	if parentID != "" {
+		parent, err := s.repo.FindByID(ctx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		parentPath, depth = parent.Path, parent.Depth+1
+	}
+
+	siblings, err := s.repo.FindChildren(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &entities.Category{
+		ID:       primitive.NewObjectID(),
+		Name:     name,
+		ParentID: parentID,
+		Depth:    depth,
+		Position: len(siblings),
+	}
+	category.Path = entities.CategoryPath(parentPath, category.ID)
+
+	if _, err := s.repo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, entities.CategoryCreated, category)
+	return category, nil
+}
+
+// GetCategory retrieves a category by its ID
+func (s *CategoryService) GetCategory(ctx context.Context, id string) (*entities.Category, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListCategories retrieves every category, parents before children
+func (s *CategoryService) ListCategories(ctx context.Context) ([]*entities.Category, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// ListChildren retrieves the direct children of a category, or the roots when parentID is empty
+func (s *CategoryService) ListChildren(ctx context.Context, parentID string) ([]*entities.Category, error) {
+	if parentID != "" {
+		if _, err := s.repo.FindByID(ctx, parentID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindChildren(ctx, parentID)
+}
+
+// ListDescendants retrieves every category below the given one
+func (s *CategoryService) ListDescendants(ctx context.Context, id string) ([]*entities.Category, error) {
+	category, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindDescendants(ctx, category.Path)
+}
+
+// GetBreadcrumb retrieves the chain of categories from the root down to the given one
+func (s *CategoryService) GetBreadcrumb(ctx context.Context, id string) ([]*entities.Category, error) {
+	category, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.repo.FindByIDs(ctx, category.AncestorIDs())
+	if err != nil {
+		return nil, err
+	}
+
+	// Depth orders the ancestors from the root down
+	breadcrumb := make([]*entities.Category, category.Depth+1)
+	for _, ancestor := range ancestors {
+		if ancestor.Depth < len(breadcrumb) {
+			breadcrumb[ancestor.Depth] = ancestor
+		}
+	}
+	breadcrumb[category.Depth] = category
+
+	return breadcrumb, nil
+}
+
+// RenameCategory changes the name of a category
+func (s *CategoryService) RenameCategory(ctx context.Context, id string, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrInvalidCategoryName
+	}
+
+	category, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	category.Name = name
+	if err := s.repo.Update(ctx, category); err != nil {
+		return err
+	}
+
+	s.publish(ctx, entities.CategoryUpdated, category)
+	return nil
+}
+
+// DeleteCategory removes a category that has no subcategories and no products
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
+	children, err := s.repo.FindChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return ErrCategoryNotEmpty
+	}
+
+	products, err := s.products.FindByCategoryIDs(ctx, []string{id})
+	if err != nil {
+		return err
+	}
+	if len(products) > 0 {
+		return ErrCategoryNotEmpty
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.publish(ctx, entities.CategoryDeleted, map[string]string{"id": id})
+	return nil
+}
+
+// MoveCategory puts a category, with its whole subtree, under parentID at the given position
+// among its new siblings. Moving within the same parent only reorders the siblings.
+func (s *CategoryService) MoveCategory(ctx context.Context, id string, parentID string, position int) (*entities.Category, error) {
+	category, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	parentPath, depth := "/", 0
+	if parentID != "" {
+		parent, err := s.repo.FindByID(ctx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(parent.Path, category.Path) {
+			return nil, ErrCategoryCycle
+		}
+		parentPath, depth = parent.Path, parent.Depth+1
+	}
+
+	oldPath := category.Path
+	newPath := entities.CategoryPath(parentPath, category.ID)
+	if newPath != oldPath {
+		if err := s.repo.MoveDescendants(ctx, oldPath, newPath, depth-category.Depth); err != nil {
+			return nil, err
+		}
+	}
+
+	category.ParentID = parentID
+	category.Path = newPath
+	category.Depth = depth
+
+	// Renumber the new siblings with the category inserted at the requested position
+	siblings, err := s.repo.FindChildren(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	ordered := make([]*entities.Category, 0, len(siblings)+1)
+	for _, sibling := range siblings {
+		if sibling.ID != category.ID {
+			ordered = append(ordered, sibling)
+		}
+	}
+	if position < 0 || position > len(ordered) {
+		position = len(ordered)
+	}
+	ordered = append(ordered[:position], append([]*entities.Category{category}, ordered[position:]...)...)
+
+	for i, sibling := range ordered {
+		if sibling.ID != category.ID && sibling.Position == i {
+			continue
+		}
+		sibling.Position = i
+		if err := s.repo.Update(ctx, sibling); err != nil {
+			return nil, err
+		}
+	}
+
+	s.publish(ctx, entities.CategoryMoved, category)
+	return category, nil
+}
+
+// AssignProductCategories replaces the categories a product is assigned to. The product is saved
+// like any update, with its revision and event.
+func (s *CategoryService) AssignProductCategories(ctx context.Context, productID string, categoryIDs []string) error {
+	categories, err := s.repo.FindByIDs(ctx, categoryIDs)
+	if err != nil {
+		return err
+	}
+	if len(categories) != len(uniqueStrings(categoryIDs)) {
+		return ports.ErrCategoryNotFound
+	}
+
+	return s.productService.updateProduct(ctx, productID, func(product *entities.Product) error {
+		product.CategoryIDs = uniqueStrings(categoryIDs)
+		return nil
+	})
+}
+
+// ListCategoryProducts retrieves the products in a category, optionally including every descendant category.
+// Only products in the lifecycle status asked for are listed, as statusFilter reads it.
+func (s *CategoryService) ListCategoryProducts(ctx context.Context, id string, includeDescendants bool, status string) ([]*entities.Product, error) {
+	visible, err := statusFilter(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := []string{category.ID.Hex()}
+	if includeDescendants {
+		descendants, err := s.repo.FindDescendants(ctx, category.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, descendant := range descendants {
+			categoryIDs = append(categoryIDs, descendant.ID.Hex())
+		}
+	}
+
+	all, err := s.products.FindByCategoryIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	products := all[:0]
+	for _, product := range all {
+		if visible(product) {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+// publish sends a category event routed by the tenant of ctx, logging instead of failing the request
+// when the broker is unavailable
+func (s *CategoryService) publish(ctx context.Context, eventType string, message interface{}) {
+	if err := s.publisher.Publish(ports.RoutingKey(ctx, eventType), message); err != nil {
+		log.Printf("Failed to publish %s event: %v", eventType, err)
+	}
+}
+
+// uniqueStrings returns values without duplicates, keeping the first occurrence order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		unique = append(unique, value)
+	}
+	return unique
+}
@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// Formats of exchange rate imports
+const (
+	ExchangeRatesJSON = "json"
+	ExchangeRatesCSV  = "csv"
+)
+
+// exchangeRateFileSource is the source of the rates imported from a file
+const exchangeRateFileSource = "file"
+
+var (
+	// ErrInvalidExchangeRateFormat is returned for imports in other formats than the ExchangeRates constants
+	ErrInvalidExchangeRateFormat = errors.New("exchange rates must be imported as json or csv")
+	// ErrNoExchangeRateSource is returned when refreshing rates without an exchange rate API configured
+	ErrNoExchangeRateSource = errors.New("no exchange rate source is configured")
+)
+
+// ExchangeRateService imports the exchange rates product prices are converted with and keeps their history
+type ExchangeRateService struct {
+	rates  ports.ExchangeRateRepository
+	source ports.ExchangeRateSource
+}
+
+// NewExchangeRateService creates a new instance of ExchangeRateService. source may be nil when rates
+// are only imported from files.
+func NewExchangeRateService(rates ports.ExchangeRateRepository, source ports.ExchangeRateSource) *ExchangeRateService {
+	return &ExchangeRateService{
+		rates:  rates,
+		source: source,
+	}
+}
+
+// ImportRates stores the rates of a rate table in format, one of the ExchangeRates constants, and
+// returns how many were stored
+func (s *ExchangeRateService) ImportRates(ctx context.Context, format string, data []byte) (int, error) {
+	var parse func([]byte, string, time.Time) ([]*entities.ExchangeRate, error)
+	switch format {
+	case ExchangeRatesJSON:
+		parse = usecases.ParseExchangeRatesJSON
+	case ExchangeRatesCSV:
+		parse = usecases.ParseExchangeRatesCSV
+	default:
+		return 0, ErrInvalidExchangeRateFormat
+	}
+
+	rates, err := parse(data, exchangeRateFileSource, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if err := s.rates.Save(ctx, rates); err != nil {
+		return 0, err
+	}
+
+	log.Printf("Imported %d exchange rates from a file", len(rates))
+	return len(rates), nil
+}
+
+// RefreshRates fetches and stores the current rates of the configured exchange rate API, and returns
+// how many were stored
+func (s *ExchangeRateService) RefreshRates(ctx context.Context) (int, error) {
+	if s.source == nil {
+		return 0, ErrNoExchangeRateSource
+	}
+
+	data, err := s.source.Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rates, err := usecases.ParseExchangeRatesJSON(data, s.source.Name(), time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if err := s.rates.Save(ctx, rates); err != nil {
+		return 0, err
+	}
+
+	log.Printf("Imported %d exchange rates from %s", len(rates), s.source.Name())
+	return len(rates), nil
+}
+
+// ListRates retrieves the rate of every currency pair in effect at the given time
+func (s *ExchangeRateService) ListRates(ctx context.Context, at time.Time) ([]*entities.ExchangeRate, error) {
+	return s.rates.FindLatest(ctx, at)
+}
+
+// RateHistory retrieves the rates of a currency pair that took effect from from until to, oldest first
+func (s *ExchangeRateService) RateHistory(ctx context.Context, base string, quote string, from time.Time, to time.Time) ([]*entities.ExchangeRate, error) {
+	base, err := usecases.ParseCurrency(base)
+	if err != nil {
+		return nil, err
+	}
+	quote, err = usecases.ParseCurrency(quote)
+	if err != nil {
+		return nil, err
+	}
+	return s.rates.FindHistory(ctx, base, quote, from, to)
+}
+
+// RunExchangeRateImporter refreshes the rates from the configured exchange rate API on start and then
+// every interval until ctx is cancelled. It returns at once when no API is configured.
+func (s *ExchangeRateService) RunExchangeRateImporter(ctx context.Context, interval time.Duration) {
+	if s.source == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RefreshRates(ctx); err != nil {
+			// Prices keep converting with the last rates until the next tick
+			log.Printf("Failed to refresh exchange rates: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+const (
+	defaultReservationTTL = 15 * time.Minute // Reservation lifetime when the caller does not pick one
+	maxReservationTTL     = 24 * time.Hour   // Longest reservation lifetime accepted
+	reaperBatchSize       = 100              // Expired reservations released per sweep
+)
+
+// ErrInvalidQuantity is returned when a stock quantity is zero or negative where it must be positive
+var ErrInvalidQuantity = errors.New("quantity must be positive")
+
+// InventoryService manages stock levels and reservations and publishes stock events
+type InventoryService struct {
+	stock        ports.InventoryRepository
+	reservations ports.ReservationRepository
+	publisher    ports.EventPublisher
+}
+
+// NewInventoryService creates a new instance of InventoryService
+func NewInventoryService(stock ports.InventoryRepository, reservations ports.ReservationRepository, publisher ports.EventPublisher) *InventoryService {
+	return &InventoryService{
+		stock:        stock,
+		reservations: reservations,
+		publisher:    publisher,
+	}
+}
+
+// GetStock retrieves the stock levels of a product in every warehouse
+func (s *InventoryService) GetStock(ctx context.Context, productID string) ([]*entities.StockLevel, error) {
+	return s.stock.FindByProduct(ctx, productID)
+}
+
+// AdjustStock adds delta (which may be negative) to the on-hand quantity of a product
+func (s *InventoryService) AdjustStock(ctx context.Context, productID string, warehouse string, delta int64, reason string) (*entities.StockLevel, error) {
+	if delta == 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	level, err := s.stock.AdjustOnHand(ctx, productID, warehouseOrDefault(warehouse), delta)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		reason = "adjustment"
+	}
+	s.publishStockChanged(ctx, level, reason, level.Available()-delta)
+	return level, nil
+}
+
+// SetLowStockThreshold changes the available quantity at or below which low-stock events are published
+func (s *InventoryService) SetLowStockThreshold(ctx context.Context, productID string, warehouse string, threshold int64) (*entities.StockLevel, error) {
+	if threshold < 0 {
+		return nil, ErrInvalidQuantity
+	}
+	return s.stock.SetLowStockThreshold(ctx, productID, warehouseOrDefault(warehouse), threshold)
+}
+
+// ReserveStock holds quantity for ttl. The reservation is released automatically once it expires.
+func (s *InventoryService) ReserveStock(ctx context.Context, productID string, warehouse string, quantity int64, ttl time.Duration) (*entities.Reservation, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if ttl <= 0 {
+		ttl = defaultReservationTTL
+	}
+	if ttl > maxReservationTTL {
+		ttl = maxReservationTTL
+	}
+	warehouse = warehouseOrDefault(warehouse)
+
+	level, err := s.stock.Reserve(ctx, productID, warehouse, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &entities.Reservation{
+		ProductID: productID,
+		Warehouse: warehouse,
+		Quantity:  quantity,
+		Status:    entities.ReservationPending,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if _, err := s.reservations.Create(ctx, reservation); err != nil {
+		// Give the stock back, otherwise it stays reserved with no reservation to release it
+		if _, releaseErr := s.stock.Release(ctx, productID, warehouse, quantity); releaseErr != nil {
+			log.Printf("Failed to roll back reservation of %d x %s: %v", quantity, productID, releaseErr)
+		}
+		return nil, err
+	}
+
+	s.publishStockChanged(ctx, level, "reserved", level.Available()+quantity)
+	return reservation, nil
+}
+
+// GetReservation retrieves a reservation by its ID
+func (s *InventoryService) GetReservation(ctx context.Context, id string) (*entities.Reservation, error) {
+	return s.reservations.FindByID(ctx, id)
+}
+
+// CommitReservation turns a pending reservation into a permanent stock decrement
+func (s *InventoryService) CommitReservation(ctx context.Context, id string) (*entities.Reservation, error) {
+	reservation, err := s.reservations.Transition(ctx, id, entities.ReservationPending, entities.ReservationCommitted)
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := s.stock.Commit(ctx, reservation.ProductID, reservation.Warehouse, reservation.Quantity)
+	if err != nil {
+		// Put the reservation back so it can still be released
+		if _, revertErr := s.reservations.Transition(ctx, id, entities.ReservationCommitted, entities.ReservationPending); revertErr != nil {
+			log.Printf("Failed to revert reservation %s: %v", id, revertErr)
+		}
+		return nil, err
+	}
+
+	s.publishStockChanged(ctx, level, "committed", level.Available())
+	return reservation, nil
+}
+
+// ReleaseReservation returns the stock held by a pending reservation
+func (s *InventoryService) ReleaseReservation(ctx context.Context, id string) (*entities.Reservation, error) {
+	return s.release(ctx, id, "released")
+}
+
+// ReleaseExpired releases every pending reservation past its expiry, whatever its tenant, and returns
+// how many were released
+func (s *InventoryService) ReleaseExpired(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		expired, err := s.reservations.FindExpired(ctx, time.Now(), reaperBatchSize)
+		if err != nil {
+			return released, err
+		}
+
+		for _, reservation := range expired {
+			_, err := s.release(ports.WithTenant(ctx, reservation.TenantID), reservation.ID.Hex(), "expired")
+			if errors.Is(err, ports.ErrReservationClosed) {
+				// Committed or released concurrently
+				continue
+			}
+			if err != nil {
+				return released, err
+			}
+			released++
+		}
+
+		if len(expired) < reaperBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// RunReservationReaper releases expired reservations every interval until ctx is cancelled
+func (s *InventoryService) RunReservationReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.ReleaseExpired(ctx)
+			if err != nil {
+				log.Printf("Failed to release expired reservations: %v", err)
+			}
+			if released > 0 {
+				log.Printf("Released %d expired reservations", released)
+			}
+		}
+	}
+}
+
+// release moves a pending reservation to released and returns its stock
+func (s *InventoryService) release(ctx context.Context, id string, reason string) (*entities.Reservation, error) {
+	reservation, err := s.reservations.Transition(ctx, id, entities.ReservationPending, entities.ReservationReleased)
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := s.stock.Release(ctx, reservation.ProductID, reservation.Warehouse, reservation.Quantity)
+	if err != nil {
+		if _, revertErr := s.reservations.Transition(ctx, id, entities.ReservationReleased, entities.ReservationPending); revertErr != nil {
+			log.Printf("Failed to revert reservation %s: %v", id, revertErr)
+		}
+		return nil, err
+	}
+
+	s.publishStockChanged(ctx, level, reason, level.Available()-reservation.Quantity)
+	return reservation, nil
+}
+
+// publishStockChanged publishes a stock-level-changed event, plus a low-stock event when
+// the available quantity has just dropped to or below the threshold
+func (s *InventoryService) publishStockChanged(ctx context.Context, level *entities.StockLevel, reason string, previousAvailable int64) {
+	event := &entities.StockEvent{
+		ProductID: level.ProductID,
+		Warehouse: level.Warehouse,
+		OnHand:    level.OnHand,
+		Reserved:  level.Reserved,
+		Available: level.Available(),
+		Reason:    reason,
+		At:        time.Now().UTC(),
+	}
+
+	if err := s.publisher.Publish(ports.RoutingKey(ctx, entities.StockLevelChanged), event); err != nil {
+		log.Printf("Failed to publish stock changed event: %v", err)
+	}
+
+	threshold := level.LowStockThreshold
+	if threshold > 0 && event.Available <= threshold && previousAvailable > threshold {
+		event.Threshold = threshold
+		if err := s.publisher.Publish(ports.RoutingKey(ctx, entities.LowStock), event); err != nil {
+			log.Printf("Failed to publish low stock event: %v", err)
+		}
+	}
+}
+
+// warehouseOrDefault falls back to the default warehouse for an empty name
+func warehouseOrDefault(warehouse string) string {
+	if warehouse == "" {
+		return entities.DefaultWarehouse
+	}
+	return warehouse
+}
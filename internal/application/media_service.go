@@ -0,0 +1,485 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+const (
+	// originalBlob is the last segment of the blob key of uploaded content; thumbnails end in their size
+	originalBlob = "original"
+	// maxMediaFilenameLength bounds the stored filename of an upload
+	maxMediaFilenameLength = 255
+	// thumbnailJPEGQuality is the quality thumbnails of JPEG images are encoded with
+	thumbnailJPEGQuality = 85
+	// thumbnailWorkerRetryDelay is how long the thumbnail worker waits before consuming again
+	thumbnailWorkerRetryDelay = 2 * time.Second
+)
+
+var (
+	// ErrEmptyMedia is returned when an upload has no content
+	ErrEmptyMedia = errors.New("media file is empty")
+
+	// ErrMediaTooLarge is returned when an upload exceeds the configured size limit
+	ErrMediaTooLarge = errors.New("media file is too large")
+
+	// ErrMediaTooManyPixels is returned when an image declares more pixels than the configured limit,
+	// however small the file is
+	ErrMediaTooManyPixels = errors.New("media image has too many pixels")
+
+	// ErrUnsupportedMediaType is returned when the content of an upload is not an accepted image
+	ErrUnsupportedMediaType = errors.New("media must be a JPEG, PNG, GIF or WebP image")
+
+	// ErrInvalidMediaOrder is returned when a new order does not list every media of the product once
+	ErrInvalidMediaOrder = errors.New("media order must list every media of the product exactly once")
+
+	// ErrInvalidThumbnailSize is returned when content is requested in an unknown size
+	ErrInvalidThumbnailSize = errors.New("size must be original, small, medium or large")
+
+	// ErrThumbnailNotReady is returned when a thumbnail was not generated, either yet or at all
+	// because the image format cannot be decoded
+	ErrThumbnailNotReady = errors.New("thumbnail is not available")
+)
+
+// MediaService manages the files attached to products. Content is kept in a blob store under its
+// SHA-256, so identical uploads of a tenant share one blob, and thumbnails are generated in the
+// background from the media.uploaded event.
+type MediaService struct {
+	media     ports.MediaRepository
+	products  ports.ProductRepository
+	blobs     ports.BlobStore
+	publisher ports.EventPublisher
+	uploads   ports.MediaEventConsumer
+	maxBytes  int64
+	maxPixels int64
+}
+
+// NewMediaService creates a new instance of MediaService accepting uploads of up to maxBytes, and
+// images of up to maxPixels
+func NewMediaService(
+	media ports.MediaRepository,
+	products ports.ProductRepository,
+	blobs ports.BlobStore,
+	publisher ports.EventPublisher,
+	uploads ports.MediaEventConsumer,
+	maxBytes int64,
+	maxPixels int64,
+) *MediaService {
+	return &MediaService{
+		media:     media,
+		products:  products,
+		blobs:     blobs,
+		publisher: publisher,
+		uploads:   uploads,
+		maxBytes:  maxBytes,
+		maxPixels: maxPixels,
+	}
+}
+
+// UploadMedia attaches the content read from body to the end of a product's media. The content
+// type is sniffed from the content rather than trusted from the client. Uploading content the
+// product already has returns the existing media and false instead of adding a copy.
+func (s *MediaService) UploadMedia(ctx context.Context, productID string, filename string, body io.Reader) (*entities.Media, bool, error) {
+	if _, err := s.products.FindByID(ctx, productID); err != nil {
+		return nil, false, err
+	}
+
+	content, err := io.ReadAll(io.LimitReader(body, s.maxBytes+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(content) == 0 {
+		return nil, false, ErrEmptyMedia
+	}
+	if int64(len(content)) > s.maxBytes {
+		return nil, false, ErrMediaTooLarge
+	}
+	contentType := http.DetectContentType(content)
+	if !supportedMediaType(contentType) {
+		return nil, false, ErrUnsupportedMediaType
+	}
+	// WebP has no decoder in the standard library, so its dimensions stay unknown
+	var width, height int
+	if config, _, err := image.DecodeConfig(bytes.NewReader(content)); err == nil {
+		width, height = config.Width, config.Height
+	}
+	// Checked before anything is stored, as the thumbnail worker would decode it all in memory
+	if s.tooManyPixels(width, height) {
+		return nil, false, ErrMediaTooManyPixels
+	}
+
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	existing, err := s.media.FindByProductAndHash(ctx, productID, hash)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, ports.ErrMediaNotFound) {
+		return nil, false, err
+	}
+
+	key := mediaBlobKey(ctx, hash, originalBlob)
+	stored, err := s.blobs.Exists(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !stored {
+		if err := s.blobs.Put(ctx, key, contentType, bytes.NewReader(content), int64(len(content))); err != nil {
+			return nil, false, err
+		}
+	}
+
+	siblings, err := s.media.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, false, err
+	}
+	media := &entities.Media{
+		ProductID:   productID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		SHA256:      hash,
+		BlobKey:     key,
+		Position:    len(siblings),
+		Width:       width,
+		Height:      height,
+	}
+	if _, err := s.media.Create(ctx, media); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.publisher.Publish(ports.RoutingKey(ctx, entities.MediaUploaded), media); err != nil {
+		log.Printf("Failed to publish upload of media %s: %v", media.ID.Hex(), err)
+	}
+	return media, true, nil
+}
+
+// ListMedia retrieves the media of a product in order. Products the caller may not see, as
+// VisibleProducts decides, are not found.
+func (s *MediaService) ListMedia(ctx context.Context, productID string) ([]*entities.Media, error) {
+	if err := s.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.media.FindByProductID(ctx, productID)
+}
+
+// GetMedia retrieves a media of a product by its ID. Products the caller may not see, as
+// VisibleProducts decides, are not found.
+func (s *MediaService) GetMedia(ctx context.Context, productID string, id string) (*entities.Media, error) {
+	if err := s.checkVisible(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.findMedia(ctx, productID, id)
+}
+
+// checkVisible fails with ports.ErrProductNotFound unless the product exists and the caller may see it
+func (s *MediaService) checkVisible(ctx context.Context, productID string) error {
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if !VisibleProducts(ctx)(product) {
+		return ports.ErrProductNotFound
+	}
+	return nil
+}
+
+// findMedia retrieves a media of a product by its ID whatever the status of the product, for writes
+func (s *MediaService) findMedia(ctx context.Context, productID string, id string) (*entities.Media, error) {
+	media, err := s.media.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if media.ProductID != productID {
+		return nil, ports.ErrMediaNotFound
+	}
+	return media, nil
+}
+
+// ReorderMedia puts the media of a product in the order of ids, which lists each of them once
+func (s *MediaService) ReorderMedia(ctx context.Context, productID string, ids []string) ([]*entities.Media, error) {
+	if _, err := s.products.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	media, err := s.media.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(media) {
+		return nil, ErrInvalidMediaOrder
+	}
+
+	byID := make(map[string]*entities.Media, len(media))
+	for _, m := range media {
+		byID[m.ID.Hex()] = m
+	}
+	ordered := make([]*entities.Media, 0, len(ids))
+	for _, id := range ids {
+		m, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidMediaOrder
+		}
+		delete(byID, id)
+		ordered = append(ordered, m)
+	}
+
+	if err := s.renumber(ctx, ordered); err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// DeleteMedia removes a media from a product and closes the gap it leaves in the order. The blob
+// and thumbnails are deleted once no media of the tenant refers to them.
+func (s *MediaService) DeleteMedia(ctx context.Context, productID string, id string) error {
+	media, err := s.findMedia(ctx, productID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.media.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	remaining, err := s.media.FindByProductID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if err := s.renumber(ctx, remaining); err != nil {
+		return err
+	}
+
+	// A concurrent upload of the same content can still lose its blob between the count and the delete
+	references, err := s.media.CountByHash(ctx, media.SHA256)
+	if err != nil {
+		return err
+	}
+	if references == 0 {
+		keys := []string{media.BlobKey}
+		for _, size := range entities.ThumbnailSizes {
+			keys = append(keys, mediaBlobKey(ctx, media.SHA256, size.Name))
+		}
+		for _, key := range keys {
+			if err := s.blobs.Delete(ctx, key); err != nil {
+				log.Printf("Failed to delete blob %s of media %s: %v", key, id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// OpenMediaContent opens the content of a media, or of its thumbnail of the given size unless
+// size is empty or original, as GetMedia finds it. The caller closes the returned reader.
+func (s *MediaService) OpenMediaContent(ctx context.Context, productID string, id string, size string) (io.ReadCloser, ports.BlobInfo, *entities.Media, error) {
+	thumbnail := size != "" && size != originalBlob
+	if thumbnail && !knownThumbnailSize(size) {
+		return nil, ports.BlobInfo{}, nil, ErrInvalidThumbnailSize
+	}
+
+	media, err := s.GetMedia(ctx, productID, id)
+	if err != nil {
+		return nil, ports.BlobInfo{}, nil, err
+	}
+	key := media.BlobKey
+	if thumbnail {
+		generated := media.Thumbnail(size)
+		if generated == nil {
+			return nil, ports.BlobInfo{}, nil, ErrThumbnailNotReady
+		}
+		key = generated.BlobKey
+	}
+
+	content, info, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		return nil, ports.BlobInfo{}, nil, err
+	}
+	return content, info, media, nil
+}
+
+// RunThumbnailWorker generates the thumbnails of uploaded images until ctx is cancelled,
+// consuming again after a failure
+func (s *MediaService) RunThumbnailWorker(ctx context.Context) {
+	for {
+		err := s.uploads.ConsumeMediaUploads(ctx, s.GenerateThumbnails)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Thumbnail worker stopped, retrying: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(thumbnailWorkerRetryDelay):
+		}
+	}
+}
+
+// GenerateThumbnails stores the thumbnails of an uploaded image and records them on its media.
+// Thumbnails already stored for the same content are reused, media deleted since the upload are
+// skipped, and images the standard library cannot decode, such as WebP, get no thumbnails.
+func (s *MediaService) GenerateThumbnails(ctx context.Context, uploaded *entities.Media) error {
+	ctx = ports.WithTenant(ctx, uploaded.TenantID)
+	media, err := s.media.FindByID(ctx, uploaded.ID.Hex())
+	if errors.Is(err, ports.ErrMediaNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(media.Thumbnails) > 0 || media.Width == 0 || media.Height == 0 {
+		return nil
+	}
+	if s.tooManyPixels(media.Width, media.Height) {
+		// Stored before the limit was lowered; decoding it could exhaust the memory of the worker
+		log.Printf("Skipped thumbnails of media %s: %dx%d exceeds %d pixels", media.ID.Hex(), media.Width, media.Height, s.maxPixels)
+		return nil
+	}
+
+	// Thumbnails of transparent or animated images stay PNG, of photos JPEG
+	contentType := "image/jpeg"
+	if media.ContentType != "image/jpeg" {
+		contentType = "image/png"
+	}
+
+	var source image.Image
+	thumbnails := make([]entities.MediaThumbnail, 0, len(entities.ThumbnailSizes))
+	for _, size := range entities.ThumbnailSizes {
+		width, height := usecases.FitWithin(media.Width, media.Height, size.Bound)
+		thumbnail := entities.MediaThumbnail{
+			Size:        size.Name,
+			Width:       width,
+			Height:      height,
+			ContentType: contentType,
+			BlobKey:     mediaBlobKey(ctx, media.SHA256, size.Name),
+		}
+		thumbnails = append(thumbnails, thumbnail)
+
+		stored, err := s.blobs.Exists(ctx, thumbnail.BlobKey)
+		if err != nil {
+			return err
+		}
+		if stored {
+			continue
+		}
+
+		if source == nil {
+			if source, err = s.decode(ctx, media); err != nil {
+				return err
+			}
+		}
+		encoded, err := encodeThumbnail(usecases.ScaleImage(source, width, height), contentType)
+		if err != nil {
+			return err
+		}
+		if err := s.blobs.Put(ctx, thumbnail.BlobKey, contentType, bytes.NewReader(encoded), int64(len(encoded))); err != nil {
+			return err
+		}
+	}
+
+	if err := s.media.SetThumbnails(ctx, media.ID.Hex(), thumbnails); err != nil {
+		return err
+	}
+	log.Printf("Generated %d thumbnails for media %s", len(thumbnails), media.ID.Hex())
+	return nil
+}
+
+// tooManyPixels reports whether an image of the given dimensions exceeds the pixel limit
+func (s *MediaService) tooManyPixels(width int, height int) bool {
+	return int64(width)*int64(height) > s.maxPixels
+}
+
+// renumber stores the position of every media in media as its index, skipping unchanged ones
+func (s *MediaService) renumber(ctx context.Context, media []*entities.Media) error {
+	for position, m := range media {
+		if m.Position == position {
+			continue
+		}
+		if err := s.media.UpdatePosition(ctx, m.ID.Hex(), position); err != nil {
+			return err
+		}
+		m.Position = position
+	}
+	return nil
+}
+
+// decode reads and decodes the original content of a media
+func (s *MediaService) decode(ctx context.Context, media *entities.Media) (image.Image, error) {
+	content, _, err := s.blobs.Get(ctx, media.BlobKey)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	source, _, err := image.Decode(content)
+	if err != nil {
+		return nil, fmt.Errorf("decode media %s: %w", media.ID.Hex(), err)
+	}
+	return source, nil
+}
+
+// encodeThumbnail encodes a thumbnail as contentType
+func encodeThumbnail(thumbnail image.Image, contentType string) ([]byte, error) {
+	var encoded bytes.Buffer
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&encoded, thumbnail, &jpeg.Options{Quality: thumbnailJPEGQuality})
+	} else {
+		err = png.Encode(&encoded, thumbnail)
+	}
+	return encoded.Bytes(), err
+}
+
+// mediaBlobKey returns the blob key of the content with the given hash in the tenant of ctx:
+// the original or one of its thumbnails
+func mediaBlobKey(ctx context.Context, hash string, name string) string {
+	return ports.TenantFromContext(ctx) + "/" + hash + "/" + name
+}
+
+// cleanFilename keeps the last element of a client supplied filename, bounded in length
+func cleanFilename(filename string) string {
+	filename = strings.TrimSpace(path.Base(strings.ReplaceAll(filename, "\\", "/")))
+	if filename == "" || filename == "." || filename == "/" {
+		return "upload"
+	}
+	if len(filename) > maxMediaFilenameLength {
+		filename = strings.ToValidUTF8(filename[:maxMediaFilenameLength], "")
+	}
+	return filename
+}
+
+// supportedMediaType reports whether a sniffed content type is accepted for media
+func supportedMediaType(contentType string) bool {
+	for _, accepted := range entities.MediaTypes {
+		if contentType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// knownThumbnailSize reports whether size names one of the generated thumbnails
+func knownThumbnailSize(size string) bool {
+	for _, known := range entities.ThumbnailSizes {
+		if known.Name == size {
+			return true
+		}
+	}
+	return false
+}
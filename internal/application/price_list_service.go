@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+var (
+	// ErrInvalidPriceList is returned when a price list has no code or name
+	ErrInvalidPriceList = errors.New("price list must have a code and a name")
+	// ErrPriceListCodeTaken is returned when another price list of the tenant has the same code
+	ErrPriceListCodeTaken = errors.New("price list code is already taken")
+	// ErrInvalidListPrice is returned for negative prices in a price list
+	ErrInvalidListPrice = errors.New("price must not be negative")
+)
+
+// PriceListService manages price lists and their explicit product prices
+type PriceListService struct {
+	lists    ports.PriceListRepository
+	products ports.ProductRepository
+}
+
+// NewPriceListService creates a new instance of PriceListService
+func NewPriceListService(lists ports.PriceListRepository, products ports.ProductRepository) *PriceListService {
+	return &PriceListService{
+		lists:    lists,
+		products: products,
+	}
+}
+
+// CreatePriceList creates a price list in currency, unique by code within the tenant
+func (s *PriceListService) CreatePriceList(ctx context.Context, list *entities.PriceList) (string, error) {
+	if err := s.validate(ctx, list); err != nil {
+		return "", err
+	}
+	list.Prices = nil
+	return s.lists.Create(ctx, list)
+}
+
+// GetPriceList retrieves a price list by its ID
+func (s *PriceListService) GetPriceList(ctx context.Context, id string) (*entities.PriceList, error) {
+	return s.lists.FindByID(ctx, id)
+}
+
+// ListPriceLists retrieves the price lists of the tenant ordered by code
+func (s *PriceListService) ListPriceLists(ctx context.Context) ([]*entities.PriceList, error) {
+	return s.lists.FindAll(ctx)
+}
+
+// UpdatePriceList changes the code, name, currency, market and customer group of a price list.
+// Its explicit prices are kept, so changing the currency assumes they are already in the new one.
+func (s *PriceListService) UpdatePriceList(ctx context.Context, list *entities.PriceList) error {
+	if _, err := s.lists.FindByID(ctx, list.ID.Hex()); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, list); err != nil {
+		return err
+	}
+	return s.lists.Update(ctx, list)
+}
+
+// DeletePriceList removes a price list with its prices
+func (s *PriceListService) DeletePriceList(ctx context.Context, id string) error {
+	return s.lists.Delete(ctx, id)
+}
+
+// SetPrice sets the explicit price of a product in a price list, in the currency of the list
+func (s *PriceListService) SetPrice(ctx context.Context, id string, productID string, price float32) error {
+	if price < 0 {
+		return ErrInvalidListPrice
+	}
+	if _, err := s.lists.FindByID(ctx, id); err != nil {
+		return err
+	}
+	if _, err := s.products.FindByID(ctx, productID); err != nil {
+		return err
+	}
+	return s.lists.SetPrice(ctx, id, productID, price)
+}
+
+// DeletePrice removes the explicit price of a product from a price list, which then converts the
+// effective price of the product instead
+func (s *PriceListService) DeletePrice(ctx context.Context, id string, productID string) error {
+	return s.lists.DeletePrice(ctx, id, productID)
+}
+
+// validate checks the fields of a price list, canonicalizing its currency, and that no other
+// price list of the tenant has its code
+func (s *PriceListService) validate(ctx context.Context, list *entities.PriceList) error {
+	if list.Code == "" || list.Name == "" {
+		return ErrInvalidPriceList
+	}
+	currency, err := usecases.ParseCurrency(list.Currency)
+	if err != nil {
+		return err
+	}
+	list.Currency = currency
+
+	existing, err := s.lists.FindByCode(ctx, list.Code)
+	if err == ports.ErrPriceListNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != list.ID {
+		return ErrPriceListCodeTaken
+	}
+	return nil
+}
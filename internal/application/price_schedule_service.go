@@ -0,0 +1,263 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// ErrInvalidPriceSchedule is returned when a schedule ends before it starts or has a negative price
+var ErrInvalidPriceSchedule = errors.New("price schedule must end after it starts and have a non-negative price")
+
+// PriceScheduleService manages scheduled price changes and publishes an event whenever they change
+// the effective price of a product
+type PriceScheduleService struct {
+	schedules ports.PriceScheduleRepository
+	products  ports.ProductRepository
+	cache     ports.ProductCache
+	publisher ports.EventPublisher
+}
+
+// NewPriceScheduleService creates a new instance of PriceScheduleService
+func NewPriceScheduleService(schedules ports.PriceScheduleRepository, products ports.ProductRepository, cache ports.ProductCache, publisher ports.EventPublisher) *PriceScheduleService {
+	return &PriceScheduleService{
+		schedules: schedules,
+		products:  products,
+		cache:     cache,
+		publisher: publisher,
+	}
+}
+
+// CreateSchedule schedules price for a product from startsAt until endsAt.
+// The repository rejects a schedule overlapping another of the same priority with ports.ErrPriceScheduleOverlap.
+func (s *PriceScheduleService) CreateSchedule(ctx context.Context, productID string, price float32, startsAt time.Time, endsAt time.Time, priority int) (*entities.PriceSchedule, error) {
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &entities.PriceSchedule{
+		ProductID: productID,
+		Price:     price,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Priority:  priority,
+	}
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	before, err := s.currentPrice(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.schedules.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.priceMayHaveChanged(ctx, product, before)
+	return schedule, nil
+}
+
+// GetSchedule retrieves a price schedule by its ID
+func (s *PriceScheduleService) GetSchedule(ctx context.Context, id string) (*entities.PriceSchedule, error) {
+	return s.schedules.FindByID(ctx, id)
+}
+
+// ListSchedules retrieves the price schedules of a product ordered by start time
+func (s *PriceScheduleService) ListSchedules(ctx context.Context, productID string) ([]*entities.PriceSchedule, error) {
+	if _, err := s.products.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.schedules.FindByProductID(ctx, productID)
+}
+
+// UpdateSchedule changes the price, period and priority of a schedule
+func (s *PriceScheduleService) UpdateSchedule(ctx context.Context, id string, price float32, startsAt time.Time, endsAt time.Time, priority int) (*entities.PriceSchedule, error) {
+	schedule, err := s.schedules.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.products.FindByID(ctx, schedule.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.currentPrice(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Price = price
+	schedule.StartsAt = startsAt
+	schedule.EndsAt = endsAt
+	schedule.Priority = priority
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.priceMayHaveChanged(ctx, product, before)
+	return schedule, nil
+}
+
+// DeleteSchedule removes a price schedule
+func (s *PriceScheduleService) DeleteSchedule(ctx context.Context, id string) error {
+	schedule, err := s.schedules.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	product, err := s.products.FindByID(ctx, schedule.ProductID)
+	if err == ports.ErrProductNotFound {
+		// Schedules of deleted products can still be cleaned up
+		return s.schedules.Delete(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	before, err := s.currentPrice(ctx, product)
+	if err != nil {
+		return err
+	}
+	if err := s.schedules.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.priceMayHaveChanged(ctx, product, before)
+	return nil
+}
+
+// PublishPriceChanges publishes a price changed event for every product with a schedule that
+// started or ended after from and up to and including to, and returns how many were published
+func (s *PriceScheduleService) PublishPriceChanges(ctx context.Context, from time.Time, to time.Time) (int, error) {
+	changing, err := s.schedules.FindChangingBetween(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	seen := make(map[string]bool)
+	for _, schedule := range changing {
+		if seen[schedule.ProductID] {
+			continue
+		}
+		seen[schedule.ProductID] = true
+
+		// The schedules span every tenant, so each product is read as its own tenant
+		ctx := ports.WithTenant(ctx, schedule.TenantID)
+		product, err := s.products.FindByID(ctx, schedule.ProductID)
+		if err == ports.ErrProductNotFound {
+			continue
+		}
+		if err != nil {
+			return published, err
+		}
+		schedules, err := s.schedules.FindByProductID(ctx, schedule.ProductID)
+		if err != nil {
+			return published, err
+		}
+
+		price, active, _ := usecases.ResolvePrice(product, schedules, to)
+		s.evict(ctx, schedule.ProductID)
+		s.publishPriceChanged(ctx, product, price, active, to)
+		published++
+	}
+
+	return published, nil
+}
+
+// RunPriceScheduler publishes the price changes of schedules starting or ending every interval
+// until ctx is cancelled
+func (s *PriceScheduleService) RunPriceScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			published, err := s.PublishPriceChanges(ctx, last, now)
+			if err != nil {
+				// Keep last so the changes are published on the next tick
+				log.Printf("Failed to publish scheduled price changes: %v", err)
+				continue
+			}
+			if published > 0 {
+				log.Printf("Published %d scheduled price changes", published)
+			}
+			last = now
+		}
+	}
+}
+
+// validateSchedule checks the period and price of a schedule. Overlaps are checked by the
+// repository as it writes, so that concurrent writes cannot both pass the check.
+func validateSchedule(schedule *entities.PriceSchedule) error {
+	if !schedule.EndsAt.After(schedule.StartsAt) || schedule.Price < 0 {
+		return ErrInvalidPriceSchedule
+	}
+	return nil
+}
+
+// currentPrice resolves the price of a product now
+func (s *PriceScheduleService) currentPrice(ctx context.Context, product *entities.Product) (float32, error) {
+	schedules, err := s.schedules.FindByProductID(ctx, product.ID.Hex())
+	if err != nil {
+		return 0, err
+	}
+	price, _, _ := usecases.ResolvePrice(product, schedules, time.Now())
+	return price, nil
+}
+
+// priceMayHaveChanged evicts the cached product, whose cache lifetime followed the old schedules,
+// and publishes a price changed event when the price now differs from before
+func (s *PriceScheduleService) priceMayHaveChanged(ctx context.Context, product *entities.Product, before float32) {
+	s.evict(ctx, product.ID.Hex())
+
+	schedules, err := s.schedules.FindByProductID(ctx, product.ID.Hex())
+	if err != nil {
+		log.Printf("Failed to resolve price of product %s: %v", product.ID.Hex(), err)
+		return
+	}
+
+	now := time.Now()
+	price, active, _ := usecases.ResolvePrice(product, schedules, now)
+	if price != before {
+		s.publishPriceChanged(ctx, product, price, active, now)
+	}
+}
+
+// evict removes a product from the cache, logging instead of failing when the cache is unavailable
+func (s *PriceScheduleService) evict(ctx context.Context, productID string) {
+	if err := s.cache.Delete(ctx, productID); err != nil {
+		log.Printf("Failed to evict product %s from cache: %v", productID, err)
+	}
+}
+
+// publishPriceChanged publishes the price of a product, set by active or by the base price when active is nil
+func (s *PriceScheduleService) publishPriceChanged(ctx context.Context, product *entities.Product, price float32, active *entities.PriceSchedule, at time.Time) {
+	event := &entities.PriceChangedEvent{
+		ProductID: product.ID.Hex(),
+		Price:     price,
+		BasePrice: product.Price,
+		At:        at.UTC(),
+	}
+	if active != nil {
+		event.ScheduleID = active.ID.Hex()
+	}
+
+	if err := s.publisher.Publish(ports.RoutingKey(ctx, entities.ProductPriceChanged), event); err != nil {
+		log.Printf("Failed to publish price changed event: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// feedRetryDelay is how long the feed waits before reopening a failed watch
+const feedRetryDelay = 2 * time.Second
+
+// FeedEvent is a product event numbered by the feed so clients can resume with Last-Event-ID
+type FeedEvent struct {
+	ID uint64 `json:"id"`
+	*entities.ProductEvent
+}
+
+// FeedFilter restricts a subscription to the events of one tenant, and optionally to some
+// products and/or event types. An empty set matches everything. Events of products Visible hides
+// are delivered without the product; a nil Visible hides nothing.
+type FeedFilter struct {
+	TenantID   string
+	ProductIDs map[string]struct{}
+	Types      map[string]struct{}
+	Visible    func(*entities.Product) bool
+}
+
+// Match reports whether the event passes the filter
+func (f FeedFilter) Match(event *entities.ProductEvent) bool {
+	if event.TenantID != f.TenantID {
+		return false
+	}
+	if len(f.ProductIDs) > 0 {
+		if _, ok := f.ProductIDs[event.ProductID]; !ok {
+			return false
+		}
+	}
+	if len(f.Types) > 0 {
+		if _, ok := f.Types[event.Type]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// redact returns event as the subscriber of the filter may see it
+func (f FeedFilter) redact(event FeedEvent) FeedEvent {
+	return FeedEvent{ID: event.ID, ProductEvent: redactEvent(event.ProductEvent, f.Visible)}
+}
+
+// Subscription receives feed events on Events until it is unsubscribed or dropped
+type Subscription struct {
+	events chan FeedEvent
+	filter FeedFilter
+}
+
+// Events returns the channel of events for this subscription.
+// The channel is closed when the subscriber falls too far behind and is dropped.
+func (s *Subscription) Events() <-chan FeedEvent {
+	return s.events
+}
+
+// ProductFeed fans product change notifications out to many in-process subscribers.
+// It keeps a bounded history so reconnecting clients can replay what they missed.
+type ProductFeed struct {
+	service     *ProductService
+	historySize int
+	bufferSize  int
+
+	mu          sync.Mutex
+	history     []FeedEvent
+	lastID      uint64
+	subscribers map[*Subscription]struct{}
+}
+
+// NewProductFeed creates a new instance of ProductFeed
+func NewProductFeed(service *ProductService, historySize int, bufferSize int) *ProductFeed {
+	return &ProductFeed{
+		service:     service,
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		history:     make([]FeedEvent, 0, historySize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Run watches product changes and publishes them to subscribers until ctx is cancelled.
+// A failed watch is reopened from the last resume token seen.
+func (f *ProductFeed) Run(ctx context.Context) {
+	var resumeToken string
+	for {
+		err := f.service.WatchAllTenants(ctx, resumeToken, func(event *entities.ProductEvent) error {
+			resumeToken = event.ResumeToken
+			f.publish(event)
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ports.ErrInvalidResumeToken) {
+			resumeToken = ""
+		}
+		log.Printf("Product feed watch stopped, retrying: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(feedRetryDelay):
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. Events in the history newer than lastEventID
+// that match the filter are queued on the subscription before any live event.
+func (f *ProductFeed) Subscribe(filter FeedFilter, lastEventID uint64) *Subscription {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var replay []FeedEvent
+	if lastEventID > 0 {
+		for _, event := range f.history {
+			if event.ID > lastEventID && filter.Match(event.ProductEvent) {
+				replay = append(replay, filter.redact(event))
+			}
+		}
+	}
+
+	sub := &Subscription{
+		events: make(chan FeedEvent, f.bufferSize+len(replay)),
+		filter: filter,
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+	f.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+// Unsubscribe removes a subscriber from the feed
+func (f *ProductFeed) Unsubscribe(sub *Subscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subscribers[sub]; ok {
+		delete(f.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publish numbers the event, appends it to the history and hands it to every matching subscriber.
+// A subscriber whose buffer is full is dropped rather than blocking the others.
+func (f *ProductFeed) publish(event *entities.ProductEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastID++
+	feedEvent := FeedEvent{ID: f.lastID, ProductEvent: event}
+
+	if f.historySize > 0 {
+		if len(f.history) == f.historySize {
+			copy(f.history, f.history[1:])
+			f.history = f.history[:len(f.history)-1]
+		}
+		f.history = append(f.history, feedEvent)
+	}
+
+	for sub := range f.subscribers {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- sub.filter.redact(feedEvent):
+		default:
+			log.Printf("Dropping slow product feed subscriber at event %d", feedEvent.ID)
+			delete(f.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// ProductStatusAny asks product reads for products in every lifecycle status
+const ProductStatusAny = "any"
+
+// lifecycleSchedulerInterval is how often the activation and deactivation dates that came are applied
+const lifecycleSchedulerInterval = 30 * time.Second
+
+// ErrAdminScopeRequired is returned when a read asks for products that are not active without an
+// API key with the admin scope
+var ErrAdminScopeRequired = errors.New("only API keys with the admin scope may read products that are not active")
+
+// TransitionProduct moves a product to status on behalf of the principal of ctx. The change is recorded
+// like any update, and a status changed event is published when the status moves; an approval
+// before the activation date of the product is recorded without moving it.
+func (s *ProductService) TransitionProduct(ctx context.Context, id string, status string) (*entities.Product, error) {
+	var product *entities.Product
+	err := s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+		useCase := s.productUseCase(tx)
+
+		var err error
+		product, err = useCase.GetProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		from := product.LifecycleStatus()
+		changed, err := useCase.TransitionProduct(ctx, product, status)
+		if err != nil {
+			return err
+		}
+		return s.publishTransition(ctx, tx, product, from, changed, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheProduct(ctx, product)
+
+	return product, nil
+}
+
+// ScheduleProduct sets the dates the scheduler activates an approved product and discontinues an
+// active one at; a nil date clears it
+func (s *ProductService) ScheduleProduct(ctx context.Context, id string, activateAt *time.Time, deactivateAt *time.Time) error {
+	return s.updateProduct(ctx, id, func(product *entities.Product) error {
+		return usecases.ScheduleProduct(product, activateAt, deactivateAt)
+	})
+}
+
+// ApplyScheduledTransitions activates and discontinues the products of every tenant whose dates have
+// come by now, and returns how many moved
+func (s *ProductService) ApplyScheduledTransitions(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.useCase.FindDueTransitions(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, candidate := range due {
+		// The products span every tenant, so each one is moved as its own tenant
+		ctx := ports.WithTenant(ctx, candidate.TenantID)
+
+		var product *entities.Product
+		var from string
+		err := s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+			useCase := s.productUseCase(tx)
+
+			var err error
+			product, err = useCase.GetProductByID(ctx, candidate.ID.Hex())
+			if err != nil {
+				return err
+			}
+
+			// The product may have moved since it was found, in which case nothing is due any more
+			from, err = useCase.ApplyProductSchedule(ctx, product, now)
+			if err != nil || from == "" {
+				return err
+			}
+			return s.publishTransition(ctx, tx, product, from, true, true)
+		})
+		if errors.Is(err, ports.ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			return applied, err
+		}
+		if from == "" {
+			continue
+		}
+
+		s.cacheProduct(ctx, product)
+		applied++
+	}
+
+	return applied, nil
+}
+
+// RunLifecycleScheduler applies the activation and deactivation dates that came until ctx is cancelled
+func (s *ProductService) RunLifecycleScheduler(ctx context.Context) {
+	ticker := time.NewTicker(lifecycleSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			applied, err := s.ApplyScheduledTransitions(ctx, now)
+			if err != nil {
+				// The remaining products are still due on the next tick
+				log.Printf("Failed to apply scheduled status changes: %v", err)
+			}
+			if applied > 0 {
+				log.Printf("Applied %d scheduled status changes", applied)
+			}
+		}
+	}
+}
+
+// publishTransition publishes the update of a product that went through a transition, followed by
+// a status changed event when its status moved from from
+func (s *ProductService) publishTransition(ctx context.Context, tx ports.ProductTx, product *entities.Product, from string, changed bool, scheduled bool) error {
+	if err := tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductUpdated), product); err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	event := &entities.ProductStatusChangedEvent{
+		ProductID: product.ID.Hex(),
+		From:      from,
+		To:        product.Status,
+		Actor:     ports.RequestInfoFromContext(ctx).Actor,
+		Scheduled: scheduled,
+		At:        time.Now().UTC(),
+	}
+	if product.Status == entities.ProductStatusActive {
+		event.ApprovedBy = product.ApprovedBy
+	}
+	return tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductStatusChanged), event)
+}
+
+// statusFilter returns which products a read asking for status shows: active products for "", every
+// product for ProductStatusAny and otherwise the products in that status. Reading products that are
+// not active needs an API key with the admin scope.
+func statusFilter(ctx context.Context, status string) (func(*entities.Product) bool, error) {
+	if status != ProductStatusAny {
+		if status == "" {
+			status = entities.ProductStatusActive
+		}
+		var err error
+		if status, err = usecases.ParseProductStatus(status); err != nil {
+			return nil, err
+		}
+	}
+	if status != entities.ProductStatusActive {
+		if key := ports.APIKeyFromContext(ctx); key == nil || !key.HasScope(entities.ScopeAdmin) {
+			return nil, ErrAdminScopeRequired
+		}
+	}
+
+	return func(product *entities.Product) bool {
+		return status == ProductStatusAny || product.LifecycleStatus() == status
+	}, nil
+}
+
+// VisibleProducts returns which products and product states a caller may see without asking for a
+// status: every one to API keys with the admin scope, and only active ones to other callers. Change
+// history, events and the reads of related resources all show products this way.
+func VisibleProducts(ctx context.Context) func(*entities.Product) bool {
+	status := entities.ProductStatusActive
+	if key := ports.APIKeyFromContext(ctx); key != nil && key.HasScope(entities.ScopeAdmin) {
+		status = ProductStatusAny
+	}
+	// Neither status needs checking
+	visible, _ := statusFilter(ctx, status)
+	return visible
+}
+
+// redactEvent returns event without its product body when visible hides the product, so callers
+// that may not read the product still learn that it changed
+func redactEvent(event *entities.ProductEvent, visible func(*entities.Product) bool) *entities.ProductEvent {
+	if event.Product == nil || visible == nil || visible(event.Product) {
+		return event
+	}
+	redacted := *event
+	redacted.Product = nil
+	return &redacted
+}
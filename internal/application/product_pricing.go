@@ -0,0 +1,81 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// ErrPriceListCurrencyMismatch is returned when a read asks for a price list in another currency than the list's
+var ErrPriceListCurrencyMismatch = errors.New("currency does not match the currency of the price list")
+
+// PriceQuery asks product reads for prices in a price list, named by its code, or in a currency.
+// The zero value reads the effective prices in the base currency without pricing details.
+type PriceQuery struct {
+	PriceList string
+	Currency  string
+}
+
+// presentProduct localizes a product read and prices it as asked for by query
+func (s *ProductService) presentProduct(ctx context.Context, product *entities.Product, query PriceQuery) (*entities.Product, error) {
+	s.localize(ctx, product)
+	if err := s.priceProducts(ctx, []*entities.Product{product}, query); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// priceProducts sets the pricing of products as asked for by query. Products without an explicit
+// price in the list are converted from the base currency with the rate in effect now, which is
+// only looked up when a product needs it.
+func (s *ProductService) priceProducts(ctx context.Context, products []*entities.Product, query PriceQuery) error {
+	if query == (PriceQuery{}) {
+		return nil
+	}
+
+	var list *entities.PriceList
+	code := s.currency
+	if query.Currency != "" {
+		var err error
+		if code, err = usecases.ParseCurrency(query.Currency); err != nil {
+			return err
+		}
+	}
+	if query.PriceList != "" {
+		var err error
+		if list, err = s.priceLists.FindByCode(ctx, query.PriceList); err != nil {
+			return err
+		}
+		if query.Currency != "" && code != list.Currency {
+			return ErrPriceListCurrencyMismatch
+		}
+		code = list.Currency
+	}
+
+	var rate *entities.ExchangeRate
+	for _, product := range products {
+		if rate == nil && usecases.NeedsConversion(product, list, code, s.currency) {
+			var err error
+			if rate, err = s.exchangeRate(ctx, code); err != nil {
+				return err
+			}
+		}
+		product.Pricing = usecases.PriceProduct(product, list, code, s.currency, rate)
+	}
+	return nil
+}
+
+// exchangeRate finds the rate in effect now from the base currency to code, or else the rate from
+// code to the base currency, which pricing inverts
+func (s *ProductService) exchangeRate(ctx context.Context, code string) (*entities.ExchangeRate, error) {
+	now := time.Now()
+	rate, err := s.rates.FindRate(ctx, s.currency, code, now)
+	if err != ports.ErrExchangeRateNotFound {
+		return rate, err
+	}
+	return s.rates.FindRate(ctx, code, s.currency, now)
+}
@@ -0,0 +1,270 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// ErrRevisionNotRestorable is returned when rolling back to a revision that deleted the product
+var ErrRevisionNotRestorable = errors.New("revision deleted the product and cannot be restored")
+
+// revisionRecorder implements the ports.ProductRepository interface by appending a revision for every
+// write to the wrapped repository. Reads are passed through.
+type revisionRecorder struct {
+	ports.ProductRepository
+	revisions ports.ProductRevisionRepository
+}
+
+// NewRevisionRecordingRepository wraps repo so every create, update and delete is appended to revisions,
+// together with the actor and request ID carried by the context
+func NewRevisionRecordingRepository(repo ports.ProductRepository, revisions ports.ProductRevisionRepository) ports.ProductRepository {
+	return &revisionRecorder{
+		ProductRepository: repo,
+		revisions:         revisions,
+	}
+}
+
+// Create stores the product and records its first revision
+func (r *revisionRecorder) Create(ctx context.Context, product *entities.Product) (string, error) {
+	id, err := r.ProductRepository.Create(ctx, product)
+	if err != nil {
+		return "", err
+	}
+
+	return id, r.record(ctx, entities.RevisionCreate, nil, product)
+}
+
+// Update stores the product and records the changes since its previous state
+func (r *revisionRecorder) Update(ctx context.Context, product *entities.Product) error {
+	before, err := r.ProductRepository.FindByID(ctx, product.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if err := r.ProductRepository.Update(ctx, product); err != nil {
+		return err
+	}
+
+	// The repository keeps the stored creation time whatever the caller passed
+	after := *product
+	after.CreatedAt = before.CreatedAt
+
+	operation := entities.RevisionUpdate
+	if _, ok := ctx.Value(rollbackKey{}).(int64); ok {
+		operation = entities.RevisionRollback
+	}
+	return r.record(ctx, operation, before, &after)
+}
+
+// Delete removes the product and records its last state
+func (r *revisionRecorder) Delete(ctx context.Context, id string) error {
+	before, err := r.ProductRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.ProductRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return r.record(ctx, entities.RevisionDelete, before, nil)
+}
+
+// record appends a revision; the snapshot is after, or before for deletes
+func (r *revisionRecorder) record(ctx context.Context, operation string, before *entities.Product, after *entities.Product) error {
+	snapshot := after
+	if snapshot == nil {
+		snapshot = before
+	}
+
+	info := ports.RequestInfoFromContext(ctx)
+	revision := &entities.ProductRevision{
+		ProductID: snapshot.ID.Hex(),
+		Operation: operation,
+		Snapshot:  snapshot,
+		Changes:   usecases.DiffProducts(before, after),
+		Actor:     info.Actor,
+		RequestID: info.RequestID,
+		CreatedAt: time.Now(),
+	}
+	if restored, ok := ctx.Value(rollbackKey{}).(int64); ok {
+		revision.RestoredRevision = restored
+	}
+
+	if err := r.revisions.Append(ctx, revision); err != nil {
+		return fmt.Errorf("record revision of product %s: %w", revision.ProductID, err)
+	}
+	return nil
+}
+
+// rollbackKey marks the context of an update that restores the given revision number
+type rollbackKey struct{}
+
+// ListRevisions retrieves the change history of a product, oldest first, leaving out the revisions
+// whose product state the caller may not read, as VisibleProducts decides
+func (s *ProductService) ListRevisions(ctx context.Context, id string) ([]*entities.ProductRevision, error) {
+	all, err := s.revisions.FindByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Products written before revisions were recorded have no history yet
+	if len(all) == 0 {
+		if _, err := s.useCase.GetProductByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	visible := VisibleProducts(ctx)
+	revisions := all[:0]
+	for _, revision := range all {
+		if revisionVisible(revision, visible) {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions, nil
+}
+
+// GetRevision retrieves one revision of a product. It returns ports.ErrRevisionNotFound for
+// revisions whose product state the caller may not read.
+func (s *ProductService) GetRevision(ctx context.Context, id string, revision int64) (*entities.ProductRevision, error) {
+	return s.findRevision(ctx, id, revision, VisibleProducts(ctx))
+}
+
+// GetProductAsOf retrieves a product as it was at the given time, if it was in the lifecycle status
+// asked for then, as statusFilter reads it. It returns ports.ErrProductNotFound when the product did
+// not exist or was in another status then.
+func (s *ProductService) GetProductAsOf(ctx context.Context, id string, at time.Time, status string) (*entities.Product, error) {
+	visible, err := statusFilter(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.revisions.FindAsOf(ctx, id, at)
+	if err == ports.ErrRevisionNotFound {
+		return nil, ports.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.localizedState(ctx, revision, visible)
+}
+
+// GetProductAtRevision retrieves a product as it was right after the given revision, if it was in
+// the lifecycle status asked for then, as statusFilter reads it. It returns ports.ErrProductNotFound
+// when that revision deleted it or left it in another status.
+func (s *ProductService) GetProductAtRevision(ctx context.Context, id string, revision int64, status string) (*entities.Product, error) {
+	visible, err := statusFilter(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.revisions.FindByRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	return s.localizedState(ctx, found, visible)
+}
+
+// DiffRevisions lists the fields that differ between the product states after two revisions. Both
+// revisions must be readable by the caller, as VisibleProducts decides.
+func (s *ProductService) DiffRevisions(ctx context.Context, id string, from int64, to int64) ([]entities.FieldChange, error) {
+	visible := VisibleProducts(ctx)
+	fromRevision, err := s.findRevision(ctx, id, from, visible)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.findRevision(ctx, id, to, visible)
+	if err != nil {
+		return nil, err
+	}
+
+	return usecases.DiffProducts(fromRevision.State(), toRevision.State()), nil
+}
+
+// RollbackProduct restores the fields of a product from one of its revisions.
+// The rollback is validated like any update and recorded as a new revision.
+func (s *ProductService) RollbackProduct(ctx context.Context, id string, revision int64) (*entities.Product, error) {
+	target, err := s.revisions.FindByRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	state := target.State()
+	if state == nil {
+		return nil, ErrRevisionNotRestorable
+	}
+
+	var product *entities.Product
+	err = s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+		useCase := s.productUseCase(tx)
+
+		var err error
+		product, err = useCase.GetProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		product.Name = state.Name
+		product.Description = state.Description
+		product.Translations = state.Translations
+		product.Price = state.Price
+		product.TaxClass = state.TaxClass
+		product.Bundle = state.Bundle
+		product.CategoryIDs = state.CategoryIDs
+		product.Options = state.Options
+		product.TypeID = state.TypeID
+		product.Attributes = state.Attributes
+
+		if err := useCase.UpdateProduct(context.WithValue(ctx, rollbackKey{}, revision), product); err != nil {
+			return err
+		}
+		return tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductUpdated), product)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheProduct(ctx, product)
+
+	return product, nil
+}
+
+// productUseCase builds the product use case on the repositories of a unit of work, recording
+// revisions and audit entries
+func (s *ProductService) productUseCase(tx ports.ProductTx) *usecases.ProductUseCase {
+	return usecases.NewProductUseCase(NewAuditRecordingRepository(NewRevisionRecordingRepository(tx.Products, tx.Revisions), tx.Audit), s.types, s.currency)
+}
+
+// findRevision retrieves one revision of a product, or ports.ErrRevisionNotFound when visible hides it
+func (s *ProductService) findRevision(ctx context.Context, id string, revision int64, visible func(*entities.Product) bool) (*entities.ProductRevision, error) {
+	found, err := s.revisions.FindByRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	if !revisionVisible(found, visible) {
+		return nil, ports.ErrRevisionNotFound
+	}
+	return found, nil
+}
+
+// revisionVisible reports whether visible shows the product state after a revision. Deletes leave
+// no state to hide.
+func revisionVisible(revision *entities.ProductRevision, visible func(*entities.Product) bool) bool {
+	state := revision.State()
+	return state == nil || visible(state)
+}
+
+// localizedState returns the product state after a revision in the locale preferred by ctx,
+// or ports.ErrProductNotFound for deletes and states visible hides
+func (s *ProductService) localizedState(ctx context.Context, revision *entities.ProductRevision, visible func(*entities.Product) bool) (*entities.Product, error) {
+	state := revision.State()
+	if state == nil || !visible(state) {
+		return nil, ports.ErrProductNotFound
+	}
+	s.localize(ctx, state)
+	return state, nil
+}
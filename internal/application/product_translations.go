@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+var (
+	// ErrTranslationNotFound is returned when a product has no translation in the locale
+	ErrTranslationNotFound = errors.New("translation not found")
+	// ErrDefaultLocaleTranslation is returned when deleting the text of the default locale, which is
+	// the name and description of the product itself
+	ErrDefaultLocaleTranslation = errors.New("the default locale cannot be deleted")
+)
+
+// Localization names the locale product text is stored in and the locales it is translated to
+type Localization struct {
+	DefaultLocale string   // Locale of the name and description of products
+	Locales       []string // Locales the missing translations report checks unless told otherwise
+}
+
+// GetProductTranslations returns the text of a product in every locale it has, the default locale included
+func (s *ProductService) GetProductTranslations(ctx context.Context, id string) (map[string]entities.ProductTranslation, error) {
+	product, err := s.useCase.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make(map[string]entities.ProductTranslation, len(product.Translations)+1)
+	for locale, text := range product.Translations {
+		translations[locale] = text
+	}
+	translations[s.locales.DefaultLocale] = entities.ProductTranslation{Name: product.Name, Description: product.Description}
+	return translations, nil
+}
+
+// SetProductTranslation sets the text of a product in a locale, replacing the name and description
+// of the product itself for the default locale. The change is validated and recorded like any update.
+func (s *ProductService) SetProductTranslation(ctx context.Context, id string, locale string, text entities.ProductTranslation) error {
+	locale, err := usecases.ParseLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	return s.updateProduct(ctx, id, func(product *entities.Product) error {
+		if locale == s.locales.DefaultLocale {
+			product.Name = text.Name
+			product.Description = text.Description
+			return nil
+		}
+		if product.Translations == nil {
+			product.Translations = make(map[string]entities.ProductTranslation)
+		}
+		product.Translations[locale] = text
+		return nil
+	})
+}
+
+// DeleteProductTranslation removes the text of a product in a locale, which then falls back to the next
+// locale of its fallback chain
+func (s *ProductService) DeleteProductTranslation(ctx context.Context, id string, locale string) error {
+	locale, err := usecases.ParseLocale(locale)
+	if err != nil {
+		return err
+	}
+	if locale == s.locales.DefaultLocale {
+		return ErrDefaultLocaleTranslation
+	}
+
+	return s.updateProduct(ctx, id, func(product *entities.Product) error {
+		if _, ok := product.Translations[locale]; !ok {
+			return ErrTranslationNotFound
+		}
+		delete(product.Translations, locale)
+		return nil
+	})
+}
+
+// MissingTranslations reports the products lacking a name or description in any of locales, or in
+// the configured locales when none are given
+func (s *ProductService) MissingTranslations(ctx context.Context, locales []string) ([]entities.MissingTranslation, error) {
+	if len(locales) == 0 {
+		locales = s.locales.Locales
+	}
+	canonical := make([]string, len(locales))
+	for i, locale := range locales {
+		var err error
+		if canonical[i], err = usecases.ParseLocale(locale); err != nil {
+			return nil, err
+		}
+	}
+
+	products, err := s.useCase.GetAllProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []entities.MissingTranslation{}
+	for _, product := range products {
+		missing = append(missing, usecases.MissingTranslations(product, canonical, s.locales.DefaultLocale)...)
+	}
+	return missing, nil
+}
+
+// updateProduct applies change to a product and saves it together with its revision and event
+func (s *ProductService) updateProduct(ctx context.Context, id string, change func(*entities.Product) error) error {
+	var product *entities.Product
+	err := s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+		useCase := s.productUseCase(tx)
+
+		var err error
+		product, err = useCase.GetProductByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := change(product); err != nil {
+			return err
+		}
+
+		if err := useCase.UpdateProduct(ctx, product); err != nil {
+			return err
+		}
+		return tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductUpdated), product)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cacheProduct(ctx, product)
+
+	return nil
+}
+
+// localize picks the name and description of a product in the locale preferred by ctx
+func (s *ProductService) localize(ctx context.Context, product *entities.Product) {
+	usecases.LocalizeProduct(product, ports.LocalesFromContext(ctx), s.locales.DefaultLocale)
+}
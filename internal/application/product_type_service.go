@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// ErrInvalidProductTypeName is returned when a product type name is blank
+var ErrInvalidProductTypeName = errors.New("product type name is required")
+
+// ErrProductTypeInUse is returned when deleting a product type that products still belong to
+var ErrProductTypeInUse = errors.New("product type still has products")
+
+// ProductTypeService manages product types and their attribute schemas
+type ProductTypeService struct {
+	repo     ports.ProductTypeRepository
+	products ports.ProductRepository
+}
+
+// NewProductTypeService creates a new instance of ProductTypeService
+func NewProductTypeService(repo ports.ProductTypeRepository, products ports.ProductRepository) *ProductTypeService {
+	return &ProductTypeService{
+		repo:     repo,
+		products: products,
+	}
+}
+
+// CreateProductType adds a product type after checking its attribute definitions
+func (s *ProductTypeService) CreateProductType(ctx context.Context, name string, attributes []entities.AttributeDefinition) (*entities.ProductType, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidProductTypeName
+	}
+	if err := usecases.ValidateAttributeSchema(attributes); err != nil {
+		return nil, err
+	}
+
+	productType := &entities.ProductType{Name: name, Attributes: attributes}
+	if _, err := s.repo.Create(ctx, productType); err != nil {
+		return nil, err
+	}
+
+	return productType, nil
+}
+
+// GetProductType retrieves a product type by its ID
+func (s *ProductTypeService) GetProductType(ctx context.Context, id string) (*entities.ProductType, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListProductTypes retrieves every product type
+func (s *ProductTypeService) ListProductTypes(ctx context.Context) ([]*entities.ProductType, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// UpdateProductType replaces the name and attribute definitions of a product type.
+// Existing products are checked against the new schema the next time they are saved.
+func (s *ProductTypeService) UpdateProductType(ctx context.Context, id string, name string, attributes []entities.AttributeDefinition) (*entities.ProductType, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidProductTypeName
+	}
+	if err := usecases.ValidateAttributeSchema(attributes); err != nil {
+		return nil, err
+	}
+
+	productType, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	productType.Name = name
+	productType.Attributes = attributes
+	if err := s.repo.Update(ctx, productType); err != nil {
+		return nil, err
+	}
+
+	return productType, nil
+}
+
+// DeleteProductType removes a product type that no product belongs to
+func (s *ProductTypeService) DeleteProductType(ctx context.Context, id string) error {
+	products, err := s.products.FindByFilter(ctx, ports.ProductFilter{TypeID: id})
+	if err != nil {
+		return err
+	}
+	if len(products) > 0 {
+		return ErrProductTypeInUse
+	}
+
+	return s.repo.Delete(ctx, id)
+}
@@ -2,146 +2,366 @@ package application
 
 import (
 	"context"
-	"encoding/json"
 	"log"
+	"time"
 
-	queue "test-go/internal/adapters/secondary/messaging"
 	"test-go/internal/core/entities"
 	"test-go/internal/core/ports"
-
-	"github.com/go-redis/redis/v8"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
+	"test-go/internal/core/usecases"
 )
 
+// AttributeQuery is an attribute condition as received from a transport, before its value
+// is converted to the type of the attribute
+type AttributeQuery struct {
+	Name  string
+	Op    string
+	Value string
+}
+
+// ProductService coordinates the product use case with caching, change notifications and events.
+// Writes, their revisions and their events run in one unit of work, so transactional stores commit them together.
 type ProductService struct {
-	repo        ports.ProductRepository
-	mongoDB     *mongo.Database
-	redisClient *redis.Client
-	queue       *queue.RabbitMQ
+	useCase    *usecases.ProductUseCase
+	types      ports.ProductTypeRepository
+	revisions  ports.ProductRevisionRepository
+	unitOfWork ports.ProductUnitOfWork
+	prices     ports.PriceScheduleRepository
+	watcher    ports.ProductWatcher
+	cache      ports.ProductCache
+	quotas     TenantQuotas
+	locales    Localization
+	priceLists ports.PriceListRepository
+	rates      ports.ExchangeRateRepository
+	currency   string // ISO 4217 code of product prices, which other currencies are converted from
+}
+
+// ProductServiceDeps groups the ports and settings a ProductService is built from
+type ProductServiceDeps struct {
+	Products     ports.ProductRepository
+	Types        ports.ProductTypeRepository
+	Revisions    ports.ProductRevisionRepository
+	UnitOfWork   ports.ProductUnitOfWork
+	Prices       ports.PriceScheduleRepository
+	Watcher      ports.ProductWatcher
+	Cache        ports.ProductCache
+	Quotas       TenantQuotas
+	Locales      Localization
+	PriceLists   ports.PriceListRepository
+	Rates        ports.ExchangeRateRepository
+	BaseCurrency string // ISO 4217 code of product prices, which other currencies are converted from
 }
 
 // NewProductService creates a new instance of ProductService
-func NewProductService(mongoDB *mongo.Database, redisClient *redis.Client, queue *queue.RabbitMQ) *ProductService {
+func NewProductService(deps ProductServiceDeps) *ProductService {
 	return &ProductService{
-		mongoDB:     mongoDB,
-		redisClient: redisClient,
-		queue:       queue,
+		useCase:    usecases.NewProductUseCase(deps.Products, deps.Types, deps.BaseCurrency),
+		types:      deps.Types,
+		revisions:  deps.Revisions,
+		unitOfWork: deps.UnitOfWork,
+		prices:     deps.Prices,
+		watcher:    deps.Watcher,
+		cache:      deps.Cache,
+		quotas:     deps.Quotas,
+		locales:    deps.Locales,
+		priceLists: deps.PriceLists,
+		rates:      deps.Rates,
+		currency:   deps.BaseCurrency,
 	}
 }
 
 // CreateProduct handles the creation of a new product
-func (s *ProductService) CreateProduct(ctx context.Context, name string, price float32) (string, error) {
+func (s *ProductService) CreateProduct(ctx context.Context, name string, description string, price float32, typeID string, attributes map[string]interface{}) (string, error) {
 	product := &entities.Product{
-		Name:  name,
-		Price: price,
+		Name:        name,
+		Description: description,
+		Price:       price,
+		TypeID:      typeID,
+		Attributes:  attributes,
 	}
 
-	// Save the product to MongoDB
-	id, err := s.repo.Create(ctx, product)
+	// Validate and save the product together with its revision and event
+	var id string
+	err := s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+		if err := s.checkProductQuota(ctx, tx.Products); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = s.productUseCase(tx).CreateProduct(ctx, product)
+		if err != nil {
+			return err
+		}
+		return tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductCreated), product)
+	})
 	if err != nil {
 		return "", err
 	}
 
-	// Set product ID
-	objID, err := primitive.ObjectIDFromHex(id)
+	s.cacheProduct(ctx, product)
+
+	return id, nil
+}
+
+// GetProductByID retrieves a product by its ID with its effective price, priced as asked for by query
+// and in the locale preferred by ctx. Products not in the lifecycle status asked for, as statusFilter
+// reads it, are not found.
+func (s *ProductService) GetProductByID(ctx context.Context, id string, status string, query PriceQuery) (*entities.Product, error) {
+	visible, err := statusFilter(ctx, status)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	product.ID = objID
 
-	// Convert the product to JSON and save to Redis
-	productJSON, _ := json.Marshal(product)
-	s.redisClient.Set(ctx, "product:"+objID.Hex(), productJSON, 0)
+	// Try the cache first, falling back to the repository when it misses or is unavailable.
+	// Products are cached with every translation, and localized and priced on the way out.
+	product, err := s.cache.Get(ctx, id)
+	if err == nil {
+		if !visible(product) {
+			return nil, ports.ErrProductNotFound
+		}
+		return s.presentProduct(ctx, product, query)
+	}
+	if err != ports.ErrCacheMiss {
+		log.Printf("Failed to read product %s from cache: %v", id, err)
+	}
 
-	// Publish event to the queue
-	err = s.queue.PublishProductCreated(product)
+	product, err = s.useCase.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := s.resolvePrice(ctx, product)
 	if err != nil {
-		log.Printf("Failed to publish product created event: %v", err)
+		return nil, err
+	}
+
+	// Save the product in the cache for future requests, until its price next changes
+	if err := s.cache.Set(ctx, product, ttl); err != nil {
+		log.Printf("Failed to cache product %s: %v", product.ID.Hex(), err)
 	}
 
-	return objID.Hex(), nil
+	if !visible(product) {
+		return nil, ports.ErrProductNotFound
+	}
+	return s.presentProduct(ctx, product, query)
 }
 
-// GetProductByID retrieves a product by its ID
-func (s *ProductService) GetProductByID(ctx context.Context, id string) (*entities.Product, error) {
-	// Try to get product from Redis
-	val, err := s.redisClient.Get(ctx, "product:"+id).Result()
-	if err == redis.Nil {
-		// If not found in Redis, fetch from MongoDB
-		product, err := s.repo.FindByID(ctx, id)
+// UpdateProduct handles updating an existing product
+func (s *ProductService) UpdateProduct(ctx context.Context, id string, name string, description string, price float32, typeID string, attributes map[string]interface{}) error {
+	// Retrieve, validate and save the changes together with their revision and event
+	var product *entities.Product
+	err := s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+		useCase := s.productUseCase(tx)
+
+		var err error
+		product, err = useCase.GetProductByID(ctx, id)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		// Save the product in Redis for future requests
-		productJSON, _ := json.Marshal(product)
-		s.redisClient.Set(ctx, "product:"+id, productJSON, 0)
+		product.Name = name
+		product.Description = description
+		product.Price = price
+		product.TypeID = typeID
+		product.Attributes = attributes
 
-		return product, nil
-	} else if err != nil {
-		return nil, err
+		if err := useCase.UpdateProduct(ctx, product); err != nil {
+			return err
+		}
+		return tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductUpdated), product)
+	})
+	if err != nil {
+		return err
 	}
 
-	// If found in Redis, unmarshal the JSON
-	product := &entities.Product{}
-	if err := json.Unmarshal([]byte(val), product); err != nil {
-		return nil, err
-	}
+	s.cacheProduct(ctx, product)
 
-	return product, nil
+	return nil
 }
 
-// UpdateProduct handles updating an existing product
-func (s *ProductService) UpdateProduct(ctx context.Context, id string, name string, price float32) error {
-	// Retrieve and update the product
-	product, err := s.repo.FindByID(ctx, id)
+// DeleteProduct handles deleting a product by its ID
+func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
+	err := s.unitOfWork.Do(ctx, func(tx ports.ProductTx) error {
+		if err := s.productUseCase(tx).DeleteProduct(ctx, id); err != nil {
+			return err
+		}
+		// Deleted events only carry the product ID
+		return tx.Events.Publish(ports.RoutingKey(ctx, entities.ProductDeleted), id)
+	})
 	if err != nil {
 		return err
 	}
 
-	product.Name = name
-	product.Price = price
+	if err := s.cache.Delete(ctx, id); err != nil {
+		log.Printf("Failed to evict product %s from cache: %v", id, err)
+	}
 
-	// Update in MongoDB
-	if err := s.repo.Update(ctx, product); err != nil {
-		return err
+	return nil
+}
+
+// ListProducts retrieves all products, or only the products of typeID whose attributes match every query,
+// with their effective prices, priced as asked for by query and in the locale preferred by ctx. Only the
+// products in the lifecycle status asked for are listed, as statusFilter reads it. A non-empty search keeps
+// the products whose localized name or description contains it, and sortBy orders them as
+// usecases.SortProducts does, both following the rules of the preferred locale.
+func (s *ProductService) ListProducts(ctx context.Context, typeID string, queries []AttributeQuery, search string, sortBy string, status string, query PriceQuery) ([]*entities.Product, error) {
+	visible, err := statusFilter(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.listProducts(ctx, typeID, queries)
+	if err != nil {
+		return nil, err
+	}
+	products := all[:0]
+	for _, product := range all {
+		if visible(product) {
+			products = append(products, product)
+		}
 	}
 
-	// Convert the updated product to JSON and save to Redis
-	productJSON, _ := json.Marshal(product)
-	s.redisClient.Set(ctx, "product:"+id, productJSON, 0)
+	// Lists are not cached
+	now := time.Now()
+	active, err := s.prices.FindActiveAt(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	byProduct := make(map[string][]*entities.PriceSchedule)
+	for _, schedule := range active {
+		byProduct[schedule.ProductID] = append(byProduct[schedule.ProductID], schedule)
+	}
+	for _, product := range products {
+		price, _, _ := usecases.ResolvePrice(product, byProduct[product.ID.Hex()], now)
+		product.EffectivePrice = &price
+		s.localize(ctx, product)
+	}
+	if err := s.priceProducts(ctx, products, query); err != nil {
+		return nil, err
+	}
+
+	locale := usecases.ResponseLocale(ports.LocalesFromContext(ctx), s.locales.DefaultLocale)
+	if search != "" {
+		products = usecases.SearchProducts(products, search, locale)
+	}
+	if sortBy != "" {
+		if err := usecases.SortProducts(products, sortBy, locale); err != nil {
+			return nil, err
+		}
+	}
 
-	// Publish event to the queue
-	err = s.queue.PublishProductUpdated(product)
+	return products, nil
+}
+
+// listProducts retrieves the products matching typeID and queries at their base prices
+func (s *ProductService) listProducts(ctx context.Context, typeID string, queries []AttributeQuery) ([]*entities.Product, error) {
+	if typeID == "" {
+		if len(queries) > 0 {
+			return nil, &usecases.AttributeError{Violations: []usecases.AttributeViolation{{Attribute: "type_id", Reason: "is required to filter by attributes"}}}
+		}
+		return s.useCase.GetAllProducts(ctx)
+	}
+
+	productType, err := s.types.FindByID(ctx, typeID)
 	if err != nil {
-		log.Printf("Failed to publish product updated event: %v", err)
+		return nil, err
 	}
 
-	return nil
+	filter := ports.ProductFilter{TypeID: typeID}
+	for _, query := range queries {
+		condition, err := usecases.ParseAttributeCondition(productType, query.Name, query.Op, query.Value)
+		if err != nil {
+			return nil, err
+		}
+		filter.Attributes = append(filter.Attributes, condition)
+	}
+
+	return s.useCase.FindProducts(ctx, filter)
 }
 
-// DeleteProduct handles deleting a product by its ID
-func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
-	// Delete from MongoDB
-	if err := s.repo.Delete(ctx, id); err != nil {
+// StreamProducts calls fn for every product in the lifecycle status asked for, as statusFilter reads
+// it, reading batchSize products at a time
+func (s *ProductService) StreamProducts(ctx context.Context, batchSize int32, status string, fn func(*entities.Product) error) error {
+	visible, err := statusFilter(ctx, status)
+	if err != nil {
 		return err
 	}
 
-	// Delete from Redis
-	s.redisClient.Del(ctx, "product:"+id)
+	return s.useCase.StreamProducts(ctx, batchSize, func(product *entities.Product) error {
+		if !visible(product) {
+			return nil
+		}
+		return fn(product)
+	})
+}
 
-	// Publish event to the queue
-	err := s.queue.PublishProductDeleted(id)
-	if err != nil {
-		log.Printf("Failed to publish product deleted event: %v", err)
+// WatchProducts calls fn for every change to a product of the tenant, starting after resumeToken
+// when it is set. Changes to products the caller may not see, as VisibleProducts decides, come
+// without the product.
+func (s *ProductService) WatchProducts(ctx context.Context, resumeToken string, fn func(*entities.ProductEvent) error) error {
+	tenantID := ports.TenantFromContext(ctx)
+	visible := VisibleProducts(ctx)
+	return s.watcher.Watch(ctx, resumeToken, func(event *entities.ProductEvent) error {
+		if event.TenantID != tenantID {
+			return nil
+		}
+		return fn(redactEvent(event, visible))
+	})
+}
+
+// WatchAllTenants calls fn for every product change of every tenant, for the product feed that
+// fans them out to subscribers filtering by tenant
+func (s *ProductService) WatchAllTenants(ctx context.Context, resumeToken string, fn func(*entities.ProductEvent) error) error {
+	return s.watcher.Watch(ctx, resumeToken, fn)
+}
+
+// checkProductQuota fails with ErrProductQuotaExceeded when the tenant has reached its product limit.
+// The count and the insert only share a transaction on transactional stores, so on MongoDB
+// concurrent creates may overshoot the limit slightly.
+func (s *ProductService) checkProductQuota(ctx context.Context, products ports.ProductRepository) error {
+	limit := s.quotas.ProductLimit(ports.TenantFromContext(ctx))
+	if limit <= 0 {
+		return nil
 	}
 
+	count, err := products.Count(ctx)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return ErrProductQuotaExceeded
+	}
 	return nil
 }
 
-// ListProducts retrieves all products
-func (s *ProductService) ListProducts(ctx context.Context) ([]*entities.Product, error) {
-	// Example without Redis caching for list operation
-	return s.repo.FindAll(ctx)
+// cacheProduct stores a product with its effective price in the cache until the price next changes,
+// logging instead of failing the request when the price or the cache is unavailable. When the price
+// cannot be resolved the cached copy is dropped, as it predates the write.
+func (s *ProductService) cacheProduct(ctx context.Context, product *entities.Product) {
+	ttl, err := s.resolvePrice(ctx, product)
+	if err != nil {
+		log.Printf("Failed to resolve price of product %s: %v", product.ID.Hex(), err)
+		if err := s.cache.Delete(ctx, product.ID.Hex()); err != nil {
+			log.Printf("Failed to evict product %s from cache: %v", product.ID.Hex(), err)
+		}
+		return
+	}
+	if err := s.cache.Set(ctx, product, ttl); err != nil {
+		log.Printf("Failed to cache product %s: %v", product.ID.Hex(), err)
+	}
+}
+
+// resolvePrice sets the effective price of a product now and returns how long it holds,
+// zero when no schedule changes it later
+func (s *ProductService) resolvePrice(ctx context.Context, product *entities.Product) (time.Duration, error) {
+	schedules, err := s.prices.FindByProductID(ctx, product.ID.Hex())
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	price, _, next := usecases.ResolvePrice(product, schedules, now)
+	product.EffectivePrice = &price
+	if next.IsZero() {
+		return 0, nil
+	}
+	return next.Sub(now), nil
 }
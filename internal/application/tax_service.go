@@ -0,0 +1,175 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+var (
+	// ErrInvalidTaxRate is returned for tax rates outside 0 to 100 percent or without an effective time
+	ErrInvalidTaxRate = errors.New("tax rate must have a percent between 0 and 100 and an effective_from time")
+	// ErrTaxRateExists is returned when a jurisdiction already has a rate of the class taking effect at the same time
+	ErrTaxRateExists = errors.New("a tax rate of the jurisdiction and class already takes effect at that time")
+)
+
+// TaxPolicy tells how prices relate to tax
+type TaxPolicy struct {
+	PricesIncludeTax bool   // Whether product prices are gross prices, from which tax is backed out
+	Rounding         string // Rounding mode of tax amounts, one of the usecases.Round constants
+}
+
+// TaxService manages the tax rates of jurisdictions and quotes product prices with tax
+type TaxService struct {
+	rates    ports.TaxRateRepository
+	products *ProductService
+	policy   TaxPolicy
+}
+
+// NewTaxService creates a new instance of TaxService
+func NewTaxService(rates ports.TaxRateRepository, products *ProductService, policy TaxPolicy) *TaxService {
+	return &TaxService{
+		rates:    rates,
+		products: products,
+		policy:   policy,
+	}
+}
+
+// CreateRate adds a rate of a tax class in a jurisdiction, canonicalizing both. An earlier rate of the
+// same jurisdiction and class stays in effect until the new one takes over.
+func (s *TaxService) CreateRate(ctx context.Context, rate *entities.TaxRate) (string, error) {
+	jurisdiction, err := usecases.ParseRegion(rate.Jurisdiction)
+	if err != nil {
+		return "", err
+	}
+	class, err := usecases.ParseTaxClass(rate.TaxClass)
+	if err != nil {
+		return "", err
+	}
+	if rate.Percent < 0 || rate.Percent > 100 || rate.EffectiveFrom.IsZero() {
+		return "", ErrInvalidTaxRate
+	}
+	rate.Jurisdiction = jurisdiction
+	rate.TaxClass = class
+
+	existing, err := s.rates.FindEffective(ctx, jurisdiction, class, rate.EffectiveFrom)
+	if err == nil && existing.EffectiveFrom.Equal(rate.EffectiveFrom) {
+		return "", ErrTaxRateExists
+	}
+	if err != nil && err != ports.ErrTaxRateNotFound {
+		return "", err
+	}
+
+	return s.rates.Create(ctx, rate)
+}
+
+// GetRate retrieves a tax rate by its ID
+func (s *TaxService) GetRate(ctx context.Context, id string) (*entities.TaxRate, error) {
+	return s.rates.FindByID(ctx, id)
+}
+
+// ListRates retrieves the rates of a jurisdiction, or of every jurisdiction when it is empty, with their history
+func (s *TaxService) ListRates(ctx context.Context, jurisdiction string) ([]*entities.TaxRate, error) {
+	if jurisdiction != "" {
+		var err error
+		if jurisdiction, err = usecases.ParseRegion(jurisdiction); err != nil {
+			return nil, err
+		}
+	}
+	return s.rates.FindAll(ctx, jurisdiction)
+}
+
+// DeleteRate removes a tax rate, so the rate before it stays in effect
+func (s *TaxService) DeleteRate(ctx context.Context, id string) error {
+	return s.rates.Delete(ctx, id)
+}
+
+// QuotePrice computes the net, tax and gross amounts of quantity units of an active product in a region. The
+// unit price is the effective price of the product, or its price as asked for by query. The tax rate
+// is the one of the tax class of the product in effect now in the region, or else in its country.
+func (s *TaxService) QuotePrice(ctx context.Context, productID string, region string, quantity int, query PriceQuery) (*entities.PriceQuote, error) {
+	region, err := usecases.ParseRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	if quantity < 1 {
+		return nil, ErrInvalidQuantity
+	}
+
+	product, err := s.products.GetProductByID(ctx, productID, "", query)
+	if err != nil {
+		return nil, err
+	}
+	unitPrice, currency := product.Price, s.products.currency
+	if product.EffectivePrice != nil {
+		unitPrice = *product.EffectivePrice
+	}
+	if product.Pricing != nil {
+		unitPrice, currency = product.Pricing.Price, product.Pricing.Currency
+	}
+
+	class, err := usecases.ParseTaxClass(product.TaxClass)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := s.effectiveRate(ctx, region, class, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	amounts, err := usecases.CalculateTax(unitPrice, quantity, rate.Percent, s.policy.PricesIncludeTax, usecases.CurrencyScale(currency), s.policy.Rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.PriceQuote{
+		ProductID:         product.ID.Hex(),
+		Region:            region,
+		Jurisdiction:      rate.Jurisdiction,
+		TaxClass:          class,
+		TaxName:           rate.Name,
+		TaxPercent:        rate.Percent,
+		RateEffectiveFrom: rate.EffectiveFrom,
+		Currency:          currency,
+		Quantity:          quantity,
+		UnitPrice:         unitPrice,
+		PricesIncludeTax:  s.policy.PricesIncludeTax,
+		Rounding:          s.policy.Rounding,
+		Net:               amounts.Net,
+		Tax:               amounts.Tax,
+		Gross:             amounts.Gross,
+	}, nil
+}
+
+// effectiveRate finds the rate of class in effect at the given time in the most specific jurisdiction
+// of region that has one
+func (s *TaxService) effectiveRate(ctx context.Context, region string, class string, at time.Time) (*entities.TaxRate, error) {
+	for _, jurisdiction := range usecases.TaxJurisdictions(region) {
+		rate, err := s.rates.FindEffective(ctx, jurisdiction, class, at)
+		if err != ports.ErrTaxRateNotFound {
+			return rate, err
+		}
+	}
+	return nil, ports.ErrTaxRateNotFound
+}
+
+// SetProductTaxClass assigns a tax class to a product, or the default class when it is empty.
+// The change is validated and recorded like any update.
+func (s *ProductService) SetProductTaxClass(ctx context.Context, id string, class string) error {
+	class, err := usecases.ParseTaxClass(class)
+	if err != nil {
+		return err
+	}
+	if class == entities.DefaultTaxClass {
+		class = ""
+	}
+
+	return s.updateProduct(ctx, id, func(product *entities.Product) error {
+		product.TaxClass = class
+		return nil
+	})
+}
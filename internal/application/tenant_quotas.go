@@ -0,0 +1,20 @@
+package application
+
+import "errors"
+
+// ErrProductQuotaExceeded is returned when a tenant already has as many products as its quota allows
+var ErrProductQuotaExceeded = errors.New("product quota of the tenant exceeded")
+
+// TenantQuotas limits how many products each tenant may keep. A limit of zero is unlimited.
+type TenantQuotas struct {
+	Default   int64            // Limit of tenants without their own
+	PerTenant map[string]int64 // Limits overriding Default, by tenant ID
+}
+
+// ProductLimit returns the product limit of a tenant, zero when it is unlimited
+func (q TenantQuotas) ProductLimit(tenantID string) int64 {
+	if limit, ok := q.PerTenant[tenantID]; ok {
+		return limit
+	}
+	return q.Default
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"test-go/internal/core/ports"
+)
+
+// DirectProductUnitOfWork implements the ports.ProductUnitOfWork interface for stores without
+// transactions. Writes go straight to the repositories and events straight to the broker; a failed
+// publish is logged instead of failing the write, because the write cannot be rolled back.
+type DirectProductUnitOfWork struct {
+	repo      ports.ProductRepository
+	revisions ports.ProductRevisionRepository
+	audit     ports.AuditRepository
+	publisher ports.EventPublisher
+}
+
+// NewDirectProductUnitOfWork creates a new instance of DirectProductUnitOfWork
+func NewDirectProductUnitOfWork(repo ports.ProductRepository, revisions ports.ProductRevisionRepository, audit ports.AuditRepository, publisher ports.EventPublisher) ports.ProductUnitOfWork {
+	return &DirectProductUnitOfWork{
+		repo:      repo,
+		revisions: revisions,
+		audit:     audit,
+		publisher: publisher,
+	}
+}
+
+// Do calls fn with the repositories and a publisher that never fails
+func (u *DirectProductUnitOfWork) Do(ctx context.Context, fn func(tx ports.ProductTx) error) error {
+	return fn(ports.ProductTx{
+		Products:  u.repo,
+		Revisions: u.revisions,
+		Audit:     u.audit,
+		Events:    loggingPublisher{u.publisher},
+	})
+}
+
+// loggingPublisher logs instead of returning publish failures, so the request does not fail when the broker is unavailable
+type loggingPublisher struct {
+	publisher ports.EventPublisher
+}
+
+// Publish sends the event, logging a failure
+func (p loggingPublisher) Publish(routingKey string, message interface{}) error {
+	if err := p.publisher.Publish(routingKey, message); err != nil {
+		log.Printf("Failed to publish %s event: %v", routingKey, err)
+	}
+	return nil
+}
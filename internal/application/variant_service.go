@@ -0,0 +1,351 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxProductOptions     = 3   // Option axes per product, e.g. size, color and material
+	maxVariantsPerProduct = 250 // Size of the generated option matrix
+)
+
+// ErrInvalidOptions is returned when option names or values are blank or repeated
+var ErrInvalidOptions = errors.New("options need unique, non-empty names and values")
+
+// ErrTooManyVariants is returned when the option matrix would generate too many variants
+var ErrTooManyVariants = fmt.Errorf("option matrix exceeds %d variants", maxVariantsPerProduct)
+
+// ErrInvalidPrice is returned when a variant price override is negative
+var ErrInvalidPrice = errors.New("price cannot be negative")
+
+// ErrInvalidSKU is returned when a variant SKU is blank
+var ErrInvalidSKU = errors.New("sku is required")
+
+// VariantUpdate carries the fields to change on one variant; nil fields are left as they are
+type VariantUpdate struct {
+	ID         string
+	SKU        *string
+	Price      *float32
+	Barcode    *string
+	Attributes map[string]string
+}
+
+// VariantService manages product option matrices and their variants. Products are read from their
+// repository and written through productService, which records and caches the change.
+type VariantService struct {
+	repo           ports.VariantRepository
+	products       ports.ProductRepository
+	productService *ProductService
+	publisher      ports.EventPublisher
+}
+
+// NewVariantService creates a new instance of VariantService
+func NewVariantService(repo ports.VariantRepository, products ports.ProductRepository, productService *ProductService, publisher ports.EventPublisher) *VariantService {
+	return &VariantService{
+		repo:           repo,
+		products:       products,
+		productService: productService,
+		publisher:      publisher,
+	}
+}
+
+// GetProductWithVariants retrieves a product in the lifecycle status asked for, as statusFilter reads
+// it, together with its variants
+func (s *VariantService) GetProductWithVariants(ctx context.Context, productID string, status string) (*entities.Product, []*entities.ProductVariant, error) {
+	visible, err := statusFilter(ctx, status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	product, err := s.findProduct(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !visible(product) {
+		return nil, nil, ports.ErrProductNotFound
+	}
+
+	variants, err := s.repo.FindByProduct(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, variants, nil
+}
+
+// GenerateVariants sets the options of a product and creates one variant per combination of option values.
+// Variants whose combination still exists keep their SKU, price and barcode; variants whose combination
+// disappeared are deleted. New variants get a SKU built from skuPrefix, or the product ID, and their values.
+func (s *VariantService) GenerateVariants(ctx context.Context, productID string, options []entities.ProductOption, skuPrefix string) ([]*entities.ProductVariant, error) {
+	options, err := normalizeOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.findProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*entities.ProductVariant, len(existing))
+	bySKU := make(map[string]*entities.ProductVariant, len(existing))
+	for _, variant := range existing {
+		byKey[variant.OptionKey(options)] = variant
+		bySKU[variant.SKU] = variant
+	}
+
+	skuPrefix = strings.TrimSpace(skuPrefix)
+	if skuPrefix == "" {
+		skuPrefix = product.ID.Hex()
+	}
+
+	var (
+		variants []*entities.ProductVariant
+		created  []*entities.ProductVariant
+		kept     = make(map[primitive.ObjectID]bool, len(existing))
+		skus     = make(map[string]bool)
+	)
+	for i, combination := range optionCombinations(options) {
+		variant := &entities.ProductVariant{Options: combination}
+		sku := variantSKU(skuPrefix, options, combination)
+		if previous, ok := byKey[variant.OptionKey(options)]; ok && !kept[previous.ID] {
+			variant = previous
+			kept[variant.ID] = true
+		} else if previous, ok := bySKU[sku]; ok && !kept[previous.ID] {
+			// A renamed option yields the same SKU; reuse the variant rather than clash with it
+			previous.Options = combination
+			variant = previous
+			kept[variant.ID] = true
+		} else {
+			variant.ID = primitive.NewObjectID()
+			variant.ProductID = productID
+			variant.SKU = sku
+			created = append(created, variant)
+		}
+		if skus[variant.SKU] {
+			return nil, ports.ErrDuplicateSKU
+		}
+		skus[variant.SKU] = true
+		variant.Position = i
+		variants = append(variants, variant)
+	}
+
+	if err := s.repo.CreateMany(ctx, created); err != nil {
+		// An ordered insert may have stored the variants before the failing one
+		if deleteErr := s.repo.DeleteByIDs(ctx, variantIDs(created)); deleteErr != nil {
+			log.Printf("Failed to roll back variants of product %s: %v", productID, deleteErr)
+		}
+		return nil, err
+	}
+
+	for _, variant := range variants {
+		if kept[variant.ID] {
+			if err := s.repo.Update(ctx, variant); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var obsolete []*entities.ProductVariant
+	for _, variant := range existing {
+		if !kept[variant.ID] {
+			obsolete = append(obsolete, variant)
+		}
+	}
+	if err := s.repo.DeleteByIDs(ctx, variantIDs(obsolete)); err != nil {
+		return nil, err
+	}
+
+	err = s.productService.updateProduct(ctx, productID, func(product *entities.Product) error {
+		product.Options = options
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, entities.VariantsGenerated, map[string]interface{}{"product_id": productID, "variants": variants})
+	return variants, nil
+}
+
+// UpdateVariants applies a batch of changes to variants of a product.
+// Every variant is checked before any is written, so a batch with an unknown variant or a
+// SKU used twice changes nothing.
+func (s *VariantService) UpdateVariants(ctx context.Context, productID string, updates []VariantUpdate) ([]*entities.ProductVariant, error) {
+	if _, err := s.findProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*entities.ProductVariant, len(existing))
+	skus := make(map[string]string, len(existing))
+	for _, variant := range existing {
+		byID[variant.ID.Hex()] = variant
+		skus[variant.SKU] = variant.ID.Hex()
+	}
+
+	updated := make([]*entities.ProductVariant, 0, len(updates))
+	for _, update := range updates {
+		variant, ok := byID[update.ID]
+		if !ok {
+			return nil, ports.ErrVariantNotFound
+		}
+
+		if update.SKU != nil {
+			sku := strings.TrimSpace(*update.SKU)
+			if sku == "" {
+				return nil, ErrInvalidSKU
+			}
+			if owner, taken := skus[sku]; taken && owner != update.ID {
+				return nil, ports.ErrDuplicateSKU
+			}
+			delete(skus, variant.SKU)
+			skus[sku] = update.ID
+			variant.SKU = sku
+		}
+		if update.Price != nil {
+			if *update.Price < 0 {
+				return nil, ErrInvalidPrice
+			}
+			variant.Price = update.Price
+		}
+		if update.Barcode != nil {
+			variant.Barcode = strings.TrimSpace(*update.Barcode)
+		}
+		if update.Attributes != nil {
+			variant.Attributes = update.Attributes
+		}
+		updated = append(updated, variant)
+	}
+
+	for _, variant := range updated {
+		if err := s.repo.Update(ctx, variant); err != nil {
+			return nil, err
+		}
+	}
+
+	s.publish(ctx, entities.VariantsUpdated, map[string]interface{}{"product_id": productID, "variants": updated})
+	return updated, nil
+}
+
+// findProduct retrieves a product, turning the repository's nil result into ErrProductNotFound
+func (s *VariantService) findProduct(ctx context.Context, productID string) (*entities.Product, error) {
+	return s.products.FindByID(ctx, productID)
+}
+
+// publish sends a variant event routed by the tenant of ctx, logging instead of failing the request
+// when the broker is unavailable
+func (s *VariantService) publish(ctx context.Context, eventType string, message interface{}) {
+	if err := s.publisher.Publish(ports.RoutingKey(ctx, eventType), message); err != nil {
+		log.Printf("Failed to publish %s event: %v", eventType, err)
+	}
+}
+
+// normalizeOptions trims option names and values and checks the matrix they span
+func normalizeOptions(options []entities.ProductOption) ([]entities.ProductOption, error) {
+	if len(options) == 0 || len(options) > maxProductOptions {
+		return nil, ErrInvalidOptions
+	}
+
+	normalized := make([]entities.ProductOption, len(options))
+	names := make(map[string]bool, len(options))
+	combinations := 1
+	for i, option := range options {
+		name := strings.TrimSpace(option.Name)
+		if name == "" || names[strings.ToLower(name)] || len(option.Values) == 0 {
+			return nil, ErrInvalidOptions
+		}
+		names[strings.ToLower(name)] = true
+
+		values := make([]string, 0, len(option.Values))
+		seen := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if value == "" || seen[strings.ToLower(value)] {
+				return nil, ErrInvalidOptions
+			}
+			seen[strings.ToLower(value)] = true
+			values = append(values, value)
+		}
+
+		combinations *= len(values)
+		if combinations > maxVariantsPerProduct {
+			return nil, ErrTooManyVariants
+		}
+		normalized[i] = entities.ProductOption{Name: name, Values: values}
+	}
+
+	return normalized, nil
+}
+
+// optionCombinations returns the cartesian product of the option values, varying the last option fastest
+func optionCombinations(options []entities.ProductOption) []map[string]string {
+	combinations := []map[string]string{{}}
+	for _, option := range options {
+		next := make([]map[string]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				extended := make(map[string]string, len(combination)+1)
+				for name, v := range combination {
+					extended[name] = v
+				}
+				extended[option.Name] = value
+				next = append(next, extended)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// variantSKU builds a SKU such as "TSHIRT-M-RED" from a prefix and the option values in option order
+func variantSKU(prefix string, options []entities.ProductOption, combination map[string]string) string {
+	parts := []string{prefix}
+	for _, option := range options {
+		parts = append(parts, skuSegment(combination[option.Name]))
+	}
+	return strings.ToUpper(strings.Join(parts, "-"))
+}
+
+// skuSegment keeps the letters and digits of a value, replacing every other run of characters with one underscore
+func skuSegment(value string) string {
+	var b strings.Builder
+	separator := false
+	for _, r := range value {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if separator && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			separator = false
+			b.WriteRune(r)
+			continue
+		}
+		separator = true
+	}
+	return b.String()
+}
+
+// variantIDs returns the hex IDs of the variants
+func variantIDs(variants []*entities.ProductVariant) []string {
+	ids := make([]string, len(variants))
+	for i, variant := range variants {
+		ids[i] = variant.ID.Hex()
+	}
+	return ids
+}
@@ -0,0 +1,337 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Webhook request headers
+const (
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	webhookMaxAttempts     = 5                // Attempts per event before the delivery counts as failed
+	webhookBaseDelay       = time.Second      // Delay before the first retry, doubled on every retry
+	webhookMaxDelay        = 5 * time.Minute  // Upper bound for the retry delay
+	webhookDisableAfter    = 10               // Consecutive failed deliveries before a subscription is disabled
+	webhookMaxConcurrency  = 32               // Deliveries in flight at the same time
+	webhookMaxResponseBody = 4 << 10          // Bytes of the response body kept in the delivery log
+	webhookRequestTimeout  = 10 * time.Second // Timeout for a single delivery attempt
+	webhookClaimLease      = time.Minute      // How long a claimed delivery is left to its consumer before it is claimed again
+	webhookPollInterval    = time.Second      // How often the queue is checked for retries that came due
+	webhookListenerRetry   = 2 * time.Second  // Delay before consuming product events again after a failure
+)
+
+// webhookPayload is the JSON body POSTed to subscribers
+type webhookPayload struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	TenantID   string            `json:"tenant_id,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	ProductID  string            `json:"product_id"`
+	Product    *entities.Product `json:"product,omitempty"`
+}
+
+// WebhookDispatcher POSTs product events to the matching webhook subscriptions. Events are queued
+// as pending deliveries before they are acknowledged, and RunDeliveryWorker sends and retries them.
+type WebhookDispatcher struct {
+	repo       ports.WebhookRepository
+	deliveries ports.WebhookDeliveryRepository
+	pending    ports.PendingWebhookDeliveryRepository
+	client     *http.Client
+	slots      chan struct{}
+	inFlight   sync.WaitGroup
+	wake       chan struct{}
+}
+
+// NewWebhookDispatcher creates a new instance of WebhookDispatcher
+func NewWebhookDispatcher(repo ports.WebhookRepository, deliveries ports.WebhookDeliveryRepository, pending ports.PendingWebhookDeliveryRepository) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:       repo,
+		deliveries: deliveries,
+		pending:    pending,
+		client:     &http.Client{Timeout: webhookRequestTimeout},
+		slots:      make(chan struct{}, webhookMaxConcurrency),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// SignWebhookPayload returns the signature header value for a payload sent at timestamp.
+// The HMAC-SHA256 covers "<timestamp>.<body>" so receivers can reject replayed requests.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// HandleEvent queues the event for every active subscription of its tenant that wants it. It
+// returns once the deliveries are stored, so the event can be acknowledged without being lost;
+// RunEventListener queues the deliveries of every product event events consumes until ctx is
+// cancelled, consuming again after a failure. cmd/event calls HandleEvent itself, after refreshing
+// bundles; this is for processes whose events never leave them.
+func (d *WebhookDispatcher) RunEventListener(ctx context.Context, events ports.EventConsumer) {
+	for {
+		err := events.ConsumeProductEvents(ctx, d.HandleEvent)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Webhook event listener stopped, retrying: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(webhookListenerRetry):
+		}
+	}
+}
+
+// RunDeliveryWorker sends them.
+func (d *WebhookDispatcher) HandleEvent(ctx context.Context, event *entities.ProductEvent) error {
+	ctx = ports.WithTenant(ctx, event.TenantID)
+	webhooks, err := d.repo.FindActiveByEventType(ctx, event.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	// Subscribers read products like anonymous callers, so only active products are sent
+	event = redactEvent(event, VisibleProducts(ctx))
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+	payload := webhookPayload{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       event.Type,
+		TenantID:   ports.TenantFromContext(ctx),
+		OccurredAt: occurredAt,
+		ProductID:  event.ProductID,
+		Product:    event.Product,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]*entities.PendingWebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, &entities.PendingWebhookDelivery{
+			SubscriptionID: webhook.ID,
+			EventID:        payload.ID,
+			EventType:      payload.Type,
+			Body:           string(body),
+			NextAttemptAt:  now,
+		})
+	}
+	if err := d.pending.Create(ctx, deliveries); err != nil {
+		return err
+	}
+
+	// Let the worker send them right away rather than on its next poll
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// RunDeliveryWorker sends the queued deliveries as they come due until ctx is cancelled. Attempts
+// already started are finished even then; use Wait to let them finish. Retries that are not due yet
+// stay queued for the next run.
+func (d *WebhookDispatcher) RunDeliveryWorker(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Wait blocks until every attempt in flight has finished
+func (d *WebhookDispatcher) Wait() {
+	d.inFlight.Wait()
+}
+
+// deliverDue claims the deliveries that are due and sends each of them in the background, at most
+// webhookMaxConcurrency at a time
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) {
+	// Attempts run to completion after shutdown starts, so they are recorded rather than cut off
+	attemptCtx := context.WithoutCancel(ctx)
+
+	for ctx.Err() == nil {
+		now := time.Now()
+		claimed, err := d.pending.ClaimDue(ctx, now, now.Add(webhookClaimLease), webhookMaxConcurrency)
+		if err != nil {
+			log.Printf("Failed to claim webhook deliveries: %v", err)
+		}
+
+		for _, delivery := range claimed {
+			d.slots <- struct{}{}
+			d.inFlight.Add(1)
+			go func(delivery *entities.PendingWebhookDelivery) {
+				defer func() {
+					<-d.slots
+					d.inFlight.Done()
+				}()
+				d.deliver(attemptCtx, delivery)
+			}(delivery)
+		}
+
+		if err != nil || len(claimed) < webhookMaxConcurrency {
+			return
+		}
+	}
+}
+
+// deliver makes the next attempt of a queued delivery, then removes it once it succeeded or ran out
+// of attempts and reschedules it with exponential backoff otherwise
+func (d *WebhookDispatcher) deliver(ctx context.Context, pending *entities.PendingWebhookDelivery) {
+	ctx = ports.WithTenant(ctx, pending.TenantID)
+	id := pending.ID.Hex()
+
+	webhook, err := d.repo.FindByID(ctx, pending.SubscriptionID.Hex())
+	if errors.Is(err, ports.ErrWebhookNotFound) || (err == nil && !webhook.Active) {
+		// Deleted or disabled since the event was queued
+		d.dropPending(ctx, id)
+		return
+	}
+	if err != nil {
+		// The claim runs out and the delivery is attempted again
+		log.Printf("Failed to load webhook %s: %v", pending.SubscriptionID.Hex(), err)
+		return
+	}
+
+	attempt := pending.Attempts + 1
+	delivery := d.attempt(ctx, webhook, pending.EventID, pending.EventType, []byte(pending.Body), attempt)
+	if _, err := d.deliveries.Create(ctx, delivery); err != nil {
+		log.Printf("Failed to record webhook delivery for %s: %v", webhook.ID.Hex(), err)
+	}
+
+	switch {
+	case delivery.Success:
+		d.recordOutcome(ctx, webhook.ID.Hex(), true)
+		d.dropPending(ctx, id)
+	case attempt >= webhookMaxAttempts:
+		d.recordOutcome(ctx, webhook.ID.Hex(), false)
+		d.dropPending(ctx, id)
+	default:
+		if err := d.pending.Reschedule(ctx, id, attempt, time.Now().Add(webhookRetryDelay(attempt))); err != nil {
+			log.Printf("Failed to reschedule webhook delivery %s: %v", id, err)
+		}
+	}
+}
+
+// dropPending removes a delivery from the queue
+func (d *WebhookDispatcher) dropPending(ctx context.Context, id string) {
+	if err := d.pending.Delete(ctx, id); err != nil {
+		log.Printf("Failed to remove webhook delivery %s: %v", id, err)
+	}
+}
+
+// webhookRetryDelay returns how long to wait after the given failed attempt, doubling from
+// webhookBaseDelay up to webhookMaxDelay
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookBaseDelay
+	for i := 1; i < attempt && delay < webhookMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxDelay)
+}
+
+// attempt sends a single signed request and captures it for the delivery log
+func (d *WebhookDispatcher) attempt(ctx context.Context, webhook *entities.WebhookSubscription, eventID string, eventType string, body []byte, attempt int) *entities.WebhookDelivery {
+	timestamp := time.Now().Unix()
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		WebhookIDHeader:        eventID,
+		WebhookEventHeader:     eventType,
+		WebhookTimestampHeader: strconv.FormatInt(timestamp, 10),
+		WebhookSignatureHeader: SignWebhookPayload(webhook.Secret, timestamp, body),
+	}
+
+	delivery := &entities.WebhookDelivery{
+		SubscriptionID: webhook.ID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Attempt:        attempt,
+		RequestHeaders: headers,
+		RequestBody:    string(body),
+		CreatedAt:      time.Now(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	delivery.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(respBody)
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+
+	return delivery
+}
+
+// recordOutcome resets the failure count of a subscription after a successful delivery and
+// increments it after a failed one, disabling the subscription once it reaches webhookDisableAfter.
+// Both are single atomic updates, so concurrent deliveries neither lose counts nor overwrite changes
+// made to the subscription meanwhile.
+func (d *WebhookDispatcher) recordOutcome(ctx context.Context, id string, success bool) {
+	if success {
+		if err := d.repo.ResetFailures(ctx, id); err != nil {
+			log.Printf("Failed to reset failures of webhook %s: %v", id, err)
+		}
+		return
+	}
+
+	disabled, err := d.repo.RecordFailure(ctx, id, webhookDisableAfter, time.Now())
+	if err != nil {
+		log.Printf("Failed to record failure of webhook %s: %v", id, err)
+		return
+	}
+	if disabled {
+		log.Printf("Webhook %s disabled after %d failed deliveries", id, webhookDisableAfter)
+	}
+}
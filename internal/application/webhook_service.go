@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// defaultDeliveryLogLimit is the number of deliveries returned when no limit is given
+const defaultDeliveryLogLimit = 50
+
+// ErrInvalidWebhookURL is returned when a webhook URL is not an absolute http(s) URL
+var ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url")
+
+// ErrInvalidEventType is returned when a webhook subscribes to an unknown event type
+var ErrInvalidEventType = errors.New("unknown event type")
+
+// WebhookService manages webhook subscriptions and exposes their delivery log
+type WebhookService struct {
+	repo       ports.WebhookRepository
+	deliveries ports.WebhookDeliveryRepository
+}
+
+// NewWebhookService creates a new instance of WebhookService
+func NewWebhookService(repo ports.WebhookRepository, deliveries ports.WebhookDeliveryRepository) *WebhookService {
+	return &WebhookService{
+		repo:       repo,
+		deliveries: deliveries,
+	}
+}
+
+// CreateWebhook registers a new subscription. A random secret is generated when none is given.
+func (s *WebhookService) CreateWebhook(ctx context.Context, rawURL string, secret string, eventTypes []string) (*entities.WebhookSubscription, error) {
+	if err := validateWebhook(rawURL, eventTypes); err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	webhook := &entities.WebhookSubscription{
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+	}
+	if _, err := s.repo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhook retrieves a subscription by its ID
+func (s *WebhookService) GetWebhook(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// UpdateWebhook changes the URL, event types and active flag of a subscription.
+// Re-activating a subscription clears its failure history.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id string, rawURL string, eventTypes []string, active bool) error {
+	if err := validateWebhook(rawURL, eventTypes); err != nil {
+		return err
+	}
+
+	webhook, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if active && !webhook.Active {
+		webhook.FailureCount = 0
+		webhook.DisabledAt = nil
+	}
+	webhook.URL = rawURL
+	webhook.EventTypes = eventTypes
+	webhook.Active = active
+
+	return s.repo.Update(ctx, webhook)
+}
+
+// DeleteWebhook removes a subscription by its ID
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ListWebhooks retrieves all subscriptions
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// ListDeliveries retrieves the latest delivery attempts for a subscription, newest first
+func (s *WebhookService) ListDeliveries(ctx context.Context, id string, limit int64) ([]*entities.WebhookDelivery, error) {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLogLimit
+	}
+	return s.deliveries.FindBySubscription(ctx, id, limit)
+}
+
+// validateWebhook checks the URL and event types of a subscription
+func validateWebhook(rawURL string, eventTypes []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	for _, eventType := range eventTypes {
+		switch eventType {
+		case entities.ProductCreated, entities.ProductUpdated, entities.ProductDeleted:
+		default:
+			return ErrInvalidEventType
+		}
+	}
+
+	return nil
+}
+
+// generateSecret returns a random hex encoded signing secret
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,629 @@
+package bootstrap
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"test-go/internal/adapters/secondary/blob"
+	"test-go/internal/adapters/secondary/cache"
+	"test-go/internal/adapters/secondary/exchangerates"
+	"test-go/internal/adapters/secondary/memory"
+	queue "test-go/internal/adapters/secondary/messaging"
+	"test-go/internal/adapters/secondary/repository/boltdb"
+	"test-go/internal/adapters/secondary/repository/mongodb"
+	"test-go/internal/application"
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/config"
+	"test-go/internal/infrastructure/db"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Container is the composition root shared by the cmd binaries. It connects to MongoDB, Redis
+// and RabbitMQ on first use and hands out adapters behind their ports, so each binary only
+// dials the services it needs and adapters can be swapped in one place. With STORAGE=memory
+// every port is served by the in-memory adapters instead, and with STORAGE=bolt products, their
+// events, price schedules, categories, stock, webhooks and API keys are kept in a bbolt file while
+// the other ports stay in memory. Media files are kept in the blob store selected by MEDIA_STORE
+// whatever the storage backend.
+// It is meant to be used from main during startup and is not safe for concurrent use.
+type Container struct {
+	Config *config.Config
+
+	mongoDB     *mongo.Database
+	redisClient *redis.Client
+	rabbitMQ    *queue.RabbitMQ
+	inMemory    *memoryAdapters
+	blobStore   ports.BlobStore
+
+	boltStore    *boltdb.Store
+	stopRelay    context.CancelFunc
+	relayStopped chan struct{}
+}
+
+// outboxRelayInterval is how often the bolt outbox retries events the broker did not accept
+const outboxRelayInterval = 5 * time.Second
+
+// memoryAdapters holds the in-memory adapters, created once so every service shares the same data
+type memoryAdapters struct {
+	eventBus          *memory.EventBus
+	changes           *memory.ChangeStream
+	products          ports.ProductRepository
+	productRevisions  ports.ProductRevisionRepository
+	priceSchedules    ports.PriceScheduleRepository
+	productTypes      ports.ProductTypeRepository
+	variants          ports.VariantRepository
+	categories        ports.CategoryRepository
+	inventory         ports.InventoryRepository
+	reservations      ports.ReservationRepository
+	webhooks          ports.WebhookRepository
+	webhookDeliveries ports.WebhookDeliveryRepository
+	pendingWebhooks   ports.PendingWebhookDeliveryRepository
+	productCache      ports.ProductCache
+	idempotencyStore  ports.IdempotencyStore
+	rateLimiter       ports.RateLimiter
+	apiKeys           ports.APIKeyRepository
+	apiKeyCache       ports.APIKeyCache
+	audit             ports.AuditRepository
+	media             ports.MediaRepository
+	priceLists        ports.PriceListRepository
+	exchangeRates     ports.ExchangeRateRepository
+	taxRates          ports.TaxRateRepository
+}
+
+// New creates a container for the given configuration without connecting to anything yet
+func New(conf *config.Config) *Container {
+	return &Container{Config: conf}
+}
+
+// MongoDB returns the MongoDB database, connecting on first use
+func (c *Container) MongoDB() *mongo.Database {
+	if c.mongoDB == nil {
+		c.mongoDB = db.GetMongoInstance(c.Config.MongoURI, c.Config.MongoDbName)
+	}
+	return c.mongoDB
+}
+
+// Redis returns the Redis client, connecting on first use
+func (c *Container) Redis() *redis.Client {
+	if c.redisClient == nil {
+		c.redisClient = db.GetRedisInstance(c.Config.RedisURI, c.Config.RedisDbName)
+	}
+	return c.redisClient
+}
+
+// RabbitMQ returns the RabbitMQ connection, connecting on first use
+func (c *Container) RabbitMQ() *queue.RabbitMQ {
+	if c.rabbitMQ == nil {
+		c.rabbitMQ = queue.GetRmqInstance(c.Config.RabbitMqURI)
+	}
+	return c.rabbitMQ
+}
+
+// memory returns the in-memory adapters, creating them on first use
+func (c *Container) memory() *memoryAdapters {
+	if c.inMemory == nil {
+		changes := memory.NewChangeStream(0)
+		products := memory.NewProductRepository(changes)
+		c.inMemory = &memoryAdapters{
+			eventBus:          memory.NewEventBus(),
+			changes:           changes,
+			products:          products,
+			productRevisions:  memory.NewProductRevisionRepository(),
+			priceSchedules:    memory.NewPriceScheduleRepository(),
+			productTypes:      memory.NewProductTypeRepository(),
+			variants:          memory.NewVariantRepository(),
+			categories:        memory.NewCategoryRepository(),
+			inventory:         memory.NewInventoryRepository(products),
+			reservations:      memory.NewReservationRepository(),
+			webhooks:          memory.NewWebhookRepository(),
+			webhookDeliveries: memory.NewWebhookDeliveryRepository(),
+			pendingWebhooks:   memory.NewPendingWebhookDeliveryRepository(),
+			productCache:      memory.NewProductCache(),
+			idempotencyStore:  memory.NewIdempotencyStore(),
+			rateLimiter:       memory.NewRateLimiter(),
+			apiKeys:           memory.NewAPIKeyRepository(),
+			apiKeyCache:       memory.NewAPIKeyCache(),
+			audit:             memory.NewAuditRepository(),
+			media:             memory.NewMediaRepository(),
+			priceLists:        memory.NewPriceListRepository(),
+			exchangeRates:     memory.NewExchangeRateRepository(),
+			taxRates:          memory.NewTaxRateRepository(),
+		}
+	}
+	return c.inMemory
+}
+
+// BoltStore returns the bbolt database, opening it and starting the outbox relay on first use
+func (c *Container) BoltStore() *boltdb.Store {
+	if c.boltStore == nil {
+		store, err := boltdb.Open(c.Config.BoltPath, c.memory().changes)
+		if err != nil {
+			log.Fatalf("Failed to open bbolt database: %v", err)
+		}
+		c.boltStore = store
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c.stopRelay = cancel
+		c.relayStopped = make(chan struct{})
+		target := c.relayTarget()
+		go func() {
+			defer close(c.relayStopped)
+			store.RelayOutbox(ctx, target, outboxRelayInterval)
+		}()
+	}
+	return c.boltStore
+}
+
+// relayTarget returns where the bolt outbox forwards events: RabbitMQ when it is configured,
+// the in-process event bus otherwise
+func (c *Container) relayTarget() ports.EventPublisher {
+	if c.Config.RabbitMqURI != "" {
+		return c.RabbitMQ()
+	}
+	return c.memory().eventBus
+}
+
+// inMemoryStorage reports whether the adapters other than products are served from process memory
+func (c *Container) inMemoryStorage() bool {
+	return c.Config.Storage != config.StorageMongoDB
+}
+
+// boltStorage reports whether products are kept in the bbolt file
+func (c *Container) boltStorage() bool {
+	return c.Config.Storage == config.StorageBolt
+}
+
+// EventPublisher returns the publisher for domain events
+func (c *Container) EventPublisher() ports.EventPublisher {
+	if c.boltStorage() {
+		return c.BoltStore()
+	}
+	if c.inMemoryStorage() {
+		return c.memory().eventBus
+	}
+	return c.RabbitMQ()
+}
+
+// InProcessEvents reports whether events stay on the in-process event bus, where only the
+// consumers of the publishing process receive them
+func (c *Container) InProcessEvents() bool {
+	return c.inMemoryStorage() && c.Config.RabbitMqURI == ""
+}
+
+// EventConsumer returns the consumer of product events
+func (c *Container) EventConsumer() ports.EventConsumer {
+	if c.InProcessEvents() {
+		return c.memory().eventBus
+	}
+	return c.RabbitMQ()
+}
+
+// APIKeyRevocationConsumer returns the consumer of API key revocations, which are broadcast on
+// the same broker as the product events
+func (c *Container) APIKeyRevocationConsumer() ports.APIKeyRevocationConsumer {
+	if c.InProcessEvents() {
+		return c.memory().eventBus
+	}
+	return c.RabbitMQ()
+}
+
+// MediaEventConsumer returns the consumer of media uploads
+func (c *Container) MediaEventConsumer() ports.MediaEventConsumer {
+	if c.InProcessEvents() {
+		return c.memory().eventBus
+	}
+	return c.RabbitMQ()
+}
+
+// ProductRepository returns the product repository, recording a revision and an audit entry for every write
+func (c *Container) ProductRepository() ports.ProductRepository {
+	return application.NewAuditRecordingRepository(application.NewRevisionRecordingRepository(c.productStore(), c.ProductRevisionRepository()), c.AuditRepository())
+}
+
+// productStore returns the product repository of the storage backend, which records no revisions
+func (c *Container) productStore() ports.ProductRepository {
+	if c.boltStorage() {
+		return boltdb.NewProductRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().products
+	}
+	return mongodb.NewProductRepository(c.MongoDB())
+}
+
+// ProductRevisionRepository returns the product change history
+func (c *Container) ProductRevisionRepository() ports.ProductRevisionRepository {
+	if c.boltStorage() {
+		return boltdb.NewProductRevisionRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().productRevisions
+	}
+	return mongodb.NewProductRevisionRepository(c.MongoDB())
+}
+
+// ProductUnitOfWork returns the unit of work writing products together with their revisions, audit entries and events
+func (c *Container) ProductUnitOfWork() ports.ProductUnitOfWork {
+	if c.boltStorage() {
+		return c.BoltStore()
+	}
+	return application.NewDirectProductUnitOfWork(c.productStore(), c.ProductRevisionRepository(), c.AuditRepository(), c.EventPublisher())
+}
+
+// StorageMaintenance returns the backup and compaction operations, or nil when the storage
+// backend has none
+func (c *Container) StorageMaintenance() ports.StorageMaintenance {
+	if c.boltStorage() {
+		return c.BoltStore()
+	}
+	return nil
+}
+
+// PriceScheduleRepository returns the scheduled price change repository
+func (c *Container) PriceScheduleRepository() ports.PriceScheduleRepository {
+	if c.boltStorage() {
+		return boltdb.NewPriceScheduleRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().priceSchedules
+	}
+	return mongodb.NewPriceScheduleRepository(c.MongoDB())
+}
+
+// ProductTypeRepository returns the product type repository
+func (c *Container) ProductTypeRepository() ports.ProductTypeRepository {
+	if c.inMemoryStorage() {
+		return c.memory().productTypes
+	}
+	return mongodb.NewProductTypeRepository(c.MongoDB())
+}
+
+// ProductWatcher returns the source of product change notifications
+func (c *Container) ProductWatcher() ports.ProductWatcher {
+	if c.inMemoryStorage() {
+		return c.memory().changes
+	}
+	return mongodb.NewProductWatcher(c.MongoDB())
+}
+
+// VariantRepository returns the product variant repository
+func (c *Container) VariantRepository() ports.VariantRepository {
+	if c.inMemoryStorage() {
+		return c.memory().variants
+	}
+	return mongodb.NewVariantRepository(c.MongoDB())
+}
+
+// CategoryRepository returns the category repository
+func (c *Container) CategoryRepository() ports.CategoryRepository {
+	if c.boltStorage() {
+		return boltdb.NewCategoryRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().categories
+	}
+	return mongodb.NewCategoryRepository(c.MongoDB())
+}
+
+// InventoryRepository returns the stock level repository
+func (c *Container) InventoryRepository() ports.InventoryRepository {
+	if c.boltStorage() {
+		return boltdb.NewInventoryRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().inventory
+	}
+	return mongodb.NewInventoryRepository(c.MongoDB())
+}
+
+// ReservationRepository returns the stock reservation repository
+func (c *Container) ReservationRepository() ports.ReservationRepository {
+	if c.boltStorage() {
+		return boltdb.NewReservationRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().reservations
+	}
+	return mongodb.NewReservationRepository(c.MongoDB())
+}
+
+// WebhookRepository returns the webhook subscription repository
+func (c *Container) WebhookRepository() ports.WebhookRepository {
+	if c.boltStorage() {
+		return boltdb.NewWebhookRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().webhooks
+	}
+	return mongodb.NewWebhookRepository(c.MongoDB())
+}
+
+// WebhookDeliveryRepository returns the webhook delivery log
+func (c *Container) WebhookDeliveryRepository() ports.WebhookDeliveryRepository {
+	if c.boltStorage() {
+		return boltdb.NewWebhookDeliveryRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().webhookDeliveries
+	}
+	return mongodb.NewWebhookDeliveryRepository(c.MongoDB())
+}
+
+// PendingWebhookDeliveryRepository returns the queue of webhook deliveries still to be attempted
+func (c *Container) PendingWebhookDeliveryRepository() ports.PendingWebhookDeliveryRepository {
+	if c.boltStorage() {
+		return boltdb.NewPendingWebhookDeliveryRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().pendingWebhooks
+	}
+	return mongodb.NewPendingWebhookDeliveryRepository(c.MongoDB())
+}
+
+// AuditRepository returns the append-only audit log, kept in the bbolt file next to the products
+// it records so both are written in one transaction
+func (c *Container) AuditRepository() ports.AuditRepository {
+	if c.boltStorage() {
+		return boltdb.NewAuditRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().audit
+	}
+	return mongodb.NewAuditRepository(c.MongoDB())
+}
+
+// APIKeyRepository returns the API key repository
+func (c *Container) APIKeyRepository() ports.APIKeyRepository {
+	if c.boltStorage() {
+		return boltdb.NewAPIKeyRepository(c.BoltStore())
+	}
+	if c.inMemoryStorage() {
+		return c.memory().apiKeys
+	}
+	return mongodb.NewAPIKeyRepository(c.MongoDB())
+}
+
+// MediaRepository returns the product media repository
+func (c *Container) MediaRepository() ports.MediaRepository {
+	if c.inMemoryStorage() {
+		return c.memory().media
+	}
+	return mongodb.NewMediaRepository(c.MongoDB())
+}
+
+// PriceListRepository returns the price list repository
+func (c *Container) PriceListRepository() ports.PriceListRepository {
+	if c.inMemoryStorage() {
+		return c.memory().priceLists
+	}
+	return mongodb.NewPriceListRepository(c.MongoDB())
+}
+
+// ExchangeRateRepository returns the exchange rate history shared by every tenant
+func (c *Container) ExchangeRateRepository() ports.ExchangeRateRepository {
+	if c.inMemoryStorage() {
+		return c.memory().exchangeRates
+	}
+	return mongodb.NewExchangeRateRepository(c.MongoDB())
+}
+
+// TaxRateRepository returns the tax rates of every jurisdiction, shared by every tenant
+func (c *Container) TaxRateRepository() ports.TaxRateRepository {
+	if c.inMemoryStorage() {
+		return c.memory().taxRates
+	}
+	return mongodb.NewTaxRateRepository(c.MongoDB())
+}
+
+// ExchangeRateSource returns the exchange rate API configured with EXCHANGE_RATE_URL, or nil when
+// rates are only imported from files
+func (c *Container) ExchangeRateSource() ports.ExchangeRateSource {
+	if c.Config.ExchangeRateURL == "" {
+		return nil
+	}
+	source, err := exchangerates.NewHTTPExchangeRateSource(c.Config.ExchangeRateURL)
+	if err != nil {
+		log.Fatalf("Failed to configure exchange rate source: %v", err)
+	}
+	return source
+}
+
+// BlobStore returns the store of media files, opening it on first use
+func (c *Container) BlobStore() ports.BlobStore {
+	if c.blobStore == nil {
+		var store ports.BlobStore
+		var err error
+		if c.Config.MediaStore == config.MediaStoreS3 {
+			store, err = blob.NewS3BlobStore(blob.S3Config{
+				Endpoint:  c.Config.S3Endpoint,
+				Region:    c.Config.S3Region,
+				Bucket:    c.Config.S3Bucket,
+				AccessKey: c.Config.S3AccessKey,
+				SecretKey: c.Config.S3SecretKey,
+				PathStyle: c.Config.S3PathStyle,
+			})
+		} else {
+			store, err = blob.NewFilesystemBlobStore(c.Config.MediaPath)
+		}
+		if err != nil {
+			log.Fatalf("Failed to open media store: %v", err)
+		}
+		c.blobStore = store
+	}
+	return c.blobStore
+}
+
+// ProductCache returns the product cache
+func (c *Container) ProductCache() ports.ProductCache {
+	if c.inMemoryStorage() {
+		return c.memory().productCache
+	}
+	return cache.NewRedisProductCache(c.Redis())
+}
+
+// IdempotencyStore returns the store remembering responses to retried mutations
+func (c *Container) IdempotencyStore() ports.IdempotencyStore {
+	if c.inMemoryStorage() {
+		return c.memory().idempotencyStore
+	}
+	return cache.NewRedisIdempotencyStore(c.Redis())
+}
+
+// RateLimiter returns the token buckets limiting the requests of each client, shared by every
+// replica through Redis and kept in memory while Redis is unavailable
+func (c *Container) RateLimiter() ports.RateLimiter {
+	if c.inMemoryStorage() {
+		return c.memory().rateLimiter
+	}
+	return cache.NewRedisRateLimiter(c.Redis(), memory.NewRateLimiter())
+}
+
+// APIKeyCache returns the cache of verified API keys shared by every replica
+func (c *Container) APIKeyCache() ports.APIKeyCache {
+	if c.inMemoryStorage() {
+		return c.memory().apiKeyCache
+	}
+	return cache.NewRedisAPIKeyCache(c.Redis())
+}
+
+// ProductService builds the product service
+func (c *Container) ProductService() *application.ProductService {
+	return application.NewProductService(application.ProductServiceDeps{
+		Products:     c.ProductRepository(),
+		Types:        c.ProductTypeRepository(),
+		Revisions:    c.ProductRevisionRepository(),
+		UnitOfWork:   c.ProductUnitOfWork(),
+		Prices:       c.PriceScheduleRepository(),
+		Watcher:      c.ProductWatcher(),
+		Cache:        c.ProductCache(),
+		Quotas:       c.TenantQuotas(),
+		Locales:      c.Localization(),
+		PriceLists:   c.PriceListRepository(),
+		Rates:        c.ExchangeRateRepository(),
+		BaseCurrency: c.Config.BaseCurrency,
+	})
+}
+
+// TenantQuotas returns the configured per-tenant limits
+func (c *Container) TenantQuotas() application.TenantQuotas {
+	return application.TenantQuotas{
+		Default:   c.Config.TenantProductQuota,
+		PerTenant: c.Config.TenantProductQuotas,
+	}
+}
+
+// Localization returns the configured default locale and translation locales
+func (c *Container) Localization() application.Localization {
+	return application.Localization{
+		DefaultLocale: c.Config.DefaultLocale,
+		Locales:       c.Config.Locales,
+	}
+}
+
+// TaxPolicy returns the configured relation of prices to tax
+func (c *Container) TaxPolicy() application.TaxPolicy {
+	return application.TaxPolicy{
+		PricesIncludeTax: c.Config.PricesIncludeTax,
+		Rounding:         c.Config.TaxRounding,
+	}
+}
+
+// PriceScheduleService builds the scheduled price change service
+func (c *Container) PriceScheduleService() *application.PriceScheduleService {
+	return application.NewPriceScheduleService(c.PriceScheduleRepository(), c.ProductRepository(), c.ProductCache(), c.EventPublisher())
+}
+
+// PriceListService builds the price list service
+func (c *Container) PriceListService() *application.PriceListService {
+	return application.NewPriceListService(c.PriceListRepository(), c.ProductRepository())
+}
+
+// ExchangeRateService builds the exchange rate import service
+func (c *Container) ExchangeRateService() *application.ExchangeRateService {
+	return application.NewExchangeRateService(c.ExchangeRateRepository(), c.ExchangeRateSource())
+}
+
+// BundleService builds the bundle service, which consumes product events to refresh bundles
+func (c *Container) BundleService() *application.BundleService {
+	return application.NewBundleService(c.ProductService(), c.InventoryRepository(), c.EventConsumer())
+}
+
+// TaxService builds the tax rate and price quote service
+func (c *Container) TaxService() *application.TaxService {
+	return application.NewTaxService(c.TaxRateRepository(), c.ProductService(), c.TaxPolicy())
+}
+
+// ProductTypeService builds the product type service
+func (c *Container) ProductTypeService() *application.ProductTypeService {
+	return application.NewProductTypeService(c.ProductTypeRepository(), c.ProductRepository())
+}
+
+// VariantService builds the product variant service
+func (c *Container) VariantService() *application.VariantService {
+	return application.NewVariantService(c.VariantRepository(), c.ProductRepository(), c.ProductService(), c.EventPublisher())
+}
+
+// CategoryService builds the category service
+func (c *Container) CategoryService() *application.CategoryService {
+	return application.NewCategoryService(c.CategoryRepository(), c.ProductRepository(), c.ProductService(), c.EventPublisher())
+}
+
+// InventoryService builds the inventory service
+func (c *Container) InventoryService() *application.InventoryService {
+	return application.NewInventoryService(c.InventoryRepository(), c.ReservationRepository(), c.EventPublisher())
+}
+
+// WebhookService builds the webhook subscription service
+func (c *Container) WebhookService() *application.WebhookService {
+	return application.NewWebhookService(c.WebhookRepository(), c.WebhookDeliveryRepository())
+}
+
+// WebhookDispatcher builds the dispatcher delivering events to webhook subscribers
+func (c *Container) WebhookDispatcher() *application.WebhookDispatcher {
+	return application.NewWebhookDispatcher(c.WebhookRepository(), c.WebhookDeliveryRepository(), c.PendingWebhookDeliveryRepository())
+}
+
+// MediaService builds the product media service
+func (c *Container) MediaService() *application.MediaService {
+	return application.NewMediaService(c.MediaRepository(), c.ProductRepository(), c.BlobStore(), c.EventPublisher(), c.MediaEventConsumer(), c.Config.MediaMaxBytes, c.Config.MediaMaxPixels)
+}
+
+// AuditService builds the audit log query service
+func (c *Container) AuditService() *application.AuditService {
+	return application.NewAuditService(c.AuditRepository())
+}
+
+// APIKeyService builds the API key service. Each call gets its own local cache, so a process
+// should build it once and share it between the middleware and the admin API.
+func (c *Container) APIKeyService() *application.APIKeyService {
+	return application.NewAPIKeyService(c.APIKeyRepository(), c.APIKeyCache(), memory.NewAPIKeyCache(), c.EventPublisher(), c.APIKeyRevocationConsumer())
+}
+
+// Close releases the connections opened so far
+func (c *Container) Close(ctx context.Context) {
+	if c.boltStore != nil {
+		c.stopRelay()
+		<-c.relayStopped
+		if err := c.boltStore.Close(); err != nil {
+			log.Printf("Failed to close bbolt database: %v", err)
+		}
+	}
+	if c.rabbitMQ != nil {
+		if err := c.rabbitMQ.Close(); err != nil {
+			log.Printf("Failed to close RabbitMQ connection: %v", err)
+		}
+	}
+	if c.redisClient != nil {
+		if err := c.redisClient.Close(); err != nil {
+			log.Printf("Failed to close Redis connection: %v", err)
+		}
+	}
+	if c.mongoDB != nil {
+		if err := db.CloseMongoInstance(ctx); err != nil {
+			log.Printf("Failed to close MongoDB connection: %v", err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/application"
+)
+
+// reservationSweepInterval is how often expired stock reservations are released
+const reservationSweepInterval = 30 * time.Second
+
+// priceSchedulerInterval is how often price schedules that started or ended are published
+const priceSchedulerInterval = 30 * time.Second
+
+// StartWorkers starts the background work that runs beside the product event consumer until ctx
+// is cancelled: webhook deliveries, the reservation reaper, the price and lifecycle schedulers, the
+// exchange rate importer and thumbnails. It returns the webhook dispatcher the deliveries are sent
+// by, whose Wait lets the attempts in flight finish.
+func (c *Container) StartWorkers(ctx context.Context) *application.WebhookDispatcher {
+	// Deliver product events to webhook subscribers, retrying from the queue of pending deliveries
+	dispatcher := c.WebhookDispatcher()
+	go dispatcher.RunDeliveryWorker(ctx)
+
+	// Release expired stock reservations
+	go c.InventoryService().RunReservationReaper(ctx, reservationSweepInterval)
+
+	// Publish price changes as price schedules start and end
+	go c.PriceScheduleService().RunPriceScheduler(ctx, priceSchedulerInterval)
+
+	// Activate and discontinue products as their scheduled dates come
+	go c.ProductService().RunLifecycleScheduler(ctx)
+
+	// Refresh exchange rates from the configured API, if any
+	go c.ExchangeRateService().RunExchangeRateImporter(ctx, c.Config.ExchangeRateRefresh)
+
+	// Generate thumbnails of uploaded product media
+	go c.MediaService().RunThumbnailWorker(ctx)
+
+	return dispatcher
+}
+
+// StartInProcessEventHandlers starts, when events never leave this process, the workers and the
+// handlers of product events that cmd/event runs otherwise: bundles are refreshed and webhook
+// deliveries queued by their own subscribers of the in-process bus. It does nothing when events go
+// through RabbitMQ.
+func (c *Container) StartInProcessEventHandlers(ctx context.Context) {
+	if !c.InProcessEvents() {
+		return
+	}
+
+	dispatcher := c.StartWorkers(ctx)
+	go dispatcher.RunEventListener(ctx, c.EventConsumer())
+	go c.BundleService().RunBundleRefresher(ctx)
+}
@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// API key scopes. A write scope includes reading, and the admin scope includes everything.
+const (
+	ScopeCatalogRead  = "catalog:read"
+	ScopeCatalogWrite = "catalog:write"
+	ScopeAdmin        = "admin"
+)
+
+// APIKeyScopes lists every scope an API key may be granted
+var APIKeyScopes = []string{ScopeCatalogRead, ScopeCatalogWrite, ScopeAdmin}
+
+// APIKeyPrefix starts every API key, which tells keys apart from bearer tokens
+const APIKeyPrefix = "ck_"
+
+// APIKeyRevoked is the event type broadcast to every replica when an API key stops being valid
+const APIKeyRevoked = "api_key.revoked"
+
+// APIKey is a long-lived credential of a machine client such as a batch job. Only a hash of the key
+// is stored; the key itself is returned once, when it is created or rotated.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID   string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"` // Start of the key, to tell keys apart
+	Hash       string             `bson:"hash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes"`
+	ExpiresAt  *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	ReplacedBy string             `bson:"replaced_by,omitempty" json:"replaced_by,omitempty"` // ID of the key it was rotated to
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ValidAt reports whether the key authenticates requests at the given time
+func (k *APIKey) ValidAt(now time.Time) bool {
+	if k.RevokedAt != nil && !now.Before(*k.RevokedAt) {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// HasScope reports whether the key was granted scope, directly or through a broader scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		switch {
+		case granted == scope, granted == ScopeAdmin:
+			return true
+		case granted == ScopeCatalogWrite && scope == ScopeCatalogRead:
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyRevocation is the message broadcast when an API key is revoked or rotated, so every
+// replica drops it from its local cache
+type APIKeyRevocation struct {
+	ID   string `json:"id"`
+	Hash string `json:"hash"`
+}
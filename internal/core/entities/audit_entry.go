@@ -0,0 +1,141 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditResourceProduct is the resource type of audit entries recording product writes
+const AuditResourceProduct = "product"
+
+// AuditEntry is an append-only record of a write, kept for compliance. Each tenant's entries form a
+// hash chain: every entry is numbered and hashed together with the hash of the entry before it, so
+// editing, removing or reordering entries breaks the chain from that point on.
+type AuditEntry struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	// Sequence numbers the entries of a tenant from 1
+	Sequence int64 `bson:"sequence" json:"sequence"`
+	// Principal is who the write was authenticated as, as ports.RequestInfo describes, and empty for
+	// unauthenticated writes. Actor is the name the caller gave for itself and only informational.
+	Principal string `bson:"principal,omitempty" json:"principal"`
+	Actor     string `bson:"actor" json:"actor"`
+	// Operation is one of the revision operations: create, update, delete or rollback
+	Operation    string        `bson:"operation" json:"operation"`
+	ResourceType string        `bson:"resource_type" json:"resource_type"`
+	ResourceID   string        `bson:"resource_id" json:"resource_id"`
+	Changes      []FieldChange `bson:"changes,omitempty" json:"changes,omitempty"`
+	// Transport is the source of the write: http, grpc, import or internal
+	Transport string    `bson:"transport" json:"transport"`
+	IP        string    `bson:"ip,omitempty" json:"ip,omitempty"`
+	RequestID string    `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	PrevHash  string    `bson:"prev_hash" json:"prev_hash"`
+	Hash      string    `bson:"hash" json:"hash"`
+}
+
+// Seal numbers the entry after prev, the latest entry of its tenant or nil for the first one,
+// and computes its hash. Times are kept to the millisecond, as stored by MongoDB.
+func (e *AuditEntry) Seal(prev *AuditEntry) {
+	e.Sequence = 1
+	e.PrevHash = ""
+	if prev != nil {
+		e.Sequence = prev.Sequence + 1
+		e.PrevHash = prev.Hash
+	}
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Millisecond)
+	e.Hash = e.ComputeHash()
+}
+
+// ComputeHash returns the SHA-256 of the entry's fields and the hash of the previous entry.
+// Change values are hashed in a canonical JSON form, so an entry hashes the same before
+// and after a round trip through BSON. An empty principal is left out, so entries written
+// before principals were recorded keep their hash.
+func (e *AuditEntry) ComputeHash() string {
+	changes := make([]auditHashedChange, len(e.Changes))
+	for i, change := range e.Changes {
+		changes[i] = auditHashedChange{Field: change.Field, From: canonical(change.From), To: canonical(change.To)}
+	}
+
+	// Marshalling cannot fail: every value is a string, number, bool, nil, slice or string-keyed map
+	data, _ := json.Marshal(auditHashed{
+		TenantID:     e.TenantID,
+		Sequence:     e.Sequence,
+		Principal:    e.Principal,
+		Actor:        e.Actor,
+		Operation:    e.Operation,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		Changes:      changes,
+		Transport:    e.Transport,
+		IP:           e.IP,
+		RequestID:    e.RequestID,
+		CreatedAt:    e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:     e.PrevHash,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// auditHashed is the part of an AuditEntry covered by its hash, in a fixed field order
+type auditHashed struct {
+	TenantID     string              `json:"tenant_id"`
+	Sequence     int64               `json:"sequence"`
+	Principal    string              `json:"principal,omitempty"`
+	Actor        string              `json:"actor"`
+	Operation    string              `json:"operation"`
+	ResourceType string              `json:"resource_type"`
+	ResourceID   string              `json:"resource_id"`
+	Changes      []auditHashedChange `json:"changes"`
+	Transport    string              `json:"transport"`
+	IP           string              `json:"ip"`
+	RequestID    string              `json:"request_id"`
+	CreatedAt    string              `json:"created_at"`
+	PrevHash     string              `json:"prev_hash"`
+}
+
+// auditHashedChange is a FieldChange with canonical values
+type auditHashedChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// canonical converts the BSON documents and arrays a value may have been decoded to into the
+// maps and slices it was built from. BSON stores float32 values such as prices as doubles, so they
+// are widened the same way; other numbers need no conversion, as JSON encodes 5, int32(5) and 5.0
+// alike.
+func canonical(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float32:
+		return float64(v)
+	case primitive.D:
+		m := make(map[string]interface{}, len(v))
+		for _, element := range v {
+			m[element.Key] = canonical(element.Value)
+		}
+		return m
+	case primitive.M:
+		return canonical(map[string]interface{}(v))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			m[key] = canonical(element)
+		}
+		return m
+	case primitive.A:
+		return canonical([]interface{}(v))
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, element := range v {
+			s[i] = canonical(element)
+		}
+		return s
+	default:
+		return value
+	}
+}
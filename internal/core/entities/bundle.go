@@ -0,0 +1,52 @@
+package entities
+
+// Bundle pricing modes
+const (
+	// BundlePriceSum prices a bundle at the sum of its components
+	BundlePriceSum = "sum"
+	// BundlePriceDiscount prices a bundle at the sum of its components less DiscountPercent
+	BundlePriceDiscount = "discount"
+	// BundlePriceFixed keeps the price set on the bundle product itself
+	BundlePriceFixed = "fixed"
+)
+
+// Bundle makes a product a gift set or kit sold as one unit and composed of other products.
+// Bundles hold no stock of their own: they are available as often as their components are.
+type Bundle struct {
+	Components []BundleComponent `bson:"components" json:"components"`
+	// Pricing is one of the BundlePrice constants
+	Pricing string `bson:"pricing" json:"pricing"`
+	// DiscountPercent is taken off the sum of the components with BundlePriceDiscount, e.g. 10 for 10%
+	DiscountPercent float64 `bson:"discount_percent,omitempty" json:"discount_percent,omitempty"`
+}
+
+// BundleComponent is a product contained in a bundle, and how many units of it one bundle holds
+type BundleComponent struct {
+	ProductID string `bson:"product_id" json:"product_id"`
+	Quantity  int    `bson:"quantity" json:"quantity"`
+}
+
+// BundleView is a bundle with the name, price and stock of its components, and how many units of
+// the bundle can be assembled from that stock
+type BundleView struct {
+	ProductID       string                `json:"product_id"`
+	Name            string                `json:"name"`
+	Price           float32               `json:"price"`
+	Pricing         string                `json:"pricing"`
+	DiscountPercent float64               `json:"discount_percent,omitempty"`
+	Available       int64                 `json:"available"`
+	Components      []BundleComponentView `json:"components"`
+}
+
+// BundleComponentView is one component of a bundle. Available is its stock in every warehouse, or
+// the availability of a nested bundle; Sets is how many bundles that is enough for. The component
+// with the fewest sets limits the bundle. Components the caller may not read are listed without
+// their name and price, and with nothing available.
+type BundleComponentView struct {
+	ProductID string  `json:"product_id"`
+	Name      string  `json:"name"`
+	Price     float32 `json:"price"`
+	Quantity  int     `json:"quantity"`
+	Available int64   `json:"available"`
+	Sets      int64   `json:"sets"`
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category event types, published as RabbitMQ routing keys
+const (
+	CategoryCreated = "category.created"
+	CategoryUpdated = "category.updated"
+	CategoryMoved   = "category.moved"
+	CategoryDeleted = "category.deleted"
+)
+
+// Category represents a node in the product category tree.
+// Path is a materialized path of every ID from the root down to the category itself,
+// e.g. "/<root id>/<parent id>/<id>/", so a subtree is every path sharing a prefix.
+type Category struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID  string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	Name      string             `bson:"name" json:"name"`
+	ParentID  string             `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	Path      string             `bson:"path" json:"path"`
+	Depth     int                `bson:"depth" json:"depth"`
+	Position  int                `bson:"position" json:"position"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CategoryPath builds the materialized path of a category below parentPath
+func CategoryPath(parentPath string, id primitive.ObjectID) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + id.Hex() + "/"
+}
+
+// AncestorIDs returns the IDs of the category's ancestors, root first
+func (c *Category) AncestorIDs() []string {
+	ids := strings.Split(strings.Trim(c.Path, "/"), "/")
+	if len(ids) <= 1 {
+		return nil
+	}
+	return ids[:len(ids)-1]
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExchangeRate is the price of one unit of the Base currency in the Quote currency from EffectiveAt
+// until a later rate of the pair takes over. Rates are market data shared by every tenant, and
+// older rates are kept as their history.
+type ExchangeRate struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Base        string             `bson:"base" json:"base"`
+	Quote       string             `bson:"quote" json:"quote"`
+	Rate        float64            `bson:"rate" json:"rate"`
+	EffectiveAt time.Time          `bson:"effective_at" json:"effective_at"`
+	// Source is "file" for uploaded rates, or the URL they were fetched from
+	Source     string    `bson:"source" json:"source"`
+	ImportedAt time.Time `bson:"imported_at" json:"imported_at"`
+}
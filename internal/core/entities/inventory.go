@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultWarehouse is used when a stock operation does not name a warehouse
+const DefaultWarehouse = "default"
+
+// Inventory event types, published as RabbitMQ routing keys
+const (
+	StockLevelChanged = "inventory.stock_changed"
+	LowStock          = "inventory.low_stock"
+)
+
+// Reservation statuses
+const (
+	ReservationPending   = "pending"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+)
+
+// StockLevel represents the quantities of a product held in one warehouse
+type StockLevel struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID          string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	ProductID         string             `bson:"product_id" json:"product_id"`
+	Warehouse         string             `bson:"warehouse" json:"warehouse"`
+	OnHand            int64              `bson:"on_hand" json:"on_hand"`
+	Reserved          int64              `bson:"reserved" json:"reserved"`
+	LowStockThreshold int64              `bson:"low_stock_threshold" json:"low_stock_threshold"`
+	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Available returns the quantity that can still be reserved
+func (s *StockLevel) Available() int64 {
+	return s.OnHand - s.Reserved
+}
+
+// Reservation holds stock for a checkout until it is committed, released or expires
+type Reservation struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID  string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	ProductID string             `bson:"product_id" json:"product_id"`
+	Warehouse string             `bson:"warehouse" json:"warehouse"`
+	Quantity  int64              `bson:"quantity" json:"quantity"`
+	Status    string             `bson:"status" json:"status"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// StockEvent is published whenever a stock level changes
+type StockEvent struct {
+	ProductID string    `json:"product_id"`
+	Warehouse string    `json:"warehouse"`
+	OnHand    int64     `json:"on_hand"`
+	Reserved  int64     `json:"reserved"`
+	Available int64     `json:"available"`
+	Reason    string    `json:"reason"`
+	Threshold int64     `json:"threshold,omitempty"`
+	At        time.Time `json:"at"`
+}
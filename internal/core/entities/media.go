@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MediaUploaded is published when a file is attached to a product, for the thumbnail worker
+const MediaUploaded = "media.uploaded"
+
+// MediaTypes lists the content types accepted for media, as sniffed from the uploaded bytes
+var MediaTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// ThumbnailSize names a thumbnail and the square, in pixels, it is scaled down to fit
+type ThumbnailSize struct {
+	Name  string
+	Bound int
+}
+
+// ThumbnailSizes are the thumbnails generated for every image, from the smallest
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "small", Bound: 150},
+	{Name: "medium", Bound: 400},
+	{Name: "large", Bound: 800},
+}
+
+// Media is a file attached to a product, such as a product photo. The file itself is kept in a
+// blob store under BlobKey; uploads with the same content share the blob within a tenant.
+type Media struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID    string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	ProductID   string             `bson:"product_id" json:"product_id"`
+	Filename    string             `bson:"filename" json:"filename"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	SHA256      string             `bson:"sha256" json:"sha256"` // Hex encoded hash of the content
+	BlobKey     string             `bson:"blob_key" json:"-"`
+	// Position orders the media of a product from 0
+	Position int `bson:"position" json:"position"`
+	Width    int `bson:"width,omitempty" json:"width,omitempty"`
+	Height   int `bson:"height,omitempty" json:"height,omitempty"`
+	// Thumbnails are added by the thumbnail worker after the upload
+	Thumbnails []MediaThumbnail `bson:"thumbnails,omitempty" json:"thumbnails,omitempty"`
+	CreatedAt  time.Time        `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time        `bson:"updated_at" json:"updated_at"`
+}
+
+// Thumbnail returns the thumbnail of the given size, or nil when there is none
+func (m *Media) Thumbnail(size string) *MediaThumbnail {
+	for i := range m.Thumbnails {
+		if m.Thumbnails[i].Size == size {
+			return &m.Thumbnails[i]
+		}
+	}
+	return nil
+}
+
+// MediaThumbnail is a scaled down copy of an image, fitting the square of one of the ThumbnailSizes
+type MediaThumbnail struct {
+	Size        string `bson:"size" json:"size"` // small, medium or large
+	Width       int    `bson:"width" json:"width"`
+	Height      int    `bson:"height" json:"height"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	BlobKey     string `bson:"blob_key" json:"-"`
+}
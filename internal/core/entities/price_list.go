@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PriceList holds the prices of products in one currency for a market or customer group.
+// Products without an explicit price in the list are converted from the base currency.
+type PriceList struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	// Code names the price list in product reads, e.g. eu-retail. It is unique within the tenant.
+	Code string `bson:"code" json:"code"`
+	Name string `bson:"name" json:"name"`
+	// Currency is the ISO 4217 code of every price of the list
+	Currency      string `bson:"currency" json:"currency"`
+	Market        string `bson:"market,omitempty" json:"market,omitempty"`
+	CustomerGroup string `bson:"customer_group,omitempty" json:"customer_group,omitempty"`
+	// Prices holds the explicit prices of the list by product ID
+	Prices    map[string]float32 `bson:"prices,omitempty" json:"prices,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// How the price of ProductPricing was derived
+const (
+	// PriceFromBase is the effective price, which is already in the requested currency
+	PriceFromBase = "base"
+	// PriceFromList is an explicit price of the price list
+	PriceFromList = "price_list"
+	// PriceFromConversion is the effective price converted from the base currency with an exchange rate
+	PriceFromConversion = "conversion"
+)
+
+// ProductPricing is the price of a product in a price list or currency and how it was derived
+type ProductPricing struct {
+	Price    float32 `json:"price"`
+	Currency string  `json:"currency"`
+	// Source is one of the PriceFrom constants
+	Source    string `json:"source"`
+	PriceList string `json:"price_list,omitempty"`
+	// BasePrice is the effective price of the product in BaseCurrency
+	BasePrice    float32 `json:"base_price"`
+	BaseCurrency string  `json:"base_currency"`
+	// ExchangeRate is the number of Currency units per BaseCurrency unit a conversion used,
+	// in effect since RateEffectiveAt
+	ExchangeRate    float64    `json:"exchange_rate,omitempty"`
+	RateEffectiveAt *time.Time `json:"rate_effective_at,omitempty"`
+}
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductPriceChanged is published when the effective price of a product changes
+// because a price schedule started, ended or was changed
+const ProductPriceChanged = "product.price_changed"
+
+// PriceSchedule overrides the price of a product from StartsAt until EndsAt.
+// When schedules overlap, the one with the highest priority applies.
+type PriceSchedule struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID  string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	ProductID string             `bson:"product_id" json:"product_id"`
+	Price     float32            `bson:"price" json:"price"`
+	StartsAt  time.Time          `bson:"starts_at" json:"starts_at"`
+	// EndsAt is exclusive: the schedule no longer applies at that instant
+	EndsAt    time.Time `bson:"ends_at" json:"ends_at"`
+	Priority  int       `bson:"priority" json:"priority"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// ActiveAt reports whether the schedule applies at t
+func (s *PriceSchedule) ActiveAt(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Overlaps reports whether both schedules apply at some instant
+func (s *PriceSchedule) Overlaps(other *PriceSchedule) bool {
+	return s.StartsAt.Before(other.EndsAt) && other.StartsAt.Before(s.EndsAt)
+}
+
+// ConflictsWith reports whether other is another schedule of the same product and priority that
+// overlaps the schedule. A higher priority schedule may cover part of a lower one, such as a flash
+// sale during a weekly promotion, but schedules of the same priority may not.
+func (s *PriceSchedule) ConflictsWith(other *PriceSchedule) bool {
+	return other.ID != s.ID && other.ProductID == s.ProductID && other.Priority == s.Priority && s.Overlaps(other)
+}
+
+// PriceChangedEvent is published whenever the effective price of a product changes
+type PriceChangedEvent struct {
+	ProductID string  `json:"product_id"`
+	Price     float32 `json:"price"`
+	BasePrice float32 `json:"base_price"`
+	// ScheduleID is the schedule now in effect, empty when the base price applies again
+	ScheduleID string    `json:"schedule_id,omitempty"`
+	At         time.Time `json:"at"`
+}
@@ -8,9 +8,43 @@ import (
 
 // Product represents a product entity in the system
 type Product struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name      string             `bson:"name" json:"name"`
-	Price     float32            `bson:"price" json:"price"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// TenantID is the tenant owning the product. Repositories set it from the context on every write.
+	TenantID    string          `bson:"tenant_id" json:"tenant_id,omitempty"`
+	Name        string          `bson:"name" json:"name"`
+	Description string          `bson:"description,omitempty" json:"description,omitempty"`
+	Price       float32         `bson:"price" json:"price"`
+	CategoryIDs []string        `bson:"category_ids,omitempty" json:"category_ids,omitempty"`
+	Options     []ProductOption `bson:"options,omitempty" json:"options,omitempty"`
+	TypeID      string          `bson:"type_id" json:"type_id,omitempty"`
+	// Attributes holds the values of the custom attributes defined by the product type
+	Attributes map[string]interface{} `bson:"attributes" json:"attributes,omitempty"`
+	// Translations holds the name and description in other locales, keyed by canonical BCP 47 tag
+	Translations map[string]ProductTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	// TaxClass selects the tax rates of the product in every jurisdiction, DefaultTaxClass when empty
+	TaxClass string `bson:"tax_class,omitempty" json:"tax_class,omitempty"`
+	// Bundle lists the components of a bundle product, nil for other products. The price of a bundle
+	// derived from its components is kept in Price and refreshed when a component changes.
+	Bundle *Bundle `bson:"bundle,omitempty" json:"bundle,omitempty"`
+	// Status is the lifecycle status, one of the ProductStatus constants. It only changes through
+	// transitions: SubmittedBy is the principal who last submitted the product for review and
+	// ApprovedBy the other principal who approved it.
+	Status      string `bson:"status,omitempty" json:"status,omitempty"`
+	SubmittedBy string `bson:"submitted_by,omitempty" json:"submitted_by,omitempty"`
+	ApprovedBy  string `bson:"approved_by,omitempty" json:"approved_by,omitempty"`
+	// ActivateAt schedules an approved product in review to become active, and DeactivateAt an
+	// active product to be discontinued
+	ActivateAt   *time.Time `bson:"activate_at,omitempty" json:"activate_at,omitempty"`
+	DeactivateAt *time.Time `bson:"deactivate_at,omitempty" json:"deactivate_at,omitempty"`
+	// Name and Description are stored in the default locale. Locale is the locale they were picked
+	// in when the product was localized for a client, and like EffectivePrice it is never stored.
+	Locale string `bson:"-" json:"locale,omitempty"`
+	// Pricing is the price in the price list or currency the product was read with, with how it
+	// was derived. It is resolved when the product is read and never stored.
+	Pricing *ProductPricing `bson:"-" json:"pricing,omitempty"`
+	// EffectivePrice is Price with the active price schedule applied. It is resolved when the
+	// product is read, never stored, and unset where prices are not resolved.
+	EffectivePrice *float32  `bson:"-" json:"effective_price,omitempty"`
+	CreatedAt      time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `bson:"updated_at" json:"updated_at"`
 }
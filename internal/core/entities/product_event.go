@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// Product event types. RabbitMQ routing keys prefix them with the tenant, e.g. "acme.product.created".
+const (
+	ProductCreated = "product.created"
+	ProductUpdated = "product.updated"
+	ProductDeleted = "product.deleted"
+)
+
+// ProductEvent represents a change notification for a product
+type ProductEvent struct {
+	Type        string    `json:"type"`
+	TenantID    string    `json:"tenant_id,omitempty"`
+	ProductID   string    `json:"product_id"`
+	Product     *Product  `json:"product,omitempty"`
+	ResumeToken string    `json:"resume_token,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
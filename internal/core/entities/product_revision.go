@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Revision operations
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionDelete   = "delete"
+	RevisionRollback = "rollback"
+)
+
+// ProductRevision is an append-only record of one product mutation. Revisions of a product
+// are numbered from 1 in the order they were written.
+type ProductRevision struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID  string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	ProductID string             `bson:"product_id" json:"product_id"`
+	Revision  int64              `bson:"revision" json:"revision"`
+	// Operation is one of create, update, delete or rollback
+	Operation string `bson:"operation" json:"operation"`
+	// Snapshot is the full product after the mutation, or right before it for deletes
+	Snapshot *Product      `bson:"snapshot" json:"snapshot"`
+	Changes  []FieldChange `bson:"changes,omitempty" json:"changes,omitempty"`
+	// RestoredRevision is the revision whose snapshot a rollback restored
+	RestoredRevision int64     `bson:"restored_revision,omitempty" json:"restored_revision,omitempty"`
+	Actor            string    `bson:"actor" json:"actor"`
+	RequestID        string    `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	CreatedAt        time.Time `bson:"created_at" json:"created_at"`
+}
+
+// State returns the product as it was right after the revision, or nil when the revision deleted it
+func (r *ProductRevision) State() *Product {
+	if r.Operation == RevisionDelete {
+		return nil
+	}
+	return r.Snapshot
+}
+
+// FieldChange is the change of one product field, such as price or attributes.color.
+// From is unset for added fields and To for removed ones.
+type FieldChange struct {
+	Field string      `bson:"field" json:"field"`
+	From  interface{} `bson:"from,omitempty" json:"from,omitempty"`
+	To    interface{} `bson:"to,omitempty" json:"to,omitempty"`
+}
@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// Product lifecycle statuses. Products are created as drafts, and only active products are shown to
+// readers without the admin scope.
+const (
+	ProductStatusDraft        = "draft"
+	ProductStatusInReview     = "review"
+	ProductStatusActive       = "active"
+	ProductStatusDiscontinued = "discontinued"
+	ProductStatusArchived     = "archived"
+)
+
+// ProductStatuses lists every lifecycle status in lifecycle order
+var ProductStatuses = []string{ProductStatusDraft, ProductStatusInReview, ProductStatusActive, ProductStatusDiscontinued, ProductStatusArchived}
+
+// ProductStatusChanged is published when a product moves from one lifecycle status to another
+const ProductStatusChanged = "product.status_changed"
+
+// ProductStatusChangedEvent is published whenever the lifecycle status of a product changes
+type ProductStatusChangedEvent struct {
+	ProductID string `json:"product_id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	// Actor made the transition; scheduled transitions are made by the internal scheduler
+	Actor      string    `json:"actor"`
+	ApprovedBy string    `json:"approved_by,omitempty"`
+	Scheduled  bool      `json:"scheduled,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// LifecycleStatus returns the lifecycle status of the product. Products stored before statuses
+// existed have none and count as active.
+func (p *Product) LifecycleStatus() string {
+	if p.Status == "" {
+		return ProductStatusActive
+	}
+	return p.Status
+}
+
+// ScheduledStatus returns the status the activation and deactivation dates of the product move it to
+// at t, or "" when none is due. Approved products in review become active once ActivateAt has come,
+// and active products are discontinued once DeactivateAt has.
+func (p *Product) ScheduledStatus(t time.Time) string {
+	switch p.LifecycleStatus() {
+	case ProductStatusInReview:
+		if p.ApprovedBy != "" && p.ActivateAt != nil && !t.Before(*p.ActivateAt) {
+			return ProductStatusActive
+		}
+	case ProductStatusActive:
+		if p.DeactivateAt != nil && !t.Before(*p.DeactivateAt) {
+			return ProductStatusDiscontinued
+		}
+	}
+	return ""
+}
@@ -0,0 +1,18 @@
+package entities
+
+// ProductTranslation holds the text of a product in one locale. Empty fields fall back to the
+// next locale of the fallback chain.
+type ProductTranslation struct {
+	Name        string `bson:"name,omitempty" json:"name,omitempty"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+// MissingTranslation reports the text fields a product lacks in one locale
+type MissingTranslation struct {
+	ProductID string `json:"product_id"`
+	// Name is the name of the product in the default locale
+	Name   string `json:"name"`
+	Locale string `json:"locale"`
+	// Fields lists name, description or both
+	Fields []string `json:"fields"`
+}
@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Attribute value types supported by product type schemas
+const (
+	AttributeString  = "string"
+	AttributeNumber  = "number"
+	AttributeEnum    = "enum"
+	AttributeBoolean = "boolean"
+	AttributeDate    = "date" // Stored as YYYY-MM-DD
+)
+
+// AttributeDateLayout is the layout attribute dates are stored in, so they sort as strings
+const AttributeDateLayout = "2006-01-02"
+
+// AttributeDefinition describes one typed custom attribute of a product type.
+// Min and Max bound the length of a string and the value of a number, Pattern is a regular
+// expression strings must match, and Values lists the choices of an enum.
+type AttributeDefinition struct {
+	Name     string   `bson:"name" json:"name"`
+	Type     string   `bson:"type" json:"type"`
+	Required bool     `bson:"required" json:"required"`
+	Min      *float64 `bson:"min,omitempty" json:"min,omitempty"`
+	Max      *float64 `bson:"max,omitempty" json:"max,omitempty"`
+	Pattern  string   `bson:"pattern,omitempty" json:"pattern,omitempty"`
+	Values   []string `bson:"values,omitempty" json:"values,omitempty"`
+}
+
+// ProductType groups products that share a set of custom attributes, such as electronics or books
+type ProductType struct {
+	ID         primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	TenantID   string                `bson:"tenant_id" json:"tenant_id,omitempty"`
+	Name       string                `bson:"name" json:"name"`
+	Attributes []AttributeDefinition `bson:"attributes" json:"attributes"`
+	CreatedAt  time.Time             `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time             `bson:"updated_at" json:"updated_at"`
+}
+
+// Attribute returns the definition of the named attribute, or nil when the type does not define it
+func (t *ProductType) Attribute(name string) *AttributeDefinition {
+	for i := range t.Attributes {
+		if t.Attributes[i].Name == name {
+			return &t.Attributes[i]
+		}
+	}
+	return nil
+}
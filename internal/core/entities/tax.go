@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultTaxClass is the tax class of products that have none
+const DefaultTaxClass = "standard"
+
+// TaxRate is the rate of a tax class in a jurisdiction from EffectiveFrom until a later rate of the
+// same jurisdiction and class takes over. Rates are set by law, so they are shared by every tenant,
+// and older rates are kept so past prices can still be explained.
+type TaxRate struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Jurisdiction is an ISO 3166-1 country code such as DE, or a subdivision such as US-CA
+	Jurisdiction string `bson:"jurisdiction" json:"jurisdiction"`
+	TaxClass     string `bson:"tax_class" json:"tax_class"`
+	// Name is the name of the tax shown to customers, such as VAT or GST
+	Name string `bson:"name,omitempty" json:"name,omitempty"`
+	// Percent is the rate in percent, e.g. 19 for 19%
+	Percent       float64   `bson:"percent" json:"percent"`
+	EffectiveFrom time.Time `bson:"effective_from" json:"effective_from"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+}
+
+// PriceQuote is the price of a quantity of a product in a region with and without tax
+type PriceQuote struct {
+	ProductID string `json:"product_id"`
+	Region    string `json:"region"`
+	// Jurisdiction is the jurisdiction whose rate applied: the region, or its country when the
+	// region has no rate of its own
+	Jurisdiction      string    `json:"jurisdiction"`
+	TaxClass          string    `json:"tax_class"`
+	TaxName           string    `json:"tax_name,omitempty"`
+	TaxPercent        float64   `json:"tax_percent"`
+	RateEffectiveFrom time.Time `json:"rate_effective_from"`
+	// Currency is the currency of the amounts, in which UnitPrice is listed
+	Currency  string  `json:"currency"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float32 `json:"unit_price"`
+	// PricesIncludeTax tells whether UnitPrice is the gross price, from which tax is backed out
+	PricesIncludeTax bool `json:"prices_include_tax"`
+	// Rounding is the rounding mode the amounts were rounded to the minor unit of the currency with
+	Rounding string  `json:"rounding"`
+	Net      float64 `json:"net"`
+	Tax      float64 `json:"tax"`
+	Gross    float64 `json:"gross"`
+}
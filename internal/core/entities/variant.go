@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Variant event types, published as RabbitMQ routing keys
+const (
+	VariantsGenerated = "variant.generated"
+	VariantsUpdated   = "variant.updated"
+)
+
+// ProductOption defines one axis of a product's variant matrix, such as size or color
+type ProductOption struct {
+	Name   string   `bson:"name" json:"name"`
+	Values []string `bson:"values" json:"values"`
+}
+
+// ProductVariant is one purchasable combination of a parent product's option values
+type ProductVariant struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID  string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	ProductID string             `bson:"product_id" json:"product_id"`
+	SKU       string             `bson:"sku" json:"sku"`
+	// Options maps each option name of the parent product to the value of this variant
+	Options map[string]string `bson:"options" json:"options"`
+	// Price overrides the parent product price when set
+	Price      *float32          `bson:"price,omitempty" json:"price,omitempty"`
+	Barcode    string            `bson:"barcode,omitempty" json:"barcode,omitempty"`
+	Attributes map[string]string `bson:"attributes,omitempty" json:"attributes,omitempty"`
+	Position   int               `bson:"position" json:"position"`
+	CreatedAt  time.Time         `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time         `bson:"updated_at" json:"updated_at"`
+}
+
+// EffectivePrice returns the variant price override, or the parent product price when there is none
+func (v *ProductVariant) EffectivePrice(product *Product) float32 {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return product.Price
+}
+
+// OptionKey identifies the option combination of a variant in the order the options are defined
+func (v *ProductVariant) OptionKey(options []ProductOption) string {
+	values := make([]string, len(options))
+	for i, option := range options {
+		values[i] = v.Options[option.Name]
+	}
+	return strings.Join(values, "/")
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookSubscription represents a partner endpoint that receives product events over HTTP
+type WebhookSubscription struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID     string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	URL          string             `bson:"url" json:"url"`
+	Secret       string             `bson:"secret" json:"secret,omitempty"`
+	EventTypes   []string           `bson:"event_types" json:"event_types"`
+	Active       bool               `bson:"active" json:"active"`
+	FailureCount int                `bson:"failure_count" json:"failure_count"`
+	DisabledAt   *time.Time         `bson:"disabled_at,omitempty" json:"disabled_at,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Subscribes reports whether the subscription wants events of the given type.
+// A subscription without event types receives everything.
+func (w *WebhookSubscription) Subscribes(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records a single attempt to deliver an event to a webhook subscription
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID       string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	SubscriptionID primitive.ObjectID `bson:"subscription_id" json:"subscription_id"`
+	EventID        string             `bson:"event_id" json:"event_id"`
+	EventType      string             `bson:"event_type" json:"event_type"`
+	Attempt        int                `bson:"attempt" json:"attempt"`
+	RequestHeaders map[string]string  `bson:"request_headers" json:"request_headers"`
+	RequestBody    string             `bson:"request_body" json:"request_body"`
+	ResponseStatus int                `bson:"response_status" json:"response_status"`
+	ResponseBody   string             `bson:"response_body" json:"response_body"`
+	Error          string             `bson:"error,omitempty" json:"error,omitempty"`
+	Success        bool               `bson:"success" json:"success"`
+	LatencyMs      int64              `bson:"latency_ms" json:"latency_ms"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// PendingWebhookDelivery is an event still to be delivered to a webhook subscription. It is stored
+// before the event is acknowledged, so deliveries and their retries survive restarts.
+type PendingWebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID       string             `bson:"tenant_id" json:"tenant_id,omitempty"`
+	SubscriptionID primitive.ObjectID `bson:"subscription_id" json:"subscription_id"`
+	EventID        string             `bson:"event_id" json:"event_id"`
+	EventType      string             `bson:"event_type" json:"event_type"`
+	Body           string             `bson:"body" json:"body"`
+	// Attempts counts the attempts made so far. NextAttemptAt is when the event is sent next; a
+	// claimed delivery is pushed back by a lease, so it is retried if its consumer dies.
+	Attempts      int       `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time `bson:"next_attempt_at" json:"next_attempt_at"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+}
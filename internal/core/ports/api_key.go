@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"test-go/internal/core/entities"
+)
+
+// apiKeyKey is the type of APIKeyKey, unexported so no other package can collide with it
+type apiKeyKey struct{}
+
+// APIKeyKey is the context key of the API key a request was authenticated with. It is exported for
+// frameworks that keep request values themselves, such as fiber's Locals; other callers use WithAPIKey.
+var APIKeyKey = apiKeyKey{}
+
+// WithAPIKey returns a copy of ctx authenticated with key
+func WithAPIKey(ctx context.Context, key *entities.APIKey) context.Context {
+	return context.WithValue(ctx, APIKeyKey, key)
+}
+
+// APIKeyFromContext returns the API key the request was authenticated with, or nil
+func APIKeyFromContext(ctx context.Context) *entities.APIKey {
+	key, _ := ctx.Value(APIKeyKey).(*entities.APIKey)
+	return key
+}
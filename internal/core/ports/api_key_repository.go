@@ -0,0 +1,51 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// APIKeyRepository defines the interface for API key data operations, scoped to the tenant of ctx
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.APIKey, error)
+	Update(ctx context.Context, key *entities.APIKey) error
+	FindAll(ctx context.Context) ([]*entities.APIKey, error)
+	// FindByHash returns the key with the given hash whatever its tenant, as the tenant of a
+	// request is only known once its key is found
+	FindByHash(ctx context.Context, hash string) (*entities.APIKey, error)
+	// TouchLastUsed records when a key of any tenant was last used
+	TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// APIKeyCache defines the interface for caching verified API keys by hash
+type APIKeyCache interface {
+	// Get returns the cached key, or ErrCacheMiss when it is not cached
+	Get(ctx context.Context, hash string) (*entities.APIKey, error)
+	// Set caches a key for ttl
+	Set(ctx context.Context, key *entities.APIKey, ttl time.Duration) error
+	Delete(ctx context.Context, hash string) error
+}
+
+// APIKeyRevocationConsumer defines the interface for receiving API key revocations. Every
+// process receives every revocation, so each can drop the key from its local cache.
+type APIKeyRevocationConsumer interface {
+	// ConsumeAPIKeyRevocations calls fn for every revocation until ctx is cancelled
+	ConsumeAPIKeyRevocations(ctx context.Context, fn func(context.Context, *entities.APIKeyRevocation) error) error
+}
+
+// APIKeyAuthenticator defines the interface for verifying the API keys presented by clients
+type APIKeyAuthenticator interface {
+	// Authenticate returns the stored key matching key, or ErrInvalidAPIKey when there is no
+	// such key or it is revoked or expired
+	Authenticate(ctx context.Context, key string) (*entities.APIKey, error)
+}
+
+// ErrAPIKeyNotFound is returned when an API key is not found in the repository
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// ErrInvalidAPIKey is returned when a presented API key is unknown, revoked or expired
+var ErrInvalidAPIKey = errors.New("invalid api key")
@@ -0,0 +1,51 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// AuditRepository defines the interface for the append-only audit log, scoped to the tenant of ctx.
+// Entries are never updated or deleted.
+type AuditRepository interface {
+	// Append seals the entry after the latest entry of the tenant and stores it
+	Append(ctx context.Context, entry *entities.AuditEntry) error
+	// Find returns up to filter.Limit matching entries in sequence order
+	Find(ctx context.Context, filter AuditFilter) ([]*entities.AuditEntry, error)
+	// Stream calls fn for every matching entry in sequence order, ignoring filter.Limit
+	Stream(ctx context.Context, filter AuditFilter, fn func(*entities.AuditEntry) error) error
+}
+
+// AuditFilter selects audit entries. Empty fields match every entry; From is inclusive and To
+// exclusive. AfterSequence pages through the log by skipping the entries up to that sequence.
+type AuditFilter struct {
+	Principal     string
+	Actor         string
+	Operation     string
+	ResourceType  string
+	ResourceID    string
+	Transport     string
+	From          time.Time
+	To            time.Time
+	AfterSequence int64
+	Limit         int64
+}
+
+// Matches reports whether an entry satisfies every set field of the filter
+func (f AuditFilter) Matches(entry *entities.AuditEntry) bool {
+	switch {
+	case f.Principal != "" && entry.Principal != f.Principal,
+		f.Actor != "" && entry.Actor != f.Actor,
+		f.Operation != "" && entry.Operation != f.Operation,
+		f.ResourceType != "" && entry.ResourceType != f.ResourceType,
+		f.ResourceID != "" && entry.ResourceID != f.ResourceID,
+		f.Transport != "" && entry.Transport != f.Transport,
+		!f.From.IsZero() && entry.CreatedAt.Before(f.From),
+		!f.To.IsZero() && !entry.CreatedAt.Before(f.To),
+		entry.Sequence <= f.AfterSequence:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// BlobStore defines the interface for storing files such as product media. Keys are slash
+// separated paths like "acme/<sha256>/original"; a key must not also be the directory of
+// other keys, which file systems cannot represent.
+type BlobStore interface {
+	// Put stores size bytes read from body under key, replacing any blob stored there
+	Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error
+	// Get returns the content of the blob, which the caller closes, or ErrBlobNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error)
+	// Delete removes the blob; deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// BlobInfo describes a stored blob
+type BlobInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// ValidBlobKey reports whether key is a relative slash separated path without empty, "." or ".."
+// segments, so it cannot escape the store's root whatever the backend
+func ValidBlobKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsRune(segment, '\\') {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrBlobNotFound is returned when no blob is stored under a key
+var ErrBlobNotFound = errors.New("blob not found")
+
+// ErrInvalidBlobKey is returned when a key is not accepted by ValidBlobKey
+var ErrInvalidBlobKey = errors.New("invalid blob key")
@@ -0,0 +1,158 @@
+package ports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BlobStoreFactory returns a blob store for a single conformance test. Stores backed by shared
+// storage, such as a bucket, need not be empty: every test writes under keys of its own.
+type BlobStoreFactory func(t *testing.T) BlobStore
+
+// RunBlobStoreConformance checks that a BlobStore implementation honours the contract every
+// implementation shares, so media can move between stores without changing behaviour. Call it
+// from a test of the implementation's package:
+//
+//	func TestFilesystemBlobStore(t *testing.T) {
+//		ports.RunBlobStoreConformance(t, func(t *testing.T) ports.BlobStore {
+//			store, err := blob.NewFilesystemBlobStore(t.TempDir())
+//			...
+//		})
+//	}
+func RunBlobStoreConformance(t *testing.T, newStore BlobStoreFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store BlobStore, prefix string)
+	}{
+		{"PutAndGet", testBlobPutAndGet},
+		{"PutReplaces", testBlobPutReplaces},
+		{"Exists", testBlobExists},
+		{"Delete", testBlobDelete},
+		{"NotFound", testBlobNotFound},
+		{"InvalidKeys", testBlobInvalidKeys},
+		{"SiblingKeys", testBlobSiblingKeys},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t), primitive.NewObjectID().Hex())
+		})
+	}
+}
+
+func testBlobPutAndGet(t *testing.T, store BlobStore, prefix string) {
+	content := []byte("\x89PNG\r\n\x1a\n not really an image")
+	mustPutBlob(t, store, prefix+"/original", "image/png", content)
+
+	got, info := mustGetBlob(t, store, prefix+"/original")
+	if !bytes.Equal(got, content) {
+		t.Fatalf("Get returned %q, want %q", got, content)
+	}
+	if info.Size != int64(len(content)) || info.ContentType != "image/png" {
+		t.Fatalf("Get returned info %+v, want size %d and type image/png", info, len(content))
+	}
+}
+
+func testBlobPutReplaces(t *testing.T, store BlobStore, prefix string) {
+	mustPutBlob(t, store, prefix+"/original", "image/png", []byte("first version"))
+	mustPutBlob(t, store, prefix+"/original", "image/jpeg", []byte("second"))
+
+	got, info := mustGetBlob(t, store, prefix+"/original")
+	if string(got) != "second" || info.Size != 6 || info.ContentType != "image/jpeg" {
+		t.Fatalf("Get after a second Put returned %q with %+v, want the second blob", got, info)
+	}
+}
+
+func testBlobExists(t *testing.T, store BlobStore, prefix string) {
+	ctx := context.Background()
+	key := prefix + "/original"
+
+	if exists, err := store.Exists(ctx, key); err != nil || exists {
+		t.Fatalf("Exists before Put = %v, %v; want false, nil", exists, err)
+	}
+	mustPutBlob(t, store, key, "image/png", []byte("content"))
+	if exists, err := store.Exists(ctx, key); err != nil || !exists {
+		t.Fatalf("Exists after Put = %v, %v; want true, nil", exists, err)
+	}
+}
+
+func testBlobDelete(t *testing.T, store BlobStore, prefix string) {
+	ctx := context.Background()
+	key := prefix + "/original"
+	mustPutBlob(t, store, key, "image/png", []byte("content"))
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, err := store.Exists(ctx, key); err != nil || exists {
+		t.Fatalf("Exists after Delete = %v, %v; want false, nil", exists, err)
+	}
+	if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("Get after Delete returned %v, want ErrBlobNotFound", err)
+	}
+	// Deleting again is not an error
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of a missing blob: %v", err)
+	}
+}
+
+func testBlobNotFound(t *testing.T, store BlobStore, prefix string) {
+	if _, _, err := store.Get(context.Background(), prefix+"/missing"); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("Get of a missing blob returned %v, want ErrBlobNotFound", err)
+	}
+}
+
+func testBlobInvalidKeys(t *testing.T, store BlobStore, prefix string) {
+	ctx := context.Background()
+	for _, key := range []string{"", "/" + prefix, prefix + "/../escape", prefix + "//double", prefix + "/./dot", prefix + "/"} {
+		if err := store.Put(ctx, key, "image/png", bytes.NewReader([]byte("x")), 1); !errors.Is(err, ErrInvalidBlobKey) {
+			t.Errorf("Put(%q) returned %v, want ErrInvalidBlobKey", key, err)
+		}
+		if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrInvalidBlobKey) {
+			t.Errorf("Get(%q) returned %v, want ErrInvalidBlobKey", key, err)
+		}
+	}
+}
+
+func testBlobSiblingKeys(t *testing.T, store BlobStore, prefix string) {
+	ctx := context.Background()
+	mustPutBlob(t, store, prefix+"/original", "image/png", []byte("original"))
+	mustPutBlob(t, store, prefix+"/small", "image/png", []byte("small"))
+
+	if err := store.Delete(ctx, prefix+"/small"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got, _ := mustGetBlob(t, store, prefix+"/original"); string(got) != "original" {
+		t.Fatalf("deleting a sibling changed the blob to %q", got)
+	}
+}
+
+// mustPutBlob stores content under key, failing the test on error
+func mustPutBlob(t *testing.T, store BlobStore, key string, contentType string, content []byte) {
+	t.Helper()
+	if err := store.Put(context.Background(), key, contentType, bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("Put(%q): %v", key, err)
+	}
+	t.Cleanup(func() { _ = store.Delete(context.Background(), key) })
+}
+
+// mustGetBlob reads the blob stored under key, failing the test on error
+func mustGetBlob(t *testing.T, store BlobStore, key string) ([]byte, BlobInfo) {
+	t.Helper()
+	content, info, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%q): %v", key, err)
+	}
+	defer content.Close()
+
+	got, err := io.ReadAll(content)
+	if err != nil {
+		t.Fatalf("reading %q: %v", key, err)
+	}
+	return got, info
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+)
+
+// CategoryRepository defines the interface for category tree data operations
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entities.Category) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.Category, error)
+	Update(ctx context.Context, category *entities.Category) error
+	Delete(ctx context.Context, id string) error
+	FindAll(ctx context.Context) ([]*entities.Category, error)
+	// FindByIDs returns the categories with the given IDs, skipping unknown IDs
+	FindByIDs(ctx context.Context, ids []string) ([]*entities.Category, error)
+	// FindChildren returns the direct children of a category ordered by position; an empty parentID returns the roots
+	FindChildren(ctx context.Context, parentID string) ([]*entities.Category, error)
+	// FindDescendants returns every category below the given path, excluding the category at that path
+	FindDescendants(ctx context.Context, path string) ([]*entities.Category, error)
+	// MoveDescendants rewrites the paths of every category below oldPath to sit below newPath instead
+	MoveDescendants(ctx context.Context, oldPath string, newPath string, depthDelta int) error
+}
+
+// ErrCategoryNotFound is returned when a category is not found in the repository
+var ErrCategoryNotFound = errors.New("category not found")
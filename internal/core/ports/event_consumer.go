@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"test-go/internal/core/entities"
+)
+
+// EventConsumer defines the interface for receiving product events from a message broker
+type EventConsumer interface {
+	// ConsumeProductEvents calls fn for every product event until ctx is cancelled
+	ConsumeProductEvents(ctx context.Context, fn func(context.Context, *entities.ProductEvent) error) error
+}
@@ -0,0 +1,6 @@
+package ports
+
+// EventPublisher defines the interface for publishing domain events to a message broker
+type EventPublisher interface {
+	Publish(routingKey string, message interface{}) error
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// ExchangeRateRepository stores exchange rates with their history. Rates are market data shared
+// by every tenant, so unlike the catalog repositories it ignores the tenant of the context.
+type ExchangeRateRepository interface {
+	// Save stores rates, replacing the rate of the same pair and effective time
+	Save(ctx context.Context, rates []*entities.ExchangeRate) error
+	// FindRate returns the rate of the pair in effect at the given time, or ErrExchangeRateNotFound
+	FindRate(ctx context.Context, base string, quote string, at time.Time) (*entities.ExchangeRate, error)
+	// FindLatest returns the rate of every pair in effect at the given time, ordered by pair
+	FindLatest(ctx context.Context, at time.Time) ([]*entities.ExchangeRate, error)
+	// FindHistory returns the rates of the pair that took effect from from (inclusive) until to
+	// (exclusive), oldest first; zero times leave the range open
+	FindHistory(ctx context.Context, base string, quote string, from time.Time, to time.Time) ([]*entities.ExchangeRate, error)
+}
+
+// ErrExchangeRateNotFound is returned when there is no rate for a currency pair at the time asked for
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRateSource fetches the current exchange rates from an external API as a JSON document
+// with a base currency, a date and the rate of every other currency:
+//
+//	{"base": "USD", "date": "2024-05-31", "rates": {"EUR": 0.92, "IDR": 16250}}
+type ExchangeRateSource interface {
+	Fetch(ctx context.Context) ([]byte, error)
+	// Name identifies the source in the stored rates, such as its URL
+	Name() string
+}
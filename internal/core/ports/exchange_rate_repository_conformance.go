@@ -0,0 +1,148 @@
+package ports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// ExchangeRateRepositoryFactory returns an empty exchange rate repository for a single conformance test
+type ExchangeRateRepositoryFactory func(t *testing.T) ExchangeRateRepository
+
+// RunExchangeRateRepositoryConformance checks that an ExchangeRateRepository implementation honours
+// the contract every implementation shares, so prices convert alike whatever the storage backend:
+//
+//	func TestMemoryExchangeRateRepository(t *testing.T) {
+//		ports.RunExchangeRateRepositoryConformance(t, func(t *testing.T) ports.ExchangeRateRepository {
+//			return memory.NewExchangeRateRepository()
+//		})
+//	}
+func RunExchangeRateRepositoryConformance(t *testing.T, newRepo ExchangeRateRepositoryFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo ExchangeRateRepository)
+	}{
+		{"FindRateInEffect", testExchangeRateInEffect},
+		{"SaveReplacesSameEffectiveTime", testExchangeRateSaveReplaces},
+		{"FindLatest", testExchangeRateFindLatest},
+		{"FindHistory", testExchangeRateFindHistory},
+		{"NotFound", testExchangeRateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepo(t))
+		})
+	}
+}
+
+// rateDay is the start of a day in UTC, at the millisecond precision every store keeps
+func rateDay(day int) time.Time {
+	return time.Date(2024, time.May, day, 0, 0, 0, 0, time.UTC)
+}
+
+func testExchangeRateInEffect(t *testing.T, repo ExchangeRateRepository) {
+	mustSaveRates(t, repo, newRate("USD", "EUR", 0.90, rateDay(1)), newRate("USD", "EUR", 0.92, rateDay(10)))
+
+	for _, tt := range []struct {
+		at   time.Time
+		want float64
+	}{
+		{rateDay(1), 0.90},
+		{rateDay(9), 0.90},
+		{rateDay(10), 0.92},
+		{rateDay(20), 0.92},
+	} {
+		rate, err := repo.FindRate(context.Background(), "USD", "EUR", tt.at)
+		if err != nil {
+			t.Fatalf("FindRate at %s: %v", tt.at, err)
+		}
+		if rate.Rate != tt.want {
+			t.Fatalf("FindRate at %s = %v, want %v", tt.at, rate.Rate, tt.want)
+		}
+	}
+}
+
+func testExchangeRateSaveReplaces(t *testing.T, repo ExchangeRateRepository) {
+	mustSaveRates(t, repo, newRate("USD", "EUR", 0.90, rateDay(1)))
+	mustSaveRates(t, repo, newRate("USD", "EUR", 0.91, rateDay(1)))
+
+	history, err := repo.FindHistory(context.Background(), "USD", "EUR", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("FindHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Rate != 0.91 {
+		t.Fatalf("FindHistory after saving the same effective time twice = %+v, want the second rate only", history)
+	}
+}
+
+func testExchangeRateFindLatest(t *testing.T, repo ExchangeRateRepository) {
+	mustSaveRates(t, repo,
+		newRate("USD", "IDR", 16000, rateDay(1)),
+		newRate("USD", "EUR", 0.90, rateDay(1)),
+		newRate("USD", "EUR", 0.92, rateDay(10)),
+		newRate("USD", "JPY", 157, rateDay(15)),
+	)
+
+	latest, err := repo.FindLatest(context.Background(), rateDay(12))
+	if err != nil {
+		t.Fatalf("FindLatest: %v", err)
+	}
+	if len(latest) != 2 {
+		t.Fatalf("FindLatest returned %d rates, want USD/EUR and USD/IDR", len(latest))
+	}
+	if latest[0].Quote != "EUR" || latest[0].Rate != 0.92 || latest[1].Quote != "IDR" {
+		t.Fatalf("FindLatest = %s %v, %s %v; want EUR 0.92 then IDR", latest[0].Quote, latest[0].Rate, latest[1].Quote, latest[1].Rate)
+	}
+	if !latest[0].EffectiveAt.Equal(rateDay(10)) {
+		t.Fatalf("FindLatest effective at %s, want %s", latest[0].EffectiveAt, rateDay(10))
+	}
+}
+
+func testExchangeRateFindHistory(t *testing.T, repo ExchangeRateRepository) {
+	mustSaveRates(t, repo,
+		newRate("USD", "EUR", 0.92, rateDay(10)),
+		newRate("USD", "EUR", 0.90, rateDay(1)),
+		newRate("USD", "EUR", 0.93, rateDay(20)),
+		newRate("EUR", "USD", 1.08, rateDay(10)),
+	)
+
+	history, err := repo.FindHistory(context.Background(), "USD", "EUR", rateDay(1), rateDay(20))
+	if err != nil {
+		t.Fatalf("FindHistory: %v", err)
+	}
+	if len(history) != 2 || history[0].Rate != 0.90 || history[1].Rate != 0.92 {
+		t.Fatalf("FindHistory from day 1 until day 20 = %+v, want 0.90 then 0.92", history)
+	}
+}
+
+func testExchangeRateNotFound(t *testing.T, repo ExchangeRateRepository) {
+	mustSaveRates(t, repo, newRate("USD", "EUR", 0.92, rateDay(10)))
+
+	if _, err := repo.FindRate(context.Background(), "USD", "EUR", rateDay(9)); err != ErrExchangeRateNotFound {
+		t.Fatalf("FindRate before the first rate returned %v, want ErrExchangeRateNotFound", err)
+	}
+	if _, err := repo.FindRate(context.Background(), "EUR", "USD", rateDay(10)); err != ErrExchangeRateNotFound {
+		t.Fatalf("FindRate of the inverse pair returned %v, want ErrExchangeRateNotFound", err)
+	}
+}
+
+func newRate(base string, quote string, rate float64, effectiveAt time.Time) *entities.ExchangeRate {
+	return &entities.ExchangeRate{
+		Base:        base,
+		Quote:       quote,
+		Rate:        rate,
+		EffectiveAt: effectiveAt,
+		Source:      "file",
+		ImportedAt:  effectiveAt,
+	}
+}
+
+func mustSaveRates(t *testing.T, repo ExchangeRateRepository, rates ...*entities.ExchangeRate) {
+	t.Helper()
+	if err := repo.Save(context.Background(), rates); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+}
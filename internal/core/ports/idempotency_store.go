@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of a request made with an idempotency key
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyStore defines the interface for remembering responses to idempotent requests
+type IdempotencyStore interface {
+	// Reserve claims key for a request with the given hash and returns nil on success.
+	// If the key is already taken the existing record is returned instead.
+	Reserve(ctx context.Context, key string, requestHash string, lockTTL time.Duration) (*IdempotencyRecord, error)
+	// Complete stores the final response for a reserved key
+	Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	// Release frees a reserved key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
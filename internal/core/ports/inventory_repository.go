@@ -0,0 +1,51 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// InventoryRepository defines the interface for stock level data operations.
+// Every quantity change is a single conditional update, so concurrent callers can never
+// drive on-hand or available stock below zero.
+type InventoryRepository interface {
+	FindByProduct(ctx context.Context, productID string) ([]*entities.StockLevel, error)
+	// AdjustOnHand adds delta to the on-hand quantity, creating the stock level if needed
+	AdjustOnHand(ctx context.Context, productID string, warehouse string, delta int64) (*entities.StockLevel, error)
+	// SetLowStockThreshold changes the available quantity at which low-stock events fire
+	SetLowStockThreshold(ctx context.Context, productID string, warehouse string, threshold int64) (*entities.StockLevel, error)
+	// Reserve moves quantity from available to reserved
+	Reserve(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error)
+	// Release moves quantity from reserved back to available
+	Release(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error)
+	// Commit removes a reserved quantity from both reserved and on-hand
+	Commit(ctx context.Context, productID string, warehouse string, quantity int64) (*entities.StockLevel, error)
+}
+
+// ReservationRepository defines the interface for stock reservation data operations
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *entities.Reservation) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.Reservation, error)
+	// Transition atomically moves a reservation from one status to another. A pending reservation is
+	// only committed before it expires, failing with ErrReservationExpired afterwards.
+	Transition(ctx context.Context, id string, from string, to string) (*entities.Reservation, error)
+	// FindExpired returns pending reservations whose expiry is before now. It spans every tenant,
+	// for the reaper.
+	FindExpired(ctx context.Context, now time.Time, limit int64) ([]*entities.Reservation, error)
+}
+
+// ErrInsufficientStock is returned when a stock change would make a quantity negative
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// ErrReservationNotFound is returned when a reservation is not found in the repository
+var ErrReservationNotFound = errors.New("reservation not found")
+
+// ErrReservationClosed is returned when a reservation is no longer pending
+var ErrReservationClosed = errors.New("reservation is no longer pending")
+
+// ErrReservationExpired is returned when committing a pending reservation past its expiry, which
+// the reaper releases instead
+var ErrReservationExpired = errors.New("reservation has expired")
@@ -0,0 +1,23 @@
+package ports
+
+import "context"
+
+// localeKey is the type of LocaleKey, unexported so no other package can collide with it
+type localeKey struct{}
+
+// LocaleKey is the context key of the locales the client prefers. It is exported for frameworks
+// that keep request values themselves, such as fiber's Locals; other callers use WithLocales.
+var LocaleKey = localeKey{}
+
+// WithLocales returns a copy of ctx preferring locales, canonical BCP 47 tags with the most
+// preferred first. Services pick the text of localized entities from them.
+func WithLocales(ctx context.Context, locales []string) context.Context {
+	return context.WithValue(ctx, LocaleKey, locales)
+}
+
+// LocalesFromContext returns the locales preferred by ctx, or nil when the client named none and
+// the default locale applies
+func LocalesFromContext(ctx context.Context) []string {
+	locales, _ := ctx.Value(LocaleKey).([]string)
+	return locales
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+)
+
+// MediaRepository defines the interface for product media data operations, scoped to the tenant of ctx
+type MediaRepository interface {
+	Create(ctx context.Context, media *entities.Media) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.Media, error)
+	Delete(ctx context.Context, id string) error
+	// UpdatePosition moves a media to position without touching its other fields
+	UpdatePosition(ctx context.Context, id string, position int) error
+	// SetThumbnails records the thumbnails generated for a media without touching its other fields
+	SetThumbnails(ctx context.Context, id string, thumbnails []entities.MediaThumbnail) error
+	// FindByProductID returns the media of a product ordered by position
+	FindByProductID(ctx context.Context, productID string) ([]*entities.Media, error)
+	// FindByProductAndHash returns the media of a product with the given content hash, or ErrMediaNotFound
+	FindByProductAndHash(ctx context.Context, productID string, sha256 string) (*entities.Media, error)
+	// CountByHash counts the media of the tenant with the given content hash, which share one blob
+	CountByHash(ctx context.Context, sha256 string) (int64, error)
+}
+
+// MediaEventConsumer defines the interface for receiving media.uploaded events
+type MediaEventConsumer interface {
+	// ConsumeMediaUploads calls fn for every uploaded media of every tenant until ctx is cancelled
+	ConsumeMediaUploads(ctx context.Context, fn func(context.Context, *entities.Media) error) error
+}
+
+// ErrMediaNotFound is returned when a media is not found in the repository
+var ErrMediaNotFound = errors.New("media not found")
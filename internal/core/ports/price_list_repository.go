@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+)
+
+// PriceListRepository defines the interface for price list data operations.
+// Every operation is scoped to the tenant carried by the context.
+type PriceListRepository interface {
+	Create(ctx context.Context, list *entities.PriceList) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.PriceList, error)
+	FindByCode(ctx context.Context, code string) (*entities.PriceList, error)
+	// FindAll returns the price lists of the tenant ordered by code
+	FindAll(ctx context.Context) ([]*entities.PriceList, error)
+	// Update replaces the code, name, currency, market and customer group of a price list, keeping its prices
+	Update(ctx context.Context, list *entities.PriceList) error
+	Delete(ctx context.Context, id string) error
+	// SetPrice sets the explicit price of a product in a price list
+	SetPrice(ctx context.Context, id string, productID string, price float32) error
+	// DeletePrice removes the explicit price of a product from a price list, which is not an error
+	// when there is none
+	DeletePrice(ctx context.Context, id string, productID string) error
+}
+
+// ErrPriceListNotFound is returned when a price list is not found in the repository
+var ErrPriceListNotFound = errors.New("price list not found")
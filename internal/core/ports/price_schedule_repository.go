@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// PriceScheduleRepository defines the interface for price schedule data operations
+type PriceScheduleRepository interface {
+	// Create stores a schedule, failing with ErrPriceScheduleOverlap when it conflicts with another
+	// schedule of the tenant. The check and the write are atomic, so concurrent writers cannot both pass it.
+	Create(ctx context.Context, schedule *entities.PriceSchedule) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.PriceSchedule, error)
+	// Update replaces a schedule, checking for conflicts like Create
+	Update(ctx context.Context, schedule *entities.PriceSchedule) error
+	Delete(ctx context.Context, id string) error
+	// FindByProductID returns the schedules of a product ordered by start time
+	FindByProductID(ctx context.Context, productID string) ([]*entities.PriceSchedule, error)
+	// FindActiveAt returns the schedules of every product of the tenant that apply at the given time
+	FindActiveAt(ctx context.Context, at time.Time) ([]*entities.PriceSchedule, error)
+	// FindChangingBetween returns the schedules starting or ending after from and up to and including to.
+	// It is the one query spanning every tenant, for the scheduler.
+	FindChangingBetween(ctx context.Context, from time.Time, to time.Time) ([]*entities.PriceSchedule, error)
+}
+
+var (
+	// ErrPriceScheduleNotFound is returned when a price schedule is not found in the repository
+	ErrPriceScheduleNotFound = errors.New("price schedule not found")
+	// ErrPriceScheduleOverlap is returned when a schedule overlaps another schedule of the same product and priority
+	ErrPriceScheduleOverlap = errors.New("price schedule overlaps another schedule of the same priority")
+)
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// ProductCache defines the interface for caching products by ID in front of the repository
+type ProductCache interface {
+	// Get returns the cached product, or ErrCacheMiss when it is not cached
+	Get(ctx context.Context, id string) (*entities.Product, error)
+	// Set caches a product for ttl, or until it is deleted when ttl is zero
+	Set(ctx context.Context, product *entities.Product, ttl time.Duration) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ErrCacheMiss is returned when a product is not in the cache
+var ErrCacheMiss = errors.New("cache miss")
@@ -0,0 +1,134 @@
+package ports
+
+import "test-go/internal/core/entities"
+
+// Matches reports whether a product satisfies every set field of the filter, comparing attributes
+// the way the MongoDB query operators do. Repositories that cannot push the filter down to their
+// storage use it to filter products themselves.
+func (f ProductFilter) Matches(product *entities.Product) bool {
+	if f.Name != "" && product.Name != f.Name {
+		return false
+	}
+	if f.TypeID != "" && product.TypeID != f.TypeID {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && product.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && !product.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+	if f.ComponentID != "" && !containsComponent(product, f.ComponentID) {
+		return false
+	}
+	for _, condition := range f.Attributes {
+		if !condition.Matches(product.Attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsComponent reports whether a product is a bundle containing the product with the given ID
+func containsComponent(product *entities.Product, id string) bool {
+	if product.Bundle == nil {
+		return false
+	}
+	for _, component := range product.Bundle.Components {
+		if component.ProductID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches applies the condition to a product's attributes. Values of different types never
+// compare, and ne also matches products without the attribute while the other operators never do.
+func (c AttributeCondition) Matches(attributes map[string]interface{}) bool {
+	value, exists := attributes[c.Name]
+	result, comparable := compareValues(value, c.Value)
+	if !exists {
+		comparable = false
+	}
+
+	switch c.Op {
+	case FilterEq:
+		return comparable && result == 0
+	case FilterNe:
+		return !comparable || result != 0
+	case FilterGt:
+		return comparable && result > 0
+	case FilterGte:
+		return comparable && result >= 0
+	case FilterLt:
+		return comparable && result < 0
+	case FilterLte:
+		return comparable && result <= 0
+	default:
+		return false
+	}
+}
+
+// compareValues orders two attribute values of the same type, returning ok false for values of different types
+func compareValues(a, b interface{}) (result int, ok bool) {
+	if x, isNumber := toFloat(a); isNumber {
+		y, isNumber := toFloat(b)
+		if !isNumber {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	}
+
+	switch x := a.(type) {
+	case string:
+		y, isString := b.(string)
+		if !isString {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	case bool:
+		y, isBool := b.(bool)
+		if !isBool {
+			return 0, false
+		}
+		if x == y {
+			return 0, true
+		}
+		if !x {
+			return -1, true
+		}
+		return 1, true
+	}
+
+	return 0, false
+}
+
+// compareOrdered returns -1, 0 or 1 as a is less than, equal to or greater than b
+func compareOrdered[T float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// toFloat converts the numeric types produced by BSON decoding to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case float32:
+		return float64(number), true
+	case int:
+		return float64(number), true
+	case int32:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	default:
+		return 0, false
+	}
+}
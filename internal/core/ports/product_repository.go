@@ -3,17 +3,62 @@ package ports
 import (
 	"context"
 	"errors"
+	"time"
+
 	"test-go/internal/core/entities"
 )
 
-// ProductRepository defines the interface for product data operations
+// ProductRepository defines the interface for product data operations.
+// Every operation is scoped to the tenant carried by the context, and products of other tenants
+// are invisible. FindByID, Update and Delete return ErrProductNotFound for unknown or malformed IDs.
 type ProductRepository interface {
 	Create(ctx context.Context, product *entities.Product) (string, error)
 	FindByID(ctx context.Context, id string) (*entities.Product, error)
 	Update(ctx context.Context, product *entities.Product) error
 	Delete(ctx context.Context, id string) error
 	FindAll(ctx context.Context) ([]*entities.Product, error)
+	// Count returns the number of products of the tenant
+	Count(ctx context.Context) (int64, error)
+	// FindByCategoryIDs returns the products assigned to any of the given categories
+	FindByCategoryIDs(ctx context.Context, categoryIDs []string) ([]*entities.Product, error)
+	// FindByFilter returns the products matching every set field of the filter
+	FindByFilter(ctx context.Context, filter ProductFilter) ([]*entities.Product, error)
+	// Stream calls fn for every product, fetching batchSize documents at a time
+	Stream(ctx context.Context, batchSize int32, fn func(*entities.Product) error) error
+	// FindDueTransitions returns the products whose activation or deactivation date makes a status
+	// change due at the given time, as Product.ScheduledStatus decides. It is the one query spanning
+	// every tenant, for the lifecycle scheduler.
+	FindDueTransitions(ctx context.Context, at time.Time) ([]*entities.Product, error)
 }
 
 // ErrProductNotFound is returned when a product is not found in the repository
 var ErrProductNotFound = errors.New("product not found")
+
+// Attribute condition operators
+const (
+	FilterEq  = "eq"
+	FilterNe  = "ne"
+	FilterGt  = "gt"
+	FilterGte = "gte"
+	FilterLt  = "lt"
+	FilterLte = "lte"
+)
+
+// AttributeCondition compares one custom attribute with a value already converted to the attribute's type
+type AttributeCondition struct {
+	Name  string
+	Op    string
+	Value interface{}
+}
+
+// ProductFilter selects products by exact name, type, creation time and custom attribute values.
+// CreatedFrom is inclusive and CreatedTo exclusive; zero times leave the range open.
+// ComponentID selects the bundles containing that product.
+type ProductFilter struct {
+	Name        string
+	TypeID      string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Attributes  []AttributeCondition
+	ComponentID string
+}
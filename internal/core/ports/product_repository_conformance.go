@@ -0,0 +1,662 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"test-go/internal/core/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// timestampPrecision is the precision timestamps are stored with; MongoDB keeps milliseconds
+const timestampPrecision = time.Millisecond
+
+// ProductRepositoryFactory returns an empty repository for a single conformance test.
+// Implementations backed by external storage should release it with t.Cleanup.
+type ProductRepositoryFactory func(t *testing.T) ProductRepository
+
+// RunProductRepositoryConformance checks that a ProductRepository implementation honours the
+// contract every implementation shares, so adapters can be swapped without changing behaviour.
+// Call it from a test of the implementation's package:
+//
+//	func TestProductRepository(t *testing.T) {
+//		ports.RunProductRepositoryConformance(t, func(t *testing.T) ports.ProductRepository {
+//			return memory.NewProductRepository(nil)
+//		})
+//	}
+func RunProductRepositoryConformance(t *testing.T, newRepository ProductRepositoryFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo ProductRepository)
+	}{
+		{"CreateAndFind", testCreateAndFind},
+		{"FindReturnsCopies", testFindReturnsCopies},
+		{"UpdateReplacesProduct", testUpdateReplacesProduct},
+		{"Delete", testDelete},
+		{"NotFound", testNotFound},
+		{"InvalidIDs", testInvalidIDs},
+		{"ConcurrentUpdates", testConcurrentUpdates},
+		{"ConcurrentCreates", testConcurrentCreates},
+		{"StreamOrderIsStable", testStreamOrderIsStable},
+		{"StreamStopsOnError", testStreamStopsOnError},
+		{"FindAllIsStable", testFindAllIsStable},
+		{"Timestamps", testTimestamps},
+		{"FindByCategoryIDs", testFindByCategoryIDs},
+		{"FindByFilter", testFindByFilter},
+		{"FindByNameAndCreatedAt", testFindByNameAndCreatedAt},
+		{"TenantIsolation", testTenantIsolation},
+		{"FindDueTransitions", testFindDueTransitions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepository(t))
+		})
+	}
+}
+
+func testCreateAndFind(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	product := &entities.Product{
+		Name:        "Widget",
+		Description: "A widget",
+		Price:       9.99,
+		CategoryIDs: []string{"c1", "c2"},
+		TypeID:      "t1",
+		Attributes:  map[string]interface{}{"color": "red", "weight": 1.5, "fragile": true},
+		Translations: map[string]entities.ProductTranslation{
+			"id":    {Name: "Widget ID", Description: "Sebuah widget"},
+			"id-ID": {Name: "Widget ID-ID"},
+		},
+	}
+
+	id := mustCreate(t, repo, product)
+	if id != product.ID.Hex() {
+		t.Fatalf("Create returned %q but set product ID %q", id, product.ID.Hex())
+	}
+
+	found, err := repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	assertSameProduct(t, found, product)
+}
+
+func testFindReturnsCopies(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	id := mustCreate(t, repo, &entities.Product{Name: "Widget", CategoryIDs: []string{"c1"}, Attributes: map[string]interface{}{"color": "red"}})
+
+	found := mustFind(t, repo, id)
+	found.Name = "Changed"
+	found.CategoryIDs[0] = "changed"
+	found.Attributes["color"] = "changed"
+
+	again, err := repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if again.Name != "Widget" || again.CategoryIDs[0] != "c1" || again.Attributes["color"] != "red" {
+		t.Fatalf("changing a returned product changed the stored product: %+v", again)
+	}
+}
+
+func testUpdateReplacesProduct(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	id := mustCreate(t, repo, &entities.Product{
+		Name:        "Widget",
+		Price:       1,
+		CategoryIDs: []string{"c1"},
+		TypeID:      "t1",
+		Attributes:  map[string]interface{}{"color": "red"},
+		Translations: map[string]entities.ProductTranslation{
+			"id": {Name: "Widget ID"},
+		},
+	})
+	created := mustFind(t, repo, id)
+
+	// Fields left empty on the update are cleared, not kept from the stored product
+	update := &entities.Product{ID: created.ID, Name: "Gadget", Price: 2}
+	if err := repo.Update(ctx, update); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	found := mustFind(t, repo, id)
+	if found.Name != "Gadget" || found.Price != 2 {
+		t.Fatalf("Update did not store the new values: %+v", found)
+	}
+	if len(found.CategoryIDs) != 0 || found.TypeID != "" || len(found.Attributes) != 0 || len(found.Translations) != 0 {
+		t.Fatalf("Update kept cleared fields: %+v", found)
+	}
+}
+
+func testDelete(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	id := mustCreate(t, repo, &entities.Product{Name: "Widget"})
+	other := mustCreate(t, repo, &entities.Product{Name: "Gadget"})
+
+	if err := repo.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, id); !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("FindByID after Delete returned %v, want ErrProductNotFound", err)
+	}
+	if err := repo.Delete(ctx, id); !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("second Delete returned %v, want ErrProductNotFound", err)
+	}
+
+	all, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(all) != 1 || all[0].ID.Hex() != other {
+		t.Fatalf("FindAll after Delete returned %d products, want only %s", len(all), other)
+	}
+}
+
+func testNotFound(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	missing := primitive.NewObjectID()
+
+	if _, err := repo.FindByID(ctx, missing.Hex()); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("FindByID returned %v, want ErrProductNotFound", err)
+	}
+	if err := repo.Update(ctx, &entities.Product{ID: missing, Name: "Widget"}); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Update returned %v, want ErrProductNotFound", err)
+	}
+	if err := repo.Delete(ctx, missing.Hex()); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Delete returned %v, want ErrProductNotFound", err)
+	}
+
+	all, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("Update of a missing product created %d products", len(all))
+	}
+}
+
+func testInvalidIDs(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo, &entities.Product{Name: "Widget"})
+
+	for _, id := range []string{"", "not-an-id", "123", "zzzzzzzzzzzzzzzzzzzzzzzz"} {
+		if _, err := repo.FindByID(ctx, id); !errors.Is(err, ErrProductNotFound) {
+			t.Errorf("FindByID(%q) returned %v, want ErrProductNotFound", id, err)
+		}
+		if err := repo.Delete(ctx, id); !errors.Is(err, ErrProductNotFound) {
+			t.Errorf("Delete(%q) returned %v, want ErrProductNotFound", id, err)
+		}
+	}
+}
+
+func testConcurrentUpdates(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	id := mustCreate(t, repo, &entities.Product{Name: "Widget"})
+	stored := mustFind(t, repo, id)
+
+	const writers = 20
+	names := make(map[string]bool, writers)
+	errs := make(chan error, writers)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		name := fmt.Sprintf("Widget %d", i)
+		names[name] = true
+
+		wg.Add(1)
+		go func(price float32) {
+			defer wg.Done()
+			errs <- repo.Update(ctx, &entities.Product{ID: stored.ID, Name: name, Price: price, CreatedAt: stored.CreatedAt})
+		}(float32(i))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent Update: %v", err)
+		}
+	}
+
+	found := mustFind(t, repo, id)
+	if !names[found.Name] {
+		t.Fatalf("product ended with name %q, which no writer stored", found.Name)
+	}
+	if want := fmt.Sprintf("Widget %d", int(found.Price)); found.Name != want {
+		t.Fatalf("product mixes fields of different updates: name %q with price %g", found.Name, found.Price)
+	}
+	if !sameInstant(found.CreatedAt, stored.CreatedAt) {
+		t.Fatalf("concurrent updates changed created_at from %v to %v", stored.CreatedAt, found.CreatedAt)
+	}
+}
+
+func testConcurrentCreates(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+
+	const writers = 20
+	ids := make(chan string, writers)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id, err := repo.Create(ctx, &entities.Product{Name: fmt.Sprintf("Widget %d", i)})
+			if err != nil {
+				t.Errorf("concurrent Create: %v", err)
+			}
+			ids <- id
+		}(i)
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool, writers)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("concurrent creates returned ID %s twice", id)
+		}
+		seen[id] = true
+	}
+
+	all, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(all) != writers {
+		t.Fatalf("FindAll returned %d products, want %d", len(all), writers)
+	}
+}
+
+func testStreamOrderIsStable(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	for i := 0; i < 25; i++ {
+		mustCreate(t, repo, &entities.Product{Name: fmt.Sprintf("Widget %02d", i)})
+	}
+
+	var want []string
+	for _, batchSize := range []int32{0, 1, 7, 25, 100} {
+		var got []string
+		err := repo.Stream(ctx, batchSize, func(product *entities.Product) error {
+			got = append(got, product.ID.Hex())
+			// Writes while streaming must not make the stream skip or repeat products
+			product.Name += " (seen)"
+			return repo.Update(ctx, product)
+		})
+		if err != nil {
+			t.Fatalf("Stream with batch size %d: %v", batchSize, err)
+		}
+
+		if len(got) != 25 {
+			t.Fatalf("Stream with batch size %d returned %d products, want 25", batchSize, len(got))
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i-1] >= got[i] {
+				t.Fatalf("Stream with batch size %d is not in ID order at position %d", batchSize, i)
+			}
+		}
+		if want == nil {
+			want = got
+		} else if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Stream with batch size %d returned a different order", batchSize)
+		}
+	}
+}
+
+func testStreamStopsOnError(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		mustCreate(t, repo, &entities.Product{Name: fmt.Sprintf("Widget %d", i)})
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := repo.Stream(ctx, 2, func(*entities.Product) error {
+		calls++
+		if calls == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Stream returned %v, want the callback's error", err)
+	}
+	if calls != 3 {
+		t.Fatalf("Stream called the callback %d times after it failed on the 3rd", calls)
+	}
+}
+
+func testFindAllIsStable(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		mustCreate(t, repo, &entities.Product{Name: fmt.Sprintf("Widget %d", i)})
+	}
+
+	first, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	second, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+
+	if !reflect.DeepEqual(productIDs(first), productIDs(second)) {
+		t.Fatalf("FindAll returned products in a different order on the second call")
+	}
+}
+
+func testTimestamps(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	before := time.Now()
+	product := &entities.Product{Name: "Widget"}
+	id := mustCreate(t, repo, product)
+
+	if product.CreatedAt.Before(before.Add(-timestampPrecision)) || product.CreatedAt.After(time.Now()) {
+		t.Fatalf("Create set created_at to %v, outside the call", product.CreatedAt)
+	}
+	if product.UpdatedAt.Before(product.CreatedAt) {
+		t.Fatalf("Create set updated_at %v before created_at %v", product.UpdatedAt, product.CreatedAt)
+	}
+
+	created := mustFind(t, repo, id)
+	time.Sleep(5 * timestampPrecision)
+
+	// Callers do not have to carry created_at through an update
+	if err := repo.Update(ctx, &entities.Product{ID: created.ID, Name: "Gadget"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	updated := mustFind(t, repo, id)
+	if !sameInstant(updated.CreatedAt, created.CreatedAt) {
+		t.Fatalf("Update changed created_at from %v to %v", created.CreatedAt, updated.CreatedAt)
+	}
+	if !updated.UpdatedAt.After(created.UpdatedAt) {
+		t.Fatalf("Update did not advance updated_at past %v, got %v", created.UpdatedAt, updated.UpdatedAt)
+	}
+}
+
+func testFindByCategoryIDs(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	both := mustCreate(t, repo, &entities.Product{Name: "Both", CategoryIDs: []string{"c1", "c2"}})
+	second := mustCreate(t, repo, &entities.Product{Name: "Second", CategoryIDs: []string{"c2"}})
+	mustCreate(t, repo, &entities.Product{Name: "Other", CategoryIDs: []string{"c3"}})
+	mustCreate(t, repo, &entities.Product{Name: "None"})
+
+	products, err := repo.FindByCategoryIDs(ctx, []string{"c1", "c2"})
+	if err != nil {
+		t.Fatalf("FindByCategoryIDs: %v", err)
+	}
+	assertIDs(t, "FindByCategoryIDs", products, both, second)
+}
+
+func testFindByFilter(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	small := mustCreate(t, repo, &entities.Product{Name: "Small", TypeID: "shirt", Attributes: map[string]interface{}{"size": 1.0, "color": "red"}})
+	large := mustCreate(t, repo, &entities.Product{Name: "Large", TypeID: "shirt", Attributes: map[string]interface{}{"size": 3.0, "color": "blue"}})
+	plain := mustCreate(t, repo, &entities.Product{Name: "Plain", TypeID: "shirt", Attributes: map[string]interface{}{"size": 2.0}})
+	mustCreate(t, repo, &entities.Product{Name: "Small", TypeID: "mug", Attributes: map[string]interface{}{"size": 1.0}})
+	set := mustCreate(t, repo, &entities.Product{Name: "Set", TypeID: "set", Bundle: &entities.Bundle{
+		Pricing:    entities.BundlePriceSum,
+		Components: []entities.BundleComponent{{ProductID: small, Quantity: 1}, {ProductID: large, Quantity: 2}},
+	}})
+
+	tests := []struct {
+		name   string
+		filter ProductFilter
+		want   []string
+	}{
+		{"name and type", ProductFilter{Name: "Small", TypeID: "shirt"}, []string{small}},
+		{"eq", ProductFilter{TypeID: "shirt", Attributes: []AttributeCondition{{Name: "color", Op: FilterEq, Value: "red"}}}, []string{small}},
+		{"ne includes missing attributes", ProductFilter{TypeID: "shirt", Attributes: []AttributeCondition{{Name: "color", Op: FilterNe, Value: "red"}}}, []string{large, plain}},
+		{"gt", ProductFilter{TypeID: "shirt", Attributes: []AttributeCondition{{Name: "size", Op: FilterGt, Value: 1.0}}}, []string{large, plain}},
+		{"range", ProductFilter{TypeID: "shirt", Attributes: []AttributeCondition{
+			{Name: "size", Op: FilterGte, Value: 1.0},
+			{Name: "size", Op: FilterLt, Value: 3.0},
+		}}, []string{small, plain}},
+		{"lte", ProductFilter{TypeID: "shirt", Attributes: []AttributeCondition{{Name: "size", Op: FilterLte, Value: 2.0}}}, []string{small, plain}},
+		{"different types never compare", ProductFilter{TypeID: "shirt", Attributes: []AttributeCondition{{Name: "size", Op: FilterGt, Value: "0"}}}, nil},
+		{"bundles of a component", ProductFilter{ComponentID: large}, []string{set}},
+		{"component of no bundle", ProductFilter{ComponentID: plain}, nil},
+	}
+
+	for _, tt := range tests {
+		products, err := repo.FindByFilter(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("FindByFilter %s: %v", tt.name, err)
+		}
+		assertIDs(t, "FindByFilter "+tt.name, products, tt.want...)
+	}
+}
+
+func testFindByNameAndCreatedAt(t *testing.T, repo ProductRepository) {
+	ctx := context.Background()
+	first := mustCreate(t, repo, &entities.Product{Name: "Lamp"})
+	time.Sleep(5 * time.Millisecond)
+	second := mustCreate(t, repo, &entities.Product{Name: "Lamp"})
+	time.Sleep(5 * time.Millisecond)
+	third := mustCreate(t, repo, &entities.Product{Name: "Lamp shade"})
+
+	// Renaming must move the product between names
+	renamed := mustFind(t, repo, first)
+	renamed.Name = "Desk lamp"
+	if err := repo.Update(ctx, renamed); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	secondCreated := mustFind(t, repo, second).CreatedAt
+	thirdCreated := mustFind(t, repo, third).CreatedAt
+
+	tests := []struct {
+		name   string
+		filter ProductFilter
+		want   []string
+	}{
+		{"exact name", ProductFilter{Name: "Lamp"}, []string{second}},
+		{"renamed", ProductFilter{Name: "Desk lamp"}, []string{first}},
+		{"unknown name", ProductFilter{Name: "Lam"}, nil},
+		{"created from is inclusive", ProductFilter{CreatedFrom: secondCreated}, []string{second, third}},
+		{"created to is exclusive", ProductFilter{CreatedTo: thirdCreated}, []string{first, second}},
+		{"created range", ProductFilter{CreatedFrom: secondCreated, CreatedTo: thirdCreated}, []string{second}},
+		{"name and created range", ProductFilter{Name: "Lamp shade", CreatedTo: thirdCreated}, nil},
+	}
+
+	for _, tt := range tests {
+		products, err := repo.FindByFilter(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("FindByFilter %s: %v", tt.name, err)
+		}
+		assertIDs(t, "FindByFilter "+tt.name, products, tt.want...)
+	}
+}
+
+func testTenantIsolation(t *testing.T, repo ProductRepository) {
+	tenantA := WithTenant(context.Background(), "tenant-a")
+	tenantB := WithTenant(context.Background(), "tenant-b")
+
+	product := &entities.Product{Name: "Widget", TypeID: "t1", CategoryIDs: []string{"c1"}}
+	id, err := repo.Create(tenantA, product)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if product.TenantID != "tenant-a" {
+		t.Fatalf("Create set tenant %q, want tenant-a", product.TenantID)
+	}
+	// Interleave products of the other tenant so streams have to skip some
+	var own []string
+	for i := 0; i < 5; i++ {
+		ownID, err := repo.Create(tenantB, &entities.Product{Name: fmt.Sprintf("Gadget %d", i)})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		own = append(own, ownID)
+		if _, err := repo.Create(tenantA, &entities.Product{Name: fmt.Sprintf("Widget %d", i)}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// Products of another tenant are not found, not forbidden, so their IDs do not leak
+	if _, err := repo.FindByID(tenantB, id); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("FindByID from another tenant returned %v, want ErrProductNotFound", err)
+	}
+	if err := repo.Update(tenantB, &entities.Product{ID: product.ID, Name: "Stolen"}); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Update from another tenant returned %v, want ErrProductNotFound", err)
+	}
+	if err := repo.Delete(tenantB, id); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Delete from another tenant returned %v, want ErrProductNotFound", err)
+	}
+	if found, err := repo.FindByID(tenantA, id); err != nil || found.Name != "Widget" {
+		t.Fatalf("product changed by another tenant: %+v, %v", found, err)
+	}
+
+	all, err := repo.FindAll(tenantB)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	assertIDs(t, "FindAll", all, own...)
+
+	byCategory, err := repo.FindByCategoryIDs(tenantB, []string{"c1"})
+	if err != nil {
+		t.Fatalf("FindByCategoryIDs: %v", err)
+	}
+	assertIDs(t, "FindByCategoryIDs", byCategory)
+
+	for _, filter := range []ProductFilter{{Name: "Widget"}, {TypeID: "t1"}, {CreatedTo: time.Now().Add(time.Hour)}} {
+		products, err := repo.FindByFilter(tenantB, filter)
+		if err != nil {
+			t.Fatalf("FindByFilter: %v", err)
+		}
+		want := own
+		if filter.CreatedTo.IsZero() {
+			want = nil
+		}
+		assertIDs(t, fmt.Sprintf("FindByFilter %+v", filter), products, want...)
+	}
+
+	for _, batchSize := range []int32{0, 1, 2} {
+		var streamed []*entities.Product
+		err := repo.Stream(tenantB, batchSize, func(product *entities.Product) error {
+			streamed = append(streamed, product)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Stream with batch size %d: %v", batchSize, err)
+		}
+		assertIDs(t, fmt.Sprintf("Stream with batch size %d", batchSize), streamed, own...)
+	}
+
+	if count, err := repo.Count(tenantA); err != nil || count != 6 {
+		t.Errorf("Count of tenant-a returned %d, %v, want 6", count, err)
+	}
+	if count, err := repo.Count(tenantB); err != nil || count != 5 {
+		t.Errorf("Count of tenant-b returned %d, %v, want 5", count, err)
+	}
+	if count, err := repo.Count(context.Background()); err != nil || count != 0 {
+		t.Errorf("Count of the default tenant returned %d, %v, want 0", count, err)
+	}
+}
+
+func testFindDueTransitions(t *testing.T, repo ProductRepository) {
+	now := time.Now().Truncate(timestampPrecision)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	otherTenant := WithTenant(context.Background(), "tenant-b")
+
+	approved := mustCreate(t, repo, &entities.Product{Name: "Approved", Status: entities.ProductStatusInReview, SubmittedBy: "alice", ApprovedBy: "bob", ActivateAt: &past})
+	mustCreate(t, repo, &entities.Product{Name: "Unapproved", Status: entities.ProductStatusInReview, SubmittedBy: "alice", ActivateAt: &past})
+	mustCreate(t, repo, &entities.Product{Name: "Later", Status: entities.ProductStatusInReview, SubmittedBy: "alice", ApprovedBy: "bob", ActivateAt: &future})
+	mustCreate(t, repo, &entities.Product{Name: "Draft", Status: entities.ProductStatusDraft, ActivateAt: &past})
+	ending := mustCreate(t, repo, &entities.Product{Name: "Ending", Status: entities.ProductStatusActive, DeactivateAt: &now})
+	mustCreate(t, repo, &entities.Product{Name: "Lasting", Status: entities.ProductStatusActive, DeactivateAt: &future})
+	mustCreate(t, repo, &entities.Product{Name: "Discontinued", Status: entities.ProductStatusDiscontinued, DeactivateAt: &past})
+	foreign, err := repo.Create(otherTenant, &entities.Product{Name: "Foreign", Status: entities.ProductStatusActive, DeactivateAt: &past})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// The scheduler sweeps every tenant, and a date is due at the instant it names
+	due, err := repo.FindDueTransitions(context.Background(), now)
+	if err != nil {
+		t.Fatalf("FindDueTransitions: %v", err)
+	}
+	assertIDs(t, "FindDueTransitions", due, approved, ending, foreign)
+	for _, product := range due {
+		if product.ScheduledStatus(now) == "" {
+			t.Errorf("FindDueTransitions returned %s, which has no status change due", product.Name)
+		}
+	}
+}
+
+// mustCreate creates a product and fails the test when that is not possible
+func mustCreate(t *testing.T, repo ProductRepository, product *entities.Product) string {
+	t.Helper()
+	id, err := repo.Create(context.Background(), product)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return id
+}
+
+// mustFind reads a product and fails the test when it cannot be found
+func mustFind(t *testing.T, repo ProductRepository, id string) *entities.Product {
+	t.Helper()
+	product, err := repo.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("FindByID(%s): %v", id, err)
+	}
+	return product
+}
+
+// assertSameProduct compares a stored product with the one it was created from
+func assertSameProduct(t *testing.T, got, want *entities.Product) {
+	t.Helper()
+	if got.ID != want.ID || got.Name != want.Name || got.Description != want.Description || got.Price != want.Price || got.TypeID != want.TypeID {
+		t.Fatalf("stored product %+v differs from created product %+v", got, want)
+	}
+	if !reflect.DeepEqual(got.Translations, want.Translations) {
+		t.Fatalf("stored translations %v differ from %v", got.Translations, want.Translations)
+	}
+	if !reflect.DeepEqual(got.CategoryIDs, want.CategoryIDs) {
+		t.Fatalf("stored category IDs %v differ from %v", got.CategoryIDs, want.CategoryIDs)
+	}
+	if !reflect.DeepEqual(got.Attributes, want.Attributes) {
+		t.Fatalf("stored attributes %v differ from %v", got.Attributes, want.Attributes)
+	}
+	if !sameInstant(got.CreatedAt, want.CreatedAt) || !sameInstant(got.UpdatedAt, want.UpdatedAt) {
+		t.Fatalf("stored timestamps %v/%v differ from %v/%v", got.CreatedAt, got.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+	}
+}
+
+// assertIDs checks that products holds exactly the products with the given IDs, in any order
+func assertIDs(t *testing.T, call string, products []*entities.Product, ids ...string) {
+	t.Helper()
+	got := make(map[string]bool, len(products))
+	for _, product := range products {
+		got[product.ID.Hex()] = true
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	if len(products) != len(ids) || !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s returned %v, want %v", call, productIDs(products), ids)
+	}
+}
+
+// productIDs lists the IDs of products in order
+func productIDs(products []*entities.Product) []string {
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID.Hex()
+	}
+	return ids
+}
+
+// sameInstant reports whether two timestamps are equal at the stored precision
+func sameInstant(a, b time.Time) bool {
+	return a.Truncate(timestampPrecision).Equal(b.Truncate(timestampPrecision))
+}
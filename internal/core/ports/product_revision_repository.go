@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// ProductRevisionRepository defines the interface for the append-only product change history
+type ProductRevisionRepository interface {
+	// Append stores a revision, numbering it right after the latest revision of its product
+	Append(ctx context.Context, revision *entities.ProductRevision) error
+	// FindByProductID returns every revision of a product, oldest first
+	FindByProductID(ctx context.Context, productID string) ([]*entities.ProductRevision, error)
+	// FindByRevision returns one revision of a product
+	FindByRevision(ctx context.Context, productID string, revision int64) (*entities.ProductRevision, error)
+	// FindAsOf returns the latest revision of a product created at or before at
+	FindAsOf(ctx context.Context, productID string, at time.Time) (*entities.ProductRevision, error)
+}
+
+// ErrRevisionNotFound is returned when a product has no matching revision
+var ErrRevisionNotFound = errors.New("revision not found")
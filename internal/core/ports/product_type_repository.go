@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+)
+
+// ProductTypeRepository defines the interface for product type data operations
+type ProductTypeRepository interface {
+	Create(ctx context.Context, productType *entities.ProductType) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.ProductType, error)
+	Update(ctx context.Context, productType *entities.ProductType) error
+	Delete(ctx context.Context, id string) error
+	FindAll(ctx context.Context) ([]*entities.ProductType, error)
+}
+
+// ErrProductTypeNotFound is returned when a product type is not found in the repository
+var ErrProductTypeNotFound = errors.New("product type not found")
@@ -0,0 +1,20 @@
+package ports
+
+import "context"
+
+// ProductUnitOfWork runs product writes together with the revisions, audit entries and events they cause.
+// Implementations backed by a transactional store commit all of them or none, so an event is
+// only published for a write that was stored (the transactional outbox pattern).
+type ProductUnitOfWork interface {
+	// Do calls fn with ports bound to one unit of work,
+	// which is committed when fn returns nil and rolled back otherwise
+	Do(ctx context.Context, fn func(tx ProductTx) error) error
+}
+
+// ProductTx holds the ports bound to one unit of work
+type ProductTx struct {
+	Products  ProductRepository
+	Revisions ProductRevisionRepository
+	Audit     AuditRepository
+	Events    EventPublisher
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+)
+
+// ProductWatcher defines the interface for observing product changes
+type ProductWatcher interface {
+	// Watch calls fn for every product change of every tenant until ctx is cancelled or fn returns
+	// an error. A non-empty resumeToken continues right after the event that carried it.
+	Watch(ctx context.Context, resumeToken string, fn func(*entities.ProductEvent) error) error
+}
+
+// ProductChangeRecorder defines the interface for recording committed product changes, which the
+// ProductWatcher of the same process then hands to its watchers
+type ProductChangeRecorder interface {
+	// Append records a change. Changes must be appended in the order they were committed.
+	Append(event *entities.ProductEvent)
+}
+
+// ErrInvalidResumeToken is returned when a resume token cannot be used to continue a watch
+var ErrInvalidResumeToken = errors.New("invalid resume token")
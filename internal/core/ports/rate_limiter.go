@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// RateLimit is a token bucket holding up to Burst requests, refilled with Requests every Period
+type RateLimit struct {
+	Requests int64
+	Period   time.Duration
+	Burst    int64
+}
+
+// RefillRate returns how many tokens the bucket regains per second
+func (l RateLimit) RefillRate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result describes a bucket left with tokens after a request was allowed or denied
+func (l RateLimit) Result(allowed bool, tokens float64) *RateLimitResult {
+	rate := l.RefillRate()
+	result := &RateLimitResult{
+		Allowed:   allowed,
+		Limit:     l.Burst,
+		Remaining: int64(math.Floor(tokens)),
+		Reset:     time.Duration((float64(l.Burst) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return result
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int64         // Requests the bucket holds when full
+	Remaining  int64         // Requests left in the bucket
+	Reset      time.Duration // Until the bucket is full again
+	RetryAfter time.Duration // Until the next request is allowed, zero when this one was
+}
+
+// RateLimiter defines the interface for token buckets shared by every replica
+type RateLimiter interface {
+	// Take removes a token from the bucket of key, creating a full bucket for unknown keys
+	Take(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error)
+}
@@ -0,0 +1,55 @@
+package ports
+
+import "context"
+
+// RequestInfo identifies the caller and the request being served. Primary adapters attach it to
+// the context so services can record who made a change, and from where.
+type RequestInfo struct {
+	Actor string
+	// Principal is who the request was authenticated as: "api_key:<id>" for an API key or
+	// "sub:<subject>" for a verified bearer token, and empty for unauthenticated requests. Unlike
+	// Actor it cannot be named by the caller, so it is what approvals are checked against.
+	Principal string
+	RequestID string
+	// Transport is one of the Transport constants
+	Transport string
+	IP        string
+}
+
+// Transports a write can come from
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+	// TransportImport is attached by bulk imports with WithRequestInfo
+	TransportImport = "import"
+	// TransportInternal marks writes of background workers, such as the price scheduler
+	TransportInternal = "internal"
+)
+
+// requestInfoKey is the type of RequestInfoKey, unexported so no other package can collide with it
+type requestInfoKey struct{}
+
+// RequestInfoKey is the context key of the RequestInfo. It is exported for frameworks that keep
+// request values themselves, such as fiber's Locals; other callers use WithRequestInfo.
+var RequestInfoKey = requestInfoKey{}
+
+// AnonymousActor is recorded when the caller did not identify itself
+const AnonymousActor = "anonymous"
+
+// WithRequestInfo returns a copy of ctx carrying info
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, RequestInfoKey, info)
+}
+
+// RequestInfoFromContext returns the RequestInfo carried by ctx, with AnonymousActor and
+// TransportInternal when there is none
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(RequestInfoKey).(RequestInfo)
+	if info.Actor == "" {
+		info.Actor = AnonymousActor
+	}
+	if info.Transport == "" {
+		info.Transport = TransportInternal
+	}
+	return info
+}
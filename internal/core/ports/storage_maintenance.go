@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"io"
+)
+
+// StorageMaintenance defines the upkeep operations of an embedded storage backend
+type StorageMaintenance interface {
+	// Backup writes a consistent snapshot of the storage to w and returns the number of bytes written
+	Backup(ctx context.Context, w io.Writer) (int64, error)
+	// Compact reclaims the space left behind by deleted and updated data
+	Compact(ctx context.Context) error
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// TaxRateRepository stores the tax rates of every jurisdiction with their history. Rates are set by
+// law and shared by every tenant, so like ExchangeRateRepository it ignores the tenant of the context.
+type TaxRateRepository interface {
+	Create(ctx context.Context, rate *entities.TaxRate) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.TaxRate, error)
+	// FindAll returns the rates of a jurisdiction, or of every jurisdiction when it is empty, ordered
+	// by jurisdiction, tax class and effective time
+	FindAll(ctx context.Context, jurisdiction string) ([]*entities.TaxRate, error)
+	// FindEffective returns the rate of a tax class in a jurisdiction in effect at the given time,
+	// or ErrTaxRateNotFound
+	FindEffective(ctx context.Context, jurisdiction string, taxClass string, at time.Time) (*entities.TaxRate, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ErrTaxRateNotFound is returned when there is no tax rate for a jurisdiction and class at the time asked for
+var ErrTaxRateNotFound = errors.New("tax rate not found")
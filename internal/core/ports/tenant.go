@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultTenant owns requests that do not name a tenant and the data written before tenants were introduced
+const DefaultTenant = "default"
+
+// tenantKey is the type of TenantKey, unexported so no other package can collide with it
+type tenantKey struct{}
+
+// TenantKey is the context key of the tenant ID. It is exported for frameworks that keep request
+// values themselves, such as fiber's Locals; other callers use WithTenant.
+var TenantKey = tenantKey{}
+
+// WithTenant returns a copy of ctx scoped to tenantID. Repositories, caches and events only see
+// the data of the tenant carried by the context.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, TenantKey, tenantID)
+}
+
+// TenantFromContext returns the tenant ID carried by ctx, or DefaultTenant when there is none
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, _ := ctx.Value(TenantKey).(string); tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenant
+}
+
+// RoutingKey prefixes an event type with the tenant of ctx, e.g. "acme.product.created",
+// so consumers can bind to the events of one tenant or of every tenant
+func RoutingKey(ctx context.Context, eventType string) string {
+	return TenantFromContext(ctx) + "." + eventType
+}
+
+// SplitRoutingKey returns the tenant and event type of a routing key built by RoutingKey.
+// Event types have two segments, so a key without a tenant prefix belongs to DefaultTenant.
+func SplitRoutingKey(routingKey string) (tenantID string, eventType string) {
+	if strings.Count(routingKey, ".") < 2 {
+		return DefaultTenant, routingKey
+	}
+	tenantID, eventType, _ = strings.Cut(routingKey, ".")
+	return tenantID, eventType
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"test-go/internal/core/entities"
+)
+
+// VariantRepository defines the interface for product variant data operations
+type VariantRepository interface {
+	// CreateMany inserts variants, returning ErrDuplicateSKU when a SKU is already taken
+	CreateMany(ctx context.Context, variants []*entities.ProductVariant) error
+	FindByID(ctx context.Context, id string) (*entities.ProductVariant, error)
+	// FindByProduct returns the variants of a product ordered by position
+	FindByProduct(ctx context.Context, productID string) ([]*entities.ProductVariant, error)
+	// Update replaces a variant, returning ErrDuplicateSKU when its SKU is already taken
+	Update(ctx context.Context, variant *entities.ProductVariant) error
+	DeleteByIDs(ctx context.Context, ids []string) error
+}
+
+// ErrVariantNotFound is returned when a variant is not found in the repository
+var ErrVariantNotFound = errors.New("variant not found")
+
+// ErrDuplicateSKU is returned when a variant SKU is already used by another variant
+var ErrDuplicateSKU = errors.New("sku already in use")
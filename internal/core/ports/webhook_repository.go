@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// WebhookRepository defines the interface for webhook subscription data operations
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entities.WebhookSubscription) (string, error)
+	FindByID(ctx context.Context, id string) (*entities.WebhookSubscription, error)
+	Update(ctx context.Context, webhook *entities.WebhookSubscription) error
+	Delete(ctx context.Context, id string) error
+	FindAll(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	// FindActiveByEventType returns the active subscriptions that want events of the given type
+	FindActiveByEventType(ctx context.Context, eventType string) ([]*entities.WebhookSubscription, error)
+	// RecordFailure atomically increments the failure count of a subscription and disables it at at
+	// once the count reaches disableAfter. It reports whether this call disabled it.
+	RecordFailure(ctx context.Context, id string, disableAfter int, at time.Time) (bool, error)
+	// ResetFailures atomically sets the failure count of a subscription back to zero
+	ResetFailures(ctx context.Context, id string) error
+}
+
+// WebhookDeliveryRepository defines the interface for the webhook delivery log
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *entities.WebhookDelivery) (string, error)
+	// FindBySubscription returns the most recent deliveries for a subscription, newest first
+	FindBySubscription(ctx context.Context, subscriptionID string, limit int64) ([]*entities.WebhookDelivery, error)
+}
+
+// PendingWebhookDeliveryRepository defines the interface for the queue of webhook deliveries still to be attempted
+type PendingWebhookDeliveryRepository interface {
+	// Create queues deliveries for the tenant of ctx
+	Create(ctx context.Context, deliveries []*entities.PendingWebhookDelivery) error
+	// ClaimDue returns up to limit deliveries of every tenant due at now, oldest first, moving their
+	// next attempt to leaseUntil so no other consumer claims them meanwhile
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int64) ([]*entities.PendingWebhookDelivery, error)
+	// Reschedule records that a delivery was attempted and when to try it again
+	Reschedule(ctx context.Context, id string, attempts int, next time.Time) error
+	// Delete removes a delivery that succeeded or was given up on
+	Delete(ctx context.Context, id string) error
+}
+
+// ErrWebhookNotFound is returned when a webhook subscription is not found in the repository
+var ErrWebhookNotFound = errors.New("webhook not found")
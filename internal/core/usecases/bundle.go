@@ -0,0 +1,148 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxBundleComponents  = 50   // Distinct products in one bundle
+	maxComponentQuantity = 1000 // Units of one component in one bundle
+)
+
+// MaxBundleDepth is how many levels of bundles may be nested in bundles
+const MaxBundleDepth = 5
+
+// ErrProductInBundle is returned when deleting a product that is still a component of a bundle
+var ErrProductInBundle = errors.New("product is a component of a bundle and must be removed from it first")
+
+// bundleRules adds the rules of the bundle of a product to rules: a pricing mode, a discount of at
+// most 100% for discounted bundles, and one to maxBundleComponents distinct components, each an
+// existing product in a positive quantity that does not contain the bundle in turn
+func bundleRules(rules *RuleSet[*entities.Product], repo ports.ProductRepository, product *entities.Product) {
+	bundle := product.Bundle
+	if bundle == nil {
+		return
+	}
+
+	rules.
+		Field("bundle.pricing", func(*entities.Product) interface{} { return bundle.Pricing },
+			OneOf(entities.BundlePriceSum, entities.BundlePriceDiscount, entities.BundlePriceFixed)).
+		Field("bundle.components", func(*entities.Product) interface{} { return len(bundle.Components) },
+			Range(1, maxBundleComponents))
+	if bundle.Pricing == entities.BundlePriceDiscount {
+		rules.Field("bundle.discount_percent", func(*entities.Product) interface{} { return bundle.DiscountPercent }, Range(0, 100))
+	}
+
+	seen := make(map[string]bool, len(bundle.Components))
+	for i, component := range bundle.Components {
+		field := fmt.Sprintf("bundle.components[%d]", i)
+		repeated := seen[component.ProductID]
+		seen[component.ProductID] = true
+		rules.
+			Field(field+".product_id", func(*entities.Product) interface{} { return component.ProductID },
+				Required(), distinct(repeated), bundleComponent(repo, product.ID)).
+			Field(field+".quantity", func(*entities.Product) interface{} { return component.Quantity },
+				Range(1, maxComponentQuantity))
+	}
+}
+
+// distinct rejects a value that was already listed
+func distinct(repeated bool) Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		if repeated {
+			return "is listed more than once", nil
+		}
+		return "", nil
+	}
+}
+
+// bundleComponent rejects components that are the bundle itself, do not exist or were deleted, or
+// contain the bundle through bundles nested in them, which would make its price and availability
+// depend on themselves
+func bundleComponent(repo ports.ProductRepository, bundleID primitive.ObjectID) Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		id, _ := value.(string)
+		if !bundleID.IsZero() && id == bundleID.Hex() {
+			return "must not be the bundle itself", nil
+		}
+
+		component, err := repo.FindByID(ctx, id)
+		if errors.Is(err, ports.ErrProductNotFound) {
+			return "must be an existing product", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return nestedBundleViolation(ctx, repo, component, bundleID, 1)
+	}
+}
+
+// nestedBundleViolation walks the bundles nested in product, reporting one that contains bundleID
+// or nesting deeper than MaxBundleDepth
+func nestedBundleViolation(ctx context.Context, repo ports.ProductRepository, product *entities.Product, bundleID primitive.ObjectID, depth int) (string, error) {
+	if product.Bundle == nil {
+		return "", nil
+	}
+	if depth >= MaxBundleDepth {
+		return fmt.Sprintf("nests bundles more than %d levels deep", MaxBundleDepth), nil
+	}
+
+	for _, component := range product.Bundle.Components {
+		if !bundleID.IsZero() && component.ProductID == bundleID.Hex() {
+			return "contains the bundle, which would make a cycle", nil
+		}
+		nested, err := repo.FindByID(ctx, component.ProductID)
+		if errors.Is(err, ports.ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if violation, err := nestedBundleViolation(ctx, repo, nested, bundleID, depth+1); violation != "" || err != nil {
+			return violation, err
+		}
+	}
+	return "", nil
+}
+
+// DeriveBundlePrice sets the price of a bundle priced from its components: the sum of the prices of
+// its components times their quantities, less the discount of discounted bundles, rounded to the
+// minor unit of currency. Components are priced at their list prices, without price schedules.
+// Products that are no bundles and bundles with a fixed price are left as they are.
+func DeriveBundlePrice(ctx context.Context, repo ports.ProductRepository, product *entities.Product, currency string) error {
+	bundle := product.Bundle
+	if bundle == nil || bundle.Pricing == entities.BundlePriceFixed {
+		return nil
+	}
+
+	var sum float64
+	for _, component := range bundle.Components {
+		price, err := repo.FindByID(ctx, component.ProductID)
+		if err != nil {
+			return err
+		}
+		sum += float64(price.Price) * float64(component.Quantity)
+	}
+	if bundle.Pricing == entities.BundlePriceDiscount {
+		sum *= 1 - bundle.DiscountPercent/100
+	}
+
+	product.Price = RoundToCurrency(sum, currency)
+	return nil
+}
+
+// BundleSets returns how many bundles an available quantity of a component is enough for, when
+// each bundle holds quantity units of it
+func BundleSets(available int64, quantity int) int64 {
+	if available <= 0 || quantity <= 0 {
+		return 0
+	}
+	return available / int64(quantity)
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// ErrInvalidExchangeRates is returned, wrapped with the offending rate, for exchange rate imports
+// that cannot be parsed
+var ErrInvalidExchangeRates = errors.New("invalid exchange rates")
+
+// exchangeRateDocument is the JSON form of a rate table, as exchange rate APIs publish it
+type exchangeRateDocument struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// exchangeRateColumns are the columns of the CSV form of a rate table, one rate per line
+var exchangeRateColumns = []string{"base", "quote", "rate", "effective_at"}
+
+// ParseExchangeRatesJSON reads a rate table such as {"base": "USD", "date": "2024-05-31", "rates": {"EUR": 0.92}}.
+// The rates take effect at the start of date in UTC, or at importedAt when the table has no date.
+func ParseExchangeRatesJSON(data []byte, source string, importedAt time.Time) ([]*entities.ExchangeRate, error) {
+	var document exchangeRateDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExchangeRates, err)
+	}
+
+	effectiveAt := importedAt
+	if document.Date != "" {
+		var err error
+		if effectiveAt, err = parseEffectiveAt(document.Date); err != nil {
+			return nil, fmt.Errorf("%w: date: %v", ErrInvalidExchangeRates, err)
+		}
+	}
+
+	rates := make([]*entities.ExchangeRate, 0, len(document.Rates))
+	for quote, value := range document.Rates {
+		rate, err := newExchangeRate(document.Base, quote, value, effectiveAt, source, importedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rates.%s: %v", ErrInvalidExchangeRates, quote, err)
+		}
+		rates = append(rates, rate)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: no rates", ErrInvalidExchangeRates)
+	}
+	return rates, nil
+}
+
+// ParseExchangeRatesCSV reads a rate table with a base,quote,rate,effective_at header and one rate
+// per line. Effective times are dates or RFC 3339 timestamps.
+func ParseExchangeRatesCSV(data []byte, source string, importedAt time.Time) ([]*entities.ExchangeRate, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = len(exchangeRateColumns)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExchangeRates, err)
+	}
+	for i, column := range exchangeRateColumns {
+		if strings.ToLower(strings.TrimSpace(header[i])) != column {
+			return nil, fmt.Errorf("%w: header must be %s", ErrInvalidExchangeRates, strings.Join(exchangeRateColumns, ","))
+		}
+	}
+
+	var rates []*entities.ExchangeRate
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExchangeRates, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: rate must be a number", ErrInvalidExchangeRates, line)
+		}
+		effectiveAt, err := parseEffectiveAt(strings.TrimSpace(record[3]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidExchangeRates, line, err)
+		}
+		rate, err := newExchangeRate(record[0], record[1], value, effectiveAt, source, importedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidExchangeRates, line, err)
+		}
+		rates = append(rates, rate)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: no rates", ErrInvalidExchangeRates)
+	}
+	return rates, nil
+}
+
+// newExchangeRate validates a rate of base in quote
+func newExchangeRate(base string, quote string, value float64, effectiveAt time.Time, source string, importedAt time.Time) (*entities.ExchangeRate, error) {
+	base, err := ParseCurrency(strings.TrimSpace(base))
+	if err != nil {
+		return nil, fmt.Errorf("base %v", err)
+	}
+	quote, err = ParseCurrency(strings.TrimSpace(quote))
+	if err != nil {
+		return nil, fmt.Errorf("quote %v", err)
+	}
+	if base == quote {
+		return nil, errors.New("base and quote must differ")
+	}
+	if value <= 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, errors.New("rate must be positive")
+	}
+
+	return &entities.ExchangeRate{
+		Base:        base,
+		Quote:       quote,
+		Rate:        value,
+		EffectiveAt: effectiveAt.UTC(),
+		Source:      source,
+		ImportedAt:  importedAt,
+	}, nil
+}
+
+// parseEffectiveAt reads a date, taken as its start in UTC, or an RFC 3339 timestamp
+func parseEffectiveAt(value string) (time.Time, error) {
+	if at, err := time.Parse(time.DateOnly, value); err == nil {
+		return at, nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("effective time must be a date such as 2024-05-31 or an RFC 3339 timestamp")
+	}
+	return at.UTC(), nil
+}
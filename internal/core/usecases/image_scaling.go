@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"image"
+	"image/draw"
+)
+
+// FitWithin returns the size of a width x height image scaled down to fit a bound x bound square,
+// keeping its aspect ratio. Images that already fit keep their size; neither side drops below 1.
+func FitWithin(width int, height int, bound int) (int, int) {
+	if width <= bound && height <= bound {
+		return width, height
+	}
+	if width >= height {
+		return bound, max(1, height*bound/width)
+	}
+	return max(1, width*bound/height), bound
+}
+
+// ScaleImage resizes src to width x height, setting every pixel to the average of the source
+// pixels it covers. It is meant for scaling down; colours are weighted by their alpha, so fully
+// transparent pixels do not darken the edges of a shape.
+func ScaleImage(src image.Image, width int, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	source := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(source, source.Bounds(), src, bounds.Min, draw.Src)
+
+	scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := coveredSpan(y, height, bounds.Dy())
+		for x := 0; x < width; x++ {
+			x0, x1 := coveredSpan(x, width, bounds.Dx())
+
+			var r, g, b, a, pixels uint64
+			for sy := y0; sy < y1; sy++ {
+				row := source.Pix[sy*source.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					alpha := uint64(p[3])
+					r += uint64(p[0]) * alpha
+					g += uint64(p[1]) * alpha
+					b += uint64(p[2]) * alpha
+					a += alpha
+					pixels++
+				}
+			}
+
+			p := scaled.Pix[y*scaled.Stride+x*4:]
+			if a > 0 {
+				p[0], p[1], p[2] = uint8(r/a), uint8(g/a), uint8(b/a)
+			}
+			p[3] = uint8(a / pixels)
+		}
+	}
+	return scaled
+}
+
+// coveredSpan returns the range of the size source pixels covered by target pixel i of n,
+// which is never empty
+func coveredSpan(i int, n int, size int) (int, int) {
+	start := i * size / n
+	end := (i + 1) * size / n
+	if end <= start {
+		end = start + 1
+	}
+	return start, min(end, size)
+}
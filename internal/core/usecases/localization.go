@@ -0,0 +1,236 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"test-go/internal/core/entities"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/search"
+)
+
+// Product list orders accepted by SortProducts; a leading "-" sorts in descending order
+const (
+	SortByName      = "name"
+	SortByPrice     = "price"
+	SortByCreatedAt = "created_at"
+)
+
+var (
+	// ErrInvalidLocale is returned for locales that are not well-formed BCP 47 language tags
+	ErrInvalidLocale = errors.New("locale must be a BCP 47 language tag such as en or id-ID")
+	// ErrInvalidSort is returned for product list orders other than the SortBy constants
+	ErrInvalidSort = errors.New("sort must be name, price or created_at, optionally prefixed with - for descending order")
+)
+
+// maxProductDescriptionLength bounds product descriptions in every locale
+const maxProductDescriptionLength = 5000
+
+// ParseLocale returns the canonical form of a BCP 47 language tag, e.g. id-ID for ID_id
+func ParseLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return "", ErrInvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// LocaleFallbacks returns the locales whose text is used for locale, most specific first: the locale,
+// its parents and finally defaultLocale, e.g. id-ID, id and en
+func LocaleFallbacks(locale string, defaultLocale string) []string {
+	return localeCandidates([]string{locale}, defaultLocale)
+}
+
+// localeCandidates chains the fallbacks of every preferred locale in order of preference, without
+// repeating a locale, and ends with defaultLocale unless a preference already reached it
+func localeCandidates(preferences []string, defaultLocale string) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			candidates = append(candidates, locale)
+		}
+	}
+
+	for _, preference := range preferences {
+		tag, err := language.Parse(preference)
+		if err != nil {
+			continue
+		}
+		for ; tag != language.Und; tag = tag.Parent() {
+			add(tag.String())
+		}
+	}
+	add(defaultLocale)
+	return candidates
+}
+
+// LocalizeProduct replaces the name and description of a product with their text in the first of
+// the preferred locales that has one, trying the fallbacks of each preference before the next one
+// and the default locale last. Fields are picked one by one, so a translation without a description
+// keeps the description of the next locale. Locale is set to the locale the name was picked in.
+func LocalizeProduct(product *entities.Product, preferences []string, defaultLocale string) {
+	var name, description, locale string
+	for _, candidate := range localeCandidates(preferences, defaultLocale) {
+		text, ok := product.Translations[candidate]
+		if candidate == defaultLocale {
+			text, ok = entities.ProductTranslation{Name: product.Name, Description: product.Description}, true
+		}
+		if !ok {
+			continue
+		}
+
+		if name == "" && text.Name != "" {
+			name, locale = text.Name, candidate
+		}
+		if description == "" {
+			description = text.Description
+		}
+		if name != "" && description != "" {
+			break
+		}
+	}
+
+	product.Name = name
+	product.Description = description
+	product.Locale = locale
+}
+
+// ResponseLocale returns the locale lists are sorted and searched in: the most preferred locale,
+// or defaultLocale when the client named none
+func ResponseLocale(preferences []string, defaultLocale string) string {
+	if len(preferences) > 0 {
+		return preferences[0]
+	}
+	return defaultLocale
+}
+
+// SortProducts orders products in place by sortBy, one of the SortBy constants with an optional
+// leading "-" for descending order. Names are compared by the collation rules of locale, so
+// accented letters sort next to their base letters; prices by the price they are listed at.
+func SortProducts(products []*entities.Product, sortBy string, locale string) error {
+	field, descending := strings.CutPrefix(sortBy, "-")
+
+	var less func(a, b *entities.Product) bool
+	switch field {
+	case SortByName:
+		collator := collate.New(language.Make(locale))
+		less = func(a, b *entities.Product) bool { return collator.CompareString(a.Name, b.Name) < 0 }
+	case SortByPrice:
+		less = func(a, b *entities.Product) bool { return listPrice(a) < listPrice(b) }
+	case SortByCreatedAt:
+		less = func(a, b *entities.Product) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	default:
+		return ErrInvalidSort
+	}
+
+	sort.SliceStable(products, func(i, j int) bool {
+		if descending {
+			return less(products[j], products[i])
+		}
+		return less(products[i], products[j])
+	})
+	return nil
+}
+
+// listPrice is the price a product is listed at: its price in the requested price list or currency
+// when priced, its effective price otherwise
+func listPrice(product *entities.Product) float32 {
+	if product.Pricing != nil {
+		return product.Pricing.Price
+	}
+	return effectivePrice(product)
+}
+
+// effectivePrice is the price of a product in the base currency: its effective price when resolved,
+// its base price otherwise
+func effectivePrice(product *entities.Product) float32 {
+	if product.EffectivePrice != nil {
+		return *product.EffectivePrice
+	}
+	return product.Price
+}
+
+// SearchProducts returns the products whose name or description contains query, ignoring case and
+// diacritics as the users of locale expect, so "cafe" finds "Café"
+func SearchProducts(products []*entities.Product, query string, locale string) []*entities.Product {
+	matcher := search.New(language.Make(locale), search.IgnoreCase, search.IgnoreDiacritics)
+	pattern := matcher.CompileString(query)
+
+	var found []*entities.Product
+	for _, product := range products {
+		if start, _ := pattern.IndexString(product.Name); start >= 0 {
+			found = append(found, product)
+			continue
+		}
+		if start, _ := pattern.IndexString(product.Description); start >= 0 {
+			found = append(found, product)
+		}
+	}
+	return found
+}
+
+// MissingTranslations lists the locales in which a product has no name, or no description although
+// it has one in the default locale. The default locale itself is never missing.
+func MissingTranslations(product *entities.Product, locales []string, defaultLocale string) []entities.MissingTranslation {
+	var missing []entities.MissingTranslation
+	for _, locale := range locales {
+		if locale == defaultLocale {
+			continue
+		}
+
+		text := product.Translations[locale]
+		var fields []string
+		if text.Name == "" {
+			fields = append(fields, "name")
+		}
+		if text.Description == "" && product.Description != "" {
+			fields = append(fields, "description")
+		}
+		if len(fields) > 0 {
+			missing = append(missing, entities.MissingTranslation{
+				ProductID: product.ID.Hex(),
+				Name:      product.Name,
+				Locale:    locale,
+				Fields:    fields,
+			})
+		}
+	}
+	return missing
+}
+
+// translationRules adds the rules of every translation of a product to rules, in locale order:
+// a canonical locale, a name or description, and the length limits of the default locale
+func translationRules(rules *RuleSet[*entities.Product], product *entities.Product) {
+	locales := make([]string, 0, len(product.Translations))
+	for locale := range product.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		field := "translations." + locale
+		text := product.Translations[locale]
+		rules.
+			Field(field, func(*entities.Product) interface{} { return locale }, canonicalLocale()).
+			Field(field, func(*entities.Product) interface{} { return text.Name + text.Description }, Required()).
+			Field(field+".name", func(*entities.Product) interface{} { return text.Name }, Length(0, maxProductNameLength)).
+			Field(field+".description", func(*entities.Product) interface{} { return text.Description }, Length(0, maxProductDescriptionLength))
+	}
+}
+
+// canonicalLocale rejects locales that are not canonical BCP 47 language tags, as ParseLocale returns them
+func canonicalLocale() Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		locale, _ := value.(string)
+		if canonical, err := ParseLocale(locale); err != nil || canonical != locale {
+			return "must be a canonical BCP 47 language tag", nil
+		}
+		return "", nil
+	}
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"errors"
+	"math"
+
+	"test-go/internal/core/entities"
+
+	"golang.org/x/text/currency"
+)
+
+// ErrInvalidCurrency is returned for currencies that are not ISO 4217 codes
+var ErrInvalidCurrency = errors.New("currency must be an ISO 4217 code such as USD or EUR")
+
+// ParseCurrency returns the canonical form of an ISO 4217 currency code, e.g. EUR for eur
+func ParseCurrency(code string) (string, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", ErrInvalidCurrency
+	}
+	return unit.String(), nil
+}
+
+// PriceProduct derives the price of a product in a price list, or in code when list is nil.
+// An explicit price of the list wins. Otherwise the effective price in baseCurrency is converted
+// with rate, which is only used when the currencies differ and may be quoted either way round.
+// Converted prices are rounded to the minor unit of the currency.
+func PriceProduct(product *entities.Product, list *entities.PriceList, code string, baseCurrency string, rate *entities.ExchangeRate) *entities.ProductPricing {
+	basePrice := effectivePrice(product)
+	pricing := &entities.ProductPricing{
+		Price:        basePrice,
+		Currency:     code,
+		Source:       entities.PriceFromBase,
+		BasePrice:    basePrice,
+		BaseCurrency: baseCurrency,
+	}
+
+	if list != nil {
+		pricing.PriceList = list.Code
+		if price, ok := list.Prices[product.ID.Hex()]; ok {
+			pricing.Price = price
+			pricing.Source = entities.PriceFromList
+			return pricing
+		}
+	}
+	if code == baseCurrency {
+		return pricing
+	}
+
+	factor := rate.Rate
+	if rate.Base != baseCurrency {
+		factor = 1 / rate.Rate
+	}
+	effectiveAt := rate.EffectiveAt
+	pricing.Price = RoundToCurrency(float64(basePrice)*factor, code)
+	pricing.Source = entities.PriceFromConversion
+	pricing.ExchangeRate = factor
+	pricing.RateEffectiveAt = &effectiveAt
+	return pricing
+}
+
+// NeedsConversion reports whether PriceProduct converts the price of the product, and so needs
+// an exchange rate
+func NeedsConversion(product *entities.Product, list *entities.PriceList, code string, baseCurrency string) bool {
+	if list != nil {
+		if _, ok := list.Prices[product.ID.Hex()]; ok {
+			return false
+		}
+	}
+	return code != baseCurrency
+}
+
+// RoundToCurrency rounds an amount to the smallest increment of the currency, such as cents for
+// EUR and whole yen for JPY
+func RoundToCurrency(amount float64, code string) float32 {
+	scale, increment := 2, 1
+	if unit, err := currency.ParseISO(code); err == nil {
+		scale, increment = currency.Standard.Rounding(unit)
+	}
+
+	steps := math.Pow10(scale) / float64(increment)
+	return float32(math.Round(amount*steps) / steps)
+}
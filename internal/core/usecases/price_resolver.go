@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+// ResolvePrice returns the price of a product at the given time, the schedule that sets it (nil for
+// the base price) and the next instant at which the price may change (zero when no schedule starts
+// or ends later). Among the schedules applying at that time the highest priority wins, then the one
+// that started last.
+func ResolvePrice(product *entities.Product, schedules []*entities.PriceSchedule, at time.Time) (float32, *entities.PriceSchedule, time.Time) {
+	var (
+		active *entities.PriceSchedule
+		next   time.Time
+	)
+	for _, schedule := range schedules {
+		if schedule.ActiveAt(at) && (active == nil || outranks(schedule, active)) {
+			active = schedule
+		}
+		for _, boundary := range []time.Time{schedule.StartsAt, schedule.EndsAt} {
+			if boundary.After(at) && (next.IsZero() || boundary.Before(next)) {
+				next = boundary
+			}
+		}
+	}
+
+	if active == nil {
+		return product.Price, nil, next
+	}
+	return active.Price, active, next
+}
+
+// outranks reports whether schedule a takes precedence over b when both apply
+func outranks(a *entities.PriceSchedule, b *entities.PriceSchedule) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return a.StartsAt.After(b.StartsAt)
+}
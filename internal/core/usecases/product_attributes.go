@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// attributeNamePattern keeps attribute names usable as document field names and query parameters
+var attributeNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// AttributeViolation describes why one attribute, or attribute definition, was rejected
+type AttributeViolation struct {
+	Attribute string `json:"attribute"`
+	Reason    string `json:"reason"`
+}
+
+// AttributeError is returned when attribute values or definitions break the product type schema
+type AttributeError struct {
+	Violations []AttributeViolation
+}
+
+// Error joins every violation into a single message
+func (e *AttributeError) Error() string {
+	reasons := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		reasons[i] = violation.Attribute + ": " + violation.Reason
+	}
+	return "invalid attributes: " + strings.Join(reasons, "; ")
+}
+
+// attributeError returns an *AttributeError for the violations, or nil when there are none
+func attributeError(violations []AttributeViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &AttributeError{Violations: violations}
+}
+
+// ValidateAttributeSchema checks that a product type's attribute definitions are usable
+func ValidateAttributeSchema(definitions []entities.AttributeDefinition) error {
+	var violations []AttributeViolation
+	names := make(map[string]bool, len(definitions))
+
+	for _, definition := range definitions {
+		add := func(reason string) {
+			violations = append(violations, AttributeViolation{Attribute: definition.Name, Reason: reason})
+		}
+
+		if !attributeNamePattern.MatchString(definition.Name) {
+			add("name must start with a letter and contain only letters, digits and underscores")
+		}
+		if names[definition.Name] {
+			add("is defined more than once")
+		}
+		names[definition.Name] = true
+
+		switch definition.Type {
+		case entities.AttributeString, entities.AttributeNumber, entities.AttributeBoolean, entities.AttributeDate:
+		case entities.AttributeEnum:
+			if len(definition.Values) == 0 {
+				add("enum needs at least one value")
+			}
+		default:
+			add(fmt.Sprintf("unknown type %q", definition.Type))
+		}
+
+		if definition.Min != nil && definition.Max != nil && *definition.Min > *definition.Max {
+			add("min is greater than max")
+		}
+		if definition.Pattern != "" {
+			if _, err := regexp.Compile(definition.Pattern); err != nil {
+				add("pattern is not a valid regular expression")
+			}
+		}
+	}
+
+	return attributeError(violations)
+}
+
+// ValidateAttributes checks attribute values against a product type and returns them normalized:
+// numbers as float64 and dates as YYYY-MM-DD. Attributes the type does not define are rejected.
+func ValidateAttributes(productType *entities.ProductType, values map[string]interface{}) (map[string]interface{}, error) {
+	var violations []AttributeViolation
+	normalized := make(map[string]interface{}, len(values))
+
+	var undefined []string
+	for name := range values {
+		if productType.Attribute(name) == nil {
+			undefined = append(undefined, name)
+		}
+	}
+	sort.Strings(undefined)
+	for _, name := range undefined {
+		violations = append(violations, AttributeViolation{Attribute: name, Reason: "is not defined by the product type"})
+	}
+
+	for _, definition := range productType.Attributes {
+		value, ok := values[definition.Name]
+		if !ok || value == nil {
+			if definition.Required {
+				violations = append(violations, AttributeViolation{Attribute: definition.Name, Reason: "is required"})
+			}
+			continue
+		}
+
+		normalizedValue, reason := normalizeAttribute(definition, value)
+		if reason != "" {
+			violations = append(violations, AttributeViolation{Attribute: definition.Name, Reason: reason})
+			continue
+		}
+		normalized[definition.Name] = normalizedValue
+	}
+
+	if err := attributeError(violations); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// CheckProductAttributes validates the attributes of a product against its type, replacing them with
+// their normalized values. A product without a type cannot carry attributes.
+func CheckProductAttributes(ctx context.Context, types ports.ProductTypeRepository, product *entities.Product) error {
+	if product.TypeID == "" {
+		if len(product.Attributes) > 0 {
+			return &AttributeError{Violations: []AttributeViolation{{Attribute: "type_id", Reason: "is required to set attributes"}}}
+		}
+		return nil
+	}
+
+	productType, err := types.FindByID(ctx, product.TypeID)
+	if errors.Is(err, ports.ErrProductTypeNotFound) {
+		return &AttributeError{Violations: []AttributeViolation{{Attribute: "type_id", Reason: "unknown product type"}}}
+	}
+	if err != nil {
+		return err
+	}
+
+	attributes, err := ValidateAttributes(productType, product.Attributes)
+	if err != nil {
+		return err
+	}
+	product.Attributes = attributes
+	return nil
+}
+
+// ParseAttributeCondition converts a raw filter value, as received in a query string, to the type of
+// the attribute it compares against
+func ParseAttributeCondition(productType *entities.ProductType, name string, op string, raw string) (ports.AttributeCondition, error) {
+	definition := productType.Attribute(name)
+	if definition == nil {
+		return ports.AttributeCondition{}, &AttributeError{Violations: []AttributeViolation{{Attribute: name, Reason: "is not defined by the product type"}}}
+	}
+
+	if op == "" {
+		op = ports.FilterEq
+	}
+	switch op {
+	case ports.FilterEq, ports.FilterNe:
+	case ports.FilterGt, ports.FilterGte, ports.FilterLt, ports.FilterLte:
+		if definition.Type != entities.AttributeNumber && definition.Type != entities.AttributeDate {
+			return ports.AttributeCondition{}, &AttributeError{Violations: []AttributeViolation{{Attribute: name, Reason: "only numbers and dates can be compared with " + op}}}
+		}
+	default:
+		return ports.AttributeCondition{}, &AttributeError{Violations: []AttributeViolation{{Attribute: name, Reason: fmt.Sprintf("unknown operator %q", op)}}}
+	}
+
+	var value interface{} = raw
+	var reason string
+	switch definition.Type {
+	case entities.AttributeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			reason = "must be a number"
+		}
+		value = number
+	case entities.AttributeBoolean:
+		boolean, err := strconv.ParseBool(raw)
+		if err != nil {
+			reason = "must be true or false"
+		}
+		value = boolean
+	case entities.AttributeDate:
+		value, reason = normalizeDate(raw)
+	}
+	if reason != "" {
+		return ports.AttributeCondition{}, &AttributeError{Violations: []AttributeViolation{{Attribute: name, Reason: reason}}}
+	}
+
+	return ports.AttributeCondition{Name: name, Op: op, Value: value}, nil
+}
+
+// normalizeAttribute checks one value against its definition, returning the stored value or the reason it was rejected
+func normalizeAttribute(definition entities.AttributeDefinition, value interface{}) (interface{}, string) {
+	switch definition.Type {
+	case entities.AttributeString:
+		text, ok := value.(string)
+		if !ok {
+			return nil, "must be a string"
+		}
+		length := float64(utf8.RuneCountInString(text))
+		if definition.Min != nil && length < *definition.Min {
+			return nil, fmt.Sprintf("must be at least %g characters", *definition.Min)
+		}
+		if definition.Max != nil && length > *definition.Max {
+			return nil, fmt.Sprintf("must be at most %g characters", *definition.Max)
+		}
+		if definition.Pattern != "" {
+			if matched, err := regexp.MatchString(definition.Pattern, text); err != nil || !matched {
+				return nil, "does not match " + definition.Pattern
+			}
+		}
+		return text, ""
+
+	case entities.AttributeNumber:
+		number, ok := toFloat(value)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, "must be a number"
+		}
+		if definition.Min != nil && number < *definition.Min {
+			return nil, fmt.Sprintf("must be at least %g", *definition.Min)
+		}
+		if definition.Max != nil && number > *definition.Max {
+			return nil, fmt.Sprintf("must be at most %g", *definition.Max)
+		}
+		return number, ""
+
+	case entities.AttributeEnum:
+		text, ok := value.(string)
+		if !ok {
+			return nil, "must be a string"
+		}
+		for _, allowed := range definition.Values {
+			if text == allowed {
+				return text, ""
+			}
+		}
+		return nil, "must be one of " + strings.Join(definition.Values, ", ")
+
+	case entities.AttributeBoolean:
+		boolean, ok := value.(bool)
+		if !ok {
+			return nil, "must be true or false"
+		}
+		return boolean, ""
+
+	case entities.AttributeDate:
+		text, ok := value.(string)
+		if !ok {
+			return nil, "must be a date"
+		}
+		return normalizeDate(text)
+	}
+
+	return nil, fmt.Sprintf("has unknown type %q", definition.Type)
+}
+
+// normalizeDate accepts a YYYY-MM-DD date or an RFC 3339 timestamp and returns it as YYYY-MM-DD
+func normalizeDate(text string) (string, string) {
+	if date, err := time.Parse(entities.AttributeDateLayout, text); err == nil {
+		return date.Format(entities.AttributeDateLayout), ""
+	}
+	if timestamp, err := time.Parse(time.RFC3339, text); err == nil {
+		return timestamp.UTC().Format(entities.AttributeDateLayout), ""
+	}
+	return "", "must be a date formatted as YYYY-MM-DD"
+}
+
+// toFloat converts the numeric types produced by JSON, BSON and protobuf decoding to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case float32:
+		return float64(number), true
+	case int:
+		return float64(number), true
+	case int32:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"reflect"
+	"sort"
+
+	"test-go/internal/core/entities"
+)
+
+// DiffProducts lists the fields that differ between two states of a product, sorted by field.
+// Custom attributes, options and translations are compared one by one as attributes.<name>,
+// options.<name> and translations.<locale>.name or .description.
+// A nil before lists every field of after as added, and a nil after every field of before as removed.
+// IDs, creation and update times, and who submitted or approved the product are not compared.
+func DiffProducts(before *entities.Product, after *entities.Product) []entities.FieldChange {
+	from, to := productFields(before), productFields(after)
+
+	fields := make([]string, 0, len(from)+len(to))
+	for field := range from {
+		fields = append(fields, field)
+	}
+	for field := range to {
+		if _, ok := from[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []entities.FieldChange
+	for _, field := range fields {
+		if reflect.DeepEqual(from[field], to[field]) {
+			continue
+		}
+		changes = append(changes, entities.FieldChange{Field: field, From: from[field], To: to[field]})
+	}
+	return changes
+}
+
+// productFields flattens the comparable fields of a product, leaving out empty ones
+func productFields(product *entities.Product) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if product == nil {
+		return fields
+	}
+
+	if product.Name != "" {
+		fields["name"] = product.Name
+	}
+	if product.Description != "" {
+		fields["description"] = product.Description
+	}
+	fields["price"] = product.Price
+	if product.TypeID != "" {
+		fields["type_id"] = product.TypeID
+	}
+	if product.TaxClass != "" {
+		fields["tax_class"] = product.TaxClass
+	}
+	if product.Status != "" {
+		fields["status"] = product.Status
+	}
+	if product.ActivateAt != nil {
+		fields["activate_at"] = product.ActivateAt.UTC()
+	}
+	if product.DeactivateAt != nil {
+		fields["deactivate_at"] = product.DeactivateAt.UTC()
+	}
+	if len(product.CategoryIDs) > 0 {
+		fields["category_ids"] = product.CategoryIDs
+	}
+	for _, option := range product.Options {
+		fields["options."+option.Name] = option.Values
+	}
+	if bundle := product.Bundle; bundle != nil {
+		fields["bundle.pricing"] = bundle.Pricing
+		if bundle.DiscountPercent != 0 {
+			fields["bundle.discount_percent"] = bundle.DiscountPercent
+		}
+		for _, component := range bundle.Components {
+			fields["bundle.components."+component.ProductID] = component.Quantity
+		}
+	}
+	for name, value := range product.Attributes {
+		fields["attributes."+name] = value
+	}
+	for locale, text := range product.Translations {
+		if text.Name != "" {
+			fields["translations."+locale+".name"] = text.Name
+		}
+		if text.Description != "" {
+			fields["translations."+locale+".description"] = text.Description
+		}
+	}
+	return fields
+}
@@ -0,0 +1,142 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+var (
+	// ErrInvalidProductStatus is returned for statuses other than the ProductStatus constants
+	ErrInvalidProductStatus = errors.New("status must be draft, review, active, discontinued or archived")
+	// ErrInvalidTransition is returned when a product cannot move from its status to the requested one
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrPrincipalRequired is returned when a product is submitted for review or approved by a caller
+	// that did not authenticate
+	ErrPrincipalRequired = errors.New("submitting a product for review or approving it requires an API key or a verified bearer token")
+	// ErrApprovalRequired is returned when a product in review is activated by the principal who submitted it
+	ErrApprovalRequired = errors.New("a product in review must be approved by another principal than the one who submitted it")
+	// ErrInvalidActivationSchedule is returned when a product is scheduled to be deactivated before it is activated
+	ErrInvalidActivationSchedule = errors.New("deactivate_at must be after activate_at")
+)
+
+// productTransitions lists the statuses a product may move to from each status. Archived products
+// can only be restored to drafts, and active products are discontinued before they are archived.
+var productTransitions = map[string][]string{
+	entities.ProductStatusDraft:        {entities.ProductStatusInReview, entities.ProductStatusArchived},
+	entities.ProductStatusInReview:     {entities.ProductStatusActive, entities.ProductStatusDraft},
+	entities.ProductStatusActive:       {entities.ProductStatusDiscontinued},
+	entities.ProductStatusDiscontinued: {entities.ProductStatusActive, entities.ProductStatusArchived},
+	entities.ProductStatusArchived:     {entities.ProductStatusDraft},
+}
+
+// ParseProductStatus checks that status is one of the ProductStatus constants
+func ParseProductStatus(status string) (string, error) {
+	for _, known := range entities.ProductStatuses {
+		if status == known {
+			return status, nil
+		}
+	}
+	return "", ErrInvalidProductStatus
+}
+
+// TransitionProduct moves a product to status on behalf of principal, the authenticated identity of
+// the caller. Submitting a product for review records the submitter, and moving it from review to
+// active approves it, which needs a principal other than the submitter; both need a principal. A
+// product approved before its activation date stays in review until the scheduler activates it. It
+// returns whether the status changed.
+func TransitionProduct(product *entities.Product, to string, principal string, now time.Time) (bool, error) {
+	to, err := ParseProductStatus(to)
+	if err != nil {
+		return false, err
+	}
+	from := product.LifecycleStatus()
+	if !canTransition(from, to) {
+		return false, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	switch to {
+	case entities.ProductStatusInReview:
+		if principal == "" {
+			return false, ErrPrincipalRequired
+		}
+		product.SubmittedBy = principal
+		product.ApprovedBy = ""
+	case entities.ProductStatusDraft:
+		product.SubmittedBy = ""
+		product.ApprovedBy = ""
+	case entities.ProductStatusActive:
+		if from == entities.ProductStatusInReview {
+			if principal == "" {
+				return false, ErrPrincipalRequired
+			}
+			if principal == product.SubmittedBy {
+				return false, ErrApprovalRequired
+			}
+			product.ApprovedBy = principal
+			if product.ActivateAt != nil && now.Before(*product.ActivateAt) {
+				return false, nil
+			}
+		}
+		product.ActivateAt = nil
+	case entities.ProductStatusDiscontinued:
+		product.DeactivateAt = nil
+	}
+
+	product.Status = to
+	return true, nil
+}
+
+// ReviewEdit withdraws the approval of a product in review that principal edits, so the scheduler
+// does not activate content nobody approved. A known editor becomes the submitter and so cannot
+// approve their own edit.
+func ReviewEdit(product *entities.Product, principal string) {
+	if product.LifecycleStatus() != entities.ProductStatusInReview {
+		return
+	}
+	product.ApprovedBy = ""
+	if principal != "" {
+		product.SubmittedBy = principal
+	}
+}
+
+// ApplyProductSchedule moves a product to the status its activation or deactivation date makes due
+// at now, clearing the date, and returns the status it moved from, or "" when nothing was due
+func ApplyProductSchedule(product *entities.Product, now time.Time) string {
+	to := product.ScheduledStatus(now)
+	if to == "" {
+		return ""
+	}
+
+	from := product.LifecycleStatus()
+	switch to {
+	case entities.ProductStatusActive:
+		product.ActivateAt = nil
+	case entities.ProductStatusDiscontinued:
+		product.DeactivateAt = nil
+	}
+	product.Status = to
+	return from
+}
+
+// ScheduleProduct sets the dates a product is activated and deactivated at; nil clears a date
+func ScheduleProduct(product *entities.Product, activateAt *time.Time, deactivateAt *time.Time) error {
+	if activateAt != nil && deactivateAt != nil && !deactivateAt.After(*activateAt) {
+		return ErrInvalidActivationSchedule
+	}
+	product.ActivateAt = activateAt
+	product.DeactivateAt = deactivateAt
+	return nil
+}
+
+// canTransition reports whether a product may move from one status to the other
+func canTransition(from string, to string) bool {
+	for _, allowed := range productTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	"test-go/internal/core/entities"
 	"test-go/internal/core/ports"
@@ -9,19 +10,29 @@ import (
 
 // ProductUseCase defines the use case for managing products
 type ProductUseCase struct {
-	repo ports.ProductRepository
+	repo     ports.ProductRepository
+	types    ports.ProductTypeRepository
+	currency string // ISO 4217 code of product prices, which derived bundle prices are rounded in
 }
 
 // NewProductUseCase creates a new instance of ProductUseCase
-func NewProductUseCase(repo ports.ProductRepository) *ProductUseCase {
+func NewProductUseCase(repo ports.ProductRepository, types ports.ProductTypeRepository, currency string) *ProductUseCase {
 	return &ProductUseCase{
-		repo: repo,
+		repo:     repo,
+		types:    types,
+		currency: currency,
 	}
 }
 
-// CreateProduct handles the creation of a new product
+// CreateProduct handles the creation of a new product as a draft, deriving the price of bundles from their components
 func (uc *ProductUseCase) CreateProduct(ctx context.Context, product *entities.Product) (string, error) {
-	// Additional business logic before saving the product can be added here
+	product.Status = entities.ProductStatusDraft
+	if err := ValidateProduct(ctx, uc.repo, uc.types, product); err != nil {
+		return "", err
+	}
+	if err := DeriveBundlePrice(ctx, uc.repo, product, uc.currency); err != nil {
+		return "", err
+	}
 	return uc.repo.Create(ctx, product)
 }
 
@@ -30,15 +41,59 @@ func (uc *ProductUseCase) GetProductByID(ctx context.Context, id string) (*entit
 	return uc.repo.FindByID(ctx, id)
 }
 
-// UpdateProduct handles updating an existing product
+// UpdateProduct handles updating an existing product, deriving the price of bundles from their components.
+// An edit to a product in review withdraws its approval, as ReviewEdit describes.
 func (uc *ProductUseCase) UpdateProduct(ctx context.Context, product *entities.Product) error {
-	// Additional business logic before updating the product can be added here
+	ReviewEdit(product, ports.RequestInfoFromContext(ctx).Principal)
+	if err := ValidateProduct(ctx, uc.repo, uc.types, product); err != nil {
+		return err
+	}
+	if err := DeriveBundlePrice(ctx, uc.repo, product, uc.currency); err != nil {
+		return err
+	}
 	return uc.repo.Update(ctx, product)
 }
 
-// DeleteProduct handles deleting a product by its ID
+// DeriveBundlePrice derives the price of a bundle from the current prices of its components
+func (uc *ProductUseCase) DeriveBundlePrice(ctx context.Context, product *entities.Product) error {
+	return DeriveBundlePrice(ctx, uc.repo, product, uc.currency)
+}
+
+// TransitionProduct moves a product to status on behalf of the principal of ctx and saves it, returning
+// whether the status changed. An approval before the activation date is saved without changing it.
+func (uc *ProductUseCase) TransitionProduct(ctx context.Context, product *entities.Product, status string) (bool, error) {
+	changed, err := TransitionProduct(product, status, ports.RequestInfoFromContext(ctx).Principal, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return changed, uc.repo.Update(ctx, product)
+}
+
+// ApplyProductSchedule saves the status change the dates of a product make due at now, returning the
+// status it moved from, or "" when nothing was due and nothing was saved
+func (uc *ProductUseCase) ApplyProductSchedule(ctx context.Context, product *entities.Product, now time.Time) (string, error) {
+	from := ApplyProductSchedule(product, now)
+	if from == "" {
+		return "", nil
+	}
+	return from, uc.repo.Update(ctx, product)
+}
+
+// FindDueTransitions handles retrieving the products of every tenant with a status change due at the given time
+func (uc *ProductUseCase) FindDueTransitions(ctx context.Context, at time.Time) ([]*entities.Product, error) {
+	return uc.repo.FindDueTransitions(ctx, at)
+}
+
+// DeleteProduct handles deleting a product by its ID. Products that are components of a bundle
+// cannot be deleted until they are removed from it.
 func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id string) error {
-	// Additional business logic before deleting the product can be added here
+	bundles, err := uc.repo.FindByFilter(ctx, ports.ProductFilter{ComponentID: id})
+	if err != nil {
+		return err
+	}
+	if len(bundles) > 0 {
+		return ErrProductInBundle
+	}
 	return uc.repo.Delete(ctx, id)
 }
 
@@ -46,3 +101,13 @@ func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id string) error {
 func (uc *ProductUseCase) GetAllProducts(ctx context.Context) ([]*entities.Product, error) {
 	return uc.repo.FindAll(ctx)
 }
+
+// FindProducts handles retrieving the products matching a filter
+func (uc *ProductUseCase) FindProducts(ctx context.Context, filter ports.ProductFilter) ([]*entities.Product, error) {
+	return uc.repo.FindByFilter(ctx, filter)
+}
+
+// StreamProducts handles walking every product in batches of batchSize
+func (uc *ProductUseCase) StreamProducts(ctx context.Context, batchSize int32, fn func(*entities.Product) error) error {
+	return uc.repo.Stream(ctx, batchSize, fn)
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxProductNameLength bounds product names so they fit listings and search indexes
+const maxProductNameLength = 200
+
+// ValidateProduct checks a product against the business rules before it is saved: a unique,
+// non-blank name, a non-negative price, a well-formed tax class, translations keyed by canonical
+// locales, existing components without cycles for bundles and attributes matching its product
+// type. Attribute values are normalized in place.
+func ValidateProduct(ctx context.Context, repo ports.ProductRepository, types ports.ProductTypeRepository, product *entities.Product) error {
+	rules := NewRuleSet[*entities.Product]().
+		Field("name", func(p *entities.Product) interface{} { return p.Name },
+			Required(), Length(1, maxProductNameLength), Unique(productNameTaken(repo, product.ID))).
+		Field("description", func(p *entities.Product) interface{} { return p.Description },
+			Length(0, maxProductDescriptionLength)).
+		Field("price", func(p *entities.Product) interface{} { return p.Price },
+			Range(0, math.MaxFloat32)).
+		Field("tax_class", func(p *entities.Product) interface{} { return p.TaxClass },
+			Matches(taxClassPattern))
+	translationRules(rules, product)
+	bundleRules(rules, repo, product)
+
+	var validationErr *ValidationError
+	if err := rules.Validate(ctx, product); err != nil && !errors.As(err, &validationErr) {
+		return err
+	}
+
+	if err := CheckProductAttributes(ctx, types, product); err != nil {
+		var attributeErr *AttributeError
+		if !errors.As(err, &attributeErr) {
+			return err
+		}
+		if validationErr == nil {
+			validationErr = &ValidationError{}
+		}
+		for _, violation := range attributeErr.Violations {
+			field := "attributes." + violation.Attribute
+			if violation.Attribute == "type_id" {
+				field = "type_id"
+			}
+			validationErr.Violations = append(validationErr.Violations, FieldViolation{Field: field, Description: violation.Reason})
+		}
+	}
+
+	if validationErr != nil {
+		return validationErr
+	}
+	return nil
+}
+
+// productNameTaken reports whether a product other than the one with excludeID already has the name
+func productNameTaken(repo ports.ProductRepository, excludeID primitive.ObjectID) func(ctx context.Context, value interface{}) (bool, error) {
+	return func(ctx context.Context, value interface{}) (bool, error) {
+		name, _ := value.(string)
+		products, err := repo.FindByFilter(ctx, ports.ProductFilter{Name: name})
+		if err != nil {
+			return false, err
+		}
+		for _, product := range products {
+			if product.ID != excludeID {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"test-go/internal/core/entities"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+var (
+	// ErrInvalidRegion is returned for regions that are neither ISO 3166-1 country codes nor subdivisions of one
+	ErrInvalidRegion = errors.New("region must be an ISO 3166-1 country code such as DE, optionally with a subdivision such as US-CA")
+	// ErrInvalidTaxClass is returned for tax classes that are not lower-case codes such as reduced
+	ErrInvalidTaxClass = errors.New("tax class must be a lower-case code of letters, digits, - and _ such as reduced")
+)
+
+// taxClassPattern matches tax class codes, empty for the default class
+const taxClassPattern = `^([a-z][a-z0-9_-]{0,49})?$`
+
+var (
+	taxClassFormat    = regexp.MustCompile(taxClassPattern)
+	subdivisionFormat = regexp.MustCompile(`^[A-Z0-9]{1,3}$`)
+)
+
+// ParseRegion returns the canonical form of a region, an ISO 3166-1 alpha-2 country code with an
+// optional ISO 3166-2 subdivision, e.g. US-CA for us-ca
+func ParseRegion(region string) (string, error) {
+	country, subdivision, hasSubdivision := strings.Cut(strings.ToUpper(strings.TrimSpace(region)), "-")
+	if len(country) != 2 {
+		return "", ErrInvalidRegion
+	}
+	// User-assigned codes such as XX and groupings such as EU parse as regions but are no countries
+	parsed, err := language.ParseRegion(country)
+	if err != nil || !parsed.IsCountry() {
+		return "", ErrInvalidRegion
+	}
+	// Deprecated codes are replaced, e.g. UK by GB
+	country = parsed.Canonicalize().String()
+	if !hasSubdivision {
+		return country, nil
+	}
+	if !subdivisionFormat.MatchString(subdivision) {
+		return "", ErrInvalidRegion
+	}
+	return country + "-" + subdivision, nil
+}
+
+// TaxJurisdictions returns the jurisdictions whose rates apply in a canonical region, most specific
+// first: the subdivision and then its country, e.g. US-CA and US
+func TaxJurisdictions(region string) []string {
+	if country, _, ok := strings.Cut(region, "-"); ok {
+		return []string{region, country}
+	}
+	return []string{region}
+}
+
+// ParseTaxClass checks a tax class code, returning DefaultTaxClass for an empty one
+func ParseTaxClass(class string) (string, error) {
+	if !taxClassFormat.MatchString(class) {
+		return "", ErrInvalidTaxClass
+	}
+	if class == "" {
+		return entities.DefaultTaxClass, nil
+	}
+	return class, nil
+}
+
+// CurrencyScale returns the number of decimal places of the minor unit of a currency, such as 2
+// for EUR and 0 for JPY, defaulting to 2 for unknown currencies
+func CurrencyScale(code string) int {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return 2
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// Rounding modes of tax amounts, applied at the minor unit of the currency
+const (
+	// RoundHalfUp rounds halves away from zero, as most tax authorities require
+	RoundHalfUp = "half_up"
+	// RoundHalfEven rounds halves to the even neighbour, so rounding errors cancel out over many lines
+	RoundHalfEven = "half_even"
+	// RoundDown truncates toward zero
+	RoundDown = "down"
+	// RoundUp rounds away from zero
+	RoundUp = "up"
+)
+
+// ErrInvalidRounding is returned for rounding modes other than the Round constants
+var ErrInvalidRounding = errors.New("rounding must be half_up, half_even, down or up")
+
+// TaxAmounts are the net, tax and gross amounts of a price. Net plus tax always equals gross.
+type TaxAmounts struct {
+	Net   float64
+	Tax   float64
+	Gross float64
+}
+
+// ParseRounding checks that mode is one of the Round constants
+func ParseRounding(mode string) (string, error) {
+	switch mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return mode, nil
+	}
+	return "", ErrInvalidRounding
+}
+
+// CalculateTax computes the net, tax and gross amounts of quantity units at unitPrice, taxed at
+// percent. With inclusive, unitPrice is the gross price and the tax is backed out of it; otherwise
+// it is the net price and the tax is added. The line amount is rounded to scale decimal places
+// first and the tax second, both with mode, and the third amount is derived from them, so the
+// amounts always add up.
+//
+// Arithmetic is exact on the decimal values of the inputs: a unit price of 1.005 is rounded as
+// 1.005, not as the binary float slightly below it.
+func CalculateTax(unitPrice float32, quantity int, percent float64, inclusive bool, scale int, mode string) (TaxAmounts, error) {
+	if _, err := ParseRounding(mode); err != nil {
+		return TaxAmounts{}, err
+	}
+
+	line := new(big.Rat).Mul(decimal(float64(unitPrice), 32), new(big.Rat).SetInt64(int64(quantity)))
+	line = roundDecimal(line, scale, mode)
+	rate := new(big.Rat).Quo(decimal(percent, 64), big.NewRat(100, 1))
+
+	var net, tax, gross *big.Rat
+	if inclusive {
+		gross = line
+		// The tax share of a gross amount is rate / (1 + rate)
+		share := new(big.Rat).Quo(rate, new(big.Rat).Add(big.NewRat(1, 1), rate))
+		tax = roundDecimal(new(big.Rat).Mul(gross, share), scale, mode)
+		net = new(big.Rat).Sub(gross, tax)
+	} else {
+		net = line
+		tax = roundDecimal(new(big.Rat).Mul(net, rate), scale, mode)
+		gross = new(big.Rat).Add(net, tax)
+	}
+
+	return TaxAmounts{Net: toFloat64(net), Tax: toFloat64(tax), Gross: toFloat64(gross)}, nil
+}
+
+// decimal returns the exact value of the shortest decimal representation of a float of the given
+// bit size, e.g. 1.005 for float32(1.005)
+func decimal(value float64, bitSize int) *big.Rat {
+	rat, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, bitSize))
+	return rat
+}
+
+// roundDecimal rounds value to scale decimal places with mode
+func roundDecimal(value *big.Rat, scale int, mode string) *big.Rat {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(unit))
+
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		// Compare the discarded fraction with one half: twice the remainder against the denominator
+		half := new(big.Int).Abs(remainder)
+		half.Lsh(half, 1)
+		beyondHalf := half.Cmp(scaled.Denom())
+
+		var away bool
+		switch mode {
+		case RoundUp:
+			away = true
+		case RoundHalfUp:
+			away = beyondHalf >= 0
+		case RoundHalfEven:
+			away = beyondHalf > 0 || (beyondHalf == 0 && quotient.Bit(0) == 1)
+		}
+		if away {
+			quotient.Add(quotient, big.NewInt(int64(scaled.Sign())))
+		}
+	}
+
+	return new(big.Rat).SetFrac(quotient, unit)
+}
+
+// toFloat64 returns the float64 nearest to value, which prints as the decimal it stands for
+func toFloat64(value *big.Rat) float64 {
+	f, _ := value.Float64()
+	return f
+}
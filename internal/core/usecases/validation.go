@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldViolation describes why the value of one field was rejected
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ValidationError is returned when one or more fields break a business rule
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+// Error joins every violation into a single message
+func (e *ValidationError) Error() string {
+	reasons := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		reasons[i] = violation.Field + ": " + violation.Description
+	}
+	return "validation failed: " + strings.Join(reasons, "; ")
+}
+
+// Rule checks the value of one field, returning a description of the violation or an empty string
+// when the value is valid. The error is reserved for failures of the rule itself, such as an
+// unreachable repository.
+type Rule func(ctx context.Context, value interface{}) (string, error)
+
+// fieldRules binds rules to the field of a subject they check
+type fieldRules[T any] struct {
+	name  string
+	value func(T) interface{}
+	rules []Rule
+}
+
+// RuleSet declares the rules every field of a subject must satisfy
+type RuleSet[T any] struct {
+	fields []fieldRules[T]
+}
+
+// NewRuleSet creates an empty rule set
+func NewRuleSet[T any]() *RuleSet[T] {
+	return &RuleSet[T]{}
+}
+
+// Field adds rules for the field returned by value. Rules run in order and stop at the first
+// violation, so a missing value is not also reported as too short.
+func (s *RuleSet[T]) Field(name string, value func(T) interface{}, rules ...Rule) *RuleSet[T] {
+	s.fields = append(s.fields, fieldRules[T]{name: name, value: value, rules: rules})
+	return s
+}
+
+// Validate runs every rule against the subject and returns a *ValidationError listing each invalid field
+func (s *RuleSet[T]) Validate(ctx context.Context, subject T) error {
+	var violations []FieldViolation
+	for _, field := range s.fields {
+		value := field.value(subject)
+		for _, rule := range field.rules {
+			description, err := rule(ctx, value)
+			if err != nil {
+				return err
+			}
+			if description != "" {
+				violations = append(violations, FieldViolation{Field: field.name, Description: description})
+				break
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// Required rejects nil values and blank strings
+func Required() Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		if value == nil {
+			return "is required", nil
+		}
+		if text, ok := value.(string); ok && strings.TrimSpace(text) == "" {
+			return "is required", nil
+		}
+		return "", nil
+	}
+}
+
+// Length rejects strings shorter than min or longer than max characters; a max of zero means no upper bound
+func Length(min, max int) Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		text, ok := value.(string)
+		if !ok {
+			return "must be a string", nil
+		}
+		length := utf8.RuneCountInString(text)
+		if length < min {
+			return fmt.Sprintf("must be at least %d characters", min), nil
+		}
+		if max > 0 && length > max {
+			return fmt.Sprintf("must be at most %d characters", max), nil
+		}
+		return "", nil
+	}
+}
+
+// Range rejects numbers below min or above max
+func Range(min, max float64) Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		number, ok := toFloat(value)
+		if !ok || math.IsNaN(number) {
+			return "must be a number", nil
+		}
+		if number < min {
+			return fmt.Sprintf("must be at least %g", min), nil
+		}
+		if number > max {
+			return fmt.Sprintf("must be at most %g", max), nil
+		}
+		return "", nil
+	}
+}
+
+// Matches rejects strings that do not match the regular expression
+func Matches(pattern string) Rule {
+	re := regexp.MustCompile(pattern)
+	return func(ctx context.Context, value interface{}) (string, error) {
+		text, ok := value.(string)
+		if !ok || !re.MatchString(text) {
+			return "must match " + pattern, nil
+		}
+		return "", nil
+	}
+}
+
+// OneOf rejects strings other than the allowed values
+func OneOf(allowed ...string) Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		text, _ := value.(string)
+		for _, candidate := range allowed {
+			if text == candidate {
+				return "", nil
+			}
+		}
+		return "must be one of " + strings.Join(allowed, ", "), nil
+	}
+}
+
+// Unique rejects values that taken reports as already used, typically by querying a repository
+func Unique(taken func(ctx context.Context, value interface{}) (bool, error)) Rule {
+	return func(ctx context.Context, value interface{}) (string, error) {
+		used, err := taken(ctx, value)
+		if err != nil {
+			return "", err
+		}
+		if used {
+			return "is already in use", nil
+		}
+		return "", nil
+	}
+}
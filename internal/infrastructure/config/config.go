@@ -4,18 +4,104 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// Storage backends selectable with STORAGE
+const (
+	StorageMongoDB = "mongodb"
+	StorageMemory  = "memory"
+	StorageBolt    = "bolt"
 )
 
+// Media stores selectable with MEDIA_STORE
+const (
+	MediaStoreFilesystem = "filesystem"
+	MediaStoreS3         = "s3"
+)
+
+// minBootstrapKeyLength is the least number of characters after the prefix of a bootstrap API key
+const minBootstrapKeyLength = 32
+
+// RateLimitRule limits the requests each client makes to the routes starting with Prefix. Method is
+// an HTTP method, GRPC for gRPC calls, whose route is the full method name, or * for any.
+type RateLimitRule struct {
+	Method string
+	Prefix string
+	Limit  ports.RateLimit
+}
+
 type Config struct {
 	HttpPort    string
 	GrpcPort    string
+	Storage     string
 	MongoURI    string
 	MongoDbName string
 	RedisURI    string
 	RedisDbName string
 	RabbitMqURI string
+	BoltPath    string
+
+	IdempotencyTTL time.Duration
+
+	// JWTSecret verifies HS256 bearer tokens carrying the tenant in TenantClaim; empty disables tokens
+	JWTSecret   string
+	TenantClaim string
+	// TenantRequired rejects requests naming no tenant instead of serving the default tenant
+	TenantRequired bool
+	// TenantProductQuota limits the products of every tenant, zero for no limit.
+	// TenantProductQuotas overrides it per tenant.
+	TenantProductQuota  int64
+	TenantProductQuotas map[string]int64
+
+	// BootstrapAPIKeys maps tenants to an admin API key seeded at startup, which issues the first keys
+	// of the tenant
+	BootstrapAPIKeys map[string]string
+
+	// RateLimits are tried in order and the first matching rule applies; requests matching none are not limited
+	RateLimits []RateLimitRule
+	// AuthFailureLimit limits the unknown API keys each IP address presents, which are looked up
+	// before any other limit applies
+	AuthFailureLimit ports.RateLimit
+
+	// MediaStore keeps product media in the MediaPath directory or in an S3-compatible bucket
+	MediaStore    string
+	MediaPath     string
+	MediaMaxBytes int64
+	// MediaMaxPixels bounds the width times height of uploaded images, which is what decoding them costs in memory
+	MediaMaxPixels int64
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	// S3PathStyle addresses the bucket in the path rather than the host name, as MinIO expects
+	S3PathStyle bool
+
+	// DefaultLocale is the locale product names and descriptions are written in and the last fallback
+	// of every locale. Locales are the locales products should be translated to.
+	DefaultLocale string
+	Locales       []string
+
+	// BaseCurrency is the ISO 4217 code of product prices. ExchangeRateURL, when set, is polled every
+	// ExchangeRateRefresh for the rates prices are converted to other currencies with.
+	BaseCurrency        string
+	ExchangeRateURL     string
+	ExchangeRateRefresh time.Duration
+
+	// PricesIncludeTax tells whether product prices are gross prices, from which price quotes back out
+	// the tax. TaxRounding is the rounding mode of tax amounts, one of the usecases.Round constants.
+	PricesIncludeTax bool
+	TaxRounding      string
 }
 
 var AppConfig *Config
@@ -25,15 +111,86 @@ func LoadConfig() *Config {
 		log.Printf("Error loading .env file: %v", err)
 	}
 
-	return &Config{
-		HttpPort:    getEnv("HTTP_PORT"),
-		GrpcPort:    getEnv("GRPC_PORT"),
-		MongoURI:    getEnv("MONGO_URI"),
-		MongoDbName: getEnv("MONGO_DBNAME"),
-		RedisURI:    getEnv("REDIS_URI"),
-		RedisDbName: getEnv("REDIS_DBNAME"),
-		RabbitMqURI: getEnv("RABBITMQ_URI"),
+	conf := &Config{
+		HttpPort: getEnv("HTTP_PORT"),
+		GrpcPort: getEnv("GRPC_PORT"),
+		Storage:  getEnvOrDefault("STORAGE", StorageMongoDB),
+
+		IdempotencyTTL: getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		JWTSecret:           getEnvOrDefault("JWT_SECRET", ""),
+		TenantClaim:         getEnvOrDefault("TENANT_CLAIM", "tenant_id"),
+		TenantRequired:      getEnvAsBoolOrDefault("TENANT_REQUIRED", false),
+		TenantProductQuota:  getEnvAsInt64OrDefault("TENANT_PRODUCT_QUOTA", 0),
+		TenantProductQuotas: getEnvAsLimits("TENANT_PRODUCT_QUOTAS"),
+
+		BootstrapAPIKeys: getEnvAsAPIKeys("BOOTSTRAP_API_KEYS"),
+
+		RateLimits:       getEnvAsRateLimits("RATE_LIMITS"),
+		AuthFailureLimit: getEnvAsRateLimit("AUTH_FAILURE_LIMIT", "10/1m 20"),
+
+		MediaStore:     getEnvOrDefault("MEDIA_STORE", MediaStoreFilesystem),
+		MediaMaxBytes:  getEnvAsInt64OrDefault("MEDIA_MAX_BYTES", 10<<20),
+		MediaMaxPixels: getEnvAsInt64OrDefault("MEDIA_MAX_PIXELS", 50_000_000),
+
+		DefaultLocale: getEnvAsLocale("DEFAULT_LOCALE", "en"),
+
+		BaseCurrency:        getEnvAsCurrency("BASE_CURRENCY", "USD"),
+		ExchangeRateURL:     getEnvOrDefault("EXCHANGE_RATE_URL", ""),
+		ExchangeRateRefresh: getEnvAsDuration("EXCHANGE_RATE_REFRESH", 24*time.Hour),
+
+		PricesIncludeTax: getEnvAsBoolOrDefault("PRICES_INCLUDE_TAX", false),
+		TaxRounding:      getEnvOrDefault("TAX_ROUNDING", usecases.RoundHalfUp),
+	}
+	conf.Locales = getEnvAsLocales("LOCALES", conf.DefaultLocale)
+
+	switch conf.MediaStore {
+	case MediaStoreFilesystem:
+		conf.MediaPath = getEnvOrDefault("MEDIA_PATH", "data/media")
+	case MediaStoreS3:
+		conf.S3Region = getEnvOrDefault("S3_REGION", "us-east-1")
+		conf.S3Endpoint = getEnvOrDefault("S3_ENDPOINT", "")
+		if conf.S3Endpoint == "" {
+			conf.S3Endpoint = "https://s3." + conf.S3Region + ".amazonaws.com"
+		}
+		conf.S3Bucket = getEnv("S3_BUCKET")
+		conf.S3AccessKey = getEnv("S3_ACCESS_KEY")
+		conf.S3SecretKey = getEnv("S3_SECRET_KEY")
+		conf.S3PathStyle = getEnvAsBoolOrDefault("S3_FORCE_PATH_STYLE", false)
+	default:
+		log.Fatalf("Environment variable MEDIA_STORE must be %q or %q, got %q", MediaStoreFilesystem, MediaStoreS3, conf.MediaStore)
+	}
+	if conf.MediaMaxBytes <= 0 {
+		log.Fatalf("Environment variable MEDIA_MAX_BYTES must be positive, got %d", conf.MediaMaxBytes)
+	}
+	if conf.MediaMaxPixels <= 0 {
+		log.Fatalf("Environment variable MEDIA_MAX_PIXELS must be positive, got %d", conf.MediaMaxPixels)
+	}
+	if conf.ExchangeRateRefresh <= 0 {
+		log.Fatalf("Environment variable EXCHANGE_RATE_REFRESH must be positive, got %s", conf.ExchangeRateRefresh)
+	}
+	if _, err := usecases.ParseRounding(conf.TaxRounding); err != nil {
+		log.Fatalf("Environment variable TAX_ROUNDING must be half_up, half_even, down or up, got %q", conf.TaxRounding)
+	}
+
+	switch conf.Storage {
+	case StorageMongoDB:
+		conf.MongoURI = getEnv("MONGO_URI")
+		conf.MongoDbName = getEnv("MONGO_DBNAME")
+		conf.RedisURI = getEnv("REDIS_URI")
+		conf.RedisDbName = getEnv("REDIS_DBNAME")
+		conf.RabbitMqURI = getEnv("RABBITMQ_URI")
+	case StorageMemory:
+		// Everything is kept in process, so no external service is needed
+	case StorageBolt:
+		conf.BoltPath = getEnvOrDefault("BOLT_PATH", "data/catalog.db")
+		// Events are relayed to RabbitMQ when it is configured, and stay in process otherwise
+		conf.RabbitMqURI = getEnvOrDefault("RABBITMQ_URI", "")
+	default:
+		log.Fatalf("Environment variable STORAGE must be %q, %q or %q, got %q", StorageMongoDB, StorageMemory, StorageBolt, conf.Storage)
 	}
+
+	return conf
 }
 
 // getEnv reads an environment variable or returns a default value if not set
@@ -45,6 +202,189 @@ func getEnv(key string) string {
 	return ""
 }
 
+// getEnvOrDefault reads an environment variable or returns defaultValue if it is not set
+func getEnvOrDefault(key string, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsDuration reads an optional environment variable as a duration such as "24h"
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnvOrDefault(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		log.Fatalf("Environment variable %s is not a valid duration: %v", key, err)
+	}
+	return value
+}
+
+// getEnvAsBoolOrDefault reads an optional environment variable as a boolean
+func getEnvAsBoolOrDefault(key string, defaultValue bool) bool {
+	valueStr := getEnvOrDefault(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Fatalf("Environment variable %s is not a valid boolean: %v", key, err)
+	}
+	return value
+}
+
+// getEnvAsInt64OrDefault reads an optional environment variable as an integer
+func getEnvAsInt64OrDefault(key string, defaultValue int64) int64 {
+	valueStr := getEnvOrDefault(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		log.Fatalf("Environment variable %s is not a valid integer: %v", key, err)
+	}
+	return value
+}
+
+// getEnvAsLimits reads an optional environment variable of comma separated name=limit pairs,
+// such as "brand-a=500,brand-b=100"
+func getEnvAsLimits(key string) map[string]int64 {
+	limits := make(map[string]int64)
+	for _, pair := range strings.Split(getEnvOrDefault(key, ""), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, limitStr, found := strings.Cut(pair, "=")
+		limit, err := strconv.ParseInt(strings.TrimSpace(limitStr), 10, 64)
+		if !found || err != nil {
+			log.Fatalf("Environment variable %s must list name=limit pairs, got %q", key, pair)
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+	return limits
+}
+
+// getEnvAsAPIKeys reads an optional environment variable of comma separated tenant=key pairs. Keys
+// start with entities.APIKeyPrefix and must be hard to guess, as they are granted the admin scope.
+func getEnvAsAPIKeys(key string) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(getEnvOrDefault(key, ""), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		tenantID, apiKey, found := strings.Cut(pair, "=")
+		apiKey = strings.TrimSpace(apiKey)
+		if !found || !strings.HasPrefix(apiKey, entities.APIKeyPrefix) || len(apiKey) < len(entities.APIKeyPrefix)+minBootstrapKeyLength {
+			log.Fatalf("Environment variable %s must list tenant=key pairs of keys starting with %q and %d more characters", key, entities.APIKeyPrefix, minBootstrapKeyLength)
+		}
+		keys[strings.TrimSpace(tenantID)] = apiKey
+	}
+	return keys
+}
+
+// getEnvAsLocale reads an optional environment variable holding a BCP 47 language tag, returned in canonical form
+func getEnvAsLocale(key string, defaultValue string) string {
+	tag, err := language.Parse(getEnvOrDefault(key, defaultValue))
+	if err != nil || tag == language.Und {
+		log.Fatalf("Environment variable %s is not a valid BCP 47 language tag: %q", key, getEnvOrDefault(key, defaultValue))
+	}
+	return tag.String()
+}
+
+// getEnvAsLocales reads an optional environment variable of comma separated BCP 47 language tags,
+// such as "en,id,id-ID", returned in canonical form
+func getEnvAsLocales(key string, defaultValue string) []string {
+	var locales []string
+	for _, locale := range strings.Split(getEnvOrDefault(key, defaultValue), ",") {
+		tag, err := language.Parse(strings.TrimSpace(locale))
+		if err != nil || tag == language.Und {
+			log.Fatalf("Environment variable %s must list BCP 47 language tags, got %q", key, locale)
+		}
+		locales = append(locales, tag.String())
+	}
+	return locales
+}
+
+// getEnvAsCurrency reads an optional environment variable holding an ISO 4217 currency code, returned in upper case
+func getEnvAsCurrency(key string, defaultValue string) string {
+	unit, err := currency.ParseISO(getEnvOrDefault(key, defaultValue))
+	if err != nil {
+		log.Fatalf("Environment variable %s is not a valid ISO 4217 currency code: %q", key, getEnvOrDefault(key, defaultValue))
+	}
+	return unit.String()
+}
+
+// getEnvAsRateLimits reads an optional environment variable of semicolon separated rate limit rules,
+// each "<method> <prefix> <requests>/<period> [burst]" such as "GET /api/v1/products 100/1m 20".
+// The burst defaults to the number of requests.
+func getEnvAsRateLimits(key string) []RateLimitRule {
+	var rules []RateLimitRule
+	for _, ruleStr := range strings.Split(getEnvOrDefault(key, ""), ";") {
+		fields := strings.Fields(ruleStr)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule, ok := parseRateLimitRule(fields)
+		if !ok {
+			log.Fatalf("Environment variable %s must list \"<method> <prefix> <requests>/<period> [burst]\" rules, got %q", key, strings.TrimSpace(ruleStr))
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// getEnvAsRateLimit reads an optional environment variable holding one "<requests>/<period> [burst]"
+// token bucket, such as "10/1m 20"
+func getEnvAsRateLimit(key string, defaultValue string) ports.RateLimit {
+	fields := strings.Fields(getEnvOrDefault(key, defaultValue))
+	rule, ok := parseRateLimitRule(append([]string{"*", "/"}, fields...))
+	if !ok {
+		log.Fatalf("Environment variable %s must be \"<requests>/<period> [burst]\", got %q", key, getEnvOrDefault(key, defaultValue))
+	}
+	return rule.Limit
+}
+
+// parseRateLimitRule parses the fields of one rate limit rule
+func parseRateLimitRule(fields []string) (RateLimitRule, bool) {
+	if len(fields) != 3 && len(fields) != 4 {
+		return RateLimitRule{}, false
+	}
+
+	requestsStr, periodStr, found := strings.Cut(fields[2], "/")
+	requests, err := strconv.ParseInt(requestsStr, 10, 64)
+	if !found || err != nil || requests <= 0 {
+		return RateLimitRule{}, false
+	}
+	// Accept "100/m" as well as "100/1m"
+	period, err := time.ParseDuration(periodStr)
+	if err != nil {
+		period, err = time.ParseDuration("1" + periodStr)
+	}
+	if err != nil || period <= 0 {
+		return RateLimitRule{}, false
+	}
+
+	burst := requests
+	if len(fields) == 4 {
+		burst, err = strconv.ParseInt(fields[3], 10, 64)
+		if err != nil || burst <= 0 {
+			return RateLimitRule{}, false
+		}
+	}
+
+	return RateLimitRule{
+		Method: strings.ToUpper(fields[0]),
+		Prefix: fields[1],
+		Limit:  ports.RateLimit{Requests: requests, Period: period, Burst: burst},
+	}, true
+}
+
 // Optional helper functions to parse other types from environment variables
 
 // GetEnvAsInt reads an environment variable as integer or returns a default value if not set
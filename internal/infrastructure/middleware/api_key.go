@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// apiKeyScheme is the authorization scheme of API keys sent in the authorization header or
+// metadata. Keys are also accepted as bearer tokens, as most HTTP clients only support those.
+const apiKeyScheme = "ApiKey "
+
+// adminPathPrefixes start the HTTP routes that need the admin scope
+var adminPathPrefixes = []string{"/api/v1/admin/", "/api/v1/audit"}
+
+// grpcReadPrefixes start the names of the RPCs that only read the catalog
+var grpcReadPrefixes = []string{"Get", "List", "Stream", "Watch", "Diff", "Quote"}
+
+// presentedAPIKey returns the API key sent in the X-API-Key header or as authorization, or ""
+func presentedAPIKey(header string, authorization string) string {
+	if header != "" {
+		return header
+	}
+	for _, scheme := range []string{apiKeyScheme, "Bearer "} {
+		if key := strings.TrimPrefix(authorization, scheme); key != authorization && strings.HasPrefix(key, entities.APIKeyPrefix) {
+			return key
+		}
+	}
+	return ""
+}
+
+// httpScope returns the scope an API key needs for an HTTP request
+func httpScope(method string, path string) string {
+	for _, prefix := range adminPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return entities.ScopeAdmin
+		}
+	}
+
+	switch {
+	case method == http.MethodGet, method == http.MethodHead, method == http.MethodOptions:
+		return entities.ScopeCatalogRead
+	default:
+		return entities.ScopeCatalogWrite
+	}
+}
+
+// grpcScope returns the scope an API key needs for an RPC, given its full method name
+func grpcScope(fullMethod string) string {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range grpcReadPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return entities.ScopeCatalogRead
+		}
+	}
+	return entities.ScopeCatalogWrite
+}
+
+// apiKeyTenant returns the tenant of the API key the request was authenticated with, or ""
+func apiKeyTenant(ctx context.Context) string {
+	if key := ports.APIKeyFromContext(ctx); key != nil {
+		return key.TenantID
+	}
+	return ""
+}
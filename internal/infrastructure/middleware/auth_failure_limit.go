@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"test-go/internal/core/ports"
+)
+
+// maxBlockedClients bounds the clients remembered as blocked before expired blocks are swept
+const maxBlockedClients = 10_000
+
+// AuthFailureLimiter counts the unknown API keys each IP address presents against a token bucket.
+// Once the bucket of an address is empty, the address is blocked in process until the bucket holds a
+// token again, so a flood of made-up keys is turned away before reaching the key repository.
+type AuthFailureLimiter struct {
+	limiter ports.RateLimiter
+	limit   ports.RateLimit
+
+	mu      sync.Mutex
+	blocked map[string]time.Time
+}
+
+// NewAuthFailureLimiter creates a new instance of AuthFailureLimiter
+func NewAuthFailureLimiter(limiter ports.RateLimiter, limit ports.RateLimit) *AuthFailureLimiter {
+	return &AuthFailureLimiter{
+		limiter: limiter,
+		limit:   limit,
+		blocked: make(map[string]time.Time),
+	}
+}
+
+// Blocked returns how long the IP address must wait before presenting another key, or zero
+func (l *AuthFailureLimiter) Blocked(ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until, ok := l.blocked[ip]
+	if !ok {
+		return 0
+	}
+	wait := time.Until(until)
+	if wait <= 0 {
+		delete(l.blocked, ip)
+		return 0
+	}
+	return wait
+}
+
+// Fail counts an unknown key presented by the IP address and returns how long the address is now
+// blocked, or zero while it has tokens left
+func (l *AuthFailureLimiter) Fail(ctx context.Context, ip string) (time.Duration, error) {
+	result, err := l.limiter.Take(ctx, "auth-failures:ip:"+ip, l.limit)
+	if err != nil || result.Allowed {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.blocked) >= maxBlockedClients {
+		for client, until := range l.blocked {
+			if !until.After(now) {
+				delete(l.blocked, client)
+			}
+		}
+	}
+	l.blocked[ip] = now.Add(result.RetryAfter)
+	return result.RetryAfter, nil
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryAPIKeyInterceptor authenticates unary RPCs carrying an API key in the x-api-key or
+// authorization metadata. Invalid keys fail with Unauthenticated and keys lacking the scope of
+// the method with PermissionDenied. Clients presenting too many unknown keys fail with
+// ResourceExhausted without their keys being looked up, as failures decides.
+func UnaryAPIKeyInterceptor(authenticator ports.APIKeyAuthenticator, failures *AuthFailureLimiter, logger *logging.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, err := withAPIKey(ctx, authenticator, failures, info.FullMethod, logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAPIKeyInterceptor authenticates streaming RPCs carrying an API key
+func StreamAPIKeyInterceptor(authenticator ports.APIKeyAuthenticator, failures *AuthFailureLimiter, logger *logging.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := withAPIKey(ss.Context(), authenticator, failures, info.FullMethod, logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withAPIKey authenticates the API key in the incoming metadata, if any, mapping failures to the
+// status codes matching the HTTP middleware. No RPC needs the admin scope, so calls without a key
+// are left to the other interceptors.
+func withAPIKey(ctx context.Context, authenticator ports.APIKeyAuthenticator, failures *AuthFailureLimiter, fullMethod string, logger *logging.Logger) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	presented := presentedAPIKey(firstValue(md, strings.ToLower(apiKeyHeader)), firstValue(md, "authorization"))
+	if presented == "" {
+		return ctx, nil
+	}
+
+	if wait := failures.Blocked(peerIP(ctx)); wait > 0 {
+		return nil, exhausted("Too many failed authentications", wait)
+	}
+
+	key, err := authenticator.Authenticate(ctx, presented)
+	if errors.Is(err, ports.ErrInvalidAPIKey) {
+		wait, err := failures.Fail(ctx, peerIP(ctx))
+		if err != nil {
+			// Fail open so an unavailable limiter does not take the API down
+			logger.Error("Failed to count failed authentication: " + err.Error())
+		}
+		if wait > 0 {
+			return nil, exhausted("Too many failed authentications", wait)
+		}
+		return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	if err != nil {
+		logger.Error("Failed to authenticate API key: " + err.Error())
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+	if scope := grpcScope(fullMethod); !key.HasScope(scope) {
+		return nil, status.Error(codes.PermissionDenied, "API key lacks the "+scope+" scope")
+	}
+
+	return ports.WithAPIKey(ctx, key), nil
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"test-go/internal/infrastructure/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryAuthInterceptor rejects unary RPCs that do not carry a valid bearer token in the authorization metadata
+func UnaryAuthInterceptor(logger *logging.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := authorize(ctx, logger); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor rejects streaming RPCs that do not carry a valid bearer token in the authorization metadata
+func StreamAuthInterceptor(logger *logging.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := authorize(ss.Context(), logger); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorize validates the bearer token found in the incoming metadata
+func authorize(ctx context.Context, logger *logging.Logger) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		logger.Warn("Missing authorization metadata")
+		return status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+
+	// Parse the metadata value to get the token
+	token := strings.TrimPrefix(values[0], "Bearer ")
+
+	if !validateToken(token) {
+		logger.Warn("Invalid or expired token")
+		return status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+
+	return nil
+}
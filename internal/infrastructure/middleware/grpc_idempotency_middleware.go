@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/logging"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// UnaryIdempotencyInterceptor replays the stored response when a unary RPC is retried with the same
+// idempotency-key metadata. A duplicate that arrives while the first call is still running fails with
+// Aborted, and a key reused with a different request fails with InvalidArgument.
+func UnaryIdempotencyInterceptor(store ports.IdempotencyStore, ttl time.Duration, logger *logging.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("idempotency-key")
+		if len(values) == 0 || values[0] == "" {
+			return handler(ctx, req)
+		}
+		key := values[0]
+		if len(key) > maxIdempotencyKeyLength {
+			return nil, status.Error(codes.InvalidArgument, "idempotency-key is too long")
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keys are per tenant, so two tenants may pick the same key
+		storeKey := "grpc:" + ports.TenantFromContext(ctx) + ":" + key
+		requestHash := hashRequest([]byte(info.FullMethod), payload)
+
+		existing, err := store.Reserve(ctx, storeKey, requestHash, idempotencyLockTTL)
+		if err != nil {
+			// Fail open so an unavailable store does not take the API down
+			logger.Error("Failed to reserve idempotency key: " + err.Error())
+			return handler(ctx, req)
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != requestHash:
+				return nil, status.Error(codes.InvalidArgument, "idempotency-key was already used with a different request")
+			case !existing.Completed:
+				return nil, status.Error(codes.Aborted, "a request with this idempotency-key is already in progress")
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+			return replayResponse(existing)
+		}
+
+		// Call the handler to finish processing
+		resp, handlerErr := handler(ctx, req)
+
+		record, err := recordResponse(requestHash, resp, handlerErr)
+		if err != nil || record == nil {
+			if releaseErr := store.Release(ctx, storeKey); releaseErr != nil {
+				logger.Error("Failed to release idempotency key: " + releaseErr.Error())
+			}
+			return resp, handlerErr
+		}
+		if err := store.Complete(ctx, storeKey, record, ttl); err != nil {
+			logger.Error("Failed to store idempotent response: " + err.Error())
+		}
+
+		return resp, handlerErr
+	}
+}
+
+// recordResponse captures a response or a non-retryable error for replay.
+// It returns nil for errors the client should be able to retry.
+func recordResponse(requestHash string, resp interface{}, handlerErr error) (*ports.IdempotencyRecord, error) {
+	if handlerErr != nil {
+		st := status.Convert(handlerErr)
+		switch st.Code() {
+		case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+			codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented:
+		default:
+			return nil, nil
+		}
+
+		body, err := proto.Marshal(st.Proto())
+		if err != nil {
+			return nil, err
+		}
+		return &ports.IdempotencyRecord{RequestHash: requestHash, StatusCode: int(st.Code()), Body: body}, nil
+	}
+
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, nil
+	}
+	packed, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	body, err := proto.Marshal(packed)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.IdempotencyRecord{RequestHash: requestHash, StatusCode: int(codes.OK), Body: body}, nil
+}
+
+// replayResponse rebuilds the response or error stored by recordResponse
+func replayResponse(record *ports.IdempotencyRecord) (interface{}, error) {
+	if codes.Code(record.StatusCode) != codes.OK {
+		st := &spb.Status{}
+		if err := proto.Unmarshal(record.Body, st); err != nil {
+			return nil, err
+		}
+		return nil, status.ErrorProto(st)
+	}
+
+	packed := &anypb.Any{}
+	if err := proto.Unmarshal(record.Body, packed); err != nil {
+		return nil, err
+	}
+	return packed.UnmarshalNew()
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"test-go/internal/core/ports"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryLocaleInterceptor attaches the locales of the accept-language metadata to the context of unary RPCs
+func UnaryLocaleInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(withLocales(ctx), req)
+	}
+}
+
+// StreamLocaleInterceptor attaches the locales to the context of streaming RPCs
+func StreamLocaleInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withLocales(ss.Context())})
+	}
+}
+
+// withLocales reads the preferred locales from the incoming metadata, which carries the same
+// syntax as the Accept-Language header
+func withLocales(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	locales := preferredLocales(strings.Join(md.Get(strings.ToLower(acceptLanguageHeader)), ","))
+	if len(locales) == 0 {
+		return ctx
+	}
+	return ports.WithLocales(ctx, locales)
+}
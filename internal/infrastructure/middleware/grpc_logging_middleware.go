@@ -37,3 +37,32 @@ func UnaryLoggingInterceptor(logger *logging.Logger) grpc.UnaryServerInterceptor
 		return h, err
 	}
 }
+
+// StreamLoggingInterceptor logs details about the gRPC stream once it completes
+func StreamLoggingInterceptor(logger *logging.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+
+		// Call the handler and wait for the stream to finish
+		err := handler(srv, ss)
+
+		// Log the details
+		duration := time.Since(start)
+		st, _ := status.FromError(err)
+
+		logger.InfoJSON(map[string]interface{}{
+			"method":   info.FullMethod,
+			"stream":   true,
+			"duration": duration.String(),
+			"status":   st.Code().String(),
+			"error":    st.Message(),
+		})
+
+		return err
+	}
+}
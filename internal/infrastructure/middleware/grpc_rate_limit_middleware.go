@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/logging"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// UnaryRateLimitInterceptor counts unary RPCs against the token bucket of their client and method,
+// reporting the bucket in ratelimit-* header metadata. Calls finding the bucket empty fail with
+// ResourceExhausted carrying a google.rpc.RetryInfo detail. It runs after UnaryAPIKeyInterceptor, so
+// only authenticated keys get a bucket of their own.
+func UnaryRateLimitInterceptor(limiter ports.RateLimiter, policy *RateLimitPolicy, logger *logging.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := takeRateLimit(ctx, limiter, policy, info.FullMethod, logger); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor counts streaming RPCs against the token bucket of their client and method
+func StreamRateLimitInterceptor(limiter ports.RateLimiter, policy *RateLimitPolicy, logger *logging.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := takeRateLimit(ss.Context(), limiter, policy, info.FullMethod, logger); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// takeRateLimit takes a token for a call and returns the status error when the bucket is empty
+func takeRateLimit(ctx context.Context, limiter ports.RateLimiter, policy *RateLimitPolicy, fullMethod string, logger *logging.Logger) error {
+	rule := policy.match(grpcRateLimitMethod, fullMethod)
+	if rule == nil {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := firstValue(md, "authorization")
+	key := policy.bucketKey(rule, ports.APIKeyFromContext(ctx), authorization, peerIP(ctx))
+	result, err := limiter.Take(ctx, key, rule.Limit)
+	if err != nil {
+		// Fail open so an unavailable limiter does not take the API down
+		logger.Error("Failed to take rate limit token: " + err.Error())
+		return nil
+	}
+
+	header := metadata.MD{}
+	for name, value := range rateLimitHeaders(result) {
+		header.Set(strings.ToLower(name), value)
+	}
+	_ = grpc.SetHeader(ctx, header)
+	if result.Allowed {
+		return nil
+	}
+	return exhausted("Too many requests", result.RetryAfter)
+}
+
+// exhausted returns a ResourceExhausted status error telling the client when to retry
+func exhausted(message string, retryAfter time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, message).WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, message)
+	}
+	return st.Err()
+}
+
+// peerIP returns the IP address of the client of a call
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
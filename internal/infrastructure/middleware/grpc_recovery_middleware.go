@@ -36,6 +36,31 @@ func UnaryRecoveryInterceptor(logger *logging.Logger) grpc.UnaryServerIntercepto
 	}
 }
 
+// StreamRecoveryInterceptor is a gRPC interceptor that recovers from panics in streaming handlers
+func StreamRecoveryInterceptor(logger *logging.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		// Use recover to catch any panic that occurs while the stream is being served
+		defer func() {
+			if r := recover(); r != nil {
+				// Log the panic and stack trace
+				logger.Error("Recovered from panic: " + logPanic(r))
+				logger.Error("Stack trace: " + string(debug.Stack()))
+
+				// Convert the panic to a gRPC error
+				err = status.Errorf(codes.Internal, "Internal server error")
+			}
+		}()
+
+		// Continue serving the stream
+		return handler(srv, ss)
+	}
+}
+
 // logPanicInterceptor formats the panic information into a string
 func logPanicInterceptor(r interface{}) string {
 	switch v := r.(type) {
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"test-go/internal/core/ports"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryRequestInfoInterceptor attaches the actor and request ID from the x-actor and x-request-id
+// metadata and the peer IP to the context of unary RPCs, generating a request ID when there is none.
+// The principal is the API key, or the subject of a bearer token verified with secret.
+func UnaryRequestInfoInterceptor(secret string) grpc.UnaryServerInterceptor {
+	jwtSecret := []byte(secret)
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(withRequestInfo(ctx, jwtSecret), req)
+	}
+}
+
+// StreamRequestInfoInterceptor attaches the actor and request ID to the context of streaming RPCs
+func StreamRequestInfoInterceptor(secret string) grpc.StreamServerInterceptor {
+	jwtSecret := []byte(secret)
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestInfo(ss.Context(), jwtSecret)})
+	}
+}
+
+// withRequestInfo reads the request info from the incoming metadata and echoes the request ID in the header
+func withRequestInfo(ctx context.Context, secret []byte) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	info := ports.RequestInfo{
+		Actor:     requestActor(ctx, firstValue(md, strings.ToLower(actorHeader))),
+		Principal: requestPrincipal(ctx, firstValue(md, "authorization"), secret),
+		RequestID: requestID(firstValue(md, strings.ToLower(requestIDHeader))),
+		Transport: ports.TransportGRPC,
+		IP:        peerIP(ctx),
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(requestIDHeader), info.RequestID))
+	return ports.WithRequestInfo(ctx, info)
+}
+
+// firstValue returns the first value of a metadata key, or an empty string
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream is a server stream whose context was replaced
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"test-go/internal/core/ports"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryTenantInterceptor attaches the tenant from the API key, the authorization token or the
+// x-tenant-id metadata to the context of unary RPCs
+func UnaryTenantInterceptor(resolver *TenantResolver) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, err := withTenant(ctx, resolver)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamTenantInterceptor attaches the tenant to the context of streaming RPCs
+func StreamTenantInterceptor(resolver *TenantResolver) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := withTenant(ss.Context(), resolver)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withTenant resolves the tenant from the incoming metadata, mapping failures to the status codes
+// matching the HTTP middleware
+func withTenant(ctx context.Context, resolver *TenantResolver) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tenantID, err := resolver.resolve(apiKeyTenant(ctx), firstValue(md, "authorization"), firstValue(md, strings.ToLower(tenantHeader)))
+	switch err {
+	case nil:
+		return ports.WithTenant(ctx, tenantID), nil
+	case errInvalidToken:
+		return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+	case errTenantMismatch, errTenantClaim:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyMiddleware authenticates requests carrying an API key in the X-API-Key header or as
+// authorization. Unknown, revoked and expired keys are rejected with 401 and keys lacking the scope
+// of the route with 403. The admin and audit routes are rejected with 401 without a key; other
+// requests without a key are left to the other middleware. Clients presenting too many unknown keys
+// get 429 with Retry-After without their keys being looked up, as failures decides.
+func APIKeyMiddleware(authenticator ports.APIKeyAuthenticator, failures *AuthFailureLimiter, logger *logging.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		presented := presentedAPIKey(c.Get(apiKeyHeader), c.Get(fiber.HeaderAuthorization))
+		if presented == "" {
+			if httpScope(c.Method(), c.Path()) == entities.ScopeAdmin {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "An API key with the admin scope is required"})
+			}
+			return c.Next()
+		}
+
+		if wait := failures.Blocked(c.IP()); wait > 0 {
+			return tooManyFailures(c, wait)
+		}
+
+		key, err := authenticator.Authenticate(c.Context(), presented)
+		if errors.Is(err, ports.ErrInvalidAPIKey) {
+			wait, err := failures.Fail(c.Context(), c.IP())
+			if err != nil {
+				// Fail open so an unavailable limiter does not take the API down
+				logger.Error("Failed to count failed authentication: " + err.Error())
+			}
+			if wait > 0 {
+				return tooManyFailures(c, wait)
+			}
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		}
+		if err != nil {
+			logger.Error("Failed to authenticate API key: " + err.Error())
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal server error"})
+		}
+		if scope := httpScope(c.Method(), c.Path()); !key.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "API key lacks the " + scope + " scope"})
+		}
+
+		// Handlers pass c.Context() to the services, whose Value looks up the locals
+		c.Locals(ports.APIKeyKey, key)
+
+		return c.Next()
+	}
+}
+
+// tooManyFailures answers a client blocked for presenting too many unknown keys
+func tooManyFailures(c *fiber.Ctx, wait time.Duration) error {
+	c.Set(fiber.HeaderRetryAfter, seconds(wait))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "Too many failed authentications"})
+}
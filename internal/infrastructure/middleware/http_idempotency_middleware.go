@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"time"
+
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IdempotencyMiddleware replays the stored response when a mutating request is retried with the same
+// Idempotency-Key header. A duplicate that arrives while the first request is still running gets 409,
+// and a key reused with a different payload gets 422.
+func IdempotencyMiddleware(store ports.IdempotencyStore, ttl time.Duration, logger *logging.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("Idempotency-Key")
+		if key == "" || !isMutatingMethod(c.Method()) {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Idempotency-Key is too long"})
+		}
+
+		// Keys are per tenant, so two tenants may pick the same key
+		storeKey := "http:" + ports.TenantFromContext(c.Context()) + ":" + key
+		requestHash := hashRequest([]byte(c.Method()), []byte(c.Path()), c.Body())
+
+		existing, err := store.Reserve(c.Context(), storeKey, requestHash, idempotencyLockTTL)
+		if err != nil {
+			// Fail open so an unavailable store does not take the API down
+			logger.Error("Failed to reserve idempotency key: " + err.Error())
+			return c.Next()
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != requestHash:
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Idempotency-Key was already used with a different request"})
+			case !existing.Completed:
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "A request with this Idempotency-Key is already in progress"})
+			}
+
+			c.Set("Idempotent-Replayed", "true")
+			if existing.ContentType != "" {
+				c.Set(fiber.HeaderContentType, existing.ContentType)
+			}
+			return c.Status(existing.StatusCode).Send(existing.Body)
+		}
+
+		// Process request
+		err = c.Next()
+
+		// Server errors are not remembered so the client can retry them
+		status := c.Response().StatusCode()
+		if err != nil || status >= fiber.StatusInternalServerError {
+			if releaseErr := store.Release(c.Context(), storeKey); releaseErr != nil {
+				logger.Error("Failed to release idempotency key: " + releaseErr.Error())
+			}
+			return err
+		}
+
+		record := &ports.IdempotencyRecord{
+			RequestHash: requestHash,
+			StatusCode:  status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := store.Complete(c.Context(), storeKey, record, ttl); err != nil {
+			logger.Error("Failed to store idempotent response: " + err.Error())
+		}
+
+		return nil
+	}
+}
+
+// isMutatingMethod reports whether the HTTP method changes server state
+func isMutatingMethod(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
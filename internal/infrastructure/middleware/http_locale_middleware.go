@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocaleMiddleware attaches the locales of the Accept-Language header to the request context, so
+// services return localized text in the locale the client prefers
+func LocaleMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Handlers pass c.Context() to the services, whose Value looks up the locals
+		if locales := preferredLocales(c.Get(acceptLanguageHeader)); len(locales) > 0 {
+			c.Locals(ports.LocaleKey, locales)
+		}
+
+		// Responses vary by locale, so shared caches must not serve them to clients preferring another one
+		c.Vary(acceptLanguageHeader)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitMiddleware counts requests against the token bucket of their client and route group,
+// reporting the bucket in RateLimit-* headers. Requests finding the bucket empty get 429 with
+// Retry-After. It runs after APIKeyMiddleware, so only authenticated keys get a bucket of their own.
+func RateLimitMiddleware(limiter ports.RateLimiter, policy *RateLimitPolicy, logger *logging.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rule := policy.match(c.Method(), c.Path())
+		if rule == nil {
+			return c.Next()
+		}
+
+		authorization := c.Get(fiber.HeaderAuthorization)
+		key := policy.bucketKey(rule, ports.APIKeyFromContext(c.Context()), authorization, c.IP())
+		result, err := limiter.Take(c.Context(), key, rule.Limit)
+		if err != nil {
+			// Fail open so an unavailable limiter does not take the API down
+			logger.Error("Failed to take rate limit token: " + err.Error())
+			return c.Next()
+		}
+
+		for name, value := range rateLimitHeaders(result) {
+			c.Set(name, value)
+		}
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, seconds(result.RetryAfter))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "Too many requests"})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestInfoMiddleware attaches the actor, request ID and client IP to the request context, so
+// services can record who made a change and from where. The actor is taken from X-Actor, or else
+// the API key of the request. The request ID is taken from X-Request-ID or generated, and echoed
+// in the response. The principal is the API key, or the subject of a bearer token verified with secret.
+func RequestInfoMiddleware(secret string) fiber.Handler {
+	jwtSecret := []byte(secret)
+	return func(c *fiber.Ctx) error {
+		info := ports.RequestInfo{
+			Actor:     requestActor(c.Context(), c.Get(actorHeader)),
+			Principal: requestPrincipal(c.Context(), c.Get(fiber.HeaderAuthorization), jwtSecret),
+			RequestID: requestID(c.Get(requestIDHeader)),
+			Transport: ports.TransportHTTP,
+			IP:        c.IP(),
+		}
+
+		// Handlers pass c.Context() to the services, whose Value looks up the locals
+		c.Locals(ports.RequestInfoKey, info)
+		c.Set(requestIDHeader, info.RequestID)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"test-go/internal/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantMiddleware attaches the tenant of the request to its context, so repositories, caches
+// and events only see that tenant's data. Invalid tokens are rejected with 401, tokens without the
+// tenant claim and a header naming another tenant than the API key or token with 403, and a missing
+// or malformed tenant with 400.
+func TenantMiddleware(resolver *TenantResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenantID, err := resolver.resolve(apiKeyTenant(c.Context()), c.Get(fiber.HeaderAuthorization), c.Get(tenantHeader))
+		switch err {
+		case nil:
+		case errInvalidToken:
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		case errTenantMismatch, errTenantClaim:
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// Handlers pass c.Context() to the services, whose Value looks up the locals
+		c.Locals(ports.TenantKey, tenantID)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	// idempotencyLockTTL bounds how long an in-flight request holds its key if the process dies mid-request
+	idempotencyLockTTL = time.Minute
+	// maxIdempotencyKeyLength is the longest idempotency key accepted from clients
+	maxIdempotencyKeyLength = 255
+)
+
+// hashRequest fingerprints a request so reusing a key with a different payload can be detected
+func hashRequest(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import "golang.org/x/text/language"
+
+// acceptLanguageHeader lists the locales the client prefers, as in "id-ID, id;q=0.9, en;q=0.5"
+const acceptLanguageHeader = "Accept-Language"
+
+// wildcardLocale is the tag Accept-Language wildcards parse to
+var wildcardLocale = language.MustParse("mul")
+
+// preferredLocales parses an Accept-Language value into canonical BCP 47 tags, the most preferred
+// first. Wildcards, excluded locales and malformed values are ignored, so the default locale applies.
+func preferredLocales(header string) []string {
+	tags, weights, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	var locales []string
+	for i, tag := range tags {
+		if tag != language.Und && tag != wildcardLocale && weights[i] > 0 {
+			locales = append(locales, tag.String())
+		}
+	}
+	return locales
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/config"
+)
+
+// apiKeyHeader carries the API key of integrations
+const apiKeyHeader = "X-API-Key"
+
+// Rate limit response headers
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// grpcRateLimitMethod is the method rate limit rules name to match gRPC calls
+const grpcRateLimitMethod = "GRPC"
+
+// RateLimitPolicy picks the rate limit rule of a request and the client it is counted against
+type RateLimitPolicy struct {
+	rules  []config.RateLimitRule
+	secret []byte
+}
+
+// NewRateLimitPolicy creates a new instance of RateLimitPolicy. Only credentials that verify identify
+// clients: API keys authenticated by the API key middleware, which runs first, and bearer tokens
+// signed with secret. Forged keys and subjects cannot spread requests over many buckets.
+func NewRateLimitPolicy(rules []config.RateLimitRule, secret string) *RateLimitPolicy {
+	return &RateLimitPolicy{
+		rules:  rules,
+		secret: []byte(secret),
+	}
+}
+
+// match returns the first rule for the method and route of a request, or nil when none applies
+func (p *RateLimitPolicy) match(method string, route string) *config.RateLimitRule {
+	for i := range p.rules {
+		rule := &p.rules[i]
+		if (rule.Method == "*" || rule.Method == method) && strings.HasPrefix(route, rule.Prefix) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// bucketKey returns the key of the bucket counting the requests of a client, preferring the
+// authenticated API key, then the subject of a verified bearer token and then the IP address
+func (p *RateLimitPolicy) bucketKey(rule *config.RateLimitRule, apiKey *entities.APIKey, authorization string, ip string) string {
+	client := "ip:" + ip
+	if token := strings.TrimPrefix(authorization, "Bearer "); len(p.secret) > 0 && token != "" {
+		if claims, err := verifyJWT(token, p.secret, time.Now()); err == nil {
+			if subject, _ := claims["sub"].(string); subject != "" {
+				client = "sub:" + subject
+			}
+		}
+	}
+	if apiKey != nil {
+		client = "key:" + apiKey.ID.Hex()
+	}
+
+	return rule.Method + ":" + rule.Prefix + ":" + client
+}
+
+// rateLimitHeaders returns the rate limit headers describing a result
+func rateLimitHeaders(result *ports.RateLimitResult) map[string]string {
+	return map[string]string{
+		rateLimitLimitHeader:     strconv.FormatInt(result.Limit, 10),
+		rateLimitRemainingHeader: strconv.FormatInt(result.Remaining, 10),
+		rateLimitResetHeader:     seconds(result.Reset),
+	}
+}
+
+// seconds formats a duration as whole seconds, rounded up so clients never retry too early
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"test-go/internal/core/ports"
+)
+
+// Request identification shared by the HTTP headers and the gRPC metadata keys
+const (
+	requestIDHeader = "X-Request-ID"
+	// actorHeader names the caller on whose behalf a change is made. It is taken on trust,
+	// so it must be set or stripped by an authenticating proxy in front of the service.
+	actorHeader = "X-Actor"
+
+	// maxRequestIDLength bounds client supplied request IDs, which are stored with every revision
+	maxRequestIDLength = 128
+)
+
+// newRequestID returns a random request ID for requests that did not bring one
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
+
+// requestID keeps a client supplied request ID when it is usable, or generates one
+func requestID(supplied string) string {
+	if supplied == "" || len(supplied) > maxRequestIDLength {
+		return newRequestID()
+	}
+	return supplied
+}
+
+// requestActor returns the actor named by the caller or, when there is none, the API key the
+// request was authenticated with
+func requestActor(ctx context.Context, named string) string {
+	if key := ports.APIKeyFromContext(ctx); named == "" && key != nil {
+		return "api_key:" + key.ID.Hex()
+	}
+	return named
+}
+
+// requestPrincipal returns who a request was authenticated as: its API key, or else the subject of
+// a bearer token verified with secret. It is empty when the request carries neither.
+func requestPrincipal(ctx context.Context, authorization string, secret []byte) string {
+	if key := ports.APIKeyFromContext(ctx); key != nil {
+		return "api_key:" + key.ID.Hex()
+	}
+	if token := strings.TrimPrefix(authorization, "Bearer "); len(secret) > 0 && token != "" {
+		if claims, err := verifyJWT(token, secret, time.Now()); err == nil {
+			if subject, _ := claims["sub"].(string); subject != "" {
+				return "sub:" + subject
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"test-go/internal/core/ports"
+)
+
+// tenantHeader names the tenant of requests that do not carry it in a token
+const tenantHeader = "X-Tenant-ID"
+
+// tenantIDPattern restricts tenant IDs to names that are safe in keys and routing keys, which
+// is why dots are not allowed
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+var (
+	errTenantRequired = errors.New("tenant is required")
+	errInvalidTenant  = errors.New("tenant ID must be 1 to 64 lowercase letters, digits, '-' or '_'")
+	errTenantMismatch = errors.New("tenant does not match the credentials")
+	errTenantClaim    = errors.New("token does not name a tenant")
+)
+
+// TenantResolver finds the tenant of a request. The tenant of an API key authenticated by the
+// API key middleware wins, then a bearer token verified with the configured secret; neither can be
+// overridden by the X-Tenant-ID header, and a verified token without the tenant claim is rejected
+// rather than left to the header. Without credentials the header names the tenant, and requests
+// naming none get ports.DefaultTenant unless a tenant is required.
+type TenantResolver struct {
+	secret   []byte
+	claim    string
+	required bool
+}
+
+// NewTenantResolver creates a new instance of TenantResolver. An empty secret ignores bearer tokens,
+// for deployments where an authenticating proxy sets the header.
+func NewTenantResolver(secret string, claim string, required bool) *TenantResolver {
+	return &TenantResolver{
+		secret:   []byte(secret),
+		claim:    claim,
+		required: required,
+	}
+}
+
+// resolve returns the tenant for the API key tenant, authorization and tenant header values of a request
+func (r *TenantResolver) resolve(keyTenant string, authorization string, header string) (string, error) {
+	tenantID := keyTenant
+	if token := strings.TrimPrefix(authorization, "Bearer "); tenantID == "" && len(r.secret) > 0 && token != "" {
+		claims, err := verifyJWT(token, r.secret, time.Now())
+		if err != nil {
+			return "", err
+		}
+		if tenantID, _ = claims[r.claim].(string); tenantID == "" {
+			return "", errTenantClaim
+		}
+	}
+
+	switch {
+	case tenantID == "":
+		tenantID = header
+	case header != "" && header != tenantID:
+		return "", errTenantMismatch
+	}
+
+	if tenantID == "" {
+		if r.required {
+			return "", errTenantRequired
+		}
+		return ports.DefaultTenant, nil
+	}
+	if !tenantIDPattern.MatchString(tenantID) {
+		return "", errInvalidTenant
+	}
+	return tenantID, nil
+}
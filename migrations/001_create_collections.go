@@ -1 +1,29 @@
 package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "create_collections",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for _, name := range []string{
+				"products",
+				"categories",
+				"inventory",
+				"reservations",
+				"webhooks",
+				"webhook_deliveries",
+			} {
+				if err := createCollectionIfMissing(ctx, db, name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
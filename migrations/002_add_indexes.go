@@ -1 +1,49 @@
 package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "add_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			indexes := map[string][]mongo.IndexModel{
+				"products": {
+					{Keys: bson.D{{Key: "category_ids", Value: 1}}},
+				},
+				"categories": {
+					{Keys: bson.D{{Key: "path", Value: 1}}},
+					{Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "position", Value: 1}}},
+				},
+				"inventory": {
+					{
+						Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "warehouse", Value: 1}},
+						Options: options.Index().SetUnique(true),
+					},
+				},
+				"reservations": {
+					{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+				},
+				"webhooks": {
+					{Keys: bson.D{{Key: "active", Value: 1}, {Key: "event_types", Value: 1}}},
+				},
+				"webhook_deliveries": {
+					{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				},
+			}
+
+			for collection, models := range indexes {
+				if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "add_variant_sku_index",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createCollectionIfMissing(ctx, db, "product_variants"); err != nil {
+				return err
+			}
+
+			_, err := db.Collection("product_variants").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "sku", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("sku_unique"),
+				},
+				{
+					Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "position", Value: 1}},
+				},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "add_product_type_index",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createCollectionIfMissing(ctx, db, "product_types"); err != nil {
+				return err
+			}
+
+			_, err := db.Collection("products").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys: bson.D{{Key: "type_id", Value: 1}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "add_product_name_created_at_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("products").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "name", Value: 1}}},
+				{Keys: bson.D{{Key: "created_at", Value: 1}}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "create_product_revisions",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// The unique index numbers revisions without gaps or duplicates, see ProductRevisionRepository.Append
+			_, err := db.Collection("product_revisions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "revision", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+				{Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "create_price_schedules",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("price_schedules").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "starts_at", Value: 1}}},
+				// The scheduler looks schedules up by the time they start or end
+				{Keys: bson.D{{Key: "starts_at", Value: 1}}},
+				{Keys: bson.D{{Key: "ends_at", Value: 1}}},
+			})
+			return err
+		},
+	})
+}
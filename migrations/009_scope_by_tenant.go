@@ -0,0 +1,138 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDB server error codes returned when dropping an index of a missing collection or a missing index
+const (
+	errCodeNamespaceNotFound = 26
+	errCodeIndexNotFound     = 27
+)
+
+// defaultTenant owns every document written before tenants were introduced, see ports.DefaultTenant
+const defaultTenant = "default"
+
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "scope_by_tenant",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			collections := []string{
+				"products", "product_types", "product_variants", "product_revisions", "categories",
+				"inventory", "reservations", "webhooks", "webhook_deliveries", "price_schedules",
+			}
+			for _, collection := range collections {
+				_, err := db.Collection(collection).UpdateMany(ctx,
+					bson.M{"tenant_id": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"tenant_id": defaultTenant}},
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Every query filters by tenant, so the indexes lead with it. The reservation reaper sweeps
+			// every tenant, so its status and expires_at index stays as it is. Unique indexes become
+			// unique per tenant, so two tenants may use the same SKU.
+			replaced := map[string][]string{
+				"products":           {"category_ids_1", "type_id_1", "name_1", "created_at_1"},
+				"categories":         {"path_1", "parent_id_1_position_1"},
+				"inventory":          {"product_id_1_warehouse_1"},
+				"webhooks":           {"active_1_event_types_1"},
+				"webhook_deliveries": {"subscription_id_1_created_at_-1"},
+				"product_variants":   {"sku_unique", "product_id_1_position_1"},
+				"product_revisions":  {"product_id_1_revision_1", "product_id_1_created_at_1"},
+				"price_schedules":    {"product_id_1_starts_at_1"},
+			}
+			for collection, names := range replaced {
+				for _, name := range names {
+					if err := dropIndexIfExists(ctx, db.Collection(collection), name); err != nil {
+						return err
+					}
+				}
+			}
+
+			unique := options.Index().SetUnique(true)
+			indexes := map[string][]mongo.IndexModel{
+				"products": {
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "category_ids", Value: 1}}},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "type_id", Value: 1}}},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}}},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				},
+				"product_types": {
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}}},
+				},
+				"categories": {
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "path", Value: 1}}},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "position", Value: 1}}},
+				},
+				"inventory": {
+					{
+						Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "warehouse", Value: 1}},
+						Options: unique,
+					},
+				},
+				"webhooks": {
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "active", Value: 1}, {Key: "event_types", Value: 1}}},
+				},
+				"webhook_deliveries": {
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "subscription_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				},
+				"product_variants": {
+					{
+						Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "sku", Value: 1}},
+						Options: options.Index().SetUnique(true).SetName("tenant_sku_unique"),
+					},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "position", Value: 1}}},
+				},
+				"product_revisions": {
+					{
+						Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "revision", Value: 1}},
+						Options: unique,
+					},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				},
+				// The scheduler sweeps every tenant, so the starts_at and ends_at indexes stay as they are
+				"price_schedules": {
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "starts_at", Value: 1}}},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "starts_at", Value: 1}, {Key: "ends_at", Value: 1}}},
+				},
+			}
+			for collection, models := range indexes {
+				if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+					return err
+				}
+			}
+
+			// Deletes only carry the product ID in the change stream; the pre-image tells which
+			// tenant the product belonged to. Pre-images need MongoDB 6.0, so older servers keep
+			// working but their product feeds leave deletes out.
+			err := db.RunCommand(ctx, bson.D{
+				{Key: "collMod", Value: "products"},
+				{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}},
+			}).Err()
+			if err != nil {
+				log.Printf("Could not enable change stream pre-images on products: %v", err)
+			}
+			return nil
+		},
+	})
+}
+
+// dropIndexIfExists drops an index by name, ignoring the error raised when it or its collection does not exist
+func dropIndexIfExists(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && (serverErr.HasErrorCode(errCodeIndexNotFound) || serverErr.HasErrorCode(errCodeNamespaceNotFound)) {
+		return nil
+	}
+	return err
+}
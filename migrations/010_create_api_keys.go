@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "create_api_keys",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("api_keys").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				// Requests are authenticated by looking the hash of their key up
+				{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "_id", Value: 1}}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "create_audit_log",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("audit_log").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				// Keeps each tenant's hash chain linear when writers race for the next sequence
+				{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "sequence", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "resource_id", Value: 1}, {Key: "sequence", Value: 1}}},
+				{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "actor", Value: 1}, {Key: "sequence", Value: 1}}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "create_media",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("media").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "position", Value: 1}}},
+				// Uploads are deduplicated and blobs released by content hash
+				{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "sha256", Value: 1}}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Version: 13,
+		Name:    "create_price_lists",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("price_lists").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "code", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			})
+			if err != nil {
+				return err
+			}
+
+			// Imports upsert by pair and effective time, and reads look up the latest rate of a pair
+			_, err = db.Collection("exchange_rates").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "base", Value: 1}, {Key: "quote", Value: 1}, {Key: "effective_at", Value: -1}},
+				Options: options.Index().SetUnique(true),
+			})
+			return err
+		},
+	})
+}
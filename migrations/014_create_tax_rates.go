@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Version: 14,
+		Name:    "create_tax_rates",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// One rate per jurisdiction, class and effective time; quotes look up the latest one
+			_, err := db.Collection("tax_rates").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "jurisdiction", Value: 1}, {Key: "tax_class", Value: 1}, {Key: "effective_from", Value: -1}},
+				Options: options.Index().SetUnique(true),
+			})
+			return err
+		},
+	})
+}
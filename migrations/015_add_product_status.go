@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	register(Migration{
+		Version: 15,
+		Name:    "add_product_status",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// Products were live as soon as they were created, so existing ones are active
+			products := db.Collection("products")
+			_, err := products.UpdateMany(ctx,
+				bson.M{"status": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"status": "active"}},
+			)
+			if err != nil {
+				return err
+			}
+
+			// The lifecycle scheduler sweeps every tenant for due activation and deactivation dates
+			_, err = products.Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "activate_at", Value: 1}}},
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "deactivate_at", Value: 1}}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	register(Migration{
+		Version: 16,
+		Name:    "create_webhook_pending_deliveries",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// The delivery worker claims the deliveries of every tenant that are due, oldest first
+			_, err := db.Collection("webhook_pending_deliveries").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys: bson.D{{Key: "next_attempt_at", Value: 1}},
+			})
+			return err
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migration is a single schema change applied once per database
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+}
+
+// appliedMigration is the record kept in the schema_migrations collection
+type appliedMigration struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// registry holds every migration registered by the numbered files in this package
+var registry []Migration
+
+// register adds a migration to the registry; called from init in each migration file
+func register(migration Migration) {
+	registry = append(registry, migration)
+}
+
+// Run applies every registered migration that has not been applied yet, in version order
+func Run(ctx context.Context, db *mongo.Database) error {
+	applied := db.Collection("schema_migrations")
+
+	sorted := make([]Migration, len(registry))
+	copy(sorted, registry)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for _, migration := range sorted {
+		err := applied.FindOne(ctx, bson.M{"_id": migration.Version}).Err()
+		if err == nil {
+			continue
+		}
+		if err != mongo.ErrNoDocuments {
+			return err
+		}
+
+		log.Printf("Applying migration %03d %s", migration.Version, migration.Name)
+		if err := migration.Up(ctx, db); err != nil {
+			return err
+		}
+
+		record := appliedMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		if _, err := applied.InsertOne(ctx, record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createCollectionIfMissing creates a collection, ignoring the error raised when it already exists
+func createCollectionIfMissing(ctx context.Context, db *mongo.Database, name string) error {
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": name})
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return nil
+	}
+	return db.CreateCollection(ctx, name)
+}
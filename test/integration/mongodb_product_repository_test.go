@@ -0,0 +1,69 @@
+//go:build mongodb
+
+package integration
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/repository/mongodb"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultMongoTestURI is used when MONGO_TEST_URI is not set
+const defaultMongoTestURI = "mongodb://localhost:27017"
+
+// TestMongoProductRepository runs the repository contract against a local mongod, giving every
+// test its own database. Run it with: go test -tags mongodb ./test/integration/...
+func TestMongoProductRepository(t *testing.T) {
+	client := connectMongo(t)
+	ports.RunProductRepositoryConformance(t, func(t *testing.T) ports.ProductRepository {
+		return mongodb.NewProductRepository(conformanceDatabase(t, client))
+	})
+}
+
+// TestMongoExchangeRateRepository runs the exchange rate contract against a local mongod
+func TestMongoExchangeRateRepository(t *testing.T) {
+	client := connectMongo(t)
+	ports.RunExchangeRateRepositoryConformance(t, func(t *testing.T) ports.ExchangeRateRepository {
+		return mongodb.NewExchangeRateRepository(conformanceDatabase(t, client))
+	})
+}
+
+// connectMongo connects to the mongod at MONGO_TEST_URI for the duration of the test
+func connectMongo(t *testing.T) *mongo.Client {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		uri = defaultMongoTestURI
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Failed to connect to MongoDB at %s: %v", uri, err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("Failed to reach MongoDB at %s: %v", uri, err)
+	}
+	return client
+}
+
+// conformanceDatabase returns a database of its own for a single conformance test, dropped afterwards
+func conformanceDatabase(t *testing.T, client *mongo.Client) *mongo.Database {
+	db := client.Database("conformance_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		if err := db.Drop(context.Background()); err != nil {
+			t.Logf("Failed to drop %s: %v", db.Name(), err)
+		}
+	})
+	return db
+}
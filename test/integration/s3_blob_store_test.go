@@ -0,0 +1,45 @@
+//go:build s3
+
+package integration
+
+import (
+	"os"
+	"testing"
+
+	"test-go/internal/adapters/secondary/blob"
+	"test-go/internal/core/ports"
+)
+
+// TestS3BlobStore runs the blob store contract against an S3-compatible service, by default a
+// local MinIO started with its default credentials and a bucket named catalog-test:
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	mc alias set local http://localhost:9000 minioadmin minioadmin && mc mb local/catalog-test
+//
+// Run it with: go test -tags s3 ./test/integration/...
+func TestS3BlobStore(t *testing.T) {
+	config := blob.S3Config{
+		Endpoint:  envOrDefault("S3_TEST_ENDPOINT", "http://localhost:9000"),
+		Region:    envOrDefault("S3_TEST_REGION", "us-east-1"),
+		Bucket:    envOrDefault("S3_TEST_BUCKET", "catalog-test"),
+		AccessKey: envOrDefault("S3_TEST_ACCESS_KEY", "minioadmin"),
+		SecretKey: envOrDefault("S3_TEST_SECRET_KEY", "minioadmin"),
+		PathStyle: os.Getenv("S3_TEST_VIRTUAL_HOSTED") == "",
+	}
+
+	ports.RunBlobStoreConformance(t, func(t *testing.T) ports.BlobStore {
+		store, err := blob.NewS3BlobStore(config)
+		if err != nil {
+			t.Fatalf("NewS3BlobStore: %v", err)
+		}
+		return store
+	})
+}
+
+// envOrDefault reads an environment variable or returns defaultValue if it is not set
+func envOrDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,194 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpadapter "test-go/internal/adapters/primary/http"
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/logging"
+	"test-go/internal/infrastructure/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const bootstrapKey = "ck_bootstrap0123456789abcdef0123456789abcdef"
+
+func newAPIKeyService() *application.APIKeyService {
+	events := memory.NewEventBus()
+	return application.NewAPIKeyService(memory.NewAPIKeyRepository(), memory.NewAPIKeyCache(), memory.NewAPIKeyCache(), events, events)
+}
+
+func newAPIKeyApp(service *application.APIKeyService) *fiber.App {
+	failures := middleware.NewAuthFailureLimiter(memory.NewRateLimiter(), ports.RateLimit{Requests: 10, Period: time.Minute, Burst: 20})
+	app := fiber.New()
+	app.Use(middleware.APIKeyMiddleware(service, failures, logging.NewLogger("test: ")))
+	app.Use(middleware.TenantMiddleware(middleware.NewTenantResolver("", "tenant_id", false)))
+	httpadapter.SetupAPIKeyRoutes(app, httpadapter.NewAPIKeyHandler(service))
+	return app
+}
+
+func postAPIKey(t *testing.T, app *fiber.App, headers map[string]string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/v1/admin/api-keys", strings.NewReader(`{"name":"ci","scopes":["admin"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestAPIKeyMiddlewareRejectsAnonymousKeyCreation(t *testing.T) {
+	app := newAPIKeyApp(newAPIKeyService())
+
+	for name, headers := range map[string]map[string]string{
+		"without tenant":      {},
+		"naming a tenant":     {"X-Tenant-ID": "acme"},
+		"with a bearer token": {"Authorization": "Bearer not-an-api-key"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			status, body := postAPIKey(t, app, headers)
+			if status != fiber.StatusUnauthorized {
+				t.Fatalf("expected 401, got %d", status)
+			}
+			if _, issued := body["key"]; issued {
+				t.Fatal("expected no key to be issued")
+			}
+		})
+	}
+}
+
+func TestAPIKeyMiddlewareRequiresTheAdminScope(t *testing.T) {
+	service := newAPIKeyService()
+	ctx := ports.WithTenant(context.Background(), "acme")
+	if err := service.SeedAPIKey(ctx, bootstrapKey); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	app := newAPIKeyApp(service)
+
+	status, body := postAPIKey(t, app, map[string]string{"X-API-Key": bootstrapKey})
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected the bootstrap key to create keys, got %d", status)
+	}
+	created, _ := body["key"].(string)
+	if key, err := service.Authenticate(ctx, created); err != nil || key.TenantID != "acme" {
+		t.Fatalf("expected the created key to belong to acme, got %+v (%v)", key, err)
+	}
+
+	admin := ports.WithAPIKey(ctx, &entities.APIKey{Scopes: []string{entities.ScopeAdmin}})
+	_, writer, err := service.CreateAPIKey(admin, "writer", []string{entities.ScopeCatalogWrite}, nil)
+	if err != nil {
+		t.Fatalf("create writer key: %v", err)
+	}
+	if status, _ := postAPIKey(t, app, map[string]string{"X-API-Key": writer}); status != fiber.StatusForbidden {
+		t.Fatalf("expected a key without the admin scope to get 403, got %d", status)
+	}
+}
+
+func TestAPIKeyServiceRequiresAnAdminKey(t *testing.T) {
+	service := newAPIKeyService()
+	ctx := ports.WithTenant(context.Background(), "acme")
+
+	if _, _, err := service.CreateAPIKey(ctx, "ci", []string{entities.ScopeAdmin}, nil); !errors.Is(err, application.ErrAPIKeyAdminRequired) {
+		t.Fatalf("expected ErrAPIKeyAdminRequired without a key, got %v", err)
+	}
+	reader := &entities.APIKey{Scopes: []string{entities.ScopeCatalogWrite}}
+	if _, err := service.ListAPIKeys(ports.WithAPIKey(ctx, reader)); !errors.Is(err, application.ErrAPIKeyAdminRequired) {
+		t.Fatalf("expected ErrAPIKeyAdminRequired for a catalog:write key, got %v", err)
+	}
+}
+
+func TestSeedAPIKeyIsIdempotent(t *testing.T) {
+	service := newAPIKeyService()
+	ctx := ports.WithTenant(context.Background(), "acme")
+	admin := ports.WithAPIKey(ctx, &entities.APIKey{Scopes: []string{entities.ScopeAdmin}})
+
+	for i := 0; i < 2; i++ {
+		if err := service.SeedAPIKey(ctx, bootstrapKey); err != nil {
+			t.Fatalf("seed %d: %v", i, err)
+		}
+	}
+	keys, err := service.ListAPIKeys(admin)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(keys) != 1 || !keys[0].HasScope(entities.ScopeAdmin) {
+		t.Fatalf("expected one admin key, got %+v", keys)
+	}
+
+	if err := service.SeedAPIKey(ctx, "not-a-key"); !errors.Is(err, ports.ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey for a key without the prefix, got %v", err)
+	}
+}
+
+// countingAuthenticator counts the keys looked up by the authenticator it wraps
+type countingAuthenticator struct {
+	ports.APIKeyAuthenticator
+	lookups atomic.Int64
+}
+
+func (a *countingAuthenticator) Authenticate(ctx context.Context, plaintext string) (*entities.APIKey, error) {
+	a.lookups.Add(1)
+	return a.APIKeyAuthenticator.Authenticate(ctx, plaintext)
+}
+
+func TestAPIKeyMiddlewareLimitsUnknownKeysPerIP(t *testing.T) {
+	const burst = 5
+	authenticator := &countingAuthenticator{APIKeyAuthenticator: newAPIKeyService()}
+	failures := middleware.NewAuthFailureLimiter(memory.NewRateLimiter(), ports.RateLimit{Requests: 1, Period: time.Hour, Burst: burst})
+	app := fiber.New()
+	app.Use(middleware.APIKeyMiddleware(authenticator, failures, logging.NewLogger("test: ")))
+	app.Get("/api/v1/products", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	statuses := make(map[int]int)
+	for i := 0; i < 50; i++ {
+		req := httptest.NewRequest("GET", "/api/v1/products", nil)
+		req.Header.Set("X-API-Key", fmt.Sprintf("ck_made-up-%d", i))
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		resp.Body.Close()
+		statuses[resp.StatusCode]++
+
+		if resp.StatusCode == fiber.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Fatalf("request %d: expected Retry-After on 429", i)
+		}
+	}
+
+	if statuses[fiber.StatusUnauthorized] != burst || statuses[fiber.StatusTooManyRequests] != 50-burst {
+		t.Fatalf("expected %d 401s and then only 429s, got %v", burst, statuses)
+	}
+	// The request emptying the bucket is looked up; every later one is turned away before
+	if lookups := authenticator.lookups.Load(); lookups != burst+1 {
+		t.Fatalf("expected %d key lookups, got %d", burst+1, lookups)
+	}
+
+	// Requests without a key are not held up by the failures of their address
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/products", nil))
+	if err != nil {
+		t.Fatalf("anonymous request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected an anonymous request to pass, got %d", resp.StatusCode)
+	}
+}
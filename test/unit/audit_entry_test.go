@@ -0,0 +1,51 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"test-go/internal/core/entities"
+)
+
+func newAuditEntry(principal string) *entities.AuditEntry {
+	entry := &entities.AuditEntry{
+		TenantID:     "acme",
+		Principal:    principal,
+		Actor:        "ci",
+		Operation:    "update",
+		ResourceType: entities.AuditResourceProduct,
+		ResourceID:   "p1",
+		Changes:      []entities.FieldChange{{Field: "price", From: float32(10), To: float32(12)}},
+		Transport:    "http",
+		RequestID:    "r1",
+		CreatedAt:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	entry.Seal(nil)
+	return entry
+}
+
+func TestAuditEntryHashCoversThePrincipal(t *testing.T) {
+	entry := newAuditEntry("api_key:writer")
+
+	for name, tamper := range map[string]func(*entities.AuditEntry){
+		"principal":    func(e *entities.AuditEntry) { e.Principal = "api_key:someone-else" },
+		"no principal": func(e *entities.AuditEntry) { e.Principal = "" },
+		"actor":        func(e *entities.AuditEntry) { e.Actor = "someone-else" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			tampered := *entry
+			tamper(&tampered)
+			if tampered.ComputeHash() == entry.Hash {
+				t.Fatal("expected the change to break the hash")
+			}
+		})
+	}
+}
+
+func TestAuditEntryHashWithoutPrincipalIsUnchanged(t *testing.T) {
+	// Hash of the same entry as sealed before principals were recorded
+	const legacy = "9c5705e2e700fba1cce4d228e8e9cc4a7508b1df35bcef77b6ec8b3521507062"
+	if hash := newAuditEntry("").Hash; hash != legacy {
+		t.Fatalf("expected entries without a principal to keep their hash %s, got %s", legacy, hash)
+	}
+}
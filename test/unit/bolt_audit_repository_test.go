@@ -0,0 +1,86 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/adapters/secondary/repository/boltdb"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+func openBoltStore(t *testing.T) *boltdb.Store {
+	store, err := boltdb.Open(filepath.Join(t.TempDir(), "catalog.db"), memory.NewChangeStream(0))
+	if err != nil {
+		t.Fatalf("open bolt store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestBoltAuditRepositoryChainsEntriesPerTenant(t *testing.T) {
+	store := openBoltStore(t)
+	repo := boltdb.NewAuditRepository(store)
+	ctx := context.Background()
+	other := ports.WithTenant(ctx, "other")
+
+	for _, c := range []context.Context{ctx, other, ctx, ctx} {
+		if err := repo.Append(c, &entities.AuditEntry{Operation: "update", ResourceType: entities.AuditResourceProduct}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	entries, err := repo.Find(ctx, ports.AuditFilter{})
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries of the default tenant, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Sequence != int64(i+1) {
+			t.Errorf("entry %d: expected sequence %d, got %d", i, i+1, entry.Sequence)
+		}
+		if entry.Hash != entry.ComputeHash() {
+			t.Errorf("entry %d: hash does not match its fields", i)
+		}
+		if i > 0 && entry.PrevHash != entries[i-1].Hash {
+			t.Errorf("entry %d: not chained to the entry before", i)
+		}
+	}
+
+	page, err := repo.Find(ctx, ports.AuditFilter{AfterSequence: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("find page: %v", err)
+	}
+	if len(page) != 1 || page[0].Sequence != 2 {
+		t.Fatalf("expected the page to hold sequence 2, got %+v", page)
+	}
+}
+
+func TestBoltAuditRepositoryRollsBackWithTheUnitOfWork(t *testing.T) {
+	store := openBoltStore(t)
+	ctx := context.Background()
+	failure := errors.New("write failed")
+
+	err := store.Do(ctx, func(tx ports.ProductTx) error {
+		if err := tx.Audit.Append(ctx, &entities.AuditEntry{Operation: "create", ResourceType: entities.AuditResourceProduct}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected the write failure, got %v", err)
+	}
+
+	entries, err := boltdb.NewAuditRepository(store).Find(ctx, ports.AuditFilter{})
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the entry to be rolled back, got %d entries", len(entries))
+	}
+}
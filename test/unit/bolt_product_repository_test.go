@@ -0,0 +1,21 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/adapters/secondary/repository/boltdb"
+	"test-go/internal/core/ports"
+)
+
+func TestBoltProductRepository(t *testing.T) {
+	ports.RunProductRepositoryConformance(t, func(t *testing.T) ports.ProductRepository {
+		store, err := boltdb.Open(filepath.Join(t.TempDir(), "catalog.db"), memory.NewChangeStream(0))
+		if err != nil {
+			t.Fatalf("open bolt store: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return boltdb.NewProductRepository(store)
+	})
+}
@@ -0,0 +1,212 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/repository/boltdb"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+func TestBoltRepositoriesSurviveReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.db")
+	ctx := context.Background()
+	now := time.Now()
+
+	store, err := boltdb.Open(path, nil)
+	if err != nil {
+		t.Fatalf("open bolt store: %v", err)
+	}
+	scheduleID, err := boltdb.NewPriceScheduleRepository(store).Create(ctx, &entities.PriceSchedule{ProductID: "p1", Price: 9, StartsAt: now, EndsAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	categoryID, err := boltdb.NewCategoryRepository(store).Create(ctx, &entities.Category{Name: "Shoes", Path: "/shoes/"})
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	productID := createBoltProduct(t, store, ctx)
+	if _, err := boltdb.NewInventoryRepository(store).AdjustOnHand(ctx, productID, entities.DefaultWarehouse, 5); err != nil {
+		t.Fatalf("adjust stock: %v", err)
+	}
+	webhookID, err := boltdb.NewWebhookRepository(store).Create(ctx, &entities.WebhookSubscription{URL: "https://example.com/hook", Active: true})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	if _, err := boltdb.NewAPIKeyRepository(store).Create(ctx, &entities.APIKey{Name: "ci", Hash: "h1"}); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	store, err = boltdb.Open(path, nil)
+	if err != nil {
+		t.Fatalf("reopen bolt store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	if _, err := boltdb.NewPriceScheduleRepository(store).FindByID(ctx, scheduleID); err != nil {
+		t.Errorf("expected the price schedule to be kept: %v", err)
+	}
+	if _, err := boltdb.NewCategoryRepository(store).FindByID(ctx, categoryID); err != nil {
+		t.Errorf("expected the category to be kept: %v", err)
+	}
+	if levels, err := boltdb.NewInventoryRepository(store).FindByProduct(ctx, productID); err != nil || len(levels) != 1 || levels[0].OnHand != 5 {
+		t.Errorf("expected 5 on hand to be kept, got %+v (%v)", levels, err)
+	}
+	if _, err := boltdb.NewWebhookRepository(store).FindByID(ctx, webhookID); err != nil {
+		t.Errorf("expected the webhook to be kept: %v", err)
+	}
+	if _, err := boltdb.NewAPIKeyRepository(store).FindByHash(ctx, "h1"); err != nil {
+		t.Errorf("expected the api key to be kept: %v", err)
+	}
+}
+
+func TestBoltRepositoriesScopeByTenant(t *testing.T) {
+	store := openBoltStore(t)
+	ctx := context.Background()
+	other := ports.WithTenant(ctx, "other")
+
+	webhooks := boltdb.NewWebhookRepository(store)
+	id, err := webhooks.Create(ctx, &entities.WebhookSubscription{URL: "https://example.com/hook", Active: true})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	if _, err := webhooks.FindByID(other, id); !errors.Is(err, ports.ErrWebhookNotFound) {
+		t.Errorf("expected another tenant not to find the webhook, got %v", err)
+	}
+	if err := webhooks.Delete(other, id); !errors.Is(err, ports.ErrWebhookNotFound) {
+		t.Errorf("expected another tenant not to delete the webhook, got %v", err)
+	}
+
+	inventory := boltdb.NewInventoryRepository(store)
+	productID := createBoltProduct(t, store, ctx)
+	if _, err := inventory.AdjustOnHand(ctx, productID, entities.DefaultWarehouse, 5); err != nil {
+		t.Fatalf("adjust stock: %v", err)
+	}
+	if _, err := inventory.Reserve(other, productID, entities.DefaultWarehouse, 1); !errors.Is(err, ports.ErrInsufficientStock) {
+		t.Errorf("expected another tenant to have no stock, got %v", err)
+	}
+
+	keys := boltdb.NewAPIKeyRepository(store)
+	if _, err := keys.Create(other, &entities.APIKey{Name: "ci", Hash: "h1"}); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	key, err := keys.FindByHash(ctx, "h1")
+	if err != nil || key.TenantID != "other" {
+		t.Fatalf("expected the key of the other tenant by hash, got %+v (%v)", key, err)
+	}
+	if _, err := keys.FindByID(ctx, key.ID.Hex()); !errors.Is(err, ports.ErrAPIKeyNotFound) {
+		t.Errorf("expected the default tenant not to find the key by ID, got %v", err)
+	}
+}
+
+func TestBoltInventoryRepositoryNeverGoesNegative(t *testing.T) {
+	store := openBoltStore(t)
+	inventory := boltdb.NewInventoryRepository(store)
+	ctx := context.Background()
+	productID := createBoltProduct(t, store, ctx)
+
+	if _, err := inventory.Reserve(ctx, productID, "east", 1); !errors.Is(err, ports.ErrInsufficientStock) {
+		t.Fatalf("expected no stock before any is added, got %v", err)
+	}
+	if _, err := inventory.AdjustOnHand(ctx, productID, "east", 3); err != nil {
+		t.Fatalf("adjust stock: %v", err)
+	}
+	if _, err := inventory.AdjustOnHand(ctx, productID, "west", 1); err != nil {
+		t.Fatalf("adjust stock: %v", err)
+	}
+	if _, err := inventory.Reserve(ctx, productID, "east", 2); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if _, err := inventory.AdjustOnHand(ctx, productID, "east", -2); !errors.Is(err, ports.ErrInsufficientStock) {
+		t.Errorf("expected reserved stock not to be removed, got %v", err)
+	}
+	level, err := inventory.Commit(ctx, productID, "east", 2)
+	if err != nil || level.OnHand != 1 || level.Reserved != 0 {
+		t.Fatalf("expected 1 on hand and none reserved after the commit, got %+v (%v)", level, err)
+	}
+
+	levels, err := inventory.FindByProduct(ctx, productID)
+	if err != nil || len(levels) != 2 || levels[0].Warehouse != "east" || levels[1].Warehouse != "west" {
+		t.Fatalf("expected the east and west levels in order, got %+v (%v)", levels, err)
+	}
+}
+
+func TestBoltPendingWebhookDeliveriesAreClaimedOnce(t *testing.T) {
+	pending := boltdb.NewPendingWebhookDeliveryRepository(openBoltStore(t))
+	ctx := context.Background()
+	now := time.Now()
+
+	deliveries := []*entities.PendingWebhookDelivery{
+		{EventID: "e2", NextAttemptAt: now.Add(-time.Minute)},
+		{EventID: "e1", NextAttemptAt: now.Add(-time.Hour)},
+		{EventID: "e3", NextAttemptAt: now.Add(time.Hour)},
+	}
+	if err := pending.Create(ctx, deliveries); err != nil {
+		t.Fatalf("queue: %v", err)
+	}
+
+	claimed, err := pending.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if len(claimed) != 2 || claimed[0].EventID != "e1" || claimed[1].EventID != "e2" {
+		t.Fatalf("expected e1 then e2, got %+v", claimed)
+	}
+	if again, err := pending.ClaimDue(ctx, now, now.Add(time.Minute), 10); err != nil || len(again) != 0 {
+		t.Fatalf("expected leased deliveries not to be claimed again, got %+v (%v)", again, err)
+	}
+
+	if err := pending.Delete(ctx, claimed[0].ID.Hex()); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := pending.Reschedule(ctx, claimed[1].ID.Hex(), 1, now); err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	due, err := pending.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+	if err != nil || len(due) != 1 || due[0].EventID != "e2" || due[0].Attempts != 1 {
+		t.Fatalf("expected only the rescheduled e2 to be due, got %+v (%v)", due, err)
+	}
+}
+
+func TestBoltCategoryRepositoryMovesDescendants(t *testing.T) {
+	categories := boltdb.NewCategoryRepository(openBoltStore(t))
+	ctx := context.Background()
+
+	for _, category := range []*entities.Category{
+		{Name: "Shoes", Path: "/shoes/", Depth: 0},
+		{Name: "Boots", Path: "/shoes/boots/", Depth: 1},
+		{Name: "Hiking", Path: "/shoes/boots/hiking/", Depth: 2},
+	} {
+		if _, err := categories.Create(ctx, category); err != nil {
+			t.Fatalf("create %s: %v", category.Name, err)
+		}
+	}
+
+	if err := categories.MoveDescendants(ctx, "/shoes/", "/sale/shoes/", 1); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	moved, err := categories.FindDescendants(ctx, "/sale/shoes/")
+	if err != nil {
+		t.Fatalf("find descendants: %v", err)
+	}
+	if len(moved) != 2 || moved[0].Path != "/sale/shoes/boots/" || moved[0].Depth != 2 || moved[1].Path != "/sale/shoes/boots/hiking/" || moved[1].Depth != 3 {
+		t.Fatalf("expected boots and hiking below /sale/shoes/, got %+v", moved)
+	}
+}
+
+// createBoltProduct stores a product of the tenant carried by ctx and returns its ID
+func createBoltProduct(t *testing.T, store *boltdb.Store, ctx context.Context) string {
+	t.Helper()
+	id, err := boltdb.NewProductRepository(store).Create(ctx, &entities.Product{Name: "Boots", Price: 1})
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	return id
+}
@@ -0,0 +1,369 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+// bundleFixture is a bundle service sharing the products of a product service fixture, with stock
+// kept in memory
+type bundleFixture struct {
+	*productServiceFixture
+	bundles *application.BundleService
+	stock   ports.InventoryRepository
+}
+
+func newBundleFixture() *bundleFixture {
+	f := &bundleFixture{productServiceFixture: newProductServiceFixture(application.TenantQuotas{})}
+	f.stock = memory.NewInventoryRepository(f.products)
+	f.bundles = application.NewBundleService(f.service, f.stock, nil)
+	return f
+}
+
+// createProduct creates a product at price, failing the test on error
+func (f *bundleFixture) createProduct(t *testing.T, ctx context.Context, name string, price float32) string {
+	t.Helper()
+	id, err := f.service.CreateProduct(ctx, name, "", price, "", nil)
+	if err != nil {
+		t.Fatalf("create %s: %v", name, err)
+	}
+	return id
+}
+
+// createBundle creates a product and makes it a bundle of components, one unit each, priced as their sum
+func (f *bundleFixture) createBundle(t *testing.T, ctx context.Context, name string, components ...string) string {
+	t.Helper()
+	id := f.createProduct(t, ctx, name, 0)
+	if err := f.bundles.SetBundle(ctx, id, sumOf(components...)); err != nil {
+		t.Fatalf("bundle %s: %v", name, err)
+	}
+	return id
+}
+
+// sumOf is a bundle of one unit of each component, priced as their sum
+func sumOf(components ...string) *entities.Bundle {
+	bundle := &entities.Bundle{Pricing: entities.BundlePriceSum}
+	for _, id := range components {
+		bundle.Components = append(bundle.Components, entities.BundleComponent{ProductID: id, Quantity: 1})
+	}
+	return bundle
+}
+
+// fieldViolations returns the violations of a *usecases.ValidationError keyed by field
+func fieldViolations(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var validationErr *usecases.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	violations := make(map[string]string, len(validationErr.Violations))
+	for _, violation := range validationErr.Violations {
+		violations[violation.Field] = violation.Description
+	}
+	return violations
+}
+
+func TestBundleServiceRejectsInvalidBundles(t *testing.T) {
+	f := newBundleFixture()
+	ctx := adminContext("acme")
+	mug := f.createProduct(t, ctx, "Mug", 10)
+	giftSet := f.createProduct(t, ctx, "Gift set", 0)
+	other := f.createProduct(t, adminContext("globex"), "Globex mug", 10)
+
+	for _, c := range []struct {
+		name   string
+		bundle *entities.Bundle
+		field  string
+		reason string // empty when only the field is checked
+	}{
+		{"unknown pricing", &entities.Bundle{Pricing: "free", Components: sumOf(mug).Components}, "bundle.pricing", "must be one of sum, discount, fixed"},
+		{"discount above 100%", &entities.Bundle{Pricing: entities.BundlePriceDiscount, DiscountPercent: 120, Components: sumOf(mug).Components}, "bundle.discount_percent", ""},
+		{"no components", &entities.Bundle{Pricing: entities.BundlePriceSum}, "bundle.components", ""},
+		{"the bundle itself", sumOf(giftSet), "bundle.components[0].product_id", "must not be the bundle itself"},
+		{"unknown component", sumOf(mug, "0123456789abcdef01234567"), "bundle.components[1].product_id", "must be an existing product"},
+		{"component of another tenant", sumOf(other), "bundle.components[0].product_id", "must be an existing product"},
+		{"repeated component", sumOf(mug, mug), "bundle.components[1].product_id", "is listed more than once"},
+		{"no units", &entities.Bundle{Pricing: entities.BundlePriceSum, Components: []entities.BundleComponent{{ProductID: mug}}}, "bundle.components[0].quantity", ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			violations := fieldViolations(t, f.bundles.SetBundle(ctx, giftSet, c.bundle))
+			reason, ok := violations[c.field]
+			if !ok || (c.reason != "" && reason != c.reason) {
+				t.Fatalf("expected %s to be rejected with %q, got %v", c.field, c.reason, violations)
+			}
+			if product, err := f.products.FindByID(ctx, giftSet); err != nil || product.Bundle != nil {
+				t.Fatalf("expected the rejected bundle not to be stored, got %+v (%v)", product, err)
+			}
+		})
+	}
+
+	// A deleted component is no longer an existing product
+	if err := f.service.DeleteProduct(ctx, mug); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if violations := fieldViolations(t, f.bundles.SetBundle(ctx, giftSet, sumOf(mug))); violations["bundle.components[0].product_id"] != "must be an existing product" {
+		t.Fatalf("expected the deleted component to be rejected, got %v", violations)
+	}
+}
+
+func TestBundleServiceRejectsCycles(t *testing.T) {
+	f := newBundleFixture()
+	ctx := adminContext("acme")
+	mug := f.createProduct(t, ctx, "Mug", 10)
+	inner := f.createBundle(t, ctx, "Inner", mug)
+	middle := f.createBundle(t, ctx, "Middle", inner)
+	outer := f.createBundle(t, ctx, "Outer", middle)
+
+	for _, c := range []struct {
+		name   string
+		bundle *entities.Bundle
+		field  string
+	}{
+		{"through its direct container", sumOf(middle), "bundle.components[0].product_id"},
+		{"through nested containers", sumOf(mug, outer), "bundle.components[1].product_id"},
+	} {
+		violations := fieldViolations(t, f.bundles.SetBundle(ctx, inner, c.bundle))
+		if violations[c.field] != "contains the bundle, which would make a cycle" {
+			t.Errorf("%s: expected a cycle on %s, got %v", c.name, c.field, violations)
+		}
+	}
+
+	// Containing the same product twice along different paths is no cycle
+	if err := f.bundles.SetBundle(ctx, outer, sumOf(middle, inner)); err != nil {
+		t.Fatalf("expected a diamond to be accepted, got %v", err)
+	}
+}
+
+func TestBundleServiceLimitsNestingDepth(t *testing.T) {
+	f := newBundleFixture()
+	ctx := adminContext("acme")
+
+	// Each level wraps the previous one, so the last holds MaxBundleDepth levels of bundles
+	level := f.createProduct(t, ctx, "Mug", 10)
+	for i := 0; i < usecases.MaxBundleDepth; i++ {
+		level = f.createBundle(t, ctx, fmt.Sprintf("Level %d", i+1), level)
+	}
+
+	tooDeep := f.createProduct(t, ctx, "Too deep", 0)
+	violations := fieldViolations(t, f.bundles.SetBundle(ctx, tooDeep, sumOf(level)))
+	if violations["bundle.components[0].product_id"] != "nests bundles more than 5 levels deep" {
+		t.Fatalf("expected the sixth level to be rejected, got %v", violations)
+	}
+}
+
+func TestBundleServiceStopsAvailabilityAtTheDepthCutOff(t *testing.T) {
+	f := newBundleFixture()
+	ctx := adminContext("acme")
+	mug := f.createProduct(t, ctx, "Mug", 10)
+	if _, err := f.stock.AdjustOnHand(ctx, mug, "main", 5); err != nil {
+		t.Fatalf("stock: %v", err)
+	}
+	inner := f.createBundle(t, ctx, "Inner", mug)
+	outer := f.createBundle(t, ctx, "Outer", inner)
+
+	// Bundles stored before validation caught the cycle, e.g. by concurrent updates
+	product, err := f.products.FindByID(ctx, inner)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	product.Bundle = sumOf(outer)
+	if err := f.products.Update(ctx, product); err != nil {
+		t.Fatalf("store cycle: %v", err)
+	}
+
+	view, err := f.bundles.GetBundle(ctx, outer, application.ProductStatusAny)
+	if err != nil {
+		t.Fatalf("expected the cycle to be cut off at the maximum depth, got %v", err)
+	}
+	if view.Available != 0 || len(view.Components) != 1 || view.Components[0].Available != 0 {
+		t.Fatalf("expected the cycle to count as unavailable, got %+v", view)
+	}
+}
+
+func TestBundleServiceDerivesAndRefreshesPrices(t *testing.T) {
+	f := newBundleFixture()
+	ctx := adminContext("acme")
+	mug := f.createProduct(t, ctx, "Mug", 10)
+	tea := f.createProduct(t, ctx, "Tea", 5.55)
+	components := []entities.BundleComponent{{ProductID: mug, Quantity: 2}, {ProductID: tea, Quantity: 1}}
+
+	bundles := make(map[string]string)
+	for _, c := range []struct {
+		name   string
+		bundle *entities.Bundle
+		price  float32
+		want   float32
+	}{
+		{"sum", &entities.Bundle{Pricing: entities.BundlePriceSum, Components: components}, 0, 25.55},
+		{"discount", &entities.Bundle{Pricing: entities.BundlePriceDiscount, DiscountPercent: 15, Components: components}, 0, 21.72},
+		{"fixed", &entities.Bundle{Pricing: entities.BundlePriceFixed, Components: components}, 19.99, 19.99},
+	} {
+		id := f.createProduct(t, ctx, "Tea set "+c.name, c.price)
+		if err := f.bundles.SetBundle(ctx, id, c.bundle); err != nil {
+			t.Fatalf("%s: bundle: %v", c.name, err)
+		}
+		view, err := f.bundles.GetBundle(ctx, id, application.ProductStatusAny)
+		if err != nil || view.Price != c.want {
+			t.Fatalf("%s: expected the bundle to cost %g, got %+v (%v)", c.name, c.want, view, err)
+		}
+		bundles[c.name] = id
+	}
+	// Nested bundles are priced from the derived price of the bundle they contain
+	bundles["gift box"] = f.createBundle(t, ctx, "Gift box", bundles["sum"])
+
+	if err := f.service.UpdateProduct(ctx, mug, "Mug", "", 12, "", nil); err != nil {
+		t.Fatalf("update mug: %v", err)
+	}
+	// The refreshed sum set is published in turn, which refreshes the gift box
+	for _, productID := range []string{mug, bundles["sum"]} {
+		if err := f.bundles.HandleEvent(context.Background(), &entities.ProductEvent{Type: entities.ProductUpdated, TenantID: "acme", ProductID: productID}); err != nil {
+			t.Fatalf("refresh: %v", err)
+		}
+	}
+
+	for name, want := range map[string]float32{
+		"sum":      29.55,
+		"discount": 25.12,
+		"fixed":    19.99,
+		"gift box": 29.55,
+	} {
+		if product, err := f.products.FindByID(ctx, bundles[name]); err != nil || product.Price != want {
+			t.Errorf("%s: expected the refreshed price %g, got %+v (%v)", name, want, product, err)
+		}
+	}
+}
+
+func TestBundleServiceKeepsComponentsUntilRemovedFromTheBundle(t *testing.T) {
+	f := newBundleFixture()
+	ctx := adminContext("acme")
+	mug := f.createProduct(t, ctx, "Mug", 10)
+	giftSet := f.createBundle(t, ctx, "Gift set", mug)
+
+	if err := f.service.DeleteProduct(ctx, mug); !errors.Is(err, usecases.ErrProductInBundle) {
+		t.Fatalf("expected ErrProductInBundle, got %v", err)
+	}
+	if err := f.bundles.RemoveBundle(ctx, mug); !errors.Is(err, application.ErrNotABundle) {
+		t.Fatalf("expected ErrNotABundle, got %v", err)
+	}
+	if err := f.bundles.RemoveBundle(ctx, giftSet); err != nil {
+		t.Fatalf("remove bundle: %v", err)
+	}
+	if product, err := f.products.FindByID(ctx, giftSet); err != nil || product.Bundle != nil || product.Price != 10 {
+		t.Fatalf("expected a plain product keeping its last derived price, got %+v (%v)", product, err)
+	}
+	if err := f.service.DeleteProduct(ctx, mug); err != nil {
+		t.Fatalf("expected the released component to be deleted, got %v", err)
+	}
+}
+
+func TestBundleServiceComputesAvailabilityFromComponentStock(t *testing.T) {
+	f := newBundleFixture()
+	ctx := adminContext("acme")
+	mug := f.createProduct(t, ctx, "Mug", 10)
+	tea := f.createProduct(t, ctx, "Tea", 5)
+	for _, stock := range []struct {
+		productID string
+		warehouse string
+		quantity  int64
+	}{
+		{mug, "north", 5},
+		{mug, "south", 2},
+		{tea, "north", 4},
+	} {
+		if _, err := f.stock.AdjustOnHand(ctx, stock.productID, stock.warehouse, stock.quantity); err != nil {
+			t.Fatalf("stock: %v", err)
+		}
+	}
+
+	giftSet := f.createProduct(t, ctx, "Gift set", 0)
+	if err := f.bundles.SetBundle(ctx, giftSet, &entities.Bundle{Pricing: entities.BundlePriceSum, Components: []entities.BundleComponent{
+		{ProductID: mug, Quantity: 2},
+		{ProductID: tea, Quantity: 1},
+	}}); err != nil {
+		t.Fatalf("bundle: %v", err)
+	}
+	crate := f.createProduct(t, ctx, "Crate", 0)
+	if err := f.bundles.SetBundle(ctx, crate, &entities.Bundle{Pricing: entities.BundlePriceSum, Components: []entities.BundleComponent{
+		{ProductID: giftSet, Quantity: 2},
+	}}); err != nil {
+		t.Fatalf("bundle: %v", err)
+	}
+
+	for _, c := range []struct {
+		reserveTea int64
+		giftSets   int64
+		crates     int64
+	}{
+		// 7 mugs make 3 sets, limited by the mugs, and a crate holds 2 sets
+		{0, 3, 1},
+		// Reserved tea is no longer available
+		{2, 2, 1},
+		{1, 1, 0},
+	} {
+		if c.reserveTea > 0 {
+			if _, err := f.stock.Reserve(ctx, tea, "north", c.reserveTea); err != nil {
+				t.Fatalf("reserve: %v", err)
+			}
+		}
+		view, err := f.bundles.GetBundle(ctx, giftSet, application.ProductStatusAny)
+		if err != nil || view.Available != c.giftSets {
+			t.Fatalf("expected %d gift sets, got %+v (%v)", c.giftSets, view, err)
+		}
+		if view.Components[0].Available != 7 || view.Components[0].Sets != 3 {
+			t.Fatalf("expected 7 mugs in every warehouse for 3 sets, got %+v", view.Components[0])
+		}
+		view, err = f.bundles.GetBundle(ctx, crate, application.ProductStatusAny)
+		if err != nil || view.Available != c.crates {
+			t.Fatalf("expected %d crates, got %+v (%v)", c.crates, view, err)
+		}
+	}
+
+	if _, err := f.bundles.GetBundle(ctx, mug, application.ProductStatusAny); !errors.Is(err, application.ErrNotABundle) {
+		t.Fatalf("expected ErrNotABundle, got %v", err)
+	}
+}
+
+func TestBundleServiceHidesComponentsTheCallerCannotRead(t *testing.T) {
+	f := newBundleFixture()
+	admin := adminContext("acme")
+	mug := f.createProduct(t, admin, "Mug", 10)
+	prototype := f.createProduct(t, admin, "Prototype", 99)
+	for _, id := range []string{mug, prototype} {
+		if _, err := f.stock.AdjustOnHand(admin, id, "main", 10); err != nil {
+			t.Fatalf("stock: %v", err)
+		}
+	}
+	giftSet := f.createBundle(t, admin, "Gift set", mug, prototype)
+
+	// The bundle and the mug go on sale while the prototype stays a draft
+	for _, id := range []string{giftSet, mug} {
+		product, err := f.products.FindByID(admin, id)
+		if err != nil {
+			t.Fatalf("find: %v", err)
+		}
+		product.Status = entities.ProductStatusActive
+		if err := f.products.Update(admin, product); err != nil {
+			t.Fatalf("activate: %v", err)
+		}
+	}
+
+	view, err := f.bundles.GetBundle(ports.WithTenant(context.Background(), "acme"), giftSet, "")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	hidden := view.Components[1]
+	if view.Available != 0 || hidden.Name != "" || hidden.Price != 0 || hidden.Available != 0 {
+		t.Fatalf("expected the draft component to be hidden and unavailable, got %+v", view)
+	}
+	if view.Components[0].Name != "Mug" || view.Components[0].Sets != 10 {
+		t.Fatalf("expected the active component to be listed, got %+v", view.Components[0])
+	}
+}
@@ -0,0 +1,243 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// categoryFixture is a category service sharing the products of a product service fixture
+type categoryFixture struct {
+	*productServiceFixture
+	categories *application.CategoryService
+}
+
+func newCategoryFixture() *categoryFixture {
+	f := &categoryFixture{productServiceFixture: newProductServiceFixture(application.TenantQuotas{})}
+	f.categories = application.NewCategoryService(memory.NewCategoryRepository(), f.products, f.service, f.events)
+	return f
+}
+
+// createCategory creates a category, failing the test on error
+func (f *categoryFixture) createCategory(t *testing.T, ctx context.Context, name string, parent *entities.Category) *entities.Category {
+	t.Helper()
+	parentID := ""
+	if parent != nil {
+		parentID = parent.ID.Hex()
+	}
+	category, err := f.categories.CreateCategory(ctx, name, parentID)
+	if err != nil {
+		t.Fatalf("create %s: %v", name, err)
+	}
+	return category
+}
+
+// categoryNames returns the names of categories in order
+func categoryNames(categories []*entities.Category) []string {
+	names := make([]string, 0, len(categories))
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+	return names
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCategoryServiceRejectsMovesIntoItsOwnSubtree(t *testing.T) {
+	f := newCategoryFixture()
+	ctx := adminContext("acme")
+	shoes := f.createCategory(t, ctx, "Shoes", nil)
+	boots := f.createCategory(t, ctx, "Boots", shoes)
+	hiking := f.createCategory(t, ctx, "Hiking", boots)
+
+	for _, c := range []struct {
+		name   string
+		moved  *entities.Category
+		parent *entities.Category
+	}{
+		{"below itself", boots, boots},
+		{"below its child", boots, hiking},
+		{"root below its grandchild", shoes, hiking},
+	} {
+		if _, err := f.categories.MoveCategory(ctx, c.moved.ID.Hex(), c.parent.ID.Hex(), 0); !errors.Is(err, application.ErrCategoryCycle) {
+			t.Errorf("%s: expected ErrCategoryCycle, got %v", c.name, err)
+		}
+	}
+
+	// A sibling is outside the subtree
+	sandals := f.createCategory(t, ctx, "Sandals", shoes)
+	if _, err := f.categories.MoveCategory(ctx, boots.ID.Hex(), sandals.ID.Hex(), 0); err != nil {
+		t.Fatalf("expected a move below a sibling to succeed, got %v", err)
+	}
+	breadcrumb, err := f.categories.GetBreadcrumb(ctx, hiking.ID.Hex())
+	if err != nil {
+		t.Fatalf("breadcrumb: %v", err)
+	}
+	if names := categoryNames(breadcrumb); !equalStrings(names, []string{"Shoes", "Sandals", "Boots", "Hiking"}) {
+		t.Fatalf("expected the subtree to move along, got %v", names)
+	}
+}
+
+func TestCategoryServiceMovesSubtreesToTheRootAndReorders(t *testing.T) {
+	f := newCategoryFixture()
+	ctx := adminContext("acme")
+	shoes := f.createCategory(t, ctx, "Shoes", nil)
+	boots := f.createCategory(t, ctx, "Boots", shoes)
+	f.createCategory(t, ctx, "Hiking", boots)
+	f.createCategory(t, ctx, "Sandals", shoes)
+	f.createCategory(t, ctx, "Trainers", shoes)
+
+	moved, err := f.categories.MoveCategory(ctx, boots.ID.Hex(), "", -1)
+	if err != nil {
+		t.Fatalf("move to root: %v", err)
+	}
+	if moved.Depth != 0 || moved.ParentID != "" || moved.Position != 1 {
+		t.Fatalf("expected boots last among the roots, got %+v", moved)
+	}
+	descendants, err := f.categories.ListDescendants(ctx, boots.ID.Hex())
+	if err != nil || len(descendants) != 1 || descendants[0].Depth != 1 {
+		t.Fatalf("expected hiking one level below boots, got %+v (%v)", descendants, err)
+	}
+
+	children, err := f.categories.ListChildren(ctx, shoes.ID.Hex())
+	if err != nil {
+		t.Fatalf("children: %v", err)
+	}
+	if names := categoryNames(children); !equalStrings(names, []string{"Sandals", "Trainers"}) {
+		t.Fatalf("expected the remaining children, got %v", names)
+	}
+
+	trainers := children[1]
+	for _, c := range []struct {
+		position int
+		want     []string
+	}{
+		{0, []string{"Trainers", "Sandals"}},
+		// Positions out of range put the category last
+		{99, []string{"Sandals", "Trainers"}},
+	} {
+		if _, err := f.categories.MoveCategory(ctx, trainers.ID.Hex(), shoes.ID.Hex(), c.position); err != nil {
+			t.Fatalf("reorder to %d: %v", c.position, err)
+		}
+		reordered, err := f.categories.ListChildren(ctx, shoes.ID.Hex())
+		if err != nil {
+			t.Fatalf("children: %v", err)
+		}
+		if names := categoryNames(reordered); !equalStrings(names, c.want) {
+			t.Fatalf("position %d: expected %v, got %v", c.position, c.want, names)
+		}
+	}
+}
+
+func TestCategoryServiceRefusesToDeleteNonEmptyCategories(t *testing.T) {
+	f := newCategoryFixture()
+	ctx := adminContext("acme")
+	shoes := f.createCategory(t, ctx, "Shoes", nil)
+	boots := f.createCategory(t, ctx, "Boots", shoes)
+
+	if err := f.categories.DeleteCategory(ctx, shoes.ID.Hex()); !errors.Is(err, application.ErrCategoryNotEmpty) {
+		t.Fatalf("expected a category with subcategories not to be deleted, got %v", err)
+	}
+
+	productID, err := f.service.CreateProduct(ctx, "Chelsea boots", "", 90, "", nil)
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	if err := f.categories.AssignProductCategories(ctx, productID, []string{boots.ID.Hex()}); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if err := f.categories.DeleteCategory(ctx, boots.ID.Hex()); !errors.Is(err, application.ErrCategoryNotEmpty) {
+		t.Fatalf("expected a category with products not to be deleted, got %v", err)
+	}
+
+	if err := f.categories.AssignProductCategories(ctx, productID, nil); err != nil {
+		t.Fatalf("unassign: %v", err)
+	}
+	if err := f.categories.DeleteCategory(ctx, boots.ID.Hex()); err != nil {
+		t.Fatalf("expected the emptied category to be deleted, got %v", err)
+	}
+}
+
+func TestCategoryServiceAssignsAndListsProductsWithDescendants(t *testing.T) {
+	f := newCategoryFixture()
+	ctx := adminContext("acme")
+	shoes := f.createCategory(t, ctx, "Shoes", nil)
+	boots := f.createCategory(t, ctx, "Boots", shoes)
+
+	productID, err := f.service.CreateProduct(ctx, "Chelsea boots", "", 90, "", nil)
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	for _, c := range []struct {
+		name        string
+		ctx         context.Context
+		categoryIDs []string
+	}{
+		{"unknown category", ctx, []string{boots.ID.Hex(), "0123456789abcdef01234567"}},
+		{"malformed ID", ctx, []string{"boots"}},
+		{"category of another tenant", adminContext("globex"), []string{boots.ID.Hex()}},
+	} {
+		if err := f.categories.AssignProductCategories(c.ctx, productID, c.categoryIDs); !errors.Is(err, ports.ErrCategoryNotFound) {
+			t.Errorf("%s: expected ErrCategoryNotFound, got %v", c.name, err)
+		}
+	}
+
+	if err := f.categories.AssignProductCategories(ctx, productID, []string{boots.ID.Hex(), boots.ID.Hex()}); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	product, err := f.products.FindByID(ctx, productID)
+	if err != nil || len(product.CategoryIDs) != 1 {
+		t.Fatalf("expected the duplicate assignment to be stored once, got %+v (%v)", product, err)
+	}
+
+	for _, c := range []struct {
+		name               string
+		includeDescendants bool
+		status             string
+		want               int
+	}{
+		{"direct only", false, application.ProductStatusAny, 0},
+		{"with descendants", true, application.ProductStatusAny, 1},
+		{"active with descendants", true, "", 0},
+	} {
+		products, err := f.categories.ListCategoryProducts(ctx, shoes.ID.Hex(), c.includeDescendants, c.status)
+		if err != nil || len(products) != c.want {
+			t.Errorf("%s: expected %d products, got %d (%v)", c.name, c.want, len(products), err)
+		}
+	}
+}
+
+func TestCategoryServiceRejectsBlankNames(t *testing.T) {
+	f := newCategoryFixture()
+	ctx := adminContext("acme")
+
+	if _, err := f.categories.CreateCategory(ctx, " \t", ""); !errors.Is(err, application.ErrInvalidCategoryName) {
+		t.Fatalf("expected a blank name to be rejected, got %v", err)
+	}
+	shoes := f.createCategory(t, ctx, "  Shoes ", nil)
+	if shoes.Name != "Shoes" {
+		t.Fatalf("expected the name to be trimmed, got %q", shoes.Name)
+	}
+	if err := f.categories.RenameCategory(ctx, shoes.ID.Hex(), ""); !errors.Is(err, application.ErrInvalidCategoryName) {
+		t.Fatalf("expected a blank rename to be rejected, got %v", err)
+	}
+	if _, err := f.categories.CreateCategory(ctx, "Boots", "0123456789abcdef01234567"); !errors.Is(err, ports.ErrCategoryNotFound) {
+		t.Fatalf("expected an unknown parent to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package unit
+
+import (
+	"testing"
+
+	"test-go/internal/adapters/secondary/blob"
+	"test-go/internal/core/ports"
+)
+
+func TestFilesystemBlobStore(t *testing.T) {
+	ports.RunBlobStoreConformance(t, func(t *testing.T) ports.BlobStore {
+		store, err := blob.NewFilesystemBlobStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("NewFilesystemBlobStore: %v", err)
+		}
+		return store
+	})
+}
@@ -0,0 +1,193 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/infrastructure/logging"
+	"test-go/internal/infrastructure/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// idempotentApp serves POST /items, numbering every call that reaches the handler, behind the tenant
+// and idempotency middleware. A body of "fail" answers 500 and a body of "block" waits for release.
+func idempotentApp(ttl time.Duration) (app *fiber.App, calls *atomic.Int64, started chan struct{}, release chan struct{}) {
+	calls = &atomic.Int64{}
+	started = make(chan struct{}, 1)
+	release = make(chan struct{})
+
+	app = fiber.New()
+	app.Use(middleware.TenantMiddleware(middleware.NewTenantResolver(tenantTestSecret, "tenant_id", false)))
+	app.Use(middleware.IdempotencyMiddleware(memory.NewIdempotencyStore(), ttl, logging.NewLogger("test: ")))
+	handler := func(c *fiber.Ctx) error {
+		n := calls.Add(1)
+		switch string(c.Body()) {
+		case "fail":
+			return c.Status(fiber.StatusInternalServerError).SendString("boom")
+		case "block":
+			started <- struct{}{}
+			<-release
+		}
+		return c.Status(fiber.StatusCreated).SendString("item " + string(rune('0'+n)))
+	}
+	app.Post("/items", handler)
+	app.Get("/items", handler)
+	return app, calls, started, release
+}
+
+// sendIdempotent makes a request with an Idempotency-Key and returns the status, body and whether
+// the response was replayed
+func sendIdempotent(t *testing.T, app *fiber.App, method string, key string, tenant string, body string) (int, string, bool) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/items", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", key)
+	if tenant != "" {
+		req.Header.Set("X-Tenant-ID", tenant)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data), resp.Header.Get("Idempotent-Replayed") == "true"
+}
+
+func TestIdempotencyMiddlewareReplaysAndRejectsMisuse(t *testing.T) {
+	app, calls, _, _ := idempotentApp(time.Hour)
+
+	status, body, replayed := sendIdempotent(t, app, http.MethodPost, "k1", "", "a")
+	if status != fiber.StatusCreated || body != "item 1" || replayed {
+		t.Fatalf("expected the first request to run, got %d %q (replayed %v)", status, body, replayed)
+	}
+	status, body, replayed = sendIdempotent(t, app, http.MethodPost, "k1", "", "a")
+	if status != fiber.StatusCreated || body != "item 1" || !replayed {
+		t.Fatalf("expected the retry to replay the first response, got %d %q (replayed %v)", status, body, replayed)
+	}
+	if status, _, _ := sendIdempotent(t, app, http.MethodPost, "k1", "", "b"); status != fiber.StatusUnprocessableEntity {
+		t.Fatalf("expected a different payload under the same key to get 422, got %d", status)
+	}
+	if status, body, _ := sendIdempotent(t, app, http.MethodPost, "k1", "globex", "a"); status != fiber.StatusCreated || body != "item 2" {
+		t.Fatalf("expected another tenant to use the same key independently, got %d %q", status, body)
+	}
+	if status, _, _ := sendIdempotent(t, app, http.MethodPost, strings.Repeat("k", 256), "", "a"); status != fiber.StatusBadRequest {
+		t.Fatalf("expected an overlong key to get 400, got %d", status)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected the handler to run twice, ran %d times", calls.Load())
+	}
+
+	// Reads are not remembered
+	sendIdempotent(t, app, http.MethodGet, "k2", "", "")
+	if _, body, replayed := sendIdempotent(t, app, http.MethodGet, "k2", "", ""); replayed || body != "item 4" {
+		t.Fatalf("expected GET to run again, got %q (replayed %v)", body, replayed)
+	}
+}
+
+func TestIdempotencyMiddlewareLetsServerErrorsBeRetried(t *testing.T) {
+	app, calls, _, _ := idempotentApp(time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if status, _, replayed := sendIdempotent(t, app, http.MethodPost, "k1", "", "fail"); status != fiber.StatusInternalServerError || replayed {
+			t.Fatalf("attempt %d: expected the 500 to be returned, not replayed, got %d (replayed %v)", i, status, replayed)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected both attempts to reach the handler, got %d", calls.Load())
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsDuplicatesInFlight(t *testing.T) {
+	app, _, started, release := idempotentApp(time.Hour)
+
+	done := make(chan int)
+	go func() {
+		status, _, _ := sendIdempotent(t, app, http.MethodPost, "k1", "", "block")
+		done <- status
+	}()
+	<-started
+
+	if status, _, _ := sendIdempotent(t, app, http.MethodPost, "k1", "", "block"); status != fiber.StatusConflict {
+		t.Fatalf("expected a duplicate in flight to get 409, got %d", status)
+	}
+	close(release)
+	if status := <-done; status != fiber.StatusCreated {
+		t.Fatalf("expected the first request to finish, got %d", status)
+	}
+	if status, _, replayed := sendIdempotent(t, app, http.MethodPost, "k1", "", "block"); status != fiber.StatusCreated || !replayed {
+		t.Fatalf("expected the finished request to be replayed, got %d (replayed %v)", status, replayed)
+	}
+}
+
+func TestIdempotencyMiddlewareForgetsResponsesAfterTheWindow(t *testing.T) {
+	app, calls, _, _ := idempotentApp(50 * time.Millisecond)
+
+	sendIdempotent(t, app, http.MethodPost, "k1", "", "a")
+	time.Sleep(100 * time.Millisecond)
+	if _, body, replayed := sendIdempotent(t, app, http.MethodPost, "k1", "", "b"); replayed || body != "item 2" {
+		t.Fatalf("expected the key to be free once the window passed, got %q (replayed %v)", body, replayed)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected both requests to run, got %d", calls.Load())
+	}
+}
+
+func TestUnaryIdempotencyInterceptorReplaysResponsesAndFinalErrors(t *testing.T) {
+	interceptor := middleware.UnaryIdempotencyInterceptor(memory.NewIdempotencyStore(), time.Hour, logging.NewLogger("test: "))
+	info := &grpc.UnaryServerInfo{FullMethod: "/product.ProductService/CreateProduct"}
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		switch req.(*wrapperspb.StringValue).Value {
+		case "missing":
+			return nil, status.Error(codes.NotFound, "product not found")
+		case "unavailable":
+			return nil, status.Error(codes.Unavailable, "database unavailable")
+		}
+		return wrapperspb.String("created"), nil
+	}
+	call := func(key string, value string) (interface{}, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", key))
+		return interceptor(ctx, wrapperspb.String(value), info, handler)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := call("k1", "boots")
+		if err != nil || resp.(*wrapperspb.StringValue).Value != "created" {
+			t.Fatalf("attempt %d: expected the created response, got %v (%v)", i, resp, err)
+		}
+	}
+	if _, err := call("k1", "shoes"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected another request under the same key to fail with InvalidArgument, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := call("k2", "missing"); status.Code(err) != codes.NotFound {
+			t.Fatalf("attempt %d: expected NotFound, got %v", i, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := call("k3", "unavailable"); status.Code(err) != codes.Unavailable {
+			t.Fatalf("attempt %d: expected Unavailable, got %v", i, err)
+		}
+	}
+	// boots once, missing once as NotFound is replayed, unavailable twice as it can be retried
+	if calls != 4 {
+		t.Fatalf("expected the handler to run 4 times, ran %d", calls)
+	}
+	if _, err := call(strings.Repeat("k", 256), "boots"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an overlong key to fail with InvalidArgument, got %v", err)
+	}
+}
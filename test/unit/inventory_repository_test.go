@@ -0,0 +1,62 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/adapters/secondary/repository/boltdb"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+func TestInventoryRepositoriesOnlyStockProductsOfTheTenant(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		open func(t *testing.T) (ports.InventoryRepository, ports.ProductRepository)
+	}{
+		{"memory", func(t *testing.T) (ports.InventoryRepository, ports.ProductRepository) {
+			products := memory.NewProductRepository(nil)
+			return memory.NewInventoryRepository(products), products
+		}},
+		{"bolt", func(t *testing.T) (ports.InventoryRepository, ports.ProductRepository) {
+			store := openBoltStore(t)
+			return boltdb.NewInventoryRepository(store), boltdb.NewProductRepository(store)
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			inventory, products := c.open(t)
+			ctx := ports.WithTenant(context.Background(), "acme")
+			other := ports.WithTenant(context.Background(), "globex")
+
+			productID, err := products.Create(ctx, &entities.Product{Name: "Boots", Price: 1})
+			if err != nil {
+				t.Fatalf("create product: %v", err)
+			}
+			if level, err := inventory.AdjustOnHand(ctx, productID, entities.DefaultWarehouse, 5); err != nil || level.OnHand != 5 {
+				t.Fatalf("expected 5 on hand, got %+v (%v)", level, err)
+			}
+
+			for _, p := range []struct {
+				name      string
+				ctx       context.Context
+				productID string
+			}{
+				{"unknown product", ctx, "0123456789abcdef01234567"},
+				{"malformed ID", ctx, "p1"},
+				{"product of another tenant", other, productID},
+			} {
+				if _, err := inventory.AdjustOnHand(p.ctx, p.productID, entities.DefaultWarehouse, 1); !errors.Is(err, ports.ErrProductNotFound) {
+					t.Errorf("%s: expected adjusting to fail with ErrProductNotFound, got %v", p.name, err)
+				}
+				if _, err := inventory.SetLowStockThreshold(p.ctx, p.productID, entities.DefaultWarehouse, 2); !errors.Is(err, ports.ErrProductNotFound) {
+					t.Errorf("%s: expected setting the threshold to fail with ErrProductNotFound, got %v", p.name, err)
+				}
+			}
+			if levels, err := inventory.FindByProduct(other, productID); err != nil || len(levels) != 0 {
+				t.Fatalf("expected no stock for another tenant, got %+v (%v)", levels, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+	"test-go/internal/infrastructure/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParseLocaleCanonicalizesTags(t *testing.T) {
+	for _, c := range []struct {
+		locale string
+		want   string
+		err    error
+	}{
+		{"id-ID", "id-ID", nil},
+		{"ID_id", "id-ID", nil},
+		{"EN", "en", nil},
+		{"zh-hant-tw", "zh-Hant-TW", nil},
+		{"", "", usecases.ErrInvalidLocale},
+		{"und", "", usecases.ErrInvalidLocale},
+		{"english please", "", usecases.ErrInvalidLocale},
+	} {
+		got, err := usecases.ParseLocale(c.locale)
+		if got != c.want || !errors.Is(err, c.err) {
+			t.Errorf("%q: expected %q (%v), got %q (%v)", c.locale, c.want, c.err, got, err)
+		}
+	}
+}
+
+func TestLocaleFallbacksEndWithTheDefaultLocale(t *testing.T) {
+	for _, c := range []struct {
+		locale string
+		want   []string
+	}{
+		{"id-ID", []string{"id-ID", "id", "en"}},
+		// Parents follow CLDR, which groups the English of most countries under en-001
+		{"en-GB", []string{"en-GB", "en-001", "en"}},
+		{"en", []string{"en"}},
+		// A malformed locale falls back to the default locale straight away
+		{"--", []string{"en"}},
+	} {
+		if got := usecases.LocaleFallbacks(c.locale, "en"); !equalStrings(got, c.want) {
+			t.Errorf("%s: expected %v, got %v", c.locale, c.want, got)
+		}
+	}
+}
+
+func TestLocalizeProductPicksEachFieldAlongTheFallbacks(t *testing.T) {
+	newProduct := func() *entities.Product {
+		return &entities.Product{
+			Name:        "Coffee",
+			Description: "Roasted beans",
+			Translations: map[string]entities.ProductTranslation{
+				"id":    {Name: "Kopi"},
+				"pt":    {Name: "Café", Description: "Grãos torrados"},
+				"es-MX": {Description: "Granos tostados"},
+			},
+		}
+	}
+
+	for _, c := range []struct {
+		name        string
+		preferences []string
+		wantName    string
+		wantDesc    string
+		wantLocale  string
+	}{
+		{"no preference", nil, "Coffee", "Roasted beans", "en"},
+		{"parent of the locale", []string{"id-ID"}, "Kopi", "Roasted beans", "id"},
+		{"parent before the next preference", []string{"pt-BR", "id"}, "Café", "Grãos torrados", "pt"},
+		{"description only", []string{"es-MX"}, "Coffee", "Granos tostados", "en"},
+		{"untranslated preferences", []string{"fr-CA", "de"}, "Coffee", "Roasted beans", "en"},
+		{"default locale preferred", []string{"en-US", "id"}, "Coffee", "Roasted beans", "en"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			product := newProduct()
+			usecases.LocalizeProduct(product, c.preferences, "en")
+			if product.Name != c.wantName || product.Description != c.wantDesc || product.Locale != c.wantLocale {
+				t.Fatalf("expected %q/%q in %s, got %q/%q in %s", c.wantName, c.wantDesc, c.wantLocale, product.Name, product.Description, product.Locale)
+			}
+		})
+	}
+}
+
+func TestSearchAndSortFollowTheLocale(t *testing.T) {
+	products := []*entities.Product{
+		{Name: "Zucchini", Price: 3},
+		{Name: "Éclair", Price: 2, Description: "Choux pastry"},
+		{Name: "apple", Price: 1},
+		{Name: "Café crème", Price: 4},
+	}
+
+	for _, c := range []struct {
+		query string
+		want  []string
+	}{
+		{"cafe", []string{"Café crème"}},
+		{"ECLAIR", []string{"Éclair"}},
+		{"pastry", []string{"Éclair"}},
+		{"tea", nil},
+	} {
+		if got := productNames(usecases.SearchProducts(products, c.query, "fr")); !equalStrings(got, c.want) {
+			t.Errorf("search %q: expected %v, got %v", c.query, c.want, got)
+		}
+	}
+
+	for _, c := range []struct {
+		sortBy string
+		want   []string
+	}{
+		{usecases.SortByName, []string{"apple", "Café crème", "Éclair", "Zucchini"}},
+		{"-" + usecases.SortByName, []string{"Zucchini", "Éclair", "Café crème", "apple"}},
+		{usecases.SortByPrice, []string{"apple", "Éclair", "Zucchini", "Café crème"}},
+	} {
+		if err := usecases.SortProducts(products, c.sortBy, "fr"); err != nil {
+			t.Fatalf("sort %s: %v", c.sortBy, err)
+		}
+		if got := productNames(products); !equalStrings(got, c.want) {
+			t.Errorf("sort %s: expected %v, got %v", c.sortBy, c.want, got)
+		}
+	}
+	if err := usecases.SortProducts(products, "stock", "fr"); !errors.Is(err, usecases.ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort, got %v", err)
+	}
+}
+
+// productNames returns the names of products in order
+func productNames(products []*entities.Product) []string {
+	var names []string
+	for _, product := range products {
+		names = append(names, product.Name)
+	}
+	return names
+}
+
+func TestLocaleMiddlewareReadsAcceptLanguage(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.LocaleMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(strings.Join(ports.LocalesFromContext(c.Context()), ","))
+	})
+
+	for _, c := range []struct {
+		header string
+		want   string
+	}{
+		{"id-ID, id;q=0.9, en;q=0.5", "id-ID,id,en"},
+		{"en;q=0.5, pt_br", "pt-BR,en"},
+		// Wildcards and excluded locales are dropped
+		{"*, fr;q=0, de;q=0.1", "de"},
+		{"", ""},
+		{"en;q=high", ""},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Language", c.header)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if got := string(body); got != c.want {
+			t.Errorf("%q: expected %q, got %q", c.header, c.want, got)
+		}
+		if vary := resp.Header.Get("Vary"); !strings.Contains(vary, "Accept-Language") {
+			t.Errorf("%q: expected responses to vary by Accept-Language, got %q", c.header, vary)
+		}
+	}
+}
+
+func TestUnaryLocaleInterceptorReadsMetadata(t *testing.T) {
+	interceptor := middleware.UnaryLocaleInterceptor()
+	var got []string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = ports.LocalesFromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "id-ID", "accept-language", "en;q=0.5"))
+	if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+	if !equalStrings(got, []string{"id-ID", "en"}) {
+		t.Fatalf("expected the locales of every metadata value, got %v", got)
+	}
+
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil || got != nil {
+		t.Fatalf("expected no locales without metadata, got %v (%v)", got, err)
+	}
+}
+
+func TestProductServiceManagesTranslations(t *testing.T) {
+	f := newProductServiceFixture(application.TenantQuotas{})
+	ctx := adminContext("acme")
+	id, err := f.service.CreateProduct(ctx, "Coffee", "Roasted beans", 8, "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := f.service.SetProductTranslation(ctx, id, "ID_id", entities.ProductTranslation{Name: "Kopi"}); err != nil {
+		t.Fatalf("set translation: %v", err)
+	}
+	if err := f.service.SetProductTranslation(ctx, id, "not a locale", entities.ProductTranslation{Name: "Kopi"}); !errors.Is(err, usecases.ErrInvalidLocale) {
+		t.Fatalf("expected ErrInvalidLocale, got %v", err)
+	}
+	for _, c := range []struct {
+		name string
+		text entities.ProductTranslation
+	}{
+		{"empty text", entities.ProductTranslation{}},
+		{"overlong name", entities.ProductTranslation{Name: strings.Repeat("n", 1000)}},
+	} {
+		var validationErr *usecases.ValidationError
+		if err := f.service.SetProductTranslation(ctx, id, "fr", c.text); !errors.As(err, &validationErr) {
+			t.Errorf("%s: expected a ValidationError, got %v", c.name, err)
+		}
+	}
+
+	for _, c := range []struct {
+		preferences []string
+		wantName    string
+		wantLocale  string
+	}{
+		{[]string{"id-ID"}, "Kopi", "id-ID"},
+		// Fallbacks only go from a locale to its parents, never to a more specific locale
+		{[]string{"id"}, "Coffee", "en"},
+	} {
+		product, err := f.service.GetProductByID(ports.WithLocales(ctx, c.preferences), id, application.ProductStatusAny, application.PriceQuery{})
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if product.Name != c.wantName || product.Description != "Roasted beans" || product.Locale != c.wantLocale {
+			t.Errorf("%v: expected %q with the default description in %s, got %q/%q in %s", c.preferences, c.wantName, c.wantLocale, product.Name, product.Description, product.Locale)
+		}
+	}
+
+	// The default locale is the product itself and cannot be deleted
+	if err := f.service.SetProductTranslation(ctx, id, "en", entities.ProductTranslation{Name: "Ground coffee", Description: "Roasted beans"}); err != nil {
+		t.Fatalf("set default locale: %v", err)
+	}
+	translations, err := f.service.GetProductTranslations(ctx, id)
+	if err != nil || translations["en"].Name != "Ground coffee" || len(translations) != 2 {
+		t.Fatalf("expected the default and id-ID translations, got %v (%v)", translations, err)
+	}
+	if err := f.service.DeleteProductTranslation(ctx, id, "en"); !errors.Is(err, application.ErrDefaultLocaleTranslation) {
+		t.Fatalf("expected ErrDefaultLocaleTranslation, got %v", err)
+	}
+	if err := f.service.DeleteProductTranslation(ctx, id, "fr"); !errors.Is(err, application.ErrTranslationNotFound) {
+		t.Fatalf("expected ErrTranslationNotFound, got %v", err)
+	}
+	if err := f.service.DeleteProductTranslation(ctx, id, "id-ID"); err != nil {
+		t.Fatalf("delete translation: %v", err)
+	}
+}
+
+func TestProductServiceReportsMissingTranslations(t *testing.T) {
+	f := newProductServiceFixture(application.TenantQuotas{})
+	ctx := adminContext("acme")
+	coffee, err := f.service.CreateProduct(ctx, "Coffee", "Roasted beans", 8, "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := f.service.CreateProduct(ctx, "Tea", "", 5, "", nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := f.service.SetProductTranslation(ctx, coffee, "id", entities.ProductTranslation{Name: "Kopi"}); err != nil {
+		t.Fatalf("set translation: %v", err)
+	}
+
+	missing, err := f.service.MissingTranslations(ctx, []string{"EN", "id"})
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	// Coffee lacks its description in id, tea its name; a product without description needs none
+	var report []string
+	for _, entry := range missing {
+		report = append(report, entry.Name+"/"+entry.Locale+"/"+strings.Join(entry.Fields, "+"))
+	}
+	want := map[string]bool{"Coffee/id/description": true, "Tea/id/name": true}
+	if len(report) != len(want) || !want[report[0]] || !want[report[1]] {
+		t.Fatalf("expected %v, got %v", want, report)
+	}
+
+	if _, err := f.service.MissingTranslations(ctx, []string{"id", "??"}); !errors.Is(err, usecases.ErrInvalidLocale) {
+		t.Fatalf("expected ErrInvalidLocale, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package unit
+
+import (
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/core/ports"
+)
+
+func TestMemoryExchangeRateRepository(t *testing.T) {
+	ports.RunExchangeRateRepositoryConformance(t, func(t *testing.T) ports.ExchangeRateRepository {
+		return memory.NewExchangeRateRepository()
+	})
+}
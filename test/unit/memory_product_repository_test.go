@@ -0,0 +1,14 @@
+package unit
+
+import (
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/core/ports"
+)
+
+func TestMemoryProductRepository(t *testing.T) {
+	ports.RunProductRepositoryConformance(t, func(t *testing.T) ports.ProductRepository {
+		return memory.NewProductRepository(memory.NewChangeStream(0))
+	})
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/adapters/secondary/repository/boltdb"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// priceScheduleRepositories opens each repository the overlap check is enforced by
+func priceScheduleRepositories() []struct {
+	name string
+	open func(t *testing.T) ports.PriceScheduleRepository
+} {
+	return []struct {
+		name string
+		open func(t *testing.T) ports.PriceScheduleRepository
+	}{
+		{"memory", func(t *testing.T) ports.PriceScheduleRepository { return memory.NewPriceScheduleRepository() }},
+		{"bolt", func(t *testing.T) ports.PriceScheduleRepository {
+			return boltdb.NewPriceScheduleRepository(openBoltStore(t))
+		}},
+	}
+}
+
+func TestPriceScheduleRepositoriesRejectOverlaps(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	for _, r := range priceScheduleRepositories() {
+		t.Run(r.name, func(t *testing.T) {
+			schedules := r.open(t)
+			ctx := ports.WithTenant(context.Background(), "acme")
+			existing := &entities.PriceSchedule{ProductID: "p1", Price: 10, StartsAt: start, EndsAt: start.Add(2 * day)}
+			if _, err := schedules.Create(ctx, existing); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			for _, c := range []struct {
+				name     string
+				ctx      context.Context
+				schedule *entities.PriceSchedule
+				want     error
+			}{
+				{"overlapping", ctx, &entities.PriceSchedule{ProductID: "p1", StartsAt: start.Add(day), EndsAt: start.Add(3 * day)}, ports.ErrPriceScheduleOverlap},
+				{"inside", ctx, &entities.PriceSchedule{ProductID: "p1", StartsAt: start.Add(time.Hour), EndsAt: start.Add(2 * time.Hour)}, ports.ErrPriceScheduleOverlap},
+				{"starting as it ends", ctx, &entities.PriceSchedule{ProductID: "p1", StartsAt: start.Add(2 * day), EndsAt: start.Add(3 * day)}, nil},
+				{"higher priority", ctx, &entities.PriceSchedule{ProductID: "p1", StartsAt: start, EndsAt: start.Add(day), Priority: 1}, nil},
+				{"another product", ctx, &entities.PriceSchedule{ProductID: "p2", StartsAt: start, EndsAt: start.Add(day)}, nil},
+				{"another tenant", ports.WithTenant(context.Background(), "globex"), &entities.PriceSchedule{ProductID: "p1", StartsAt: start, EndsAt: start.Add(day)}, nil},
+			} {
+				if _, err := schedules.Create(c.ctx, c.schedule); !errors.Is(err, c.want) {
+					t.Errorf("%s: expected %v, got %v", c.name, c.want, err)
+				}
+			}
+
+			moved := *existing
+			moved.StartsAt = start.Add(-day)
+			if err := schedules.Update(ctx, &moved); err != nil {
+				t.Errorf("expected a schedule not to conflict with itself, got %v", err)
+			}
+			moved.EndsAt = start.Add(5 * day)
+			if err := schedules.Update(ctx, &moved); !errors.Is(err, ports.ErrPriceScheduleOverlap) {
+				t.Errorf("expected an update onto the next schedule to overlap, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPriceScheduleRepositoriesAcceptOneOfConcurrentOverlappingCreates(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const writers = 20
+
+	for _, r := range priceScheduleRepositories() {
+		t.Run(r.name, func(t *testing.T) {
+			schedules := r.open(t)
+			ctx := ports.WithTenant(context.Background(), "acme")
+
+			var wg sync.WaitGroup
+			errs := make(chan error, writers)
+			for i := 0; i < writers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					offset := time.Duration(i) * time.Minute
+					_, err := schedules.Create(ctx, &entities.PriceSchedule{ProductID: "p1", Price: 5, StartsAt: start.Add(offset), EndsAt: start.Add(time.Hour + offset)})
+					errs <- err
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+
+			created := 0
+			for err := range errs {
+				switch {
+				case err == nil:
+					created++
+				case !errors.Is(err, ports.ErrPriceScheduleOverlap):
+					t.Fatalf("create: %v", err)
+				}
+			}
+			stored, err := schedules.FindByProductID(ctx, "p1")
+			if err != nil {
+				t.Fatalf("find: %v", err)
+			}
+			if created != 1 || len(stored) != 1 {
+				t.Fatalf("expected exactly one of the overlapping schedules, created %d and stored %d", created, len(stored))
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+func bound(v float64) *float64 { return &v }
+
+// attributeReasons returns the violations of an *usecases.AttributeError keyed by attribute
+func attributeReasons(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var attributeErr *usecases.AttributeError
+	if !errors.As(err, &attributeErr) {
+		t.Fatalf("expected an AttributeError, got %v", err)
+	}
+	reasons := make(map[string]string, len(attributeErr.Violations))
+	for _, violation := range attributeErr.Violations {
+		reasons[violation.Attribute] = violation.Reason
+	}
+	return reasons
+}
+
+func TestValidateAttributeSchema(t *testing.T) {
+	for _, c := range []struct {
+		name        string
+		definitions []entities.AttributeDefinition
+		want        map[string]string
+	}{
+		{"every type", []entities.AttributeDefinition{
+			{Name: "isbn", Type: entities.AttributeString, Pattern: `^\d{13}$`},
+			{Name: "pages", Type: entities.AttributeNumber, Min: bound(1), Max: bound(1)},
+			{Name: "format", Type: entities.AttributeEnum, Values: []string{"hardcover"}},
+			{Name: "signed", Type: entities.AttributeBoolean},
+			{Name: "published_on", Type: entities.AttributeDate},
+		}, nil},
+		{"bad names", []entities.AttributeDefinition{
+			{Name: "1st", Type: entities.AttributeString},
+			{Name: "page-count", Type: entities.AttributeNumber},
+		}, map[string]string{
+			"1st":        "name must start with a letter and contain only letters, digits and underscores",
+			"page-count": "name must start with a letter and contain only letters, digits and underscores",
+		}},
+		{"duplicate name", []entities.AttributeDefinition{
+			{Name: "isbn", Type: entities.AttributeString},
+			{Name: "isbn", Type: entities.AttributeNumber},
+		}, map[string]string{"isbn": "is defined more than once"}},
+		{"unknown type", []entities.AttributeDefinition{{Name: "weight", Type: "decimal"}}, map[string]string{"weight": `unknown type "decimal"`}},
+		{"enum without values", []entities.AttributeDefinition{{Name: "format", Type: entities.AttributeEnum}}, map[string]string{"format": "enum needs at least one value"}},
+		{"min above max", []entities.AttributeDefinition{{Name: "pages", Type: entities.AttributeNumber, Min: bound(2), Max: bound(1)}}, map[string]string{"pages": "min is greater than max"}},
+		{"invalid pattern", []entities.AttributeDefinition{{Name: "isbn", Type: entities.AttributeString, Pattern: "("}}, map[string]string{"isbn": "pattern is not a valid regular expression"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := usecases.ValidateAttributeSchema(c.definitions)
+			if c.want == nil {
+				if err != nil {
+					t.Fatalf("expected a valid schema, got %v", err)
+				}
+				return
+			}
+			if got := attributeReasons(t, err); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateAttributesChecksAndNormalizesValues(t *testing.T) {
+	book := &entities.ProductType{Attributes: []entities.AttributeDefinition{
+		{Name: "isbn", Type: entities.AttributeString, Required: true, Pattern: `^\d{13}$`},
+		{Name: "title", Type: entities.AttributeString, Min: bound(2), Max: bound(4)},
+		{Name: "pages", Type: entities.AttributeNumber, Min: bound(1), Max: bound(2000)},
+		{Name: "format", Type: entities.AttributeEnum, Values: []string{"hardcover", "paperback"}},
+		{Name: "signed", Type: entities.AttributeBoolean},
+		{Name: "published_on", Type: entities.AttributeDate},
+	}}
+	isbn := "9780132350884"
+
+	for _, c := range []struct {
+		name   string
+		values map[string]interface{}
+		want   interface{} // the normalized value of the one other attribute, or the reason it is rejected
+		reject bool
+	}{
+		{"int pages become float64", map[string]interface{}{"pages": 464}, float64(464), false},
+		{"int64 pages become float64", map[string]interface{}{"pages": int64(2000)}, float64(2000), false},
+		{"pages at min", map[string]interface{}{"pages": 1.0}, float64(1), false},
+		{"pages below min", map[string]interface{}{"pages": 0.5}, "must be at least 1", true},
+		{"pages above max", map[string]interface{}{"pages": 2000.5}, "must be at most 2000", true},
+		{"pages NaN", map[string]interface{}{"pages": math.NaN()}, "must be a number", true},
+		{"pages infinite", map[string]interface{}{"pages": math.Inf(1)}, "must be a number", true},
+		{"pages as text", map[string]interface{}{"pages": "464"}, "must be a number", true},
+		{"title counts characters", map[string]interface{}{"title": "Éééé"}, "Éééé", false},
+		{"title too short", map[string]interface{}{"title": "A"}, "must be at least 2 characters", true},
+		{"title too long", map[string]interface{}{"title": "Clean"}, "must be at most 4 characters", true},
+		{"enum choice", map[string]interface{}{"format": "paperback"}, "paperback", false},
+		{"enum is case sensitive", map[string]interface{}{"format": "Paperback"}, "must be one of hardcover, paperback", true},
+		{"boolean as text", map[string]interface{}{"signed": "true"}, "must be true or false", true},
+		{"date", map[string]interface{}{"published_on": "2008-08-01"}, "2008-08-01", false},
+		{"timestamp becomes a UTC date", map[string]interface{}{"published_on": "2008-08-01T23:30:00-02:00"}, "2008-08-02", false},
+		{"date in another layout", map[string]interface{}{"published_on": "01/08/2008"}, "must be a date formatted as YYYY-MM-DD", true},
+		{"impossible date", map[string]interface{}{"published_on": "2008-02-30"}, "must be a date formatted as YYYY-MM-DD", true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			values := map[string]interface{}{"isbn": isbn}
+			var name string
+			for key, value := range c.values {
+				name = key
+				values[key] = value
+			}
+
+			normalized, err := usecases.ValidateAttributes(book, values)
+			if c.reject {
+				if got := attributeReasons(t, err); got[name] != c.want || len(got) != 1 {
+					t.Fatalf("expected only %s to be rejected with %q, got %v", name, c.want, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected the values to be valid, got %v", err)
+			}
+			if got := normalized[name]; got != c.want {
+				t.Fatalf("expected %s to be stored as %#v, got %#v", name, c.want, got)
+			}
+		})
+	}
+
+	t.Run("missing and undefined attributes", func(t *testing.T) {
+		_, err := usecases.ValidateAttributes(book, map[string]interface{}{"isbn": nil, "colour": "red", "author": "Martin"})
+		want := map[string]string{
+			"author": "is not defined by the product type",
+			"colour": "is not defined by the product type",
+			"isbn":   "is required",
+		}
+		if got := attributeReasons(t, err); !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+}
+
+func TestCheckProductAttributesRequiresAKnownType(t *testing.T) {
+	types := memory.NewProductTypeRepository()
+	acme := adminContext("acme")
+	typeID, err := types.Create(acme, &entities.ProductType{Name: "Book", Attributes: []entities.AttributeDefinition{
+		{Name: "pages", Type: entities.AttributeNumber},
+	}})
+	if err != nil {
+		t.Fatalf("create type: %v", err)
+	}
+
+	for _, c := range []struct {
+		name    string
+		ctx     context.Context
+		product *entities.Product
+		reason  string
+	}{
+		{"attributes without a type", acme, &entities.Product{Attributes: map[string]interface{}{"pages": 464}}, "is required to set attributes"},
+		{"unknown type", acme, &entities.Product{TypeID: "0123456789abcdef01234567"}, "unknown product type"},
+		{"malformed type", acme, &entities.Product{TypeID: "book"}, "unknown product type"},
+		{"type of another tenant", adminContext("globex"), &entities.Product{TypeID: typeID}, "unknown product type"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := usecases.CheckProductAttributes(c.ctx, types, c.product)
+			if got := attributeReasons(t, err); got["type_id"] != c.reason {
+				t.Fatalf("expected %q, got %v", c.reason, got)
+			}
+		})
+	}
+
+	untyped := &entities.Product{}
+	if err := usecases.CheckProductAttributes(acme, types, untyped); err != nil {
+		t.Fatalf("expected a product without type or attributes to pass, got %v", err)
+	}
+	typed := &entities.Product{TypeID: typeID, Attributes: map[string]interface{}{"pages": 464}}
+	if err := usecases.CheckProductAttributes(acme, types, typed); err != nil || typed.Attributes["pages"] != float64(464) {
+		t.Fatalf("expected the attributes to be normalized, got %v (%v)", typed.Attributes, err)
+	}
+}
+
+func TestParseAttributeConditionAndMatch(t *testing.T) {
+	book := &entities.ProductType{Attributes: []entities.AttributeDefinition{
+		{Name: "pages", Type: entities.AttributeNumber},
+		{Name: "format", Type: entities.AttributeEnum, Values: []string{"hardcover", "paperback"}},
+		{Name: "signed", Type: entities.AttributeBoolean},
+		{Name: "published_on", Type: entities.AttributeDate},
+	}}
+
+	for _, c := range []struct {
+		name   string
+		attr   string
+		op     string
+		raw    string
+		reason string
+	}{
+		{"undefined attribute", "colour", "", "red", "is not defined by the product type"},
+		{"unknown operator", "pages", "between", "1", `unknown operator "between"`},
+		{"ordering an enum", "format", ports.FilterGt, "hardcover", "only numbers and dates can be compared with gt"},
+		{"number that is not", "pages", ports.FilterGte, "many", "must be a number"},
+		{"boolean that is not", "signed", "", "yes", "must be true or false"},
+		{"date that is not", "published_on", ports.FilterLt, "last year", "must be a date formatted as YYYY-MM-DD"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := usecases.ParseAttributeCondition(book, c.attr, c.op, c.raw)
+			if got := attributeReasons(t, err); got[c.attr] != c.reason {
+				t.Fatalf("expected %q, got %v", c.reason, got)
+			}
+		})
+	}
+
+	attributes := map[string]interface{}{"pages": float64(464), "published_on": "2008-08-01", "signed": false}
+	for _, c := range []struct {
+		attr  string
+		op    string
+		raw   string
+		match bool
+	}{
+		{"pages", "", "464", true},
+		{"pages", ports.FilterGt, "463.5", true},
+		{"pages", ports.FilterLte, "463", false},
+		{"published_on", ports.FilterGte, "2008-08-01T00:00:00Z", true},
+		{"published_on", ports.FilterLt, "2008-08-01", false},
+		{"signed", "", "false", true},
+		// ne matches products without the attribute, the other operators never do
+		{"format", ports.FilterNe, "hardcover", true},
+		{"format", ports.FilterEq, "hardcover", false},
+	} {
+		condition, err := usecases.ParseAttributeCondition(book, c.attr, c.op, c.raw)
+		if err != nil {
+			t.Fatalf("%s %s %s: %v", c.attr, c.op, c.raw, err)
+		}
+		if got := condition.Matches(attributes); got != c.match {
+			t.Errorf("%s %s %s: expected match %v, got %v", c.attr, c.op, c.raw, c.match, got)
+		}
+	}
+
+	// Values of different types never compare
+	mismatched := ports.AttributeCondition{Name: "pages", Op: ports.FilterEq, Value: "464"}
+	if mismatched.Matches(attributes) {
+		t.Fatal("expected a string not to equal a number")
+	}
+}
@@ -0,0 +1,207 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// feedWarmupTenant is the tenant of the events startProductFeed appends until the feed is watching
+const feedWarmupTenant = "feed-warmup"
+
+// startProductFeed runs a product feed on an in-memory change stream and returns once the feed is
+// watching it, so every change appended afterwards reaches the feed
+func startProductFeed(t *testing.T, historySize int, bufferSize int) (*application.ProductFeed, *memory.ChangeStream) {
+	t.Helper()
+	changes := memory.NewChangeStream(0)
+	service := application.NewProductService(application.ProductServiceDeps{Watcher: changes, BaseCurrency: "USD"})
+	feed := application.NewProductFeed(service, historySize, bufferSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go feed.Run(ctx)
+
+	probe := feed.Subscribe(application.FeedFilter{TenantID: feedWarmupTenant}, 0)
+	defer feed.Unsubscribe(probe)
+	deadline := time.After(5 * time.Second)
+	for {
+		changes.Append(&entities.ProductEvent{Type: entities.ProductUpdated, TenantID: feedWarmupTenant})
+		select {
+		case <-probe.Events():
+			return feed, changes
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("the feed never started watching")
+		}
+	}
+}
+
+// nextFeedEvent waits for the next event of a subscription, failing when it is closed or idle
+func nextFeedEvent(t *testing.T, sub *application.Subscription) application.FeedEvent {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+	return application.FeedEvent{}
+}
+
+// expectNoFeedEvent fails when the subscription has an event queued
+func expectNoFeedEvent(t *testing.T, sub *application.Subscription) {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		t.Fatalf("expected no event, got %+v (open %v)", event, ok)
+	default:
+	}
+}
+
+func TestProductFeedFiltersByTenantProductAndType(t *testing.T) {
+	feed, changes := startProductFeed(t, 10, 10)
+
+	sub := feed.Subscribe(application.FeedFilter{
+		TenantID:   "acme",
+		ProductIDs: map[string]struct{}{"p1": {}},
+		Types:      map[string]struct{}{entities.ProductDeleted: {}},
+	}, 0)
+	defer feed.Unsubscribe(sub)
+	everything := feed.Subscribe(application.FeedFilter{TenantID: "acme"}, 0)
+	defer feed.Unsubscribe(everything)
+
+	for _, event := range []*entities.ProductEvent{
+		{Type: entities.ProductDeleted, TenantID: "globex", ProductID: "p1"},
+		{Type: entities.ProductUpdated, TenantID: "acme", ProductID: "p1"},
+		{Type: entities.ProductDeleted, TenantID: "acme", ProductID: "p2"},
+		{Type: entities.ProductDeleted, TenantID: "acme", ProductID: "p1"},
+	} {
+		changes.Append(event)
+	}
+
+	// The unfiltered subscriber sees the three acme events, so the feed is past all of them
+	for i := 0; i < 3; i++ {
+		nextFeedEvent(t, everything)
+	}
+	event := nextFeedEvent(t, sub)
+	if event.TenantID != "acme" || event.ProductID != "p1" || event.Type != entities.ProductDeleted {
+		t.Fatalf("expected only the deletion of p1 of acme, got %+v", event.ProductEvent)
+	}
+	expectNoFeedEvent(t, sub)
+}
+
+func TestProductFeedReplaysOnlyWhatTheHistoryHolds(t *testing.T) {
+	feed, changes := startProductFeed(t, 3, 10)
+
+	live := feed.Subscribe(application.FeedFilter{TenantID: "acme"}, 0)
+	defer feed.Unsubscribe(live)
+	var ids []uint64
+	for i := 0; i < 5; i++ {
+		changes.Append(&entities.ProductEvent{Type: entities.ProductUpdated, TenantID: "acme", ProductID: "p1"})
+		ids = append(ids, nextFeedEvent(t, live).ID)
+	}
+
+	for _, c := range []struct {
+		name        string
+		lastEventID uint64
+		want        []uint64
+	}{
+		{"no Last-Event-ID", 0, nil},
+		{"older than the history", ids[0], ids[2:]},
+		{"within the history", ids[3], ids[4:]},
+		{"latest", ids[4], nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			sub := feed.Subscribe(application.FeedFilter{TenantID: "acme"}, c.lastEventID)
+			defer feed.Unsubscribe(sub)
+			for _, want := range c.want {
+				if got := nextFeedEvent(t, sub).ID; got != want {
+					t.Fatalf("expected event %d, got %d", want, got)
+				}
+			}
+			expectNoFeedEvent(t, sub)
+		})
+	}
+
+	t.Run("other tenants", func(t *testing.T) {
+		sub := feed.Subscribe(application.FeedFilter{TenantID: "globex"}, ids[0])
+		defer feed.Unsubscribe(sub)
+		expectNoFeedEvent(t, sub)
+	})
+}
+
+func TestProductFeedDropsSlowSubscribers(t *testing.T) {
+	feed, changes := startProductFeed(t, 10, 1)
+
+	slow := feed.Subscribe(application.FeedFilter{TenantID: "acme"}, 0)
+	fast := feed.Subscribe(application.FeedFilter{TenantID: "acme"}, 0)
+	defer feed.Unsubscribe(fast)
+
+	for i := 0; i < 3; i++ {
+		changes.Append(&entities.ProductEvent{Type: entities.ProductUpdated, TenantID: "acme", ProductID: "p1"})
+		nextFeedEvent(t, fast)
+	}
+
+	// The slow subscriber keeps what fitted its buffer, then finds its channel closed
+	nextFeedEvent(t, slow)
+	if _, ok := <-slow.Events(); ok {
+		t.Fatal("expected the slow subscriber to be dropped")
+	}
+	// Unsubscribing a dropped subscriber is harmless
+	feed.Unsubscribe(slow)
+}
+
+func TestProductFeedRedactsHiddenProducts(t *testing.T) {
+	feed, changes := startProductFeed(t, 10, 10)
+
+	activeOnly := func(product *entities.Product) bool { return product.Status == entities.ProductStatusActive }
+	sub := feed.Subscribe(application.FeedFilter{TenantID: "acme", Visible: activeOnly}, 0)
+	defer feed.Unsubscribe(sub)
+
+	changes.Append(&entities.ProductEvent{Type: entities.ProductCreated, TenantID: "acme", ProductID: "p1", Product: &entities.Product{Name: "Draft", Status: entities.ProductStatusDraft}})
+	changes.Append(&entities.ProductEvent{Type: entities.ProductUpdated, TenantID: "acme", ProductID: "p2", Product: &entities.Product{Name: "Boots", Status: entities.ProductStatusActive}})
+
+	if event := nextFeedEvent(t, sub); event.ProductID != "p1" || event.Product != nil {
+		t.Fatalf("expected the draft to be announced without its product, got %+v", event.ProductEvent)
+	}
+	if event := nextFeedEvent(t, sub); event.ProductID != "p2" || event.Product == nil || event.Product.Name != "Boots" {
+		t.Fatalf("expected the active product to be delivered, got %+v", event.ProductEvent)
+	}
+}
+
+func TestChangeStreamRejectsResumeTokensOutsideItsHistory(t *testing.T) {
+	changes := memory.NewChangeStream(2)
+	for i := 0; i < 4; i++ {
+		changes.Append(&entities.ProductEvent{Type: entities.ProductUpdated, TenantID: "acme", ProductID: "p1"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	for _, token := range []string{"1", "5", "not-a-token"} {
+		if err := changes.Watch(ctx, token, func(*entities.ProductEvent) error { return nil }); !errors.Is(err, ports.ErrInvalidResumeToken) {
+			t.Errorf("token %q: expected ErrInvalidResumeToken, got %v", token, err)
+		}
+	}
+
+	var tokens []string
+	stop := errors.New("stop")
+	err := changes.Watch(ctx, "2", func(event *entities.ProductEvent) error {
+		tokens = append(tokens, event.ResumeToken)
+		if len(tokens) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || len(tokens) != 2 || tokens[0] != "3" || tokens[1] != "4" {
+		t.Fatalf("expected to resume with 3 and 4 from the oldest token kept, got %v (%v)", tokens, err)
+	}
+}
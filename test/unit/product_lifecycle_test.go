@@ -0,0 +1,400 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+	"test-go/internal/core/usecases"
+)
+
+const (
+	submitter = "api_key:submitter"
+	approver  = "sub:approver"
+)
+
+var lifecycleNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// allowedTransitions is the lifecycle every status may move along
+var allowedTransitions = map[string][]string{
+	entities.ProductStatusDraft:        {entities.ProductStatusInReview, entities.ProductStatusArchived},
+	entities.ProductStatusInReview:     {entities.ProductStatusActive, entities.ProductStatusDraft},
+	entities.ProductStatusActive:       {entities.ProductStatusDiscontinued},
+	entities.ProductStatusDiscontinued: {entities.ProductStatusActive, entities.ProductStatusArchived},
+	entities.ProductStatusArchived:     {entities.ProductStatusDraft},
+}
+
+func TestTransitionProductFollowsTheLifecycle(t *testing.T) {
+	for _, from := range entities.ProductStatuses {
+		for _, to := range entities.ProductStatuses {
+			allowed := false
+			for _, status := range allowedTransitions[from] {
+				allowed = allowed || status == to
+			}
+
+			t.Run(from+" to "+to, func(t *testing.T) {
+				product := &entities.Product{Status: from, SubmittedBy: submitter}
+				changed, err := usecases.TransitionProduct(product, to, approver, lifecycleNow)
+
+				if !allowed {
+					if !errors.Is(err, usecases.ErrInvalidTransition) {
+						t.Fatalf("expected ErrInvalidTransition, got %v", err)
+					}
+					if product.Status != from {
+						t.Fatalf("expected the status to stay %s, got %s", from, product.Status)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("expected the transition to be allowed, got %v", err)
+				}
+				if !changed || product.Status != to {
+					t.Fatalf("expected the product to move to %s, got %s (changed %v)", to, product.Status, changed)
+				}
+			})
+		}
+	}
+}
+
+func TestTransitionProductRejectsUnknownStatuses(t *testing.T) {
+	product := &entities.Product{Status: entities.ProductStatusDraft}
+	if _, err := usecases.TransitionProduct(product, "published", approver, lifecycleNow); !errors.Is(err, usecases.ErrInvalidProductStatus) {
+		t.Fatalf("expected ErrInvalidProductStatus, got %v", err)
+	}
+}
+
+func TestTransitionProductTreatsProductsWithoutStatusAsActive(t *testing.T) {
+	product := &entities.Product{}
+	if _, err := usecases.TransitionProduct(product, entities.ProductStatusDiscontinued, "", lifecycleNow); err != nil {
+		t.Fatalf("expected a product without status to be discontinued like an active one, got %v", err)
+	}
+	if _, err := usecases.TransitionProduct(&entities.Product{}, entities.ProductStatusInReview, approver, lifecycleNow); !errors.Is(err, usecases.ErrInvalidTransition) {
+		t.Fatalf("expected a product without status not to go back to review, got %v", err)
+	}
+}
+
+func TestTransitionProductApproval(t *testing.T) {
+	later := lifecycleNow.Add(time.Hour)
+	earlier := lifecycleNow.Add(-time.Hour)
+
+	cases := []struct {
+		name       string
+		product    entities.Product
+		to         string
+		principal  string
+		err        error
+		changed    bool
+		status     string
+		submitted  string
+		approved   string
+		activateAt *time.Time
+	}{
+		{
+			name:      "submitting records the submitter",
+			product:   entities.Product{Status: entities.ProductStatusDraft},
+			to:        entities.ProductStatusInReview,
+			principal: submitter,
+			changed:   true,
+			status:    entities.ProductStatusInReview,
+			submitted: submitter,
+		},
+		{
+			name:      "anonymous submission is rejected",
+			product:   entities.Product{Status: entities.ProductStatusDraft},
+			to:        entities.ProductStatusInReview,
+			principal: "",
+			err:       usecases.ErrPrincipalRequired,
+			status:    entities.ProductStatusDraft,
+		},
+		{
+			name:      "another principal approves",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter},
+			to:        entities.ProductStatusActive,
+			principal: approver,
+			changed:   true,
+			status:    entities.ProductStatusActive,
+			submitted: submitter,
+			approved:  approver,
+		},
+		{
+			name:      "the submitter cannot approve",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter},
+			to:        entities.ProductStatusActive,
+			principal: submitter,
+			err:       usecases.ErrApprovalRequired,
+			status:    entities.ProductStatusInReview,
+			submitted: submitter,
+		},
+		{
+			name:      "anonymous approval is rejected",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter},
+			to:        entities.ProductStatusActive,
+			principal: "",
+			err:       usecases.ErrPrincipalRequired,
+			status:    entities.ProductStatusInReview,
+			submitted: submitter,
+		},
+		{
+			name:      "anonymous approval of a product submitted anonymously is rejected",
+			product:   entities.Product{Status: entities.ProductStatusInReview},
+			to:        entities.ProductStatusActive,
+			principal: "",
+			err:       usecases.ErrPrincipalRequired,
+			status:    entities.ProductStatusInReview,
+		},
+		{
+			name:       "approval before the activation date stays in review",
+			product:    entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ActivateAt: &later},
+			to:         entities.ProductStatusActive,
+			principal:  approver,
+			changed:    false,
+			status:     entities.ProductStatusInReview,
+			submitted:  submitter,
+			approved:   approver,
+			activateAt: &later,
+		},
+		{
+			name:      "approval after the activation date activates",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ActivateAt: &earlier},
+			to:        entities.ProductStatusActive,
+			principal: approver,
+			changed:   true,
+			status:    entities.ProductStatusActive,
+			submitted: submitter,
+			approved:  approver,
+		},
+		{
+			name:      "sending back to draft clears the review",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ApprovedBy: approver},
+			to:        entities.ProductStatusDraft,
+			principal: submitter,
+			changed:   true,
+			status:    entities.ProductStatusDraft,
+		},
+		{
+			name:      "reactivating a discontinued product needs no approval",
+			product:   entities.Product{Status: entities.ProductStatusDiscontinued},
+			to:        entities.ProductStatusActive,
+			principal: "",
+			changed:   true,
+			status:    entities.ProductStatusActive,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			product := c.product
+			changed, err := usecases.TransitionProduct(&product, c.to, c.principal, lifecycleNow)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("expected error %v, got %v", c.err, err)
+			}
+			if changed != c.changed {
+				t.Errorf("expected changed %v, got %v", c.changed, changed)
+			}
+			if product.Status != c.status {
+				t.Errorf("expected status %s, got %s", c.status, product.Status)
+			}
+			if product.SubmittedBy != c.submitted {
+				t.Errorf("expected submitted by %q, got %q", c.submitted, product.SubmittedBy)
+			}
+			if product.ApprovedBy != c.approved {
+				t.Errorf("expected approved by %q, got %q", c.approved, product.ApprovedBy)
+			}
+			if (product.ActivateAt == nil) != (c.activateAt == nil) {
+				t.Errorf("expected activate_at %v, got %v", c.activateAt, product.ActivateAt)
+			}
+		})
+	}
+}
+
+func TestApplyProductSchedule(t *testing.T) {
+	due := lifecycleNow.Add(-time.Minute)
+	pending := lifecycleNow.Add(time.Minute)
+
+	cases := []struct {
+		name         string
+		product      entities.Product
+		from         string
+		status       string
+		activateAt   bool
+		deactivateAt bool
+	}{
+		{
+			name:    "approved product in review activates once due",
+			product: entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ApprovedBy: approver, ActivateAt: &due},
+			from:    entities.ProductStatusInReview,
+			status:  entities.ProductStatusActive,
+		},
+		{
+			name:    "activation exactly at now is due",
+			product: entities.Product{Status: entities.ProductStatusInReview, ApprovedBy: approver, ActivateAt: &lifecycleNow},
+			from:    entities.ProductStatusInReview,
+			status:  entities.ProductStatusActive,
+		},
+		{
+			name:       "approved product in review waits for its activation date",
+			product:    entities.Product{Status: entities.ProductStatusInReview, ApprovedBy: approver, ActivateAt: &pending},
+			status:     entities.ProductStatusInReview,
+			activateAt: true,
+		},
+		{
+			name:       "unapproved product in review is not activated",
+			product:    entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ActivateAt: &due},
+			status:     entities.ProductStatusInReview,
+			activateAt: true,
+		},
+		{
+			name:       "draft is not activated",
+			product:    entities.Product{Status: entities.ProductStatusDraft, ActivateAt: &due},
+			status:     entities.ProductStatusDraft,
+			activateAt: true,
+		},
+		{
+			name:    "active product is discontinued once due",
+			product: entities.Product{Status: entities.ProductStatusActive, DeactivateAt: &due},
+			from:    entities.ProductStatusActive,
+			status:  entities.ProductStatusDiscontinued,
+		},
+		{
+			name:    "product without status is discontinued like an active one",
+			product: entities.Product{DeactivateAt: &due},
+			from:    entities.ProductStatusActive,
+			status:  entities.ProductStatusDiscontinued,
+		},
+		{
+			name:         "active product waits for its deactivation date",
+			product:      entities.Product{Status: entities.ProductStatusActive, DeactivateAt: &pending},
+			status:       entities.ProductStatusActive,
+			deactivateAt: true,
+		},
+		{
+			name:         "approved product activates but keeps a later deactivation date",
+			product:      entities.Product{Status: entities.ProductStatusInReview, ApprovedBy: approver, ActivateAt: &due, DeactivateAt: &pending},
+			from:         entities.ProductStatusInReview,
+			status:       entities.ProductStatusActive,
+			deactivateAt: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			product := c.product
+			from := usecases.ApplyProductSchedule(&product, lifecycleNow)
+			if from != c.from {
+				t.Errorf("expected to move from %q, got %q", c.from, from)
+			}
+			if product.LifecycleStatus() != c.status {
+				t.Errorf("expected status %s, got %s", c.status, product.LifecycleStatus())
+			}
+			if (product.ActivateAt != nil) != c.activateAt {
+				t.Errorf("expected activate_at kept %v, got %v", c.activateAt, product.ActivateAt)
+			}
+			if (product.DeactivateAt != nil) != c.deactivateAt {
+				t.Errorf("expected deactivate_at kept %v, got %v", c.deactivateAt, product.DeactivateAt)
+			}
+		})
+	}
+}
+
+func TestReviewEdit(t *testing.T) {
+	const editor = "api_key:editor"
+
+	cases := []struct {
+		name      string
+		product   entities.Product
+		principal string
+		submitted string
+		approved  string
+	}{
+		{
+			name:      "editing an approved product in review withdraws the approval",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ApprovedBy: approver},
+			principal: editor,
+			submitted: editor,
+		},
+		{
+			name:      "the approver editing becomes the submitter",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ApprovedBy: approver},
+			principal: approver,
+			submitted: approver,
+		},
+		{
+			name:      "an anonymous edit keeps the submitter",
+			product:   entities.Product{Status: entities.ProductStatusInReview, SubmittedBy: submitter, ApprovedBy: approver},
+			submitted: submitter,
+		},
+		{
+			name:      "editing a draft changes nothing",
+			product:   entities.Product{Status: entities.ProductStatusDraft},
+			principal: editor,
+		},
+		{
+			name:      "editing an active product keeps its approval",
+			product:   entities.Product{Status: entities.ProductStatusActive, SubmittedBy: submitter, ApprovedBy: approver},
+			principal: editor,
+			submitted: submitter,
+			approved:  approver,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			product := c.product
+			usecases.ReviewEdit(&product, c.principal)
+			if product.SubmittedBy != c.submitted {
+				t.Errorf("expected submitted by %q, got %q", c.submitted, product.SubmittedBy)
+			}
+			if product.ApprovedBy != c.approved {
+				t.Errorf("expected approved by %q, got %q", c.approved, product.ApprovedBy)
+			}
+		})
+	}
+}
+
+func TestUpdateProductNeedsApprovingAgainBeforeActivation(t *testing.T) {
+	repo := memory.NewProductRepository(memory.NewChangeStream(0))
+	useCase := usecases.NewProductUseCase(repo, memory.NewProductTypeRepository(), "USD")
+	ctx := context.Background()
+
+	// Approvals are checked against the clock, so the activation date must be ahead of it
+	activateAt := time.Now().Add(time.Hour)
+	product := &entities.Product{Name: "Lamp", Price: 10, Status: entities.ProductStatusDraft, ActivateAt: &activateAt}
+	if _, err := useCase.CreateProduct(ctx, product); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, step := range []struct{ status, principal string }{
+		{entities.ProductStatusInReview, submitter},
+		{entities.ProductStatusActive, approver},
+	} {
+		as := ports.WithRequestInfo(ctx, ports.RequestInfo{Principal: step.principal})
+		if _, err := useCase.TransitionProduct(as, product, step.status); err != nil {
+			t.Fatalf("move to %s: %v", step.status, err)
+		}
+	}
+	if product.ApprovedBy != approver {
+		t.Fatalf("expected the product to be approved ahead of its activation, got %+v", product)
+	}
+
+	product.Name = "Lamp with unreviewed copy"
+	if err := useCase.UpdateProduct(ports.WithRequestInfo(ctx, ports.RequestInfo{Principal: approver}), product); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	stored, err := useCase.GetProductByID(ctx, product.ID.Hex())
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.ApprovedBy != "" || stored.SubmittedBy != approver {
+		t.Fatalf("expected the edit to withdraw the approval and make the editor the submitter, got %+v", stored)
+	}
+	if from, err := useCase.ApplyProductSchedule(ctx, stored, activateAt); err != nil || from != "" {
+		t.Fatalf("expected the edited product not to be activated, moved from %q (%v)", from, err)
+	}
+	if _, err := useCase.TransitionProduct(ports.WithRequestInfo(ctx, ports.RequestInfo{Principal: approver}), stored, entities.ProductStatusActive); !errors.Is(err, usecases.ErrApprovalRequired) {
+		t.Fatalf("expected the editor not to approve their own edit, got %v", err)
+	}
+}
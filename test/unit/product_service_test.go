@@ -0,0 +1,198 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// recordingPublisher records the routing keys of the events it is handed, failing every publish
+// with err when it is set
+type recordingPublisher struct {
+	mu   sync.Mutex
+	keys []string
+	err  error
+}
+
+func (p *recordingPublisher) Publish(routingKey string, message interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = append(p.keys, routingKey)
+	return p.err
+}
+
+func (p *recordingPublisher) published() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.keys...)
+}
+
+// productServiceFixture is a product service on in-memory ports, with the ports tests inspect
+type productServiceFixture struct {
+	service   *application.ProductService
+	products  ports.ProductRepository
+	revisions ports.ProductRevisionRepository
+	prices    ports.PriceScheduleRepository
+	cache     ports.ProductCache
+	events    *recordingPublisher
+}
+
+func newProductServiceFixture(quotas application.TenantQuotas) *productServiceFixture {
+	f := &productServiceFixture{
+		products:  memory.NewProductRepository(nil),
+		revisions: memory.NewProductRevisionRepository(),
+		prices:    memory.NewPriceScheduleRepository(),
+		cache:     memory.NewProductCache(),
+		events:    &recordingPublisher{},
+	}
+	products := application.NewRevisionRecordingRepository(f.products, f.revisions)
+	f.service = application.NewProductService(application.ProductServiceDeps{
+		Products:     products,
+		Types:        memory.NewProductTypeRepository(),
+		Revisions:    f.revisions,
+		UnitOfWork:   application.NewDirectProductUnitOfWork(f.products, f.revisions, memory.NewAuditRepository(), f.events),
+		Prices:       f.prices,
+		Watcher:      memory.NewChangeStream(0),
+		Cache:        f.cache,
+		Quotas:       quotas,
+		PriceLists:   memory.NewPriceListRepository(),
+		Rates:        memory.NewExchangeRateRepository(),
+		BaseCurrency: "USD",
+		Locales:      application.Localization{DefaultLocale: "en", Locales: []string{"en"}},
+	})
+	return f
+}
+
+// adminContext returns a context of the tenant carrying an API key with the admin scope, which
+// reads products that are not active yet
+func adminContext(tenantID string) context.Context {
+	ctx := ports.WithTenant(context.Background(), tenantID)
+	return ports.WithAPIKey(ctx, &entities.APIKey{TenantID: tenantID, Scopes: []string{entities.ScopeAdmin}})
+}
+
+func TestProductServiceCreateRecordsARevisionAndPublishes(t *testing.T) {
+	f := newProductServiceFixture(application.TenantQuotas{})
+	ctx := adminContext("acme")
+
+	id, err := f.service.CreateProduct(ctx, "Boots", "Leather", 120, "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	product, err := f.service.GetProductByID(ctx, id, application.ProductStatusAny, application.PriceQuery{})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if product.Status != entities.ProductStatusDraft || product.TenantID != "acme" {
+		t.Fatalf("expected a draft of acme, got status %q of %q", product.Status, product.TenantID)
+	}
+	if _, err := f.service.GetProductByID(ports.WithTenant(context.Background(), "acme"), id, "", application.PriceQuery{}); !errors.Is(err, ports.ErrProductNotFound) {
+		t.Fatalf("expected the draft to be hidden from anonymous callers, got %v", err)
+	}
+
+	revisions, err := f.revisions.FindByProductID(ctx, id)
+	if err != nil || len(revisions) != 1 {
+		t.Fatalf("expected one revision, got %d (%v)", len(revisions), err)
+	}
+	if keys := f.events.published(); len(keys) != 1 || keys[0] != "acme."+entities.ProductCreated {
+		t.Fatalf("expected one created event of acme, got %v", keys)
+	}
+}
+
+func TestProductServiceWritesSucceedWhenPublishingFails(t *testing.T) {
+	f := newProductServiceFixture(application.TenantQuotas{})
+	f.events.err = errors.New("broker unavailable")
+	ctx := adminContext("acme")
+
+	id, err := f.service.CreateProduct(ctx, "Boots", "", 120, "", nil)
+	if err != nil {
+		t.Fatalf("expected the create to succeed without the broker, got %v", err)
+	}
+	if _, err := f.products.FindByID(ctx, id); err != nil {
+		t.Fatalf("expected the product to be stored: %v", err)
+	}
+}
+
+func TestProductServiceEnforcesTheProductQuota(t *testing.T) {
+	f := newProductServiceFixture(application.TenantQuotas{Default: 1, PerTenant: map[string]int64{"big": 2}})
+
+	for _, c := range []struct {
+		tenantID string
+		allowed  int
+	}{
+		{"acme", 1},
+		{"big", 2},
+	} {
+		ctx := adminContext(c.tenantID)
+		for i := 0; i < c.allowed; i++ {
+			if _, err := f.service.CreateProduct(ctx, c.tenantID+string(rune('a'+i)), "", 1, "", nil); err != nil {
+				t.Fatalf("%s: create %d: %v", c.tenantID, i, err)
+			}
+		}
+		if _, err := f.service.CreateProduct(ctx, c.tenantID+"-over", "", 1, "", nil); !errors.Is(err, application.ErrProductQuotaExceeded) {
+			t.Fatalf("%s: expected ErrProductQuotaExceeded past %d products, got %v", c.tenantID, c.allowed, err)
+		}
+	}
+}
+
+func TestProductServiceKeepsTheCacheInStep(t *testing.T) {
+	f := newProductServiceFixture(application.TenantQuotas{})
+	ctx := adminContext("acme")
+
+	id, err := f.service.CreateProduct(ctx, "Boots", "", 100, "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if cached, err := f.cache.Get(ctx, id); err != nil || cached.Name != "Boots" {
+		t.Fatalf("expected the create to cache the product, got %+v (%v)", cached, err)
+	}
+
+	// The price schedule service evicts the products whose schedules change
+	now := time.Now()
+	if _, err := f.prices.Create(ctx, &entities.PriceSchedule{ProductID: id, Price: 80, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if err := f.cache.Delete(ctx, id); err != nil {
+		t.Fatalf("evict: %v", err)
+	}
+
+	product, err := f.service.GetProductByID(ctx, id, application.ProductStatusAny, application.PriceQuery{})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if product.EffectivePrice == nil || *product.EffectivePrice != 80 {
+		t.Fatalf("expected the scheduled price 80, got %v", product.EffectivePrice)
+	}
+	if cached, err := f.cache.Get(ctx, id); err != nil || cached.EffectivePrice == nil || *cached.EffectivePrice != 80 {
+		t.Fatalf("expected the read to cache the product at its scheduled price, got %+v (%v)", cached, err)
+	}
+
+	if err := f.service.UpdateProduct(ctx, id, "Hiking boots", "", 100, "", nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if cached, err := f.cache.Get(ctx, id); err != nil || cached.Name != "Hiking boots" {
+		t.Fatalf("expected the update to refresh the cache, got %+v (%v)", cached, err)
+	}
+
+	if err := f.service.DeleteProduct(ctx, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := f.cache.Get(ctx, id); !errors.Is(err, ports.ErrCacheMiss) {
+		t.Fatalf("expected the delete to evict the product, got %v", err)
+	}
+	if _, err := f.service.GetProductByID(ctx, id, application.ProductStatusAny, application.PriceQuery{}); !errors.Is(err, ports.ErrProductNotFound) {
+		t.Fatalf("expected the deleted product not to be found, got %v", err)
+	}
+
+	want := []string{"acme." + entities.ProductCreated, "acme." + entities.ProductUpdated, "acme." + entities.ProductDeleted}
+	if keys := f.events.published(); len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] || keys[2] != want[2] {
+		t.Fatalf("expected %v, got %v", want, keys)
+	}
+}
@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errWatched stops a watch once the events under test were seen
+var errWatched = errors.New("watched")
+
+func TestVisibleProducts(t *testing.T) {
+	ctx := context.Background()
+	admin := ports.WithAPIKey(ctx, &entities.APIKey{Scopes: []string{entities.ScopeAdmin}})
+	writer := ports.WithAPIKey(ctx, &entities.APIKey{Scopes: []string{entities.ScopeCatalogWrite}})
+
+	for _, status := range entities.ProductStatuses {
+		product := &entities.Product{Status: status}
+		active := status == entities.ProductStatusActive
+
+		if visible := application.VisibleProducts(ctx)(product); visible != active {
+			t.Errorf("%s: expected anonymous callers to see it %v, got %v", status, active, visible)
+		}
+		if visible := application.VisibleProducts(writer)(product); visible != active {
+			t.Errorf("%s: expected a catalog:write key to see it %v, got %v", status, active, visible)
+		}
+		if !application.VisibleProducts(admin)(product) {
+			t.Errorf("%s: expected an admin key to see it", status)
+		}
+	}
+}
+
+func TestWatchProductsHidesProductsThatAreNotActive(t *testing.T) {
+	changes := memory.NewChangeStream(0)
+	service := application.NewProductService(application.ProductServiceDeps{Watcher: changes, BaseCurrency: "USD"})
+
+	draft := &entities.Product{ID: primitive.NewObjectID(), Status: entities.ProductStatusDraft}
+	active := &entities.Product{ID: primitive.NewObjectID(), Status: entities.ProductStatusActive}
+	for _, product := range []*entities.Product{draft, active} {
+		changes.Append(&entities.ProductEvent{Type: entities.ProductUpdated, TenantID: ports.DefaultTenant, ProductID: product.ID.Hex(), Product: product})
+	}
+
+	watch := func(ctx context.Context) []*entities.ProductEvent {
+		var events []*entities.ProductEvent
+		err := service.WatchProducts(ctx, "0", func(event *entities.ProductEvent) error {
+			events = append(events, event)
+			if len(events) == 2 {
+				return errWatched
+			}
+			return nil
+		})
+		if !errors.Is(err, errWatched) {
+			t.Fatalf("watch: %v", err)
+		}
+		return events
+	}
+
+	events := watch(context.Background())
+	if events[0].ProductID != draft.ID.Hex() || events[0].Product != nil {
+		t.Errorf("expected the change to the draft without its product, got %+v", events[0])
+	}
+	if events[1].Product == nil {
+		t.Error("expected the change to the active product with its product")
+	}
+
+	admin := ports.WithAPIKey(context.Background(), &entities.APIKey{Scopes: []string{entities.ScopeAdmin}})
+	for _, event := range watch(admin) {
+		if event.Product == nil {
+			t.Errorf("expected an admin key to get every product, got %+v", event)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"test-go/internal/core/usecases"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the tests with their current output")
+
+// taxCase is a price to compute the tax of in every rounding mode
+type taxCase struct {
+	name      string
+	unitPrice float32
+	quantity  int
+	percent   float64
+	inclusive bool
+	currency  string
+}
+
+var taxCases = []taxCase{
+	{"tax exactly half a cent", 10.50, 1, 5, false, "EUR"},
+	{"tax just below half a cent", 10.49, 1, 5, false, "EUR"},
+	{"unit price not exact in binary", 1.005, 1, 0, false, "USD"},
+	{"line of three thirds", 0.333, 3, 20, false, "EUR"},
+	{"inclusive 19 percent", 9.99, 1, 19, true, "EUR"},
+	{"inclusive 19 percent without remainder", 119, 1, 19, true, "EUR"},
+	{"inclusive tax exactly half a cent", 0.75, 1, 20, true, "EUR"},
+	{"fractional rate", 19.99, 1, 8.875, false, "USD"},
+	{"fractional rate quantity", 19.99, 7, 8.875, false, "USD"},
+	{"zero rate", 4.99, 2, 0, false, "USD"},
+	{"zero rate inclusive", 4.99, 2, 0, true, "USD"},
+	{"full rate", 0.01, 1, 100, false, "USD"},
+	{"yen without minor unit", 1575, 1, 8, false, "JPY"},
+	{"yen half a yen", 1985, 1, 10, false, "JPY"},
+	{"yen inclusive", 1000, 1, 10, true, "JPY"},
+	{"dinar three decimals", 12.345, 1, 10, false, "BHD"},
+	{"dinar inclusive", 1.1, 1, 10, true, "BHD"},
+	{"zero price", 0, 5, 19, false, "EUR"},
+}
+
+var roundingModes = []string{usecases.RoundHalfUp, usecases.RoundHalfEven, usecases.RoundDown, usecases.RoundUp}
+
+func TestCalculateTaxGolden(t *testing.T) {
+	var out bytes.Buffer
+	for _, c := range taxCases {
+		scale := usecases.CurrencyScale(c.currency)
+		kind := "exclusive"
+		if c.inclusive {
+			kind = "inclusive"
+		}
+		fmt.Fprintf(&out, "# %s: %v x %d at %v%% %s %s\n", c.name, c.unitPrice, c.quantity, c.percent, kind, c.currency)
+
+		for _, mode := range roundingModes {
+			amounts, err := usecases.CalculateTax(c.unitPrice, c.quantity, c.percent, c.inclusive, scale, mode)
+			if err != nil {
+				t.Fatalf("%s, %s: %v", c.name, mode, err)
+			}
+			if net, tax, gross := round(amounts.Net, scale), round(amounts.Tax, scale), round(amounts.Gross, scale); net+tax != gross {
+				t.Errorf("%s, %s: net %v plus tax %v is not gross %v", c.name, mode, amounts.Net, amounts.Tax, amounts.Gross)
+			}
+
+			format := func(amount float64) string { return strconv.FormatFloat(amount, 'f', scale, 64) }
+			fmt.Fprintf(&out, "%-9s net=%s tax=%s gross=%s\n", mode, format(amounts.Net), format(amounts.Tax), format(amounts.Gross))
+		}
+	}
+
+	golden := filepath.Join("testdata", "tax_calculator.golden")
+	if *update {
+		if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file, run with -update to create it: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("tax amounts differ from %s, run with -update if the change is intended:\n%s", golden, out.String())
+	}
+}
+
+func TestCalculateTaxRejectsUnknownRounding(t *testing.T) {
+	if _, err := usecases.CalculateTax(1, 1, 10, false, 2, "nearest"); err != usecases.ErrInvalidRounding {
+		t.Fatalf("expected ErrInvalidRounding, got %v", err)
+	}
+}
+
+// round returns amount in minor units of a currency with scale decimal places
+func round(amount float64, scale int) int64 {
+	return int64(math.Round(amount * math.Pow10(scale)))
+}
@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"test-go/internal/core/ports"
+	"test-go/internal/infrastructure/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const tenantTestSecret = "tenant-test-secret"
+
+// signJWT builds an HS256 token carrying claims, signed with secret
+func signJWT(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("encode claims: %v", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestTenantMiddlewareBindsTokensToTheirTenant(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.TenantMiddleware(middleware.NewTenantResolver(tenantTestSecret, "tenant_id", false)))
+	app.Get("/tenant", func(c *fiber.Ctx) error {
+		return c.SendString(ports.TenantFromContext(c.Context()))
+	})
+
+	withTenant := signJWT(t, tenantTestSecret, map[string]interface{}{"sub": "u1", "tenant_id": "acme"})
+	withoutTenant := signJWT(t, tenantTestSecret, map[string]interface{}{"sub": "u1"})
+	emptyTenant := signJWT(t, tenantTestSecret, map[string]interface{}{"sub": "u1", "tenant_id": ""})
+	numericTenant := signJWT(t, tenantTestSecret, map[string]interface{}{"sub": "u1", "tenant_id": 7})
+	forged := signJWT(t, "another-secret", map[string]interface{}{"sub": "u1", "tenant_id": "acme"})
+
+	for _, c := range []struct {
+		name       string
+		token      string
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"token tenant", withTenant, "", fiber.StatusOK, "acme"},
+		{"token tenant repeated by the header", withTenant, "acme", fiber.StatusOK, "acme"},
+		{"header naming another tenant", withTenant, "globex", fiber.StatusForbidden, ""},
+		{"token without tenant", withoutTenant, "", fiber.StatusForbidden, ""},
+		{"token without tenant naming one in the header", withoutTenant, "globex", fiber.StatusForbidden, ""},
+		{"token with an empty tenant", emptyTenant, "globex", fiber.StatusForbidden, ""},
+		{"token with a tenant that is not a string", numericTenant, "globex", fiber.StatusForbidden, ""},
+		{"token signed with another secret", forged, "", fiber.StatusUnauthorized, ""},
+		{"header without a token", "", "globex", fiber.StatusOK, "globex"},
+		{"neither", "", "", fiber.StatusOK, ports.DefaultTenant},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/tenant", nil)
+			if c.token != "" {
+				req.Header.Set("Authorization", "Bearer "+c.token)
+			}
+			if c.header != "" {
+				req.Header.Set("X-Tenant-ID", c.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != c.wantStatus {
+				t.Fatalf("expected %d, got %d", c.wantStatus, resp.StatusCode)
+			}
+			if c.wantTenant != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != c.wantTenant {
+					t.Fatalf("expected tenant %q, got %q", c.wantTenant, body)
+				}
+			}
+		})
+	}
+}
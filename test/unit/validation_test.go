@@ -0,0 +1,203 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/usecases"
+)
+
+// ruleCase is the value a rule is run against and the violation it must report, empty when valid
+type ruleCase struct {
+	name  string
+	value interface{}
+	want  string
+}
+
+func runRuleCases(t *testing.T, rule usecases.Rule, cases []ruleCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := rule(context.Background(), c.value)
+			if err != nil {
+				t.Fatalf("rule failed: %v", err)
+			}
+			if got != c.want {
+				t.Fatalf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestRequiredRule(t *testing.T) {
+	runRuleCases(t, usecases.Required(), []ruleCase{
+		{"nil", nil, "is required"},
+		{"empty string", "", "is required"},
+		{"blank string", " \t\n", "is required"},
+		{"one character", "x", ""},
+		{"padded text", "  x  ", ""},
+		{"zero number", 0, ""},
+	})
+}
+
+func TestLengthRule(t *testing.T) {
+	runRuleCases(t, usecases.Length(2, 4), []ruleCase{
+		{"empty", "", "must be at least 2 characters"},
+		{"one below min", "a", "must be at least 2 characters"},
+		{"at min", "ab", ""},
+		{"at max", "abcd", ""},
+		{"one above max", "abcde", "must be at most 4 characters"},
+		{"counts characters not bytes", "éééé", ""},
+		{"multibyte above max", "ééééé", "must be at most 4 characters"},
+		{"not a string", 42, "must be a string"},
+		{"nil", nil, "must be a string"},
+	})
+
+	t.Run("zero max has no upper bound", func(t *testing.T) {
+		runRuleCases(t, usecases.Length(0, 0), []ruleCase{
+			{"empty", "", ""},
+			{"long", strings.Repeat("x", 10_000), ""},
+		})
+	})
+}
+
+func TestRangeRule(t *testing.T) {
+	runRuleCases(t, usecases.Range(0, 10), []ruleCase{
+		{"just below min", -0.001, "must be at least 0"},
+		{"at min", 0.0, ""},
+		{"at max", 10.0, ""},
+		{"just above max", 10.001, "must be at most 10"},
+		{"float32", float32(5.5), ""},
+		{"int", 7, ""},
+		{"int32 above max", int32(11), "must be at most 10"},
+		{"int64 below min", int64(-1), "must be at least 0"},
+		{"NaN", math.NaN(), "must be a number"},
+		{"numeric string", "5", "must be a number"},
+		{"nil", nil, "must be a number"},
+	})
+}
+
+func TestMatchesRule(t *testing.T) {
+	runRuleCases(t, usecases.Matches(`^[a-z]{1,3}$`), []ruleCase{
+		{"shortest match", "a", ""},
+		{"longest match", "abc", ""},
+		{"too long", "abcd", "must match ^[a-z]{1,3}$"},
+		{"empty", "", "must match ^[a-z]{1,3}$"},
+		{"upper case", "ABC", "must match ^[a-z]{1,3}$"},
+		{"not a string", 1, "must match ^[a-z]{1,3}$"},
+	})
+}
+
+func TestUniqueRule(t *testing.T) {
+	taken := map[interface{}]bool{"used": true}
+	unique := usecases.Unique(func(ctx context.Context, value interface{}) (bool, error) {
+		return taken[value], nil
+	})
+	runRuleCases(t, unique, []ruleCase{
+		{"taken", "used", "is already in use"},
+		{"free", "free", ""},
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		failure := errors.New("repository unreachable")
+		rule := usecases.Unique(func(ctx context.Context, value interface{}) (bool, error) { return false, failure })
+		if _, err := rule(context.Background(), "x"); !errors.Is(err, failure) {
+			t.Fatalf("expected the lookup failure, got %v", err)
+		}
+	})
+}
+
+func TestRuleSetStopsAtTheFirstViolationOfEachField(t *testing.T) {
+	type subject struct{ name, code string }
+	rules := usecases.NewRuleSet[subject]().
+		Field("name", func(s subject) interface{} { return s.name }, usecases.Required(), usecases.Length(3, 0)).
+		Field("code", func(s subject) interface{} { return s.code }, usecases.Length(2, 2), usecases.Matches(`^[A-Z]+$`))
+
+	err := rules.Validate(context.Background(), subject{name: "", code: "abc"})
+	var validationErr *usecases.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	want := []usecases.FieldViolation{
+		{Field: "name", Description: "is required"},
+		{Field: "code", Description: "must be at most 2 characters"},
+	}
+	if !reflect.DeepEqual(validationErr.Violations, want) {
+		t.Fatalf("expected %+v, got %+v", want, validationErr.Violations)
+	}
+
+	if err := rules.Validate(context.Background(), subject{name: "abc", code: "AB"}); err != nil {
+		t.Fatalf("expected a valid subject to pass, got %v", err)
+	}
+}
+
+func TestValidateProduct(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewProductRepository(nil)
+	types := memory.NewProductTypeRepository()
+	existingID, err := repo.Create(ctx, &entities.Product{Name: "Taken", Price: 1})
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	existing, err := repo.FindByID(ctx, existingID)
+	if err != nil {
+		t.Fatalf("find seed: %v", err)
+	}
+
+	for _, c := range []struct {
+		name    string
+		product *entities.Product
+		want    []usecases.FieldViolation
+	}{
+		{"valid", &entities.Product{Name: "Boots", Price: 10, TaxClass: "standard"}, nil},
+		{"zero price and no tax class", &entities.Product{Name: "Free"}, nil},
+		{"name at max length", &entities.Product{Name: strings.Repeat("n", 200)}, nil},
+		{"description at max length", &entities.Product{Name: "Long", Description: strings.Repeat("d", 5000)}, nil},
+		{"renaming to its own name", existing, nil},
+		{"blank name", &entities.Product{Name: "   "}, []usecases.FieldViolation{
+			{Field: "name", Description: "is required"},
+		}},
+		{"name above max length", &entities.Product{Name: strings.Repeat("n", 201)}, []usecases.FieldViolation{
+			{Field: "name", Description: "must be at most 200 characters"},
+		}},
+		{"name of another product", &entities.Product{Name: "Taken"}, []usecases.FieldViolation{
+			{Field: "name", Description: "is already in use"},
+		}},
+		{"description above max length", &entities.Product{Name: "Long", Description: strings.Repeat("d", 5001)}, []usecases.FieldViolation{
+			{Field: "description", Description: "must be at most 5000 characters"},
+		}},
+		{"negative price", &entities.Product{Name: "Refund", Price: -0.01}, []usecases.FieldViolation{
+			{Field: "price", Description: "must be at least 0"},
+		}},
+		{"every field invalid", &entities.Product{Name: "", Price: -1, TaxClass: "Standard", Attributes: map[string]interface{}{"size": "M"}}, []usecases.FieldViolation{
+			{Field: "name", Description: "is required"},
+			{Field: "price", Description: "must be at least 0"},
+			{Field: "tax_class", Description: "must match ^([a-z][a-z0-9_-]{0,49})?$"},
+			{Field: "type_id", Description: "is required to set attributes"},
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			err := usecases.ValidateProduct(ctx, repo, types, c.product)
+			if c.want == nil {
+				if err != nil {
+					t.Fatalf("expected no violations, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *usecases.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(validationErr.Violations, c.want) {
+				t.Fatalf("expected %+v, got %+v", c.want, validationErr.Violations)
+			}
+		})
+	}
+}
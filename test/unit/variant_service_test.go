@@ -0,0 +1,223 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+// variantFixture is a variant service sharing the products of a product service fixture
+type variantFixture struct {
+	*productServiceFixture
+	variants     *application.VariantService
+	variantsRepo ports.VariantRepository
+}
+
+func newVariantFixture() *variantFixture {
+	f := &variantFixture{
+		productServiceFixture: newProductServiceFixture(application.TenantQuotas{}),
+		variantsRepo:          memory.NewVariantRepository(),
+	}
+	f.variants = application.NewVariantService(f.variantsRepo, f.products, f.service, f.events)
+	return f
+}
+
+// createProduct creates a product, failing the test on error
+func (f *variantFixture) createProduct(t *testing.T, ctx context.Context, name string) string {
+	t.Helper()
+	id, err := f.service.CreateProduct(ctx, name, "", 20, "", nil)
+	if err != nil {
+		t.Fatalf("create %s: %v", name, err)
+	}
+	return id
+}
+
+// variantSKUs returns the SKUs of variants in order
+func variantSKUs(variants []*entities.ProductVariant) []string {
+	skus := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		skus = append(skus, variant.SKU)
+	}
+	return skus
+}
+
+var shirtOptions = []entities.ProductOption{
+	{Name: "Size", Values: []string{"M", "Extra large"}},
+	{Name: "Color", Values: []string{"Red", "Navy / White"}},
+}
+
+func TestVariantServiceGeneratesTheOptionMatrix(t *testing.T) {
+	f := newVariantFixture()
+	ctx := adminContext("acme")
+	productID := f.createProduct(t, ctx, "T-shirt")
+
+	variants, err := f.variants.GenerateVariants(ctx, productID, shirtOptions, " tee ")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	want := []string{"TEE-M-RED", "TEE-M-NAVY_WHITE", "TEE-EXTRA_LARGE-RED", "TEE-EXTRA_LARGE-NAVY_WHITE"}
+	if skus := variantSKUs(variants); !equalStrings(skus, want) {
+		t.Fatalf("expected %v, got %v", want, skus)
+	}
+
+	product, err := f.products.FindByID(ctx, productID)
+	if err != nil || len(product.Options) != 2 {
+		t.Fatalf("expected the options to be stored on the product, got %+v (%v)", product, err)
+	}
+}
+
+func TestVariantServiceRejectsInvalidOptions(t *testing.T) {
+	f := newVariantFixture()
+	ctx := adminContext("acme")
+	productID := f.createProduct(t, ctx, "T-shirt")
+
+	values := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = string(rune('a'+i%26)) + string(rune('a'+i/26))
+		}
+		return out
+	}
+
+	for _, c := range []struct {
+		name    string
+		options []entities.ProductOption
+		want    error
+	}{
+		{"no options", nil, application.ErrInvalidOptions},
+		{"blank name", []entities.ProductOption{{Name: " ", Values: []string{"M"}}}, application.ErrInvalidOptions},
+		{"names differing in case", []entities.ProductOption{{Name: "Size", Values: []string{"M"}}, {Name: "size", Values: []string{"L"}}}, application.ErrInvalidOptions},
+		{"no values", []entities.ProductOption{{Name: "Size"}}, application.ErrInvalidOptions},
+		{"blank value", []entities.ProductOption{{Name: "Size", Values: []string{"M", " "}}}, application.ErrInvalidOptions},
+		{"values differing in case", []entities.ProductOption{{Name: "Size", Values: []string{"m", "M"}}}, application.ErrInvalidOptions},
+		{"four options", []entities.ProductOption{{Name: "A", Values: []string{"1"}}, {Name: "B", Values: []string{"1"}}, {Name: "C", Values: []string{"1"}}, {Name: "D", Values: []string{"1"}}}, application.ErrInvalidOptions},
+		{"matrix above the limit", []entities.ProductOption{{Name: "A", Values: values(16)}, {Name: "B", Values: values(16)}}, application.ErrTooManyVariants},
+		{"matrix at the limit", []entities.ProductOption{{Name: "A", Values: values(10)}, {Name: "B", Values: values(25)}}, nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := f.variants.GenerateVariants(ctx, productID, c.options, ""); !errors.Is(err, c.want) {
+				t.Fatalf("expected %v, got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestVariantServiceKeepsSKUsUniquePerTenant(t *testing.T) {
+	f := newVariantFixture()
+	acme := adminContext("acme")
+	globex := adminContext("globex")
+
+	first := f.createProduct(t, acme, "T-shirt")
+	if _, err := f.variants.GenerateVariants(acme, first, shirtOptions, "TEE"); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	second := f.createProduct(t, acme, "Other T-shirt")
+	if _, err := f.variants.GenerateVariants(acme, second, shirtOptions, "tee"); !errors.Is(err, ports.ErrDuplicateSKU) {
+		t.Fatalf("expected the SKUs of another product of the tenant to clash, got %v", err)
+	}
+	if left, err := f.variantsRepo.FindByProduct(acme, second); err != nil || len(left) != 0 {
+		t.Fatalf("expected the failed generation to be rolled back, got %v (%v)", variantSKUs(left), err)
+	}
+	if kept, err := f.variantsRepo.FindByProduct(acme, first); err != nil || len(kept) != 4 {
+		t.Fatalf("expected the variants of the first product to be kept, got %d (%v)", len(kept), err)
+	}
+
+	other := f.createProduct(t, globex, "T-shirt")
+	if _, err := f.variants.GenerateVariants(globex, other, shirtOptions, "TEE"); err != nil {
+		t.Fatalf("expected another tenant to use the same SKUs, got %v", err)
+	}
+
+	// Two values that reduce to the same SKU segment clash within one product
+	clashing := []entities.ProductOption{{Name: "Color", Values: []string{"Navy/White", "Navy White"}}}
+	if _, err := f.variants.GenerateVariants(acme, second, clashing, "SHIRT"); !errors.Is(err, ports.ErrDuplicateSKU) {
+		t.Fatalf("expected values yielding the same SKU to clash, got %v", err)
+	}
+}
+
+func TestVariantServiceRegenerationKeepsSurvivingVariants(t *testing.T) {
+	f := newVariantFixture()
+	ctx := adminContext("acme")
+	productID := f.createProduct(t, ctx, "T-shirt")
+
+	variants, err := f.variants.GenerateVariants(ctx, productID, shirtOptions, "TEE")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	price := float32(25)
+	sku := "TEE-M-RED-2024"
+	if _, err := f.variants.UpdateVariants(ctx, productID, []application.VariantUpdate{{ID: variants[0].ID.Hex(), SKU: &sku, Price: &price}}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Drop the extra large size and add a color
+	regenerated, err := f.variants.GenerateVariants(ctx, productID, []entities.ProductOption{
+		{Name: "Size", Values: []string{"M"}},
+		{Name: "Color", Values: []string{"Red", "Navy / White", "Black"}},
+	}, "TEE")
+	if err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	want := []string{"TEE-M-RED-2024", "TEE-M-NAVY_WHITE", "TEE-M-BLACK"}
+	if skus := variantSKUs(regenerated); !equalStrings(skus, want) {
+		t.Fatalf("expected %v, got %v", want, skus)
+	}
+	if regenerated[0].ID != variants[0].ID || regenerated[0].Price == nil || *regenerated[0].Price != 25 {
+		t.Fatalf("expected M/Red to keep its ID, SKU and price, got %+v", regenerated[0])
+	}
+
+	stored, err := f.variantsRepo.FindByProduct(ctx, productID)
+	if err != nil || len(stored) != 3 {
+		t.Fatalf("expected the extra large variants to be deleted, got %v (%v)", variantSKUs(stored), err)
+	}
+}
+
+func TestVariantServiceBulkUpdatesChangeAllOrNothing(t *testing.T) {
+	f := newVariantFixture()
+	ctx := adminContext("acme")
+	productID := f.createProduct(t, ctx, "T-shirt")
+	variants, err := f.variants.GenerateVariants(ctx, productID, shirtOptions, "TEE")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	first, second := variants[0].ID.Hex(), variants[1].ID.Hex()
+
+	str := func(s string) *string { return &s }
+	negative := float32(-1)
+	price := float32(30)
+	for _, c := range []struct {
+		name    string
+		updates []application.VariantUpdate
+		want    error
+	}{
+		{"unknown variant", []application.VariantUpdate{{ID: first, Price: &price}, {ID: "0123456789abcdef01234567", Price: &price}}, ports.ErrVariantNotFound},
+		{"blank SKU", []application.VariantUpdate{{ID: first, Price: &price}, {ID: second, SKU: str("  ")}}, application.ErrInvalidSKU},
+		{"negative price", []application.VariantUpdate{{ID: first, SKU: str("NEW")}, {ID: second, Price: &negative}}, application.ErrInvalidPrice},
+		{"SKU of another variant", []application.VariantUpdate{{ID: first, SKU: str(variants[1].SKU)}}, ports.ErrDuplicateSKU},
+		{"one SKU given twice", []application.VariantUpdate{{ID: first, SKU: str("SAME")}, {ID: second, SKU: str("SAME")}}, ports.ErrDuplicateSKU},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := f.variants.UpdateVariants(ctx, productID, c.updates); !errors.Is(err, c.want) {
+				t.Fatalf("expected %v, got %v", c.want, err)
+			}
+			stored, err := f.variantsRepo.FindByID(ctx, first)
+			if err != nil || stored.SKU != variants[0].SKU || stored.Price != nil {
+				t.Fatalf("expected the rejected batch to change nothing, got %+v (%v)", stored, err)
+			}
+		})
+	}
+
+	// A SKU freed earlier in the batch may be taken later in it
+	updated, err := f.variants.UpdateVariants(ctx, productID, []application.VariantUpdate{
+		{ID: first, SKU: str("TEE-FIRST")},
+		{ID: second, SKU: str(variants[0].SKU)},
+	})
+	if err != nil || updated[1].SKU != variants[0].SKU {
+		t.Fatalf("expected the second variant to take the freed SKU, got %v (%v)", variantSKUs(updated), err)
+	}
+}
@@ -0,0 +1,278 @@
+package unit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"test-go/internal/adapters/secondary/memory"
+	"test-go/internal/application"
+	"test-go/internal/core/entities"
+	"test-go/internal/core/ports"
+)
+
+func TestSignWebhookPayloadCoversTimestampAndBody(t *testing.T) {
+	body := []byte(`{"id":"e1"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := application.SignWebhookPayload("secret", 1700000000, body); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	for name, other := range map[string]string{
+		"another timestamp": application.SignWebhookPayload("secret", 1700000001, body),
+		"another body":      application.SignWebhookPayload("secret", 1700000000, []byte(`{"id":"e2"}`)),
+		"another secret":    application.SignWebhookPayload("other", 1700000000, body),
+	} {
+		if other == want {
+			t.Errorf("%s: expected a different signature", name)
+		}
+	}
+}
+
+func TestWebhookServiceValidatesSubscriptions(t *testing.T) {
+	service := application.NewWebhookService(memory.NewWebhookRepository(), memory.NewWebhookDeliveryRepository())
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		name       string
+		url        string
+		eventTypes []string
+		want       error
+	}{
+		{"https", "https://example.com/hook", nil, nil},
+		{"http with port and every type", "http://example.com:8080/hook", []string{entities.ProductCreated, entities.ProductUpdated, entities.ProductDeleted}, nil},
+		{"relative", "/hook", nil, application.ErrInvalidWebhookURL},
+		{"no host", "https:///hook", nil, application.ErrInvalidWebhookURL},
+		{"other scheme", "ftp://example.com/hook", nil, application.ErrInvalidWebhookURL},
+		{"unparseable", "http://exa mple.com/%zz", nil, application.ErrInvalidWebhookURL},
+		{"unknown event type", "https://example.com/hook", []string{"product.renamed"}, application.ErrInvalidEventType},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := service.CreateWebhook(ctx, c.url, "", c.eventTypes); !errors.Is(err, c.want) {
+				t.Fatalf("expected %v, got %v", c.want, err)
+			}
+		})
+	}
+
+	webhook, err := service.CreateWebhook(ctx, "https://example.com/hook", "", nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if secret, err := hex.DecodeString(webhook.Secret); err != nil || len(secret) != 32 {
+		t.Fatalf("expected a generated 32 byte hex secret, got %q", webhook.Secret)
+	}
+}
+
+func TestWebhookRepositoryDisablesAfterConcurrentFailures(t *testing.T) {
+	repo := memory.NewWebhookRepository()
+	ctx := context.Background()
+	id, err := repo.Create(ctx, &entities.WebhookSubscription{URL: "https://example.com/hook", Active: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	const disableAfter = 10
+	var wg sync.WaitGroup
+	disabled := make(chan bool, disableAfter+5)
+	for i := 0; i < disableAfter+5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.RecordFailure(ctx, id, disableAfter, time.Now())
+			if err != nil {
+				t.Errorf("record failure: %v", err)
+			}
+			disabled <- ok
+		}()
+	}
+	wg.Wait()
+	close(disabled)
+
+	times := 0
+	for ok := range disabled {
+		if ok {
+			times++
+		}
+	}
+	if times != 1 {
+		t.Fatalf("expected exactly one failure to disable the webhook, got %d", times)
+	}
+	webhook, err := repo.FindByID(ctx, id)
+	if err != nil || webhook.Active || webhook.DisabledAt == nil {
+		t.Fatalf("expected the webhook to be disabled, got %+v (%v)", webhook, err)
+	}
+
+	service := application.NewWebhookService(repo, memory.NewWebhookDeliveryRepository())
+	if err := service.UpdateWebhook(ctx, id, webhook.URL, nil, true); err != nil {
+		t.Fatalf("reactivate: %v", err)
+	}
+	if webhook, err := repo.FindByID(ctx, id); err != nil || !webhook.Active || webhook.FailureCount != 0 || webhook.DisabledAt != nil {
+		t.Fatalf("expected reactivating to clear the failures, got %+v (%v)", webhook, err)
+	}
+}
+
+// webhookFixture is a dispatcher on in-memory repositories with one subscription per tenant
+type webhookFixture struct {
+	dispatcher *application.WebhookDispatcher
+	webhooks   ports.WebhookRepository
+	deliveries ports.WebhookDeliveryRepository
+	pending    ports.PendingWebhookDeliveryRepository
+}
+
+func newWebhookFixture() *webhookFixture {
+	f := &webhookFixture{
+		webhooks:   memory.NewWebhookRepository(),
+		deliveries: memory.NewWebhookDeliveryRepository(),
+		pending:    memory.NewPendingWebhookDeliveryRepository(),
+	}
+	f.dispatcher = application.NewWebhookDispatcher(f.webhooks, f.deliveries, f.pending)
+	return f
+}
+
+// queued claims every queued delivery as if they were all due
+func (f *webhookFixture) queued(t *testing.T) []*entities.PendingWebhookDelivery {
+	t.Helper()
+	later := time.Now().Add(time.Hour)
+	claimed, err := f.pending.ClaimDue(context.Background(), later, later, 100)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	return claimed
+}
+
+func TestWebhookDispatcherQueuesMatchingSubscriptionsOnly(t *testing.T) {
+	f := newWebhookFixture()
+	acme := ports.WithTenant(context.Background(), "acme")
+	globex := ports.WithTenant(context.Background(), "globex")
+
+	for _, c := range []struct {
+		ctx     context.Context
+		webhook *entities.WebhookSubscription
+	}{
+		{acme, &entities.WebhookSubscription{URL: "https://acme.example/all", Active: true}},
+		{acme, &entities.WebhookSubscription{URL: "https://acme.example/deleted", EventTypes: []string{entities.ProductDeleted}, Active: true}},
+		{acme, &entities.WebhookSubscription{URL: "https://acme.example/disabled", Active: false}},
+		{globex, &entities.WebhookSubscription{URL: "https://globex.example/all", Active: true}},
+	} {
+		if _, err := f.webhooks.Create(c.ctx, c.webhook); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	draft := &entities.Product{Name: "Secret launch", Status: entities.ProductStatusDraft}
+	if err := f.dispatcher.HandleEvent(context.Background(), &entities.ProductEvent{Type: entities.ProductCreated, TenantID: "acme", ProductID: "p1", Product: draft}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	queued := f.queued(t)
+	if len(queued) != 1 || queued[0].TenantID != "acme" || queued[0].EventType != entities.ProductCreated {
+		t.Fatalf("expected one created delivery to the active acme subscription for every type, got %+v", queued)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(queued[0].Body), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if _, ok := payload["product"]; ok || payload["product_id"] != "p1" {
+		t.Fatalf("expected the draft to be announced without its product, got %v", payload)
+	}
+}
+
+func TestWebhookDispatcherSignsAndRetriesDeliveries(t *testing.T) {
+	f := newWebhookFixture()
+	ctx := ports.WithTenant(context.Background(), "acme")
+
+	var mu sync.Mutex
+	status := http.StatusInternalServerError
+	received := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	id, err := f.webhooks.Create(ctx, &entities.WebhookSubscription{URL: server.URL, Secret: "s3cret", Active: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	workerCtx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		f.dispatcher.Wait()
+	}()
+	go f.dispatcher.RunDeliveryWorker(workerCtx)
+
+	if err := f.dispatcher.HandleEvent(context.Background(), &entities.ProductEvent{Type: entities.ProductDeleted, TenantID: "acme", ProductID: "p1"}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the delivery was never attempted")
+	}
+	body := <-bodies
+	timestamp, err := strconv.ParseInt(req.Header.Get(application.WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp header: %v", err)
+	}
+	if got := req.Header.Get(application.WebhookSignatureHeader); got != application.SignWebhookPayload("s3cret", timestamp, body) {
+		t.Fatalf("signature %s does not match the timestamp and body", got)
+	}
+	if req.Header.Get(application.WebhookEventHeader) != entities.ProductDeleted || req.Header.Get(application.WebhookIDHeader) == "" {
+		t.Fatalf("expected the event headers, got %v", req.Header)
+	}
+
+	// The failed attempt is logged and the delivery retried once the first backoff passes
+	mu.Lock()
+	status = http.StatusNoContent
+	mu.Unlock()
+	select {
+	case retry := <-received:
+		<-bodies
+		if retry.Header.Get(application.WebhookIDHeader) != req.Header.Get(application.WebhookIDHeader) {
+			t.Fatal("expected the retry to carry the same event ID")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the delivery was never retried")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := f.deliveries.FindBySubscription(ctx, id, 10)
+		if err != nil {
+			t.Fatalf("delivery log: %v", err)
+		}
+		if len(deliveries) == 2 {
+			if deliveries[0].Attempt != 2 || !deliveries[0].Success || deliveries[1].Attempt != 1 || deliveries[1].Success || deliveries[1].ResponseStatus != http.StatusInternalServerError {
+				t.Fatalf("expected a failed first attempt then a successful second, newest first, got %+v", deliveries)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected two logged attempts, got %d", len(deliveries))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if queued := f.queued(t); len(queued) != 0 {
+		t.Fatalf("expected the delivered event to leave the queue, got %+v", queued)
+	}
+}